@@ -0,0 +1,105 @@
+// Command restore loads an account backup archive (from GET
+// /api/v1/users/me/export) into this server's database. The target
+// account is created when no user has the given email yet.
+//
+//    go run ./cmd/restore -file backup.zip -email me@example.edu -password secret
+package main
+
+import (
+    "errors"
+    "flag"
+    "log"
+    "os"
+    "strings"
+
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+func main() {
+    file := flag.String("file", "", "backup archive to restore")
+    email := flag.String("email", "", "email of the account to restore into")
+    username := flag.String("username", "", "username when creating the account (default: email local part)")
+    password := flag.String("password", "", "password when creating the account")
+    firstName := flag.String("first-name", "", "first name when creating the account (default: from the backup)")
+    lastName := flag.String("last-name", "", "last name when creating the account (default: from the backup)")
+    flag.Parse()
+
+    if *file == "" || *email == "" {
+        flag.Usage()
+        os.Exit(2)
+    }
+
+    // Load configuration
+    config := configs.LoadConfig()
+
+    // Connect to database
+    database.Connect(config)
+    database.Migrate()
+
+    archive, err := os.Open(*file)
+    if err != nil {
+        log.Fatal("Could not open backup: ", err)
+    }
+    defer archive.Close()
+    info, err := archive.Stat()
+    if err != nil {
+        log.Fatal("Could not read backup: ", err)
+    }
+
+    var user models.User
+    err = database.GetDB().Where("email = ?", *email).First(&user).Error
+    switch {
+    case err == nil:
+        log.Printf("Restoring into existing account %s", user.Email)
+    case errors.Is(err, gorm.ErrRecordNotFound):
+        if *password == "" {
+            log.Fatal("-password is required to create a new account")
+        }
+        if *username == "" {
+            *username = strings.SplitN(*email, "@", 2)[0]
+        }
+        profile, err := services.NewBackupService().ReadProfile(archive, info.Size())
+        if err != nil {
+            log.Fatal("Could not read backup: ", err)
+        }
+        if *firstName == "" {
+            *firstName = profile.FirstName
+        }
+        if *lastName == "" {
+            *lastName = profile.LastName
+        }
+        created, err := services.NewUserService().Register(services.RegisterRequest{
+            Email:     *email,
+            Username:  *username,
+            Password:  *password,
+            FirstName: *firstName,
+            LastName:  *lastName,
+            Program:   profile.Program,
+            Year:      profile.Year,
+            Advisor:   profile.Advisor,
+        })
+        if err != nil {
+            log.Fatal("Could not create account: ", err)
+        }
+        user = *created
+        log.Printf("Created account %s (%s)", user.Email, user.Username)
+    default:
+        log.Fatal("Could not look up account: ", err)
+    }
+
+    result, err := services.NewBackupService().Restore(user.ID, archive, info.Size())
+    if err != nil {
+        log.Fatal("Restore failed: ", err)
+    }
+
+    for name, count := range result.Restored {
+        log.Printf("Restored %d %s", count, name)
+    }
+    for name, count := range result.Skipped {
+        log.Printf("Skipped %d %s with missing references", count, name)
+    }
+}
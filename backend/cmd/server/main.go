@@ -1,13 +1,19 @@
 package main
 
 import (
+    "context"
     "log"
+    "time"
 
     "github.com/gin-gonic/gin"
     "github.com/anayy09/academiaflow-backend/configs"
     "github.com/anayy09/academiaflow-backend/internal/database"
     "github.com/anayy09/academiaflow-backend/internal/handlers"
+    "github.com/anayy09/academiaflow-backend/internal/jobs"
+    "github.com/anayy09/academiaflow-backend/internal/mail"
     "github.com/anayy09/academiaflow-backend/internal/middleware"
+    "github.com/anayy09/academiaflow-backend/internal/notify"
+    "github.com/anayy09/academiaflow-backend/internal/services"
 )
 
 func main() {
@@ -36,6 +42,43 @@ func main() {
     userHandler := handlers.NewUserHandler(config)
     courseHandler := handlers.NewCourseHandler(config)
     assignmentHandler := handlers.NewAssignmentHandler(config)
+    seriesHandler := handlers.NewSeriesHandler(config)
+    dependencyHandler := handlers.NewDependencyHandler(config)
+    timeEntryHandler := handlers.NewTimeEntryHandler(config)
+    analyticsHandler := handlers.NewAnalyticsHandler(config)
+    plannerHandler := handlers.NewPlannerHandler(config)
+    calendarHandler := handlers.NewCalendarHandler(config)
+    importHandler := handlers.NewImportHandler(config)
+    caldavHandler := handlers.NewCalDAVHandler(config)
+    exportHandler := handlers.NewExportHandler(config)
+    backupHandler := handlers.NewBackupHandler(config)
+    meetingHandler := handlers.NewMeetingHandler(config)
+    termHandler := handlers.NewTermHandler(config)
+    academicsHandler := handlers.NewAcademicsHandler(config)
+    gradebookHandler := handlers.NewGradebookHandler(config)
+    degreeHandler := handlers.NewDegreeHandler(config)
+    milestoneHandler := handlers.NewMilestoneHandler(config)
+    paperHandler := handlers.NewPaperHandler(config)
+    readingHandler := handlers.NewReadingHandler(config)
+    advisorHandler := handlers.NewAdvisorHandler(config)
+    reportHandler := handlers.NewReportHandler(config)
+    appointmentHandler := handlers.NewAppointmentHandler(config)
+    fundingHandler := handlers.NewFundingHandler(config)
+    thesisHandler := handlers.NewThesisHandler(config)
+    goalHandler := handlers.NewGoalHandler(config)
+    notificationHandler := handlers.NewNotificationHandler(config)
+
+    // Background jobs: weekly report emails and deadline reminders
+    mailer := mail.NewMailer(config.Mail)
+    scheduler := jobs.NewScheduler(database.GetDB(), config.Jobs)
+    now := time.Now()
+    if err := services.NewReportService(mailer).RegisterJobs(scheduler, now); err != nil {
+        log.Fatal("Failed to schedule report jobs:", err)
+    }
+    if err := services.NewNotificationService(notify.NewChannels(mailer)).RegisterJobs(scheduler, now); err != nil {
+        log.Fatal("Failed to schedule notification jobs:", err)
+    }
+    go scheduler.Run(context.Background())
 
     // Health check endpoint
     router.GET("/health", func(c *gin.Context) {
@@ -45,6 +88,17 @@ func main() {
         })
     })
 
+    // CalDAV task sync, authenticated with the account email and password
+    router.GET("/.well-known/caldav", caldavHandler.WellKnown)
+    router.Handle("PROPFIND", "/.well-known/caldav", caldavHandler.WellKnown)
+    caldav := router.Group("/caldav")
+    caldav.Use(middleware.BasicAuthMiddleware())
+    {
+        for _, method := range []string{"OPTIONS", "PROPFIND", "REPORT", "GET", "HEAD", "PUT", "DELETE"} {
+            caldav.Handle(method, "/*path", caldavHandler.Serve)
+        }
+    }
+
     // API v1 routes
     v1 := router.Group("/api/v1")
     {
@@ -55,6 +109,13 @@ func main() {
             auth.POST("/login", authHandler.Login)
         }
 
+        // Calendar feeds are authenticated by the secret token in the URL
+        v1.GET("/calendar/feeds/:token", calendarHandler.GetFeed)
+
+        // Word count pushes from editors and git hooks are authenticated by
+        // the thesis push token
+        v1.POST("/thesis/push/:token", thesisHandler.PushWordCountsByToken)
+
         // Protected routes
         protected := v1.Group("/")
         protected.Use(middleware.AuthMiddleware(config))
@@ -64,6 +125,177 @@ func main() {
             {
                 users.GET("/profile", authHandler.GetProfile)
                 users.PUT("/profile", userHandler.UpdateProfile)
+                users.GET("/me/export", backupHandler.ExportAccount)
+                users.POST("/me/restore", backupHandler.RestoreAccount)
+            }
+
+            // Term routes
+            terms := protected.Group("/terms")
+            {
+                terms.GET("/", termHandler.GetTerms)
+                terms.POST("/", termHandler.CreateTerm)
+                terms.GET("/current", termHandler.GetCurrentTerm)
+                terms.GET("/:id", termHandler.GetTerm)
+                terms.PUT("/:id", termHandler.UpdateTerm)
+                terms.DELETE("/:id", termHandler.DeleteTerm)
+            }
+
+            // Grades and GPA
+            academics := protected.Group("/academics")
+            {
+                academics.GET("/summary", academicsHandler.GetSummary)
+                academics.GET("/scales", academicsHandler.GetScales)
+                academics.POST("/scales", academicsHandler.CreateScale)
+                academics.PUT("/scales/:id", academicsHandler.UpdateScale)
+                academics.DELETE("/scales/:id", academicsHandler.DeleteScale)
+            }
+
+            // Degree requirements
+            degree := protected.Group("/degree")
+            {
+                degree.GET("/programs", degreeHandler.GetPrograms)
+                degree.POST("/programs", degreeHandler.CreateProgram)
+                degree.GET("/programs/:id", degreeHandler.GetProgram)
+                degree.PUT("/programs/:id", degreeHandler.UpdateProgram)
+                degree.DELETE("/programs/:id", degreeHandler.DeleteProgram)
+                degree.PUT("/program", degreeHandler.AttachProgram)
+                degree.GET("/audit", degreeHandler.GetAudit)
+            }
+
+            // Research milestones
+            milestones := protected.Group("/milestones")
+            {
+                milestones.GET("/", milestoneHandler.GetMilestones)
+                milestones.POST("/", milestoneHandler.CreateMilestone)
+                milestones.GET("/timeline", milestoneHandler.GetTimeline)
+                milestones.GET("/:id", milestoneHandler.GetMilestone)
+                milestones.PUT("/:id", milestoneHandler.UpdateMilestone)
+                milestones.DELETE("/:id", milestoneHandler.DeleteMilestone)
+                milestones.POST("/:id/assignments", milestoneHandler.LinkAssignment)
+                milestones.DELETE("/:id/assignments/:assignmentId", milestoneHandler.UnlinkAssignment)
+            }
+
+            // Publications
+            papers := protected.Group("/papers")
+            {
+                papers.GET("/", paperHandler.GetPapers)
+                papers.POST("/", paperHandler.CreatePaper)
+                papers.GET("/:id", paperHandler.GetPaper)
+                papers.PUT("/:id", paperHandler.UpdatePaper)
+                papers.DELETE("/:id", paperHandler.DeletePaper)
+                papers.POST("/:id/rounds", paperHandler.CreateRound)
+                papers.PUT("/:id/rounds/:roundId", paperHandler.UpdateRound)
+                papers.DELETE("/:id/rounds/:roundId", paperHandler.DeleteRound)
+            }
+
+            // Reading list
+            readings := protected.Group("/readings")
+            {
+                readings.GET("/", readingHandler.GetReadings)
+                readings.POST("/", readingHandler.CreateReading)
+                readings.POST("/import", readingHandler.ImportReadings)
+                readings.GET("/export", readingHandler.ExportReadings)
+                readings.GET("/:id", readingHandler.GetReading)
+                readings.PUT("/:id", readingHandler.UpdateReading)
+                readings.DELETE("/:id", readingHandler.DeleteReading)
+            }
+
+            // Advisor meetings and action items
+            advisor := protected.Group("/advisor")
+            {
+                advisor.GET("/meetings", advisorHandler.GetMeetings)
+                advisor.POST("/meetings", advisorHandler.CreateMeeting)
+                advisor.GET("/meetings/:id", advisorHandler.GetMeeting)
+                advisor.PUT("/meetings/:id", advisorHandler.UpdateMeeting)
+                advisor.DELETE("/meetings/:id", advisorHandler.DeleteMeeting)
+                advisor.GET("/meetings/:id/review", advisorHandler.GetReview)
+                advisor.POST("/meetings/:id/items", advisorHandler.CreateActionItem)
+                advisor.PUT("/meetings/:id/items/:itemId", advisorHandler.UpdateActionItem)
+                advisor.DELETE("/meetings/:id/items/:itemId", advisorHandler.DeleteActionItem)
+                advisor.POST("/meetings/:id/items/:itemId/promote", advisorHandler.PromoteActionItem)
+                advisor.GET("/action-items", advisorHandler.GetOpenItems)
+            }
+
+            // TA/RA appointments and their duties
+            appointments := protected.Group("/appointments")
+            {
+                appointments.GET("/", appointmentHandler.GetAppointments)
+                appointments.POST("/", appointmentHandler.CreateAppointment)
+                appointments.GET("/:id", appointmentHandler.GetAppointment)
+                appointments.PUT("/:id", appointmentHandler.UpdateAppointment)
+                appointments.DELETE("/:id", appointmentHandler.DeleteAppointment)
+                appointments.GET("/:id/hours", appointmentHandler.GetHours)
+                appointments.POST("/:id/duties", appointmentHandler.CreateDuty)
+                appointments.PUT("/:id/duties/:dutyId", appointmentHandler.UpdateDuty)
+                appointments.DELETE("/:id/duties/:dutyId", appointmentHandler.DeleteDuty)
+            }
+
+            // Funding sources and their deadlines
+            funding := protected.Group("/funding")
+            {
+                funding.GET("/", fundingHandler.GetFundings)
+                funding.POST("/", fundingHandler.CreateFunding)
+                funding.GET("/eligibility", fundingHandler.GetAllEligibility)
+                funding.GET("/:id", fundingHandler.GetFunding)
+                funding.PUT("/:id", fundingHandler.UpdateFunding)
+                funding.DELETE("/:id", fundingHandler.DeleteFunding)
+                funding.GET("/:id/eligibility", fundingHandler.GetEligibility)
+                funding.POST("/:id/deadlines", fundingHandler.CreateDeadline)
+                funding.PUT("/:id/deadlines/:deadlineId", fundingHandler.UpdateDeadline)
+                funding.DELETE("/:id/deadlines/:deadlineId", fundingHandler.DeleteDeadline)
+                funding.POST("/:id/deadlines/:deadlineId/complete", fundingHandler.CompleteDeadline)
+            }
+
+            // Thesis chapters and word counts
+            thesis := protected.Group("/thesis")
+            {
+                thesis.GET("/", thesisHandler.GetTheses)
+                thesis.POST("/", thesisHandler.CreateThesis)
+                thesis.GET("/:id", thesisHandler.GetThesis)
+                thesis.PUT("/:id", thesisHandler.UpdateThesis)
+                thesis.DELETE("/:id", thesisHandler.DeleteThesis)
+                thesis.POST("/:id/push-token/rotate", thesisHandler.RotatePushToken)
+                thesis.PUT("/:id/word-counts", thesisHandler.PushWordCounts)
+                thesis.GET("/:id/burnup", thesisHandler.GetBurnUp)
+                thesis.POST("/:id/chapters", thesisHandler.CreateChapter)
+                thesis.PUT("/:id/chapters/:chapterId", thesisHandler.UpdateChapter)
+                thesis.DELETE("/:id/chapters/:chapterId", thesisHandler.DeleteChapter)
+            }
+
+            // Goals with key results and check-ins
+            goals := protected.Group("/goals")
+            {
+                goals.GET("/", goalHandler.GetGoals)
+                goals.POST("/", goalHandler.CreateGoal)
+                goals.GET("/review", goalHandler.GetReview)
+                goals.GET("/:id", goalHandler.GetGoal)
+                goals.PUT("/:id", goalHandler.UpdateGoal)
+                goals.DELETE("/:id", goalHandler.DeleteGoal)
+                goals.POST("/:id/key-results", goalHandler.CreateKeyResult)
+                goals.PUT("/:id/key-results/:keyResultId", goalHandler.UpdateKeyResult)
+                goals.DELETE("/:id/key-results/:keyResultId", goalHandler.DeleteKeyResult)
+                goals.POST("/:id/check-ins", goalHandler.CreateCheckIn)
+                goals.DELETE("/:id/check-ins/:checkInId", goalHandler.DeleteCheckIn)
+            }
+
+            // In-app notifications and reminder preferences
+            notifications := protected.Group("/notifications")
+            {
+                notifications.GET("/", notificationHandler.GetNotifications)
+                notifications.POST("/read-all", notificationHandler.MarkAllRead)
+                notifications.POST("/test", notificationHandler.SendTest)
+                notifications.GET("/preferences", notificationHandler.GetPreferences)
+                notifications.PUT("/preferences", notificationHandler.UpdatePreferences)
+                notifications.POST("/:id/read", notificationHandler.MarkRead)
+            }
+
+            // Weekly progress reports
+            reports := protected.Group("/reports")
+            {
+                reports.GET("/weekly", reportHandler.GetWeekly)
+                reports.POST("/weekly/send", reportHandler.SendWeekly)
+                reports.GET("/schedule", reportHandler.GetSchedule)
+                reports.PUT("/schedule", reportHandler.UpdateSchedule)
             }
 
             // Course routes
@@ -74,7 +306,22 @@ func main() {
                 courses.GET("/:id", courseHandler.GetCourse)
                 courses.PUT("/:id", courseHandler.UpdateCourse)
                 courses.DELETE("/:id", courseHandler.DeleteCourse)
+
+                // Weekly meeting slots
+                courses.GET("/:id/meetings", meetingHandler.GetMeetings)
+                courses.POST("/:id/meetings", meetingHandler.CreateMeeting)
+                courses.PUT("/:id/meetings/:meetingId", meetingHandler.UpdateMeeting)
+                courses.DELETE("/:id/meetings/:meetingId", meetingHandler.DeleteMeeting)
+
+                // Weighted grade components
+                courses.GET("/:id/categories", gradebookHandler.GetCategories)
+                courses.POST("/:id/categories", gradebookHandler.CreateCategory)
+                courses.PUT("/:id/categories/:categoryId", gradebookHandler.UpdateCategory)
+                courses.DELETE("/:id/categories/:categoryId", gradebookHandler.DeleteCategory)
+                courses.GET("/:id/grade", gradebookHandler.GetCourseGrade)
+                courses.POST("/:id/what-if", gradebookHandler.WhatIf)
             }
+            protected.GET("/timetable", meetingHandler.GetTimetable)
 
             // Assignment routes
             assignments := protected.Group("/assignments")
@@ -85,7 +332,64 @@ func main() {
                 assignments.PUT("/:id", assignmentHandler.UpdateAssignment)
                 assignments.DELETE("/:id", assignmentHandler.DeleteAssignment)
                 assignments.PATCH("/:id/status", assignmentHandler.UpdateStatus)
+                assignments.PUT("/:id/grade", gradebookHandler.GradeAssignment)
+
+                // Recurring assignment series
+                assignments.GET("/series", seriesHandler.GetAllSeries)
+                assignments.POST("/series", seriesHandler.CreateSeries)
+                assignments.GET("/series/:id", seriesHandler.GetSeries)
+                assignments.PUT("/series/:id", seriesHandler.UpdateSeries)
+                assignments.DELETE("/series/:id", seriesHandler.DeleteSeries)
+
+                // Dependencies between assignments
+                assignments.GET("/critical-path", dependencyHandler.GetCriticalPath)
+                assignments.GET("/:id/dependencies", dependencyHandler.GetDependencies)
+                assignments.POST("/:id/dependencies", dependencyHandler.AddDependency)
+                assignments.DELETE("/:id/dependencies/:blockerId", dependencyHandler.RemoveDependency)
+
+                // Time tracking
+                assignments.POST("/:id/timer/start", timeEntryHandler.StartTimer)
+                assignments.POST("/:id/timer/stop", timeEntryHandler.StopTimer)
+                assignments.GET("/:id/time-entries", timeEntryHandler.GetTimeEntries)
+                assignments.POST("/:id/time-entries", timeEntryHandler.CreateTimeEntry)
+            }
+
+            // Time entry routes
+            protected.GET("/timer", timeEntryHandler.GetRunningTimer)
+            timeEntries := protected.Group("/time-entries")
+            {
+                timeEntries.PUT("/:id", timeEntryHandler.UpdateTimeEntry)
+                timeEntries.DELETE("/:id", timeEntryHandler.DeleteTimeEntry)
+            }
+
+            // Analytics routes
+            analytics := protected.Group("/analytics")
+            {
+                analytics.GET("/estimation", analyticsHandler.GetEstimationReport)
+            }
+
+            // Workload planner routes
+            planner := protected.Group("/planner")
+            {
+                planner.GET("", plannerHandler.GetPlan)
+                planner.GET("/availability", plannerHandler.GetAvailability)
+                planner.PUT("/availability", plannerHandler.UpdateAvailability)
+            }
+
+            // Calendar feed management
+            calendar := protected.Group("/calendar")
+            {
+                calendar.GET("/feed", calendarHandler.GetFeedInfo)
+                calendar.POST("/feed/rotate", calendarHandler.RotateFeedToken)
+            }
+
+            // Import and export routes
+            imports := protected.Group("/import")
+            {
+                imports.POST("", importHandler.ImportRecords)
+                imports.POST("/ics", importHandler.ImportICS)
             }
+            protected.GET("/export", exportHandler.Export)
         }
     }
 
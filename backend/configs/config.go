@@ -13,6 +13,8 @@ type Config struct {
     Database DatabaseConfig
     JWT      JWTConfig
     Server   ServerConfig
+    Mail     MailConfig
+    Jobs     JobsConfig
 }
 
 type DatabaseConfig struct {
@@ -30,8 +32,28 @@ type JWTConfig struct {
 }
 
 type ServerConfig struct {
-    Port string
-    Host string
+    Port      string
+    Host      string
+    PublicURL string // Base URL used in links handed to other apps, e.g. calendar feeds
+}
+
+// MailConfig is the SMTP relay used for outgoing email. Mail is disabled
+// while Host is empty.
+type MailConfig struct {
+    Host     string
+    Port     int
+    Username string
+    Password string
+    From     string
+}
+
+// JobsConfig tunes the background job runner. A job is leased to one
+// instance for Lease; if that instance dies the job is run again once the
+// lease runs out.
+type JobsConfig struct {
+    WorkerID     string // Defaults to the host name and process ID
+    PollInterval time.Duration
+    Lease        time.Duration
 }
 
 func LoadConfig() *Config {
@@ -40,7 +62,10 @@ func LoadConfig() *Config {
     }
 
     port, _ := strconv.Atoi(getEnv("DB_PORT", "5432"))
+    smtpPort, _ := strconv.Atoi(getEnv("SMTP_PORT", "587"))
     expiresIn, _ := time.ParseDuration(getEnv("JWT_EXPIRES_IN", "24h"))
+    pollInterval, _ := time.ParseDuration(getEnv("JOBS_POLL_INTERVAL", "15s"))
+    lease, _ := time.ParseDuration(getEnv("JOBS_LEASE", "5m"))
 
     return &Config{
         Database: DatabaseConfig{
@@ -56,8 +81,21 @@ func LoadConfig() *Config {
             ExpiresIn: expiresIn,
         },
         Server: ServerConfig{
-            Port: getEnv("SERVER_PORT", "8080"),
-            Host: getEnv("SERVER_HOST", "localhost"),
+            Port:      getEnv("SERVER_PORT", "8080"),
+            Host:      getEnv("SERVER_HOST", "localhost"),
+            PublicURL: getEnv("PUBLIC_URL", "http://localhost:8080"),
+        },
+        Mail: MailConfig{
+            Host:     getEnv("SMTP_HOST", ""),
+            Port:     smtpPort,
+            Username: getEnv("SMTP_USERNAME", ""),
+            Password: getEnv("SMTP_PASSWORD", ""),
+            From:     getEnv("SMTP_FROM", "AcademiaFlow <no-reply@localhost>"),
+        },
+        Jobs: JobsConfig{
+            WorkerID:     getEnv("JOBS_WORKER_ID", ""),
+            PollInterval: pollInterval,
+            Lease:        lease,
         },
     }
 }
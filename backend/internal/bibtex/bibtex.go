@@ -0,0 +1,257 @@
+package bibtex
+
+import (
+    "bytes"
+    "io"
+    "sort"
+    "strings"
+    "unicode"
+)
+
+// Entry is a single reference such as @article{key, ...}. Field names are
+// lower case and values are kept as written, minus the outer delimiters;
+// use Field for display text.
+type Entry struct {
+    Type   string
+    Key    string
+    Fields map[string]string
+}
+
+func NewEntry(entryType, key string) *Entry {
+    return &Entry{Type: strings.ToLower(entryType), Key: key, Fields: map[string]string{}}
+}
+
+// Field returns a field's value with braces and common LaTeX escapes and
+// accents resolved. Identifiers such as doi and url only lose their
+// braces.
+func (e *Entry) Field(name string) string {
+    if verbatimFields[name] {
+        value := strings.NewReplacer("{", "", "}", "", `\_`, "_", `\%`, "%", `\&`, "&").Replace(e.Fields[name])
+        return strings.TrimSpace(value)
+    }
+    return Clean(e.Fields[name])
+}
+
+// Names splits a name-list field such as author or editor on "and".
+func (e *Entry) Names(name string) []string {
+    return SplitNames(e.Fields[name])
+}
+
+// SplitNames splits a BibTeX name list on " and " outside braces, so
+// corporate names like {Barnes and Noble} stay whole.
+func SplitNames(value string) []string {
+    var names []string
+    depth, start := 0, 0
+    lower := strings.ToLower(value)
+    for i := 0; i < len(value); i++ {
+        switch value[i] {
+        case '{':
+            depth++
+        case '}':
+            depth--
+        default:
+            if depth == 0 && strings.HasPrefix(lower[i:], " and ") {
+                names = appendName(names, value[start:i])
+                start = i + len(" and ")
+                i = start - 1
+            }
+        }
+    }
+    return appendName(names, value[start:])
+}
+
+func appendName(names []string, raw string) []string {
+    if name := Clean(raw); name != "" {
+        return append(names, name)
+    }
+    return names
+}
+
+// JoinNames builds a name-list value from plain names, bracing any name
+// that would otherwise be split on its own "and".
+func JoinNames(names []string) string {
+    values := make([]string, 0, len(names))
+    for _, name := range names {
+        name = strings.TrimSpace(name)
+        if name == "" {
+            continue
+        }
+        value := Escape(name)
+        if strings.Contains(strings.ToLower(name), " and ") {
+            value = "{" + value + "}"
+        }
+        values = append(values, value)
+    }
+    return strings.Join(values, " and ")
+}
+
+// LastName picks the family name out of "Last, First" or "First Last".
+func LastName(name string) string {
+    if i := strings.Index(name, ","); i >= 0 {
+        return strings.TrimSpace(name[:i])
+    }
+    parts := strings.Fields(name)
+    if len(parts) == 0 {
+        return ""
+    }
+    return parts[len(parts)-1]
+}
+
+// Fields written first, in this order; the rest follow alphabetically.
+var fieldOrder = []string{"author", "editor", "title", "journal", "booktitle", "year", "month", "volume", "number", "pages", "publisher", "school", "institution", "doi", "url"}
+
+// Fields holding identifiers, which are written without escaping.
+var verbatimFields = map[string]bool{"doi": true, "url": true, "eprint": true, "isbn": true, "issn": true}
+
+// Encode writes entries as BibTeX. Values are written as stored, so
+// entries built from plain text should go through Escape and JoinNames
+// first.
+func Encode(w io.Writer, entries []*Entry) error {
+    var buf bytes.Buffer
+    for i, e := range entries {
+        if i > 0 {
+            buf.WriteString("\n")
+        }
+        buf.WriteString("@" + e.Type + "{" + e.Key + ",\n")
+        for _, name := range orderedFields(e.Fields) {
+            buf.WriteString("  " + name + " = {" + e.Fields[name] + "},\n")
+        }
+        buf.WriteString("}\n")
+    }
+    _, err := w.Write(buf.Bytes())
+    return err
+}
+
+func orderedFields(fields map[string]string) []string {
+    rank := make(map[string]int, len(fieldOrder))
+    for i, name := range fieldOrder {
+        rank[name] = i + 1
+    }
+    var names []string
+    for name, value := range fields {
+        if strings.TrimSpace(value) != "" {
+            names = append(names, name)
+        }
+    }
+    sort.Slice(names, func(i, j int) bool {
+        ri, rj := rank[names[i]], rank[names[j]]
+        switch {
+        case ri > 0 && rj > 0:
+            return ri < rj
+        case ri > 0 || rj > 0:
+            return ri > 0
+        }
+        return names[i] < names[j]
+    })
+    return names
+}
+
+// Escape makes plain text safe inside a braced BibTeX value.
+func Escape(s string) string {
+    var b strings.Builder
+    for _, r := range s {
+        switch r {
+        case '&', '%', '$', '#', '_', '{', '}':
+            b.WriteRune('\\')
+            b.WriteRune(r)
+        case '\\':
+            b.WriteString(`\textbackslash{}`)
+        default:
+            b.WriteRune(r)
+        }
+    }
+    return b.String()
+}
+
+// EscapeField escapes a plain-text value for the named field, leaving
+// identifiers such as doi and url as they are.
+func EscapeField(name, value string) string {
+    if verbatimFields[name] {
+        return value
+    }
+    return Escape(value)
+}
+
+// Accent commands and the precomposed letters they produce
+var accents = map[byte]map[rune]rune{
+    '\'': {'a': 'á', 'e': 'é', 'i': 'í', 'o': 'ó', 'u': 'ú', 'y': 'ý', 'c': 'ć', 'n': 'ń', 's': 'ś', 'z': 'ź',
+        'A': 'Á', 'E': 'É', 'I': 'Í', 'O': 'Ó', 'U': 'Ú', 'Y': 'Ý', 'C': 'Ć', 'N': 'Ń', 'S': 'Ś', 'Z': 'Ź'},
+    '`':  {'a': 'à', 'e': 'è', 'i': 'ì', 'o': 'ò', 'u': 'ù', 'A': 'À', 'E': 'È', 'I': 'Ì', 'O': 'Ò', 'U': 'Ù'},
+    '"':  {'a': 'ä', 'e': 'ë', 'i': 'ï', 'o': 'ö', 'u': 'ü', 'y': 'ÿ', 'A': 'Ä', 'E': 'Ë', 'I': 'Ï', 'O': 'Ö', 'U': 'Ü'},
+    '^':  {'a': 'â', 'e': 'ê', 'i': 'î', 'o': 'ô', 'u': 'û', 'A': 'Â', 'E': 'Ê', 'I': 'Î', 'O': 'Ô', 'U': 'Û'},
+    '~':  {'a': 'ã', 'n': 'ñ', 'o': 'õ', 'A': 'Ã', 'N': 'Ñ', 'O': 'Õ'},
+    'c':  {'c': 'ç', 's': 'ş', 'C': 'Ç', 'S': 'Ş'},
+    'v':  {'c': 'č', 's': 'š', 'z': 'ž', 'r': 'ř', 'e': 'ě', 'C': 'Č', 'S': 'Š', 'Z': 'Ž', 'R': 'Ř', 'E': 'Ě'},
+}
+
+// Letter commands such as \ss and \o
+var letters = map[string]string{
+    "ss": "ß", "o": "ø", "O": "Ø", "ae": "æ", "AE": "Æ", "aa": "å", "AA": "Å", "l": "ł", "L": "Ł", "i": "ı",
+}
+
+// Clean converts a raw BibTeX value to plain text: braces are dropped,
+// escaped specials and accents are resolved, ~ becomes a space and runs
+// of whitespace collapse.
+func Clean(value string) string {
+    var b strings.Builder
+    for i := 0; i < len(value); i++ {
+        c := value[i]
+        switch {
+        case c == '{' || c == '}':
+            continue
+        case c == '~':
+            b.WriteByte(' ')
+            continue
+        case c != '\\' || i+1 == len(value):
+            b.WriteByte(c)
+            continue
+        }
+
+        next := value[i+1]
+        if table, ok := accents[next]; ok {
+            // \'e, \'{e}, {\'e} and \c{c} all name the same letter
+            j := i + 2
+            for j < len(value) && (value[j] == '{' || value[j] == ' ' || value[j] == '\\') {
+                j++
+            }
+            if j < len(value) {
+                if letter, ok := table[rune(value[j])]; ok && (next != 'c' && next != 'v' || j > i+2) {
+                    b.WriteRune(letter)
+                    i = j
+                    for i+1 < len(value) && value[i+1] == '}' {
+                        i++
+                    }
+                    continue
+                }
+            }
+        }
+        if strings.ContainsRune(`&%$#_{}`, rune(next)) {
+            b.WriteByte(next)
+            i++
+            continue
+        }
+
+        // Named commands: known letters are substituted, anything else
+        // (\emph, \textit, ...) is dropped and its argument kept
+        j := i + 1
+        for j < len(value) && unicode.IsLetter(rune(value[j])) {
+            j++
+        }
+        name := value[i+1 : j]
+        if name == "" {
+            b.WriteByte(next)
+            i++
+            continue
+        }
+        if letter, ok := letters[name]; ok {
+            b.WriteString(letter)
+        } else if name == "textbackslash" {
+            b.WriteByte('\\')
+        }
+        i = j - 1
+        if i+1 < len(value) && value[i+1] == ' ' {
+            i++
+        }
+    }
+    return strings.Join(strings.Fields(b.String()), " ")
+}
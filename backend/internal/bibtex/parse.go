@@ -0,0 +1,267 @@
+package bibtex
+
+import (
+    "fmt"
+    "io"
+    "strings"
+    "unicode"
+)
+
+// Month macros every BibTeX style predefines
+var monthMacros = map[string]string{
+    "jan": "January", "feb": "February", "mar": "March", "apr": "April", "may": "May", "jun": "June",
+    "jul": "July", "aug": "August", "sep": "September", "oct": "October", "nov": "November", "dec": "December",
+}
+
+// Parse reads a BibTeX file. @string macros are expanded, @comment and
+// @preamble blocks are skipped, and text between entries is ignored as
+// BibTeX itself does.
+func Parse(r io.Reader) ([]*Entry, error) {
+    data, err := io.ReadAll(r)
+    if err != nil {
+        return nil, err
+    }
+    p := &parser{src: string(data), macros: map[string]string{}}
+    for k, v := range monthMacros {
+        p.macros[k] = v
+    }
+
+    var entries []*Entry
+    for {
+        at := strings.IndexByte(p.src[p.pos:], '@')
+        if at < 0 {
+            break
+        }
+        p.pos += at + 1
+        entry, err := p.entry()
+        if err != nil {
+            return nil, fmt.Errorf("line %d: %w", p.line(), err)
+        }
+        if entry != nil {
+            entries = append(entries, entry)
+        }
+    }
+    return entries, nil
+}
+
+type parser struct {
+    src    string
+    pos    int
+    macros map[string]string
+}
+
+func (p *parser) line() int {
+    pos := p.pos
+    if pos > len(p.src) {
+        pos = len(p.src)
+    }
+    return strings.Count(p.src[:pos], "\n") + 1
+}
+
+func (p *parser) skipSpace() {
+    for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+        p.pos++
+    }
+}
+
+func (p *parser) peek() byte {
+    if p.pos < len(p.src) {
+        return p.src[p.pos]
+    }
+    return 0
+}
+
+func (p *parser) ident() string {
+    start := p.pos
+    for p.pos < len(p.src) {
+        c := p.src[p.pos]
+        if unicode.IsSpace(rune(c)) || strings.IndexByte(`{}(),="#%@`, c) >= 0 {
+            break
+        }
+        p.pos++
+    }
+    return p.src[start:p.pos]
+}
+
+// entry parses what follows an @. It returns nil for blocks that hold no
+// reference.
+func (p *parser) entry() (*Entry, error) {
+    p.skipSpace()
+    kind := strings.ToLower(p.ident())
+    if kind == "" {
+        return nil, nil
+    }
+    p.skipSpace()
+    open := p.peek()
+    if open != '{' && open != '(' {
+        if kind == "comment" {
+            return nil, nil
+        }
+        return nil, fmt.Errorf("expected { after @%s", kind)
+    }
+    end := byte('}')
+    if open == '(' {
+        end = ')'
+    }
+
+    switch kind {
+    case "comment", "preamble":
+        return nil, p.skipBlock(open, end)
+    case "string":
+        p.pos++
+        name, value, err := p.field()
+        if err != nil {
+            return nil, err
+        }
+        p.macros[name] = value
+        p.skipSpace()
+        if p.peek() != end {
+            return nil, fmt.Errorf("unterminated @string")
+        }
+        p.pos++
+        return nil, nil
+    }
+
+    p.pos++
+    p.skipSpace()
+    start := p.pos
+    for p.pos < len(p.src) && p.src[p.pos] != ',' && p.src[p.pos] != end && p.src[p.pos] != '\n' {
+        p.pos++
+    }
+    entry := NewEntry(kind, strings.TrimSpace(p.src[start:p.pos]))
+
+    for {
+        p.skipSpace()
+        switch p.peek() {
+        case ',':
+            p.pos++
+            continue
+        case end:
+            p.pos++
+            return entry, nil
+        case 0:
+            return nil, fmt.Errorf("unterminated entry %q", entry.Key)
+        }
+        name, value, err := p.field()
+        if err != nil {
+            return nil, fmt.Errorf("entry %q: %w", entry.Key, err)
+        }
+        entry.Fields[name] = value
+    }
+}
+
+// field parses name = part # part ... and returns the joined value.
+func (p *parser) field() (string, string, error) {
+    p.skipSpace()
+    name := strings.ToLower(p.ident())
+    if name == "" {
+        return "", "", fmt.Errorf("expected a field name")
+    }
+    p.skipSpace()
+    if p.peek() != '=' {
+        return "", "", fmt.Errorf("expected = after %s", name)
+    }
+    p.pos++
+
+    var value strings.Builder
+    for {
+        p.skipSpace()
+        switch c := p.peek(); {
+        case c == '{':
+            part, err := p.braced()
+            if err != nil {
+                return "", "", err
+            }
+            value.WriteString(part)
+        case c == '"':
+            part, err := p.quoted()
+            if err != nil {
+                return "", "", err
+            }
+            value.WriteString(part)
+        default:
+            word := p.ident()
+            if word == "" {
+                return "", "", fmt.Errorf("expected a value for %s", name)
+            }
+            if expanded, ok := p.macros[strings.ToLower(word)]; ok {
+                word = expanded
+            }
+            value.WriteString(word)
+        }
+        p.skipSpace()
+        if p.peek() != '#' {
+            break
+        }
+        p.pos++
+    }
+    return name, strings.Join(strings.Fields(value.String()), " "), nil
+}
+
+// braced reads a {...} group and returns its content with inner braces
+// kept.
+func (p *parser) braced() (string, error) {
+    start := p.pos + 1
+    depth := 0
+    for ; p.pos < len(p.src); p.pos++ {
+        switch p.src[p.pos] {
+        case '\\':
+            // The escaped byte can't close the value, but there must be one
+            if p.pos+1 >= len(p.src) {
+                return "", fmt.Errorf("unterminated value")
+            }
+            p.pos++
+        case '{':
+            depth++
+        case '}':
+            depth--
+            if depth == 0 {
+                p.pos++
+                return p.src[start : p.pos-1], nil
+            }
+        }
+    }
+    return "", fmt.Errorf("unbalanced braces")
+}
+
+func (p *parser) skipBlock(open, end byte) error {
+    depth := 0
+    for ; p.pos < len(p.src); p.pos++ {
+        switch p.src[p.pos] {
+        case open:
+            depth++
+        case end:
+            depth--
+            if depth == 0 {
+                p.pos++
+                return nil
+            }
+        }
+    }
+    return fmt.Errorf("unterminated block")
+}
+
+// quoted reads a "..." value; quotes inside braces don't end it.
+func (p *parser) quoted() (string, error) {
+    start := p.pos + 1
+    depth := 0
+    for p.pos++; p.pos < len(p.src); p.pos++ {
+        switch p.src[p.pos] {
+        case '\\':
+            if p.pos+1 >= len(p.src) {
+                return "", fmt.Errorf("unterminated value")
+            }
+            p.pos++
+        case '{':
+            depth++
+        case '}':
+            depth--
+        case '"':
+            if depth == 0 {
+                p.pos++
+                return p.src[start : p.pos-1], nil
+            }
+        }
+    }
+    return "", fmt.Errorf("unterminated quoted value")
+}
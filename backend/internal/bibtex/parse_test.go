@@ -0,0 +1,203 @@
+package bibtex
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestParse(t *testing.T) {
+    tests := []struct {
+        name   string
+        input  string
+        keys   []string
+        fields map[string]string // Raw fields of the first entry
+    }{
+        {
+            name:   "braced and quoted values",
+            input:  `@article{smith2020, title={A Study}, journal = "Nature", year = 2020}`,
+            keys:   []string{"smith2020"},
+            fields: map[string]string{"title": "A Study", "journal": "Nature", "year": "2020"},
+        },
+        {
+            name:   "nested braces are kept",
+            input:  `@article{k, title={The {DNA} of {{Deep}} Nets}}`,
+            keys:   []string{"k"},
+            fields: map[string]string{"title": "The {DNA} of {{Deep}} Nets"},
+        },
+        {
+            name:   "quotes inside braces don't end a quoted value",
+            input:  `@misc{k, note = "say {"}hi{"}"}`,
+            keys:   []string{"k"},
+            fields: map[string]string{"note": `say {"}hi{"}`},
+        },
+        {
+            name:   "escaped braces",
+            input:  `@misc{k, title = {50\% \{off\}}}`,
+            keys:   []string{"k"},
+            fields: map[string]string{"title": `50\% \{off\}`},
+        },
+        {
+            name: "string macros and concatenation",
+            input: `@string{nips = "Advances in Neural Information Processing Systems"}
+@inproceedings{k, booktitle = nips # " 33", month = jan}`,
+            keys:   []string{"k"},
+            fields: map[string]string{"booktitle": "Advances in Neural Information Processing Systems 33", "month": "January"},
+        },
+        {
+            name: "comments and preamble are skipped",
+            input: `@comment{ @article{hidden, title={No}} }
+@preamble{"\newcommand{\x}{y}"}
+@comment this line is just text
+@book(k, title = {Kept})`,
+            keys:   []string{"k"},
+            fields: map[string]string{"title": "Kept"},
+        },
+        {
+            name:  "text between entries is ignored",
+            input: "Some notes\n@misc{a, title={A}}\nmore notes\n@misc{b, title={B},}\n",
+            keys:  []string{"a", "b"},
+        },
+        {
+            name:  "whitespace collapses",
+            input: "@misc{k, title = {Split\n    across   lines}}",
+            keys:  []string{"k"},
+            fields: map[string]string{"title": "Split across lines"},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            entries, err := Parse(strings.NewReader(tt.input))
+            if err != nil {
+                t.Fatalf("Parse: %v", err)
+            }
+            if len(entries) != len(tt.keys) {
+                t.Fatalf("got %d entries, want %d", len(entries), len(tt.keys))
+            }
+            for i, key := range tt.keys {
+                if entries[i].Key != key {
+                    t.Errorf("entry %d key = %q, want %q", i, entries[i].Key, key)
+                }
+            }
+            for name, want := range tt.fields {
+                if got := entries[0].Fields[name]; got != want {
+                    t.Errorf("%s = %q, want %q", name, got, want)
+                }
+            }
+        })
+    }
+}
+
+func TestParseErrors(t *testing.T) {
+    tests := []struct {
+        name  string
+        input string
+    }{
+        {"trailing backslash in braces", `@article{k, title={abc\`},
+        {"trailing backslash in quotes", `@article{k, title="abc\`},
+        {"unbalanced braces", `@article{k, title={abc}`},
+        {"unterminated quoted value", `@article{k, title="abc`},
+        {"unterminated entry", `@article{k, title={abc}`},
+        {"missing equals", `@article{k, title {abc}}`},
+        {"missing value", `@article{k, title = }`},
+        {"unterminated string", `@string{x = "y"`},
+        {"unterminated comment block", `@comment{ never closed`},
+        {"missing opening brace", `@article k, title={abc}}`},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            entries, err := Parse(strings.NewReader(tt.input))
+            if err == nil {
+                t.Fatalf("Parse(%q) = %d entries, want error", tt.input, len(entries))
+            }
+            if !strings.HasPrefix(err.Error(), "line ") {
+                t.Errorf("error %q has no line number", err)
+            }
+        })
+    }
+}
+
+func TestParseTruncated(t *testing.T) {
+    // Every prefix of a valid file must either parse or fail cleanly
+    input := `@string{v = "Venue"}
+@article{k, author = {M{\"u}ller, J. and Doe, A.}, title = "A {"}quoted{"} \{title\}", booktitle = v # " 2020"}`
+    for i := 0; i <= len(input); i++ {
+        if _, err := Parse(strings.NewReader(input[:i])); err != nil && !strings.HasPrefix(err.Error(), "line ") {
+            t.Errorf("prefix %d: error %q has no line number", i, err)
+        }
+    }
+}
+
+func TestParseRIS(t *testing.T) {
+    input := "TY  - JOUR\r\n" +
+        "ID  - doe2021\r\n" +
+        "AU  - Doe, Jane\r\n" +
+        "AU  - Roe, Rick\r\n" +
+        "TI  - Fast & Accurate\r\n" +
+        "  Parsing of 100% of files\r\n" +
+        "T2  - Journal of Tests\r\n" +
+        "PY  - 2021/05/01\r\n" +
+        "SP  - 10\r\n" +
+        "EP  - 20\r\n" +
+        "KW  - parsing\r\n" +
+        "KW  - ris\r\n" +
+        "DO  - 10.1000/x_y\r\n" +
+        "ER  - \r\n" +
+        "\r\n" +
+        "TY  - THES\r\n" +
+        "TI  - A Thesis\r\n" +
+        "PB  - Some University\r\n" +
+        "ER  -\r\n"
+
+    entries, err := ParseRIS(strings.NewReader(input))
+    if err != nil {
+        t.Fatalf("ParseRIS: %v", err)
+    }
+    if len(entries) != 2 {
+        t.Fatalf("got %d entries, want 2", len(entries))
+    }
+
+    article := entries[0]
+    checks := map[string]string{
+        "title":    "Fast & Accurate Parsing of 100% of files",
+        "journal":  "Journal of Tests",
+        "year":     "2021",
+        "pages":    "10--20",
+        "keywords": "parsing, ris",
+        "doi":      "10.1000/x_y",
+    }
+    if article.Type != "article" || article.Key != "doe2021" {
+        t.Errorf("got @%s{%s}, want @article{doe2021}", article.Type, article.Key)
+    }
+    for name, want := range checks {
+        if got := article.Field(name); got != want {
+            t.Errorf("%s = %q, want %q", name, got, want)
+        }
+    }
+    if names := article.Names("author"); len(names) != 2 || names[0] != "Doe, Jane" {
+        t.Errorf("authors = %q", names)
+    }
+
+    thesis := entries[1]
+    if thesis.Type != "phdthesis" || thesis.Field("school") != "Some University" || thesis.Fields["publisher"] != "" {
+        t.Errorf("thesis = %+v", thesis)
+    }
+}
+
+func TestParseRISErrors(t *testing.T) {
+    tests := []struct {
+        name  string
+        input string
+    }{
+        {"missing ER", "TY  - JOUR\nTI  - Truncated\n"},
+        {"TY inside a record", "TY  - JOUR\nTY  - BOOK\nER  -\n"},
+        {"tag outside a record", "TI  - Stray\n"},
+        {"not RIS", "@article{k, title={abc}}\n"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if entries, err := ParseRIS(strings.NewReader(tt.input)); err == nil {
+                t.Errorf("ParseRIS = %d entries, want error", len(entries))
+            }
+        })
+    }
+}
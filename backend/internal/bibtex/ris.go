@@ -0,0 +1,169 @@
+package bibtex
+
+import (
+    "bufio"
+    "fmt"
+    "io"
+    "regexp"
+    "strings"
+)
+
+// RIS reference types and the BibTeX entry types they become
+var risTypes = map[string]string{
+    "JOUR": "article", "JFULL": "article", "MGZN": "article", "NEWS": "article", "EJOUR": "article",
+    "CONF": "inproceedings", "CPAPER": "inproceedings",
+    "BOOK": "book", "EBOOK": "book", "EDBOOK": "book",
+    "CHAP": "incollection", "ECHAP": "incollection",
+    "THES": "phdthesis",
+    "RPRT": "techreport",
+    "UNPB": "unpublished",
+}
+
+// RIS tags copied straight into a BibTeX field; the first tag present
+// wins when several map to the same field
+var risFields = [][2]string{
+    {"VL", "volume"}, {"IS", "number"}, {"PB", "publisher"}, {"DO", "doi"}, {"UR", "url"}, {"L2", "url"},
+    {"AB", "abstract"}, {"N2", "abstract"}, {"SN", "issn"}, {"CY", "address"}, {"N1", "note"}, {"LA", "language"},
+}
+
+var risLine = regexp.MustCompile(`^([A-Z][A-Z0-9])  ?-( (.*))?$`)
+
+var yearPattern = regexp.MustCompile(`\d{4}`)
+
+// ParseRIS reads an RIS file and converts each record to the equivalent
+// BibTeX entry. RIS values are plain text, so they are escaped on the way
+// in and Field gives back the original text.
+func ParseRIS(r io.Reader) ([]*Entry, error) {
+    scanner := bufio.NewScanner(r)
+    scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+    var entries []*Entry
+    var current *risRecord
+    var lastTag string
+    n := 0
+    for scanner.Scan() {
+        n++
+        line := strings.TrimRight(strings.TrimPrefix(scanner.Text(), "\ufeff"), "\r ")
+        if strings.TrimSpace(line) == "" {
+            continue
+        }
+        m := risLine.FindStringSubmatch(line)
+        if m == nil {
+            // Continuation of a long value
+            if current != nil && lastTag != "" {
+                current.append(lastTag, line)
+                continue
+            }
+            return nil, fmt.Errorf("line %d: expected a TAG  - value line", n)
+        }
+        tag, value := m[1], strings.TrimSpace(m[3])
+
+        switch {
+        case tag == "TY":
+            if current != nil {
+                return nil, fmt.Errorf("line %d: TY before the previous record's ER", n)
+            }
+            current = &risRecord{kind: strings.ToUpper(value), tags: map[string][]string{}}
+        case current == nil:
+            return nil, fmt.Errorf("line %d: %s outside a record", n, tag)
+        case tag == "ER":
+            entries = append(entries, current.entry())
+            current = nil
+        default:
+            current.tags[tag] = append(current.tags[tag], value)
+        }
+        lastTag = tag
+    }
+    if err := scanner.Err(); err != nil {
+        return nil, err
+    }
+    if current != nil {
+        return nil, fmt.Errorf("record %q is missing ER", current.first("TI", "T1"))
+    }
+    return entries, nil
+}
+
+type risRecord struct {
+    kind string
+    tags map[string][]string
+}
+
+func (r *risRecord) append(tag, text string) {
+    values := r.tags[tag]
+    if len(values) > 0 {
+        values[len(values)-1] += " " + strings.TrimSpace(text)
+    }
+}
+
+func (r *risRecord) first(tags ...string) string {
+    for _, tag := range tags {
+        if values := r.tags[tag]; len(values) > 0 && values[0] != "" {
+            return values[0]
+        }
+    }
+    return ""
+}
+
+func (r *risRecord) all(tags ...string) []string {
+    var values []string
+    for _, tag := range tags {
+        values = append(values, r.tags[tag]...)
+    }
+    return values
+}
+
+func (r *risRecord) entry() *Entry {
+    kind, ok := risTypes[r.kind]
+    if !ok {
+        kind = "misc"
+    }
+    e := NewEntry(kind, r.first("ID"))
+    set := func(field, value string) {
+        if value = strings.TrimSpace(value); value != "" {
+            e.Fields[field] = EscapeField(field, value)
+        }
+    }
+
+    set("title", r.first("TI", "T1", "CT"))
+    if authors := r.all("AU", "A1"); len(authors) > 0 {
+        e.Fields["author"] = JoinNames(authors)
+    }
+    if editors := r.all("ED", "A2"); len(editors) > 0 && kind != "article" {
+        e.Fields["editor"] = JoinNames(editors)
+    }
+
+    container := r.first("T2", "JO", "JF", "JA", "BT", "T3")
+    switch kind {
+    case "article":
+        set("journal", container)
+    case "phdthesis":
+        set("school", r.first("PB"))
+    case "techreport":
+        set("institution", r.first("PB"))
+    default:
+        set("booktitle", container)
+    }
+    if year := yearPattern.FindString(r.first("PY", "Y1", "DA")); year != "" {
+        e.Fields["year"] = year
+    }
+
+    start, end := r.first("SP"), r.first("EP")
+    switch {
+    case start != "" && end != "":
+        e.Fields["pages"] = start + "--" + end
+    case start != "":
+        e.Fields["pages"] = start
+    }
+    if keywords := r.all("KW"); len(keywords) > 0 {
+        set("keywords", strings.Join(keywords, ", "))
+    }
+    for _, pair := range risFields {
+        if _, taken := e.Fields[pair[1]]; !taken {
+            set(pair[1], r.first(pair[0]))
+        }
+    }
+    if kind == "phdthesis" || kind == "techreport" {
+        delete(e.Fields, "publisher")
+    }
+    return e
+}
@@ -0,0 +1,320 @@
+package caldav
+
+import (
+    "encoding/xml"
+    "errors"
+    "fmt"
+    "io"
+    "net/http"
+    "strings"
+    "time"
+)
+
+const (
+    NSDAV         = "DAV:"
+    NSCalDAV      = "urn:ietf:params:xml:ns:caldav"
+    NSCalendarSrv = "http://calendarserver.org/ns/"
+)
+
+// Prefixes used when writing responses. Elements from any other namespace
+// get a local xmlns declaration.
+var prefixes = map[string]string{
+    NSDAV:         "d",
+    NSCalDAV:      "c",
+    NSCalendarSrv: "cs",
+}
+
+func DAV(local string) xml.Name    { return xml.Name{Space: NSDAV, Local: local} }
+func CalDAV(local string) xml.Name { return xml.Name{Space: NSCalDAV, Local: local} }
+func CS(local string) xml.Name     { return xml.Name{Space: NSCalendarSrv, Local: local} }
+
+// Node is a generic XML element. Request bodies are small, so they are
+// decoded into a tree and inspected rather than mapped onto fixed structs.
+type Node struct {
+    XMLName  xml.Name
+    Attrs    []xml.Attr `xml:",any,attr"`
+    Children []Node     `xml:",any"`
+    Text     string     `xml:",chardata"`
+}
+
+// ParseBody decodes a request body. An empty body yields nil.
+func ParseBody(r io.Reader) (*Node, error) {
+    var root Node
+    err := xml.NewDecoder(r).Decode(&root)
+    if errors.Is(err, io.EOF) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("invalid XML body: %w", err)
+    }
+    return &root, nil
+}
+
+func (n *Node) Is(name xml.Name) bool {
+    return n != nil && n.XMLName == name
+}
+
+// Child returns the first child with the given name, or nil.
+func (n *Node) Child(name xml.Name) *Node {
+    if n == nil {
+        return nil
+    }
+    for i := range n.Children {
+        if n.Children[i].XMLName == name {
+            return &n.Children[i]
+        }
+    }
+    return nil
+}
+
+func (n *Node) Attr(local string) string {
+    for _, a := range n.Attrs {
+        if a.Name.Local == local {
+            return a.Value
+        }
+    }
+    return ""
+}
+
+// PropRequest is what a PROPFIND or REPORT asked for.
+type PropRequest struct {
+    AllProp  bool
+    PropName bool
+    Names    []xml.Name
+}
+
+// ParsePropRequest reads the prop/allprop/propname child of a PROPFIND or
+// REPORT body. A missing body or prop element means allprop.
+func ParsePropRequest(root *Node) PropRequest {
+    switch {
+    case root == nil:
+        return PropRequest{AllProp: true}
+    case root.Child(DAV("propname")) != nil:
+        return PropRequest{PropName: true}
+    }
+    prop := root.Child(DAV("prop"))
+    if prop == nil {
+        return PropRequest{AllProp: true}
+    }
+    req := PropRequest{}
+    for _, child := range prop.Children {
+        req.Names = append(req.Names, child.XMLName)
+    }
+    return req
+}
+
+func (r PropRequest) Wants(name xml.Name) bool {
+    if r.AllProp || r.PropName {
+        return false
+    }
+    for _, n := range r.Names {
+        if n == name {
+            return true
+        }
+    }
+    return false
+}
+
+// Prop is a property value ready to be written. Inner is XML using the
+// d:, c: and cs: prefixes.
+type Prop struct {
+    Name  xml.Name
+    Inner string
+}
+
+func TextProp(name xml.Name, value string) Prop {
+    return Prop{Name: name, Inner: escape(value)}
+}
+
+func HrefProp(name xml.Name, href string) Prop {
+    return Prop{Name: name, Inner: "<d:href>" + escape(href) + "</d:href>"}
+}
+
+func RawProp(name xml.Name, inner string) Prop {
+    return Prop{Name: name, Inner: inner}
+}
+
+// Response is one <d:response> of a multistatus. A non-zero Status is
+// written instead of propstats, e.g. 404 for an unknown multiget href.
+type Response struct {
+    Href     string
+    Status   int
+    Props    []Prop
+    NotFound []xml.Name
+}
+
+// NewResponse picks the requested properties out of available. allprop
+// returns everything except calendar-data, which must be asked for.
+func NewResponse(href string, available []Prop, req PropRequest) Response {
+    resp := Response{Href: href}
+    switch {
+    case req.PropName:
+        for _, p := range available {
+            resp.Props = append(resp.Props, Prop{Name: p.Name})
+        }
+    case req.AllProp:
+        for _, p := range available {
+            if p.Name != CalDAV("calendar-data") {
+                resp.Props = append(resp.Props, p)
+            }
+        }
+    default:
+        for _, name := range req.Names {
+            found := false
+            for _, p := range available {
+                if p.Name == name {
+                    resp.Props = append(resp.Props, p)
+                    found = true
+                    break
+                }
+            }
+            if !found {
+                resp.NotFound = append(resp.NotFound, name)
+            }
+        }
+    }
+    return resp
+}
+
+type Multistatus struct {
+    Responses []Response
+}
+
+func (m *Multistatus) Add(resp Response) {
+    m.Responses = append(m.Responses, resp)
+}
+
+func (m *Multistatus) Bytes() []byte {
+    var b strings.Builder
+    b.WriteString(xml.Header)
+    b.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="` + NSCalDAV + `" xmlns:cs="` + NSCalendarSrv + `">`)
+    for _, resp := range m.Responses {
+        b.WriteString("<d:response><d:href>" + escape(resp.Href) + "</d:href>")
+        if resp.Status != 0 {
+            b.WriteString(statusLine(resp.Status))
+        }
+        if len(resp.Props) > 0 {
+            b.WriteString("<d:propstat><d:prop>")
+            for _, p := range resp.Props {
+                writeElement(&b, p.Name, p.Inner)
+            }
+            b.WriteString("</d:prop>" + statusLine(http.StatusOK) + "</d:propstat>")
+        }
+        if len(resp.NotFound) > 0 {
+            b.WriteString("<d:propstat><d:prop>")
+            for _, name := range resp.NotFound {
+                writeElement(&b, name, "")
+            }
+            b.WriteString("</d:prop>" + statusLine(http.StatusNotFound) + "</d:propstat>")
+        }
+        b.WriteString("</d:response>")
+    }
+    b.WriteString("</d:multistatus>")
+    return []byte(b.String())
+}
+
+// Error renders a DAV:error body naming a failed precondition, such as
+// CalDAV("supported-calendar-component").
+func Error(precondition xml.Name) []byte {
+    var b strings.Builder
+    b.WriteString(xml.Header)
+    b.WriteString(`<d:error xmlns:d="DAV:" xmlns:c="` + NSCalDAV + `">`)
+    writeElement(&b, precondition, "")
+    b.WriteString("</d:error>")
+    return []byte(b.String())
+}
+
+func writeElement(b *strings.Builder, name xml.Name, inner string) {
+    tag, decl := name.Local, ""
+    if prefix, ok := prefixes[name.Space]; ok {
+        tag = prefix + ":" + name.Local
+    } else if name.Space != "" {
+        tag = "x:" + name.Local
+        decl = ` xmlns:x="` + escape(name.Space) + `"`
+    }
+    if inner == "" {
+        b.WriteString("<" + tag + decl + "/>")
+        return
+    }
+    b.WriteString("<" + tag + decl + ">" + inner + "</" + tag + ">")
+}
+
+func statusLine(code int) string {
+    return fmt.Sprintf("<d:status>HTTP/1.1 %d %s</d:status>", code, http.StatusText(code))
+}
+
+func escape(s string) string {
+    var b strings.Builder
+    xml.EscapeText(&b, []byte(s))
+    return b.String()
+}
+
+// Filter is the supported part of a calendar-query filter: the component
+// asked for, a time-range on it and prop-filters on COMPLETED and STATUS.
+type Filter struct {
+    Component    string
+    Start        *time.Time
+    End          *time.Time
+    Completed    *bool // false for COMPLETED is-not-defined, true for is-defined
+    Status       string
+    StatusNegate bool
+}
+
+// ParseFilter reads the <c:filter> of a calendar-query report.
+func ParseFilter(query *Node) (Filter, error) {
+    var f Filter
+    comp := query.Child(CalDAV("filter")).Child(CalDAV("comp-filter"))
+    for comp != nil {
+        f.Component = strings.ToUpper(comp.Attr("name"))
+        if tr := comp.Child(CalDAV("time-range")); tr != nil {
+            start, err := parseRangeTime(tr.Attr("start"))
+            if err != nil {
+                return f, err
+            }
+            end, err := parseRangeTime(tr.Attr("end"))
+            if err != nil {
+                return f, err
+            }
+            f.Start, f.End = start, end
+        }
+        for _, child := range comp.Children {
+            if !child.Is(CalDAV("prop-filter")) {
+                continue
+            }
+            switch strings.ToUpper(child.Attr("name")) {
+            case "COMPLETED":
+                defined := child.Child(CalDAV("is-not-defined")) == nil
+                f.Completed = &defined
+            case "STATUS":
+                if match := child.Child(CalDAV("text-match")); match != nil {
+                    f.Status = strings.TrimSpace(match.Text)
+                    f.StatusNegate = match.Attr("negate-condition") == "yes"
+                }
+            }
+        }
+        comp = comp.Child(CalDAV("comp-filter"))
+    }
+    return f, nil
+}
+
+func parseRangeTime(value string) (*time.Time, error) {
+    if value == "" {
+        return nil, nil
+    }
+    t, err := time.Parse("20060102T150405Z", value)
+    if err != nil {
+        return nil, fmt.Errorf("invalid time-range value %q", value)
+    }
+    return &t, nil
+}
+
+// Hrefs lists the <d:href> children of a calendar-multiget report.
+func Hrefs(multiget *Node) []string {
+    var hrefs []string
+    for _, child := range multiget.Children {
+        if child.Is(DAV("href")) {
+            hrefs = append(hrefs, strings.TrimSpace(child.Text))
+        }
+    }
+    return hrefs
+}
@@ -1,8 +1,10 @@
 package database
 
 import (
+    "errors"
     "fmt"
     "log"
+    "strings"
 
     "github.com/anayy09/academiaflow-backend/configs"
     "github.com/anayy09/academiaflow-backend/internal/models"
@@ -26,7 +28,8 @@ func Connect(config *configs.Config) {
 
     var err error
     DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
-        Logger: logger.Default.LogMode(logger.Info),
+        Logger:         logger.Default.LogMode(logger.Info),
+        TranslateError: true, // Surface unique violations as gorm.ErrDuplicatedKey
     })
 
     if err != nil {
@@ -37,19 +40,162 @@ func Connect(config *configs.Config) {
 }
 
 func Migrate() {
+    if err := dedupeWordCountSamples(); err != nil {
+        log.Fatal("Failed to remove duplicate word count samples:", err)
+    }
+    if err := renumberPaperRounds(); err != nil {
+        log.Fatal("Failed to renumber paper rounds:", err)
+    }
+
     err := DB.AutoMigrate(
         &models.User{},
         &models.Course{},
         &models.Assignment{},
+        &models.AssignmentSeries{},
+        &models.AssignmentDependency{},
+        &models.TimeEntry{},
+        &models.Availability{},
+        &models.CalendarFeed{},
+        &models.CourseMeeting{},
+        &models.Term{},
+        &models.GradingScale{},
+        &models.GradeCategory{},
+        &models.DegreeProgram{},
+        &models.Milestone{},
+        &models.Paper{},
+        &models.PaperRound{},
+        &models.Reading{},
+        &models.AdvisorMeeting{},
+        &models.ActionItem{},
+        &models.ReportSchedule{},
+        &models.Appointment{},
+        &models.Duty{},
+        &models.Funding{},
+        &models.FundingDeadline{},
+        &models.Thesis{},
+        &models.ThesisChapter{},
+        &models.WordCountSample{},
+        &models.Goal{},
+        &models.KeyResult{},
+        &models.GoalCheckIn{},
+        &models.Job{},
+        &models.NotificationPreference{},
+        &models.Notification{},
     )
 
     if err != nil {
         log.Fatal("Failed to migrate database:", err)
     }
 
+    if err := migrateCourseTerms(); err != nil {
+        log.Fatal("Failed to link courses to terms:", err)
+    }
+    if err := backfillCompletedAt(); err != nil {
+        log.Fatal("Failed to backfill assignment completion times:", err)
+    }
+
     log.Println("Database migration completed!")
 }
 
+// migrateCourseTerms links courses that only have a semester string to a
+// term, matching names case-insensitively and creating terms with
+// estimated dates as needed. Strings that don't parse as a term are left
+// alone. Safe to run on every start.
+func migrateCourseTerms() error {
+    var courses []models.Course
+    if err := DB.Where("term_id IS NULL AND COALESCE(semester, '') <> ''").Find(&courses).Error; err != nil {
+        return err
+    }
+
+    linked := 0
+    for _, course := range courses {
+        // Term names match case-insensitively, as when linking a course
+        var term models.Term
+        err := DB.Where("user_id = ? AND LOWER(name) = LOWER(?)", course.UserID, strings.TrimSpace(course.Semester)).First(&term).Error
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            estimated, ok := models.NewEstimatedTerm(course.UserID, course.Semester)
+            if !ok {
+                continue
+            }
+            err = DB.Where("user_id = ? AND LOWER(name) = LOWER(?)", course.UserID, estimated.Name).
+                Attrs(*estimated).FirstOrCreate(&term).Error
+        }
+        if err != nil {
+            return err
+        }
+        if err := DB.Model(&models.Course{}).Where("id = ?", course.ID).
+            UpdateColumn("term_id", term.ID).Error; err != nil {
+            return err
+        }
+        linked++
+    }
+
+    if linked > 0 {
+        log.Printf("Linked %d courses to terms", linked)
+    }
+    return nil
+}
+
+// dedupeWordCountSamples keeps only the latest sample per chapter and day,
+// which the unique index on word_count_samples requires. Safe to run on
+// every start.
+func dedupeWordCountSamples() error {
+    if !DB.Migrator().HasTable(&models.WordCountSample{}) {
+        return nil
+    }
+    result := DB.Exec(`DELETE FROM word_count_samples s
+        USING word_count_samples newer
+        WHERE s.chapter_id = newer.chapter_id AND s.day = newer.day
+        AND (s.recorded_at, s.id) < (newer.recorded_at, newer.id)`)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected > 0 {
+        log.Printf("Removed %d duplicate word count samples", result.RowsAffected)
+    }
+    return nil
+}
+
+// renumberPaperRounds numbers the rounds of papers that have repeated
+// round numbers 1, 2, 3... in submission order, deleted rounds included,
+// which the unique index on paper_rounds requires. Other papers keep
+// their numbers. Safe to run on every start.
+func renumberPaperRounds() error {
+    if !DB.Migrator().HasTable(&models.PaperRound{}) {
+        return nil
+    }
+    result := DB.Exec(`UPDATE paper_rounds r SET number = ordered.n
+        FROM (
+            SELECT id, ROW_NUMBER() OVER (PARTITION BY paper_id ORDER BY created_at, id) AS n
+            FROM paper_rounds
+            WHERE paper_id IN (SELECT paper_id FROM paper_rounds GROUP BY paper_id, number HAVING COUNT(*) > 1)
+        ) ordered
+        WHERE r.id = ordered.id AND r.number <> ordered.n`)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected > 0 {
+        log.Printf("Renumbered %d paper rounds", result.RowsAffected)
+    }
+    return nil
+}
+
+// backfillCompletedAt stamps completed assignments from before
+// completed_at existed with their last update, the best record there is
+// of when they were finished. Safe to run on every start.
+func backfillCompletedAt() error {
+    result := DB.Model(&models.Assignment{}).
+        Where("status = ? AND completed_at IS NULL", "completed").
+        UpdateColumn("completed_at", gorm.Expr("updated_at"))
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected > 0 {
+        log.Printf("Backfilled completion times for %d assignments", result.RowsAffected)
+    }
+    return nil
+}
+
 func GetDB() *gorm.DB {
     return DB
 }
\ No newline at end of file
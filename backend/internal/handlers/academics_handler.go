@@ -0,0 +1,128 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+type AcademicsHandler struct {
+    academicsService *services.AcademicsService
+    config           *configs.Config
+}
+
+func NewAcademicsHandler(config *configs.Config) *AcademicsHandler {
+    return &AcademicsHandler{
+        academicsService: services.NewAcademicsService(),
+        config:           config,
+    }
+}
+
+func (h *AcademicsHandler) GetSummary(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    summary, err := h.academicsService.Summary(userID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not compute academic summary"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"summary": summary})
+}
+
+func (h *AcademicsHandler) GetScales(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    scales, err := h.academicsService.GetScales(userID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch grading scales"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"scales": scales})
+}
+
+func (h *AcademicsHandler) CreateScale(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var req services.GradingScaleRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    scale, err := h.academicsService.CreateScale(userID, req)
+    if errors.Is(err, services.ErrInvalidGradingScale) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create grading scale"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Grading scale created successfully",
+        "scale":   scale,
+    })
+}
+
+func (h *AcademicsHandler) UpdateScale(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    scaleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid grading scale ID"})
+        return
+    }
+
+    var req services.GradingScaleRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    scale, err := h.academicsService.UpdateScale(userID, uint(scaleID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Grading scale not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidGradingScale) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update grading scale"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Grading scale updated successfully",
+        "scale":   scale,
+    })
+}
+
+func (h *AcademicsHandler) DeleteScale(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    scaleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid grading scale ID"})
+        return
+    }
+
+    err = h.academicsService.DeleteScale(userID, uint(scaleID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Grading scale not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete grading scale"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Grading scale deleted successfully"})
+}
@@ -0,0 +1,309 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+type AdvisorHandler struct {
+    advisorService *services.AdvisorService
+    config         *configs.Config
+}
+
+func NewAdvisorHandler(config *configs.Config) *AdvisorHandler {
+    return &AdvisorHandler{
+        advisorService: services.NewAdvisorService(),
+        config:         config,
+    }
+}
+
+func (h *AdvisorHandler) GetMeetings(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    meetings, err := h.advisorService.GetMeetings(userID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch advisor meetings"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"meetings": meetings})
+}
+
+func (h *AdvisorHandler) GetMeeting(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    meetingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+        return
+    }
+
+    meeting, err := h.advisorService.GetMeeting(userID, uint(meetingID))
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Advisor meeting not found"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"meeting": meeting})
+}
+
+func (h *AdvisorHandler) CreateMeeting(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var req services.CreateAdvisorMeetingRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    meeting, err := h.advisorService.CreateMeeting(userID, req)
+    if errors.Is(err, services.ErrInvalidAdvisorMeeting) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create advisor meeting"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Advisor meeting created successfully",
+        "meeting": meeting,
+    })
+}
+
+func (h *AdvisorHandler) UpdateMeeting(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    meetingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+        return
+    }
+
+    var req services.UpdateAdvisorMeetingRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    meeting, err := h.advisorService.UpdateMeeting(userID, uint(meetingID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Advisor meeting not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update advisor meeting"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Advisor meeting updated successfully",
+        "meeting": meeting,
+    })
+}
+
+func (h *AdvisorHandler) DeleteMeeting(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    meetingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+        return
+    }
+
+    err = h.advisorService.DeleteMeeting(userID, uint(meetingID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Advisor meeting not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete advisor meeting"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Advisor meeting deleted successfully"})
+}
+
+func (h *AdvisorHandler) CreateActionItem(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    meetingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+        return
+    }
+
+    var req services.ActionItemRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    item, err := h.advisorService.CreateActionItem(userID, uint(meetingID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Advisor meeting not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidAdvisorMeeting) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create action item"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message":     "Action item created successfully",
+        "action_item": item,
+    })
+}
+
+func (h *AdvisorHandler) UpdateActionItem(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    meetingID, itemID, ok := actionItemIDs(c)
+    if !ok {
+        return
+    }
+
+    var req services.UpdateActionItemRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    item, err := h.advisorService.UpdateActionItem(userID, meetingID, itemID, req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Action item not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidAdvisorMeeting) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update action item"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":     "Action item updated successfully",
+        "action_item": item,
+    })
+}
+
+func (h *AdvisorHandler) DeleteActionItem(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    meetingID, itemID, ok := actionItemIDs(c)
+    if !ok {
+        return
+    }
+
+    err := h.advisorService.DeleteActionItem(userID, meetingID, itemID)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Action item not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete action item"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Action item deleted successfully"})
+}
+
+func (h *AdvisorHandler) PromoteActionItem(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    meetingID, itemID, ok := actionItemIDs(c)
+    if !ok {
+        return
+    }
+
+    // The body is optional; an empty one promotes with the defaults
+    var req services.PromoteActionItemRequest
+    if c.Request.ContentLength != 0 {
+        if err := c.ShouldBindJSON(&req); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+    }
+
+    item, err := h.advisorService.PromoteActionItem(userID, meetingID, itemID, req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Action item not found"})
+        return
+    }
+    if errors.Is(err, services.ErrCourseNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+        return
+    }
+    if errors.Is(err, services.ErrAlreadyPromoted) {
+        c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not promote action item"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message":     "Action item promoted to an assignment",
+        "action_item": item,
+    })
+}
+
+// GetOpenItems lists action items still open from past meetings.
+func (h *AdvisorHandler) GetOpenItems(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    now := time.Now()
+
+    items, err := h.advisorService.OpenItems(userID, now, now)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch open action items"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"open_items": items})
+}
+
+// GetReview lists what is still open from the meetings before this one,
+// to go through at its start.
+func (h *AdvisorHandler) GetReview(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    meetingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+        return
+    }
+
+    date, err := h.advisorService.MeetingDate(userID, uint(meetingID))
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Advisor meeting not found"})
+        return
+    }
+    items, err := h.advisorService.OpenItems(userID, date, time.Now())
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch open action items"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"open_items": items})
+}
+
+func actionItemIDs(c *gin.Context) (uint, uint, bool) {
+    meetingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+        return 0, 0, false
+    }
+    itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action item ID"})
+        return 0, 0, false
+    }
+    return uint(meetingID), uint(itemID), true
+}
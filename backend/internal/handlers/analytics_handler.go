@@ -0,0 +1,33 @@
+package handlers
+
+import (
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+)
+
+type AnalyticsHandler struct {
+    analyticsService *services.AnalyticsService
+    config           *configs.Config
+}
+
+func NewAnalyticsHandler(config *configs.Config) *AnalyticsHandler {
+    return &AnalyticsHandler{
+        analyticsService: services.NewAnalyticsService(),
+        config:           config,
+    }
+}
+
+func (h *AnalyticsHandler) GetEstimationReport(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    report, err := h.analyticsService.GetEstimationReport(userID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not compute estimation analytics"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"estimation": report})
+}
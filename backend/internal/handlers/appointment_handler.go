@@ -0,0 +1,281 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+type AppointmentHandler struct {
+    appointmentService *services.AppointmentService
+    config             *configs.Config
+}
+
+func NewAppointmentHandler(config *configs.Config) *AppointmentHandler {
+    return &AppointmentHandler{
+        appointmentService: services.NewAppointmentService(),
+        config:             config,
+    }
+}
+
+func (h *AppointmentHandler) GetAppointments(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    appointments, err := h.appointmentService.GetAppointments(userID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch appointments"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"appointments": appointments})
+}
+
+func (h *AppointmentHandler) GetAppointment(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    appointmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appointment ID"})
+        return
+    }
+
+    appointment, err := h.appointmentService.GetAppointment(userID, uint(appointmentID))
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Appointment not found"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"appointment": appointment})
+}
+
+func (h *AppointmentHandler) CreateAppointment(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var req services.CreateAppointmentRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    appointment, err := h.appointmentService.CreateAppointment(userID, req)
+    if isAppointmentInputError(err) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create appointment"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message":     "Appointment created successfully",
+        "appointment": appointment,
+    })
+}
+
+func (h *AppointmentHandler) UpdateAppointment(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    appointmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appointment ID"})
+        return
+    }
+
+    var req services.UpdateAppointmentRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    appointment, err := h.appointmentService.UpdateAppointment(userID, uint(appointmentID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Appointment not found"})
+        return
+    }
+    if isAppointmentInputError(err) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update appointment"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":     "Appointment updated successfully",
+        "appointment": appointment,
+    })
+}
+
+func (h *AppointmentHandler) DeleteAppointment(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    appointmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appointment ID"})
+        return
+    }
+
+    err = h.appointmentService.DeleteAppointment(userID, uint(appointmentID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Appointment not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete appointment"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Appointment deleted successfully"})
+}
+
+func (h *AppointmentHandler) CreateDuty(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    appointmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appointment ID"})
+        return
+    }
+
+    var req services.DutyRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    duty, err := h.appointmentService.CreateDuty(userID, uint(appointmentID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Appointment not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidDuty) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create duty"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Duty created successfully",
+        "duty":    duty,
+    })
+}
+
+func (h *AppointmentHandler) UpdateDuty(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    appointmentID, dutyID, ok := dutyIDs(c)
+    if !ok {
+        return
+    }
+
+    var req services.UpdateDutyRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    duty, err := h.appointmentService.UpdateDuty(userID, appointmentID, dutyID, req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Duty not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidDuty) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update duty"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Duty updated successfully",
+        "duty":    duty,
+    })
+}
+
+func (h *AppointmentHandler) DeleteDuty(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    appointmentID, dutyID, ok := dutyIDs(c)
+    if !ok {
+        return
+    }
+
+    err := h.appointmentService.DeleteDuty(userID, appointmentID, dutyID)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Duty not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete duty"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Duty deleted successfully"})
+}
+
+// GetHours compares contracted and actual hours per pay period, optionally
+// limited to ?from= and ?to= (YYYY-MM-DD).
+func (h *AppointmentHandler) GetHours(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    appointmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appointment ID"})
+        return
+    }
+
+    var from, to *time.Time
+    for _, param := range []struct {
+        name string
+        dest **time.Time
+    }{{"from", &from}, {"to", &to}} {
+        value := c.Query(param.name)
+        if value == "" {
+            continue
+        }
+        date, err := time.Parse("2006-01-02", value)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": param.name + " must be YYYY-MM-DD"})
+            return
+        }
+        *param.dest = &date
+    }
+
+    hours, err := h.appointmentService.Hours(userID, uint(appointmentID), from, to, time.Now())
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Appointment not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not compute appointment hours"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"hours": hours})
+}
+
+func isAppointmentInputError(err error) bool {
+    return errors.Is(err, services.ErrInvalidAppointment) ||
+        errors.Is(err, services.ErrInvalidDuty) ||
+        errors.Is(err, services.ErrCourseNotFound)
+}
+
+func dutyIDs(c *gin.Context) (uint, uint, bool) {
+    appointmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appointment ID"})
+        return 0, 0, false
+    }
+    dutyID, err := strconv.ParseUint(c.Param("dutyId"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid duty ID"})
+        return 0, 0, false
+    }
+    return uint(appointmentID), uint(dutyID), true
+}
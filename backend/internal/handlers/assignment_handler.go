@@ -1,22 +1,26 @@
 package handlers
 
 import (
+    "errors"
     "net/http"
     "strconv"
 
     "github.com/gin-gonic/gin"
     "github.com/anayy09/academiaflow-backend/configs"
     "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
 )
 
 type AssignmentHandler struct {
     assignmentService *services.AssignmentService
+    recurrenceService *services.RecurrenceService
     config           *configs.Config
 }
 
 func NewAssignmentHandler(config *configs.Config) *AssignmentHandler {
     return &AssignmentHandler{
         assignmentService: services.NewAssignmentService(),
+        recurrenceService: services.NewRecurrenceService(),
         config:           config,
     }
 }
@@ -27,6 +31,12 @@ func (h *AssignmentHandler) GetAssignments(c *gin.Context) {
     // Optional filters
     status := c.Query("status")
     priority := c.Query("priority")
+
+    // Make sure recurring series have occurrences up to the horizon
+    if err := h.recurrenceService.MaterializeUpcoming(userID); err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch assignments"})
+        return
+    }
     
     assignments, err := h.assignmentService.GetUserAssignments(userID, status, priority)
     if err != nil {
@@ -89,7 +99,48 @@ func (h *AssignmentHandler) UpdateAssignment(c *gin.Context) {
         return
     }
 
+    // Recurring occurrences can be edited for this one, this and following, or all
+    scope := c.Query("scope")
+    switch scope {
+    case "", services.ScopeThis:
+    case services.ScopeFollowing, services.ScopeAll:
+        series, err := h.recurrenceService.UpdateSeriesFrom(userID, uint(assignmentID), scope, updates)
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
+            return
+        }
+        if isRecurrenceInputError(err) {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update assignment series"})
+            return
+        }
+
+        c.JSON(http.StatusOK, gin.H{
+            "message": "Assignment series updated successfully",
+            "series":  series,
+        })
+        return
+    default:
+        c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrInvalidScope.Error()})
+        return
+    }
+
     assignment, err := h.assignmentService.UpdateAssignment(userID, uint(assignmentID), updates)
+    var blocked *services.BlockedError
+    if errors.As(err, &blocked) {
+        c.JSON(http.StatusConflict, gin.H{
+            "error":    blocked.Error(),
+            "blockers": blocked.Blockers,
+        })
+        return
+    }
+    if errors.Is(err, services.ErrInvalidAssignment) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update assignment"})
         return
@@ -109,7 +160,23 @@ func (h *AssignmentHandler) DeleteAssignment(c *gin.Context) {
         return
     }
 
-    err = h.assignmentService.DeleteAssignment(userID, uint(assignmentID))
+    switch scope := c.Query("scope"); scope {
+    case "":
+        err = h.assignmentService.DeleteAssignment(userID, uint(assignmentID))
+    case services.ScopeThis, services.ScopeFollowing, services.ScopeAll:
+        err = h.recurrenceService.DeleteOccurrence(userID, uint(assignmentID), scope)
+    default:
+        c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrInvalidScope.Error()})
+        return
+    }
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
+        return
+    }
+    if isRecurrenceInputError(err) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete assignment"})
         return
@@ -137,6 +204,14 @@ func (h *AssignmentHandler) UpdateStatus(c *gin.Context) {
     assignment, err := h.assignmentService.UpdateAssignment(userID, uint(assignmentID), map[string]interface{}{
         "status": req.Status,
     })
+    var blocked *services.BlockedError
+    if errors.As(err, &blocked) {
+        c.JSON(http.StatusConflict, gin.H{
+            "error":    blocked.Error(),
+            "blockers": blocked.Blockers,
+        })
+        return
+    }
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update assignment status"})
         return
@@ -146,4 +221,10 @@ func (h *AssignmentHandler) UpdateStatus(c *gin.Context) {
         "message":    "Assignment status updated successfully",
         "assignment": assignment,
     })
-}
\ No newline at end of file
+}
+
+func isRecurrenceInputError(err error) bool {
+    return errors.Is(err, services.ErrInvalidScope) ||
+        errors.Is(err, services.ErrNotRecurring) ||
+        errors.Is(err, services.ErrInvalidSeries)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "io"
+    "net/http"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+)
+
+// Upper bound on an uploaded backup archive.
+const maxBackupSize = 50 << 20
+
+type BackupHandler struct {
+    backupService *services.BackupService
+    config        *configs.Config
+}
+
+func NewBackupHandler(config *configs.Config) *BackupHandler {
+    return &BackupHandler{
+        backupService: services.NewBackupService(),
+        config:        config,
+    }
+}
+
+func (h *BackupHandler) ExportAccount(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    // Build the archive first so a failure can still be reported as JSON
+    var buf bytes.Buffer
+    if err := h.backupService.Export(userID, &buf); err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not export account"})
+        return
+    }
+
+    filename := fmt.Sprintf("academiaflow-backup-%s-%s.zip", c.GetString("username"), time.Now().UTC().Format("2006-01-02"))
+    c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+    c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// RestoreAccount loads a backup archive, sent as the "file" field of a
+// multipart form or as the raw body, into the current (empty) account.
+func (h *BackupHandler) RestoreAccount(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBackupSize)
+
+    body, _, err := importBody(c)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    defer body.Close()
+
+    data, err := io.ReadAll(body)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Could not read backup archive"})
+        return
+    }
+
+    result, err := h.backupService.Restore(userID, bytes.NewReader(data), int64(len(data)))
+    if err != nil {
+        switch {
+        case errors.Is(err, services.ErrAccountNotEmpty):
+            c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+        case errors.Is(err, services.ErrInvalidBackup):
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        default:
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not restore account"})
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Account restored successfully",
+        "restore": result,
+    })
+}
@@ -0,0 +1,376 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "net/url"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/caldav"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+const (
+    caldavRoot     = "/caldav/"
+    taskCollection = "tasks"
+    // Upper bound on a single uploaded task
+    maxTaskSize = 1 << 20
+)
+
+const (
+    davRoot       = "root"
+    davPrincipal  = "principal"
+    davHome       = "home"
+    davCollection = "collection"
+    davTask       = "task"
+)
+
+const davPrivileges = "<d:privilege><d:read/></d:privilege>" +
+    "<d:privilege><d:write/></d:privilege>" +
+    "<d:privilege><d:write-content/></d:privilege>" +
+    "<d:privilege><d:bind/></d:privilege>" +
+    "<d:privilege><d:unbind/></d:privilege>"
+
+type CalDAVHandler struct {
+    caldavService *services.CalDAVService
+    config        *configs.Config
+}
+
+func NewCalDAVHandler(config *configs.Config) *CalDAVHandler {
+    return &CalDAVHandler{
+        caldavService: services.NewCalDAVService(),
+        config:        config,
+    }
+}
+
+// WellKnown points service discovery (RFC 6764) at the CalDAV root.
+func (h *CalDAVHandler) WellKnown(c *gin.Context) {
+    c.Redirect(http.StatusMovedPermanently, caldavRoot)
+}
+
+// Serve handles every CalDAV request. The tree is small and fixed:
+//
+//    /caldav/                                   service root
+//    /caldav/principals/<username>/             principal
+//    /caldav/calendars/<username>/              calendar home
+//    /caldav/calendars/<username>/tasks/        VTODO collection
+//    /caldav/calendars/<username>/tasks/<name>  one assignment
+func (h *CalDAVHandler) Serve(c *gin.Context) {
+    if c.Request.Method == http.MethodOptions {
+        c.Header("DAV", "1, 3, calendar-access")
+        c.Header("Allow", "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT")
+        c.Status(http.StatusOK)
+        return
+    }
+
+    kind, name, ok := h.resolve(c)
+    if !ok {
+        return
+    }
+
+    switch {
+    case c.Request.Method == "PROPFIND":
+        h.propfind(c, kind, name)
+    case c.Request.Method == "REPORT" && kind == davCollection:
+        h.report(c)
+    case kind != davTask:
+        c.Status(http.StatusMethodNotAllowed)
+    case c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead:
+        h.getTask(c, name)
+    case c.Request.Method == http.MethodPut:
+        h.putTask(c, name)
+    case c.Request.Method == http.MethodDelete:
+        h.deleteTask(c, name)
+    default:
+        c.Status(http.StatusMethodNotAllowed)
+    }
+}
+
+// resolve maps the request path onto a node of the tree, rejecting paths
+// that belong to another user.
+func (h *CalDAVHandler) resolve(c *gin.Context) (kind, name string, ok bool) {
+    var segments []string
+    for _, s := range strings.Split(c.Param("path"), "/") {
+        if s != "" {
+            segments = append(segments, s)
+        }
+    }
+
+    switch {
+    case len(segments) == 0:
+        return davRoot, "", true
+    case len(segments) == 2 && segments[0] == "principals":
+        kind = davPrincipal
+    case len(segments) == 2 && segments[0] == "calendars":
+        kind = davHome
+    case len(segments) == 3 && segments[0] == "calendars" && segments[2] == taskCollection:
+        kind = davCollection
+    case len(segments) == 4 && segments[0] == "calendars" && segments[2] == taskCollection:
+        kind, name = davTask, segments[3]
+    default:
+        c.Status(http.StatusNotFound)
+        return "", "", false
+    }
+
+    if segments[1] != c.GetString("username") {
+        c.Status(http.StatusForbidden)
+        return "", "", false
+    }
+    return kind, name, true
+}
+
+func (h *CalDAVHandler) principalHref(c *gin.Context) string {
+    return caldavRoot + "principals/" + url.PathEscape(c.GetString("username")) + "/"
+}
+
+func (h *CalDAVHandler) homeHref(c *gin.Context) string {
+    return caldavRoot + "calendars/" + url.PathEscape(c.GetString("username")) + "/"
+}
+
+func (h *CalDAVHandler) collectionHref(c *gin.Context) string {
+    return h.homeHref(c) + taskCollection + "/"
+}
+
+func (h *CalDAVHandler) taskHref(c *gin.Context, a *models.Assignment) string {
+    return h.collectionHref(c) + url.PathEscape(services.TaskResourceName(a))
+}
+
+func (h *CalDAVHandler) propfind(c *gin.Context, kind, name string) {
+    body, err := caldav.ParseBody(c.Request.Body)
+    if err != nil {
+        c.String(http.StatusBadRequest, err.Error())
+        return
+    }
+    req := caldav.ParsePropRequest(body)
+    depth := c.GetHeader("Depth")
+    // Our tree is shallow, so infinity is served like depth 1
+    children := depth != "0"
+
+    var ms caldav.Multistatus
+    principal := caldav.HrefProp(caldav.DAV("current-user-principal"), h.principalHref(c))
+
+    switch kind {
+    case davRoot:
+        ms.Add(caldav.NewResponse(caldavRoot, []caldav.Prop{
+            caldav.RawProp(caldav.DAV("resourcetype"), "<d:collection/>"),
+            caldav.TextProp(caldav.DAV("displayname"), "AcademiaFlow"),
+            principal,
+        }, req))
+    case davPrincipal:
+        ms.Add(caldav.NewResponse(h.principalHref(c), []caldav.Prop{
+            caldav.RawProp(caldav.DAV("resourcetype"), "<d:collection/><d:principal/>"),
+            caldav.TextProp(caldav.DAV("displayname"), c.GetString("username")),
+            principal,
+            caldav.HrefProp(caldav.DAV("principal-URL"), h.principalHref(c)),
+            caldav.HrefProp(caldav.CalDAV("calendar-home-set"), h.homeHref(c)),
+            caldav.HrefProp(caldav.CalDAV("calendar-user-address-set"), "mailto:"+c.GetString("user_email")),
+        }, req))
+    case davHome:
+        ms.Add(caldav.NewResponse(h.homeHref(c), []caldav.Prop{
+            caldav.RawProp(caldav.DAV("resourcetype"), "<d:collection/>"),
+            principal,
+        }, req))
+        if children {
+            if !h.addCollection(c, &ms, req) {
+                return
+            }
+        }
+    case davCollection:
+        if !h.addCollection(c, &ms, req) {
+            return
+        }
+        if children {
+            tasks, err := h.caldavService.QueryTasks(c.GetUint("user_id"), caldav.Filter{})
+            if err != nil {
+                c.Status(http.StatusInternalServerError)
+                return
+            }
+            for i := range tasks {
+                ms.Add(caldav.NewResponse(h.taskHref(c, &tasks[i]), h.taskProps(&tasks[i], req), req))
+            }
+        }
+    case davTask:
+        task, err := h.caldavService.GetTask(c.GetUint("user_id"), name)
+        if err != nil {
+            h.taskError(c, err)
+            return
+        }
+        ms.Add(caldav.NewResponse(h.taskHref(c, task), h.taskProps(task, req), req))
+    }
+
+    c.Data(http.StatusMultiStatus, "application/xml; charset=utf-8", ms.Bytes())
+}
+
+func (h *CalDAVHandler) addCollection(c *gin.Context, ms *caldav.Multistatus, req caldav.PropRequest) bool {
+    ctag, err := h.caldavService.CollectionTag(c.GetUint("user_id"))
+    if err != nil {
+        c.Status(http.StatusInternalServerError)
+        return false
+    }
+
+    ms.Add(caldav.NewResponse(h.collectionHref(c), []caldav.Prop{
+        caldav.RawProp(caldav.DAV("resourcetype"), "<d:collection/><c:calendar/>"),
+        caldav.TextProp(caldav.DAV("displayname"), "AcademiaFlow Tasks"),
+        caldav.TextProp(caldav.CalDAV("calendar-description"), "Assignments from AcademiaFlow"),
+        caldav.RawProp(caldav.CalDAV("supported-calendar-component-set"), `<c:comp name="VTODO"/>`),
+        caldav.RawProp(caldav.CalDAV("supported-calendar-data"), `<c:calendar-data content-type="text/calendar" version="2.0"/>`),
+        caldav.RawProp(caldav.DAV("supported-report-set"),
+            "<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>"+
+                "<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>"),
+        caldav.RawProp(caldav.DAV("current-user-privilege-set"), davPrivileges),
+        caldav.HrefProp(caldav.DAV("current-user-principal"), h.principalHref(c)),
+        caldav.HrefProp(caldav.DAV("owner"), h.principalHref(c)),
+        caldav.TextProp(caldav.CS("getctag"), ctag),
+    }, req))
+    return true
+}
+
+// taskProps lists a task's properties. calendar-data is only rendered
+// when it was asked for by name.
+func (h *CalDAVHandler) taskProps(a *models.Assignment, req caldav.PropRequest) []caldav.Prop {
+    props := []caldav.Prop{
+        caldav.TextProp(caldav.DAV("resourcetype"), ""),
+        caldav.TextProp(caldav.DAV("getetag"), services.TaskETag(a)),
+        caldav.TextProp(caldav.DAV("getcontenttype"), "text/calendar; charset=utf-8; component=VTODO"),
+        caldav.TextProp(caldav.DAV("getlastmodified"), a.UpdatedAt.UTC().Format(http.TimeFormat)),
+        caldav.RawProp(caldav.DAV("current-user-privilege-set"), davPrivileges),
+    }
+    if req.Wants(caldav.CalDAV("calendar-data")) {
+        props = append(props, caldav.TextProp(caldav.CalDAV("calendar-data"), string(services.RenderTask(a))))
+    }
+    return props
+}
+
+func (h *CalDAVHandler) report(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    body, err := caldav.ParseBody(c.Request.Body)
+    if err != nil || body == nil {
+        c.String(http.StatusBadRequest, "Invalid REPORT body")
+        return
+    }
+    req := caldav.ParsePropRequest(body)
+
+    var ms caldav.Multistatus
+    switch {
+    case body.Is(caldav.CalDAV("calendar-query")):
+        filter, err := caldav.ParseFilter(body)
+        if err != nil {
+            c.String(http.StatusBadRequest, err.Error())
+            return
+        }
+        tasks, err := h.caldavService.QueryTasks(userID, filter)
+        if err != nil {
+            c.Status(http.StatusInternalServerError)
+            return
+        }
+        for i := range tasks {
+            ms.Add(caldav.NewResponse(h.taskHref(c, &tasks[i]), h.taskProps(&tasks[i], req), req))
+        }
+    case body.Is(caldav.CalDAV("calendar-multiget")):
+        for _, href := range caldav.Hrefs(body) {
+            name, err := h.resourceName(c, href)
+            if err != nil {
+                ms.Add(caldav.Response{Href: href, Status: http.StatusNotFound})
+                continue
+            }
+            task, err := h.caldavService.GetTask(userID, name)
+            if errors.Is(err, gorm.ErrRecordNotFound) {
+                ms.Add(caldav.Response{Href: href, Status: http.StatusNotFound})
+                continue
+            }
+            if err != nil {
+                c.Status(http.StatusInternalServerError)
+                return
+            }
+            ms.Add(caldav.NewResponse(href, h.taskProps(task, req), req))
+        }
+    default:
+        c.Data(http.StatusForbidden, "application/xml; charset=utf-8", caldav.Error(caldav.DAV("supported-report")))
+        return
+    }
+
+    c.Data(http.StatusMultiStatus, "application/xml; charset=utf-8", ms.Bytes())
+}
+
+// resourceName extracts the task name from a multiget href, which may be
+// an absolute URL.
+func (h *CalDAVHandler) resourceName(c *gin.Context, href string) (string, error) {
+    u, err := url.Parse(href)
+    if err != nil {
+        return "", err
+    }
+    name, found := strings.CutPrefix(u.Path, h.collectionHref(c))
+    if !found || name == "" || strings.Contains(name, "/") {
+        return "", errors.New("href outside of the task collection")
+    }
+    return url.PathUnescape(name)
+}
+
+func (h *CalDAVHandler) getTask(c *gin.Context, name string) {
+    task, err := h.caldavService.GetTask(c.GetUint("user_id"), name)
+    if err != nil {
+        h.taskError(c, err)
+        return
+    }
+
+    etag := services.TaskETag(task)
+    c.Header("ETag", etag)
+    c.Header("Last-Modified", task.UpdatedAt.UTC().Format(http.TimeFormat))
+    if c.GetHeader("If-None-Match") == etag {
+        c.Status(http.StatusNotModified)
+        return
+    }
+    c.Data(http.StatusOK, "text/calendar; charset=utf-8", services.RenderTask(task))
+}
+
+func (h *CalDAVHandler) putTask(c *gin.Context, name string) {
+    body := http.MaxBytesReader(c.Writer, c.Request.Body, maxTaskSize)
+
+    task, created, err := h.caldavService.PutTask(c.GetUint("user_id"), name, body,
+        c.GetHeader("If-Match"), c.GetHeader("If-None-Match"))
+    if err != nil {
+        h.taskError(c, err)
+        return
+    }
+
+    c.Header("ETag", services.TaskETag(task))
+    if created {
+        c.Status(http.StatusCreated)
+        return
+    }
+    c.Status(http.StatusNoContent)
+}
+
+func (h *CalDAVHandler) deleteTask(c *gin.Context, name string) {
+    err := h.caldavService.DeleteTask(c.GetUint("user_id"), name, c.GetHeader("If-Match"))
+    if err != nil {
+        h.taskError(c, err)
+        return
+    }
+    c.Status(http.StatusNoContent)
+}
+
+func (h *CalDAVHandler) taskError(c *gin.Context, err error) {
+    var blocked *services.BlockedError
+    switch {
+    case errors.Is(err, gorm.ErrRecordNotFound):
+        c.Status(http.StatusNotFound)
+    case errors.Is(err, services.ErrPreconditionFailed):
+        c.Status(http.StatusPreconditionFailed)
+    case errors.Is(err, services.ErrUnsupportedComponent):
+        c.Data(http.StatusForbidden, "application/xml; charset=utf-8", caldav.Error(caldav.CalDAV("supported-calendar-component")))
+    case errors.Is(err, services.ErrUIDConflict):
+        c.Data(http.StatusForbidden, "application/xml; charset=utf-8", caldav.Error(caldav.CalDAV("no-uid-conflict")))
+    case errors.Is(err, services.ErrInvalidTask):
+        c.Data(http.StatusForbidden, "application/xml; charset=utf-8", caldav.Error(caldav.CalDAV("valid-calendar-data")))
+    case errors.As(err, &blocked):
+        c.String(http.StatusConflict, blocked.Error())
+    default:
+        c.Status(http.StatusInternalServerError)
+    }
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+type CalendarHandler struct {
+    calendarService *services.CalendarService
+    config          *configs.Config
+}
+
+func NewCalendarHandler(config *configs.Config) *CalendarHandler {
+    return &CalendarHandler{
+        calendarService: services.NewCalendarService(),
+        config:          config,
+    }
+}
+
+func (h *CalendarHandler) feedURL(token string) string {
+    return strings.TrimRight(h.config.Server.PublicURL, "/") + "/api/v1/calendar/feeds/" + token + ".ics"
+}
+
+func (h *CalendarHandler) GetFeedInfo(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    feed, err := h.calendarService.GetFeed(userID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch calendar feed"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "feed": feed,
+        "url":  h.feedURL(feed.Token),
+    })
+}
+
+func (h *CalendarHandler) RotateFeedToken(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    feed, err := h.calendarService.RotateFeedToken(userID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not rotate calendar feed token"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Calendar feed token rotated; the previous URL no longer works",
+        "feed":    feed,
+        "url":     h.feedURL(feed.Token),
+    })
+}
+
+// GetFeed serves the .ics document. It is public: the token in the URL is
+// the credential. Filters come from the query string, e.g.
+// ?course_id=3&status=pending,in_progress&tag=exam&type=both&alarm=60,1440.
+// Class meetings are included unless ?meetings=false.
+func (h *CalendarHandler) GetFeed(c *gin.Context) {
+    token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+    var filter services.FeedFilter
+    for _, value := range splitQuery(c.Query("course_id")) {
+        id, err := strconv.ParseUint(value, 10, 32)
+        if err != nil {
+            c.String(http.StatusBadRequest, "invalid course_id")
+            return
+        }
+        filter.CourseIDs = append(filter.CourseIDs, uint(id))
+    }
+    filter.Statuses = splitQuery(c.Query("status"))
+    filter.Priorities = splitQuery(c.Query("priority"))
+    filter.Tags = splitQuery(c.Query("tag"))
+    filter.SkipMeetings = c.Query("meetings") == "false"
+
+    filter.Components = c.DefaultQuery("type", services.FeedEvents)
+    if filter.Components != services.FeedEvents && filter.Components != services.FeedTodos && filter.Components != services.FeedBoth {
+        c.String(http.StatusBadRequest, "type must be event, todo or both")
+        return
+    }
+
+    for _, value := range splitQuery(c.Query("alarm")) {
+        minutes, err := strconv.Atoi(value)
+        if err != nil || minutes < 0 {
+            c.String(http.StatusBadRequest, "invalid alarm")
+            return
+        }
+        filter.AlarmMinutes = append(filter.AlarmMinutes, minutes)
+    }
+
+    body, err := h.calendarService.RenderFeed(token, filter)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.String(http.StatusNotFound, "calendar feed not found")
+        return
+    }
+    if err != nil {
+        c.String(http.StatusInternalServerError, "could not render calendar feed")
+        return
+    }
+
+    c.Header("Cache-Control", "private, max-age=300")
+    c.Data(http.StatusOK, "text/calendar; charset=utf-8", body)
+}
+
+// splitQuery splits a comma-separated query value, dropping empty parts.
+func splitQuery(value string) []string {
+    var result []string
+    for _, part := range strings.Split(value, ",") {
+        if part = strings.TrimSpace(part); part != "" {
+            result = append(result, part)
+        }
+    }
+    return result
+}
@@ -1,12 +1,14 @@
 package handlers
 
 import (
+    "errors"
     "net/http"
     "strconv"
 
     "github.com/gin-gonic/gin"
     "github.com/anayy09/academiaflow-backend/configs"
     "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
 )
 
 type CourseHandler struct {
@@ -43,6 +45,18 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
     }
 
     course, err := h.courseService.CreateCourse(userID, req)
+    if errors.Is(err, services.ErrTermRequired) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if errors.Is(err, services.ErrTermNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Term not found"})
+        return
+    }
+    if errors.Is(err, services.ErrGradingScaleNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Grading scale not found"})
+        return
+    }
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create course"})
         return
@@ -86,6 +100,22 @@ func (h *CourseHandler) UpdateCourse(c *gin.Context) {
     }
 
     course, err := h.courseService.UpdateCourse(userID, uint(courseID), updates)
+    if errors.Is(err, services.ErrTermRequired) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if errors.Is(err, services.ErrTermNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Term not found"})
+        return
+    }
+    if errors.Is(err, services.ErrGradingScaleNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Grading scale not found"})
+        return
+    }
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+        return
+    }
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update course"})
         return
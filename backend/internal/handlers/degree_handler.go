@@ -0,0 +1,191 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+type DegreeHandler struct {
+    degreeService *services.DegreeService
+    config        *configs.Config
+}
+
+func NewDegreeHandler(config *configs.Config) *DegreeHandler {
+    return &DegreeHandler{
+        degreeService: services.NewDegreeService(),
+        config:        config,
+    }
+}
+
+func (h *DegreeHandler) GetPrograms(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    programs, err := h.degreeService.GetPrograms(userID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch degree programs"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"programs": programs})
+}
+
+func (h *DegreeHandler) GetProgram(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    programID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid program ID"})
+        return
+    }
+
+    program, err := h.degreeService.GetProgram(userID, uint(programID))
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Degree program not found"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"program": program})
+}
+
+func (h *DegreeHandler) CreateProgram(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var req services.DegreeProgramRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    program, err := h.degreeService.CreateProgram(userID, req)
+    if errors.Is(err, services.ErrInvalidDegreeProgram) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create degree program"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Degree program created successfully",
+        "program": program,
+    })
+}
+
+func (h *DegreeHandler) UpdateProgram(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    programID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid program ID"})
+        return
+    }
+
+    var req services.DegreeProgramRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    program, err := h.degreeService.UpdateProgram(userID, uint(programID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Degree program not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidDegreeProgram) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update degree program"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Degree program updated successfully",
+        "program": program,
+    })
+}
+
+func (h *DegreeHandler) DeleteProgram(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    programID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid program ID"})
+        return
+    }
+
+    err = h.degreeService.DeleteProgram(userID, uint(programID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Degree program not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete degree program"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Degree program deleted successfully"})
+}
+
+func (h *DegreeHandler) AttachProgram(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var req services.AttachProgramRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    user, err := h.degreeService.AttachProgram(userID, req.ProgramID)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Degree program not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not attach degree program"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Degree program updated successfully",
+        "user":    services.ToUserResponse(user),
+    })
+}
+
+// GetAudit audits against the attached program, or ?program_id= to try
+// another one.
+func (h *DegreeHandler) GetAudit(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var programID *uint
+    if raw := c.Query("program_id"); raw != "" {
+        id, err := strconv.ParseUint(raw, 10, 32)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid program ID"})
+            return
+        }
+        value := uint(id)
+        programID = &value
+    }
+
+    audit, err := h.degreeService.Audit(userID, programID)
+    if errors.Is(err, services.ErrNoDegreeProgram) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Degree program not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not run degree audit"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"audit": audit})
+}
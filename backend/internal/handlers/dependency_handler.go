@@ -0,0 +1,131 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+type DependencyHandler struct {
+    dependencyService *services.DependencyService
+    config            *configs.Config
+}
+
+func NewDependencyHandler(config *configs.Config) *DependencyHandler {
+    return &DependencyHandler{
+        dependencyService: services.NewDependencyService(),
+        config:            config,
+    }
+}
+
+func (h *DependencyHandler) GetDependencies(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+        return
+    }
+
+    blockers, dependents, err := h.dependencyService.GetDependencies(userID, uint(assignmentID))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch dependencies"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "blocked_by": blockers,
+        "blocking":   dependents,
+    })
+}
+
+func (h *DependencyHandler) AddDependency(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+        return
+    }
+
+    var req services.AddDependencyRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    dependency, err := h.dependencyService.AddDependency(userID, uint(assignmentID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidDependency) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if errors.Is(err, services.ErrDependencyExists) || errors.Is(err, services.ErrDependencyCycle) {
+        c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not add dependency"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message":    "Dependency added successfully",
+        "dependency": dependency,
+    })
+}
+
+func (h *DependencyHandler) RemoveDependency(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+        return
+    }
+    blockedByID, err := strconv.ParseUint(c.Param("blockerId"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blocker ID"})
+        return
+    }
+
+    err = h.dependencyService.RemoveDependency(userID, uint(assignmentID), uint(blockedByID))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not remove dependency"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Dependency removed successfully"})
+}
+
+func (h *DependencyHandler) GetCriticalPath(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    hoursPerDay := 8.0
+    if value := c.Query("hours_per_day"); value != "" {
+        parsed, err := strconv.ParseFloat(value, 64)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid hours_per_day"})
+            return
+        }
+        hoursPerDay = parsed
+    }
+
+    report, err := h.dependencyService.CriticalPath(userID, hoursPerDay, time.Now())
+    if errors.Is(err, services.ErrInvalidHoursPerDay) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not compute critical path"})
+        return
+    }
+
+    c.JSON(http.StatusOK, report)
+}
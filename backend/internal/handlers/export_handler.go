@@ -0,0 +1,73 @@
+package handlers
+
+import (
+    "encoding/csv"
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+)
+
+type ExportHandler struct {
+    exportService *services.ExportService
+    config        *configs.Config
+}
+
+func NewExportHandler(config *configs.Config) *ExportHandler {
+    return &ExportHandler{
+        exportService: services.NewExportService(),
+        config:        config,
+    }
+}
+
+// Export downloads courses and assignments as JSON (both by default) or
+// as CSV, which needs type=courses or type=assignments.
+func (h *ExportHandler) Export(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    format := c.DefaultQuery("format", "json")
+    entity := c.Query("type")
+
+    if entity != "" && entity != services.EntityCourses && entity != services.EntityAssignments {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "type must be courses or assignments"})
+        return
+    }
+    if format != "json" && format != "csv" {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+        return
+    }
+    if format == "csv" && entity == "" {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "type is required for CSV exports"})
+        return
+    }
+
+    data, err := h.exportService.Export(userID, entity)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not export data"})
+        return
+    }
+
+    if format == "json" {
+        c.Header("Content-Disposition", `attachment; filename="academiaflow-export.json"`)
+        c.JSON(http.StatusOK, data)
+        return
+    }
+
+    c.Header("Content-Type", "text/csv; charset=utf-8")
+    c.Header("Content-Disposition", `attachment; filename="academiaflow-`+entity+`.csv"`)
+    c.Status(http.StatusOK)
+
+    w := csv.NewWriter(c.Writer)
+    if entity == services.EntityCourses {
+        w.Write(services.CourseColumns)
+        for _, record := range data.Courses {
+            w.Write(record.CSVRow())
+        }
+    } else {
+        w.Write(services.AssignmentColumns)
+        for _, record := range data.Assignments {
+            w.Write(record.CSVRow())
+        }
+    }
+    w.Flush()
+}
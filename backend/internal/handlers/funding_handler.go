@@ -0,0 +1,300 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+type FundingHandler struct {
+    fundingService *services.FundingService
+    config         *configs.Config
+}
+
+func NewFundingHandler(config *configs.Config) *FundingHandler {
+    return &FundingHandler{
+        fundingService: services.NewFundingService(),
+        config:         config,
+    }
+}
+
+func (h *FundingHandler) GetFundings(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    fundings, err := h.fundingService.GetFundings(userID, c.Query("status"))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch funding"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"funding": fundings})
+}
+
+func (h *FundingHandler) GetFunding(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    fundingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid funding ID"})
+        return
+    }
+
+    funding, err := h.fundingService.GetFunding(userID, uint(fundingID))
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Funding not found"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"funding": funding})
+}
+
+func (h *FundingHandler) CreateFunding(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var req services.CreateFundingRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    funding, err := h.fundingService.CreateFunding(userID, req, time.Now())
+    if isFundingInputError(err) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create funding"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Funding created successfully",
+        "funding": funding,
+    })
+}
+
+func (h *FundingHandler) UpdateFunding(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    fundingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid funding ID"})
+        return
+    }
+
+    var req services.UpdateFundingRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    funding, err := h.fundingService.UpdateFunding(userID, uint(fundingID), req, time.Now())
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Funding not found"})
+        return
+    }
+    if isFundingInputError(err) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update funding"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Funding updated successfully",
+        "funding": funding,
+    })
+}
+
+func (h *FundingHandler) DeleteFunding(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    fundingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid funding ID"})
+        return
+    }
+
+    err = h.fundingService.DeleteFunding(userID, uint(fundingID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Funding not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete funding"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Funding deleted successfully"})
+}
+
+// GetAllEligibility checks the conditions of every funding source that is
+// held or being applied for.
+func (h *FundingHandler) GetAllEligibility(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    eligibility, err := h.fundingService.AllEligibility(userID, time.Now())
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check funding eligibility"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"eligibility": eligibility})
+}
+
+func (h *FundingHandler) GetEligibility(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    fundingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid funding ID"})
+        return
+    }
+
+    eligibility, err := h.fundingService.Eligibility(userID, uint(fundingID), time.Now())
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Funding not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check funding eligibility"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"eligibility": eligibility})
+}
+
+func (h *FundingHandler) CreateDeadline(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    fundingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid funding ID"})
+        return
+    }
+
+    var req services.FundingDeadlineRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    deadline, err := h.fundingService.CreateDeadline(userID, uint(fundingID), req, time.Now())
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Funding not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidFunding) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create deadline"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message":  "Deadline created successfully",
+        "deadline": deadline,
+    })
+}
+
+func (h *FundingHandler) UpdateDeadline(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    fundingID, deadlineID, ok := fundingDeadlineIDs(c)
+    if !ok {
+        return
+    }
+
+    var req services.UpdateFundingDeadlineRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    deadline, err := h.fundingService.UpdateDeadline(userID, fundingID, deadlineID, req, time.Now())
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Deadline not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidFunding) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update deadline"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":  "Deadline updated successfully",
+        "deadline": deadline,
+    })
+}
+
+func (h *FundingHandler) DeleteDeadline(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    fundingID, deadlineID, ok := fundingDeadlineIDs(c)
+    if !ok {
+        return
+    }
+
+    err := h.fundingService.DeleteDeadline(userID, fundingID, deadlineID)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Deadline not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete deadline"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Deadline deleted successfully"})
+}
+
+// CompleteDeadline marks a deadline as met, scheduling the next one for
+// renewals and other repeating deadlines.
+func (h *FundingHandler) CompleteDeadline(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    fundingID, deadlineID, ok := fundingDeadlineIDs(c)
+    if !ok {
+        return
+    }
+
+    deadline, next, err := h.fundingService.CompleteDeadline(userID, fundingID, deadlineID, time.Now())
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Deadline not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not complete deadline"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":  "Deadline completed successfully",
+        "deadline": deadline,
+        "next":     next,
+    })
+}
+
+func isFundingInputError(err error) bool {
+    return errors.Is(err, services.ErrInvalidFunding) || errors.Is(err, services.ErrGradingScaleNotFound)
+}
+
+func fundingDeadlineIDs(c *gin.Context) (uint, uint, bool) {
+    fundingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid funding ID"})
+        return 0, 0, false
+    }
+    deadlineID, err := strconv.ParseUint(c.Param("deadlineId"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid deadline ID"})
+        return 0, 0, false
+    }
+    return uint(fundingID), uint(deadlineID), true
+}
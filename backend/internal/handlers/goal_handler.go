@@ -0,0 +1,325 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+type GoalHandler struct {
+    goalService *services.GoalService
+    config      *configs.Config
+}
+
+func NewGoalHandler(config *configs.Config) *GoalHandler {
+    return &GoalHandler{
+        goalService: services.NewGoalService(),
+        config:      config,
+    }
+}
+
+func (h *GoalHandler) GetGoals(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    goals, err := h.goalService.GetGoals(userID, c.Query("status"), c.Query("period"), time.Now())
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch goals"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"goals": goals})
+}
+
+func (h *GoalHandler) GetGoal(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    goalID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+        return
+    }
+
+    goal, err := h.goalService.GetGoal(userID, uint(goalID), time.Now())
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"goal": goal})
+}
+
+func (h *GoalHandler) CreateGoal(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var req services.CreateGoalRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    goal, err := h.goalService.CreateGoal(userID, req, time.Now())
+    if isGoalInputError(err) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create goal"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Goal created successfully",
+        "goal":    goal,
+    })
+}
+
+func (h *GoalHandler) UpdateGoal(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    goalID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+        return
+    }
+
+    var req services.UpdateGoalRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    goal, err := h.goalService.UpdateGoal(userID, uint(goalID), req, time.Now())
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidGoal) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update goal"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Goal updated successfully",
+        "goal":    goal,
+    })
+}
+
+func (h *GoalHandler) DeleteGoal(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    goalID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+        return
+    }
+
+    err = h.goalService.DeleteGoal(userID, uint(goalID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete goal"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Goal deleted successfully"})
+}
+
+func (h *GoalHandler) CreateKeyResult(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    goalID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+        return
+    }
+
+    var req services.KeyResultRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    keyResult, err := h.goalService.CreateKeyResult(userID, uint(goalID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+        return
+    }
+    if isGoalInputError(err) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create key result"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message":    "Key result created successfully",
+        "key_result": keyResult,
+    })
+}
+
+func (h *GoalHandler) UpdateKeyResult(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    goalID, keyResultID, ok := goalChildIDs(c, "keyResultId", "Invalid key result ID")
+    if !ok {
+        return
+    }
+
+    var req services.UpdateKeyResultRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    keyResult, err := h.goalService.UpdateKeyResult(userID, goalID, keyResultID, req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Key result not found"})
+        return
+    }
+    if isGoalInputError(err) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update key result"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":    "Key result updated successfully",
+        "key_result": keyResult,
+    })
+}
+
+func (h *GoalHandler) DeleteKeyResult(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    goalID, keyResultID, ok := goalChildIDs(c, "keyResultId", "Invalid key result ID")
+    if !ok {
+        return
+    }
+
+    err := h.goalService.DeleteKeyResult(userID, goalID, keyResultID)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Key result not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete key result"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Key result deleted successfully"})
+}
+
+func (h *GoalHandler) CreateCheckIn(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    goalID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+        return
+    }
+
+    var req services.CheckInRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    checkIn, err := h.goalService.CheckIn(userID, uint(goalID), req, time.Now())
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not record check-in"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message":  "Check-in recorded successfully",
+        "check_in": checkIn,
+    })
+}
+
+func (h *GoalHandler) DeleteCheckIn(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    goalID, checkInID, ok := goalChildIDs(c, "checkInId", "Invalid check-in ID")
+    if !ok {
+        return
+    }
+
+    err := h.goalService.DeleteCheckIn(userID, goalID, checkInID)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Check-in not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete check-in"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Check-in deleted successfully"})
+}
+
+// GetReview sums up hits and misses for ?period=week (with ?week=YYYY-Www
+// or a date, default this week) or ?period=term (with ?term_id=, default
+// the current term).
+func (h *GoalHandler) GetReview(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var termID *uint
+    if value := c.Query("term_id"); value != "" {
+        id, err := strconv.ParseUint(value, 10, 32)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term ID"})
+            return
+        }
+        term := uint(id)
+        termID = &term
+    }
+
+    review, err := h.goalService.Review(userID, c.DefaultQuery("period", services.GoalPeriodWeek), c.Query("week"), termID, time.Now())
+    if errors.Is(err, services.ErrTermNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Term not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidGoal) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not build goal review"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"review": review})
+}
+
+func isGoalInputError(err error) bool {
+    return errors.Is(err, services.ErrInvalidGoal) ||
+        errors.Is(err, services.ErrTermNotFound) ||
+        errors.Is(err, services.ErrAssignmentNotFound)
+}
+
+func goalChildIDs(c *gin.Context, param, invalid string) (uint, uint, bool) {
+    goalID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+        return 0, 0, false
+    }
+    childID, err := strconv.ParseUint(c.Param(param), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": invalid})
+        return 0, 0, false
+    }
+    return uint(goalID), uint(childID), true
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+type GradebookHandler struct {
+    gradebookService *services.GradebookService
+    config           *configs.Config
+}
+
+func NewGradebookHandler(config *configs.Config) *GradebookHandler {
+    return &GradebookHandler{
+        gradebookService: services.NewGradebookService(),
+        config:           config,
+    }
+}
+
+func (h *GradebookHandler) GetCategories(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+        return
+    }
+
+    categories, err := h.gradebookService.GetCategories(userID, uint(courseID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch grade categories"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"categories": categories})
+}
+
+func (h *GradebookHandler) CreateCategory(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+        return
+    }
+
+    var req services.CategoryRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    category, err := h.gradebookService.CreateCategory(userID, uint(courseID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+        return
+    }
+    if errors.Is(err, services.ErrWeightExceeded) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create grade category"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message":  "Grade category created successfully",
+        "category": category,
+    })
+}
+
+func (h *GradebookHandler) UpdateCategory(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+        return
+    }
+    categoryID, err := strconv.ParseUint(c.Param("categoryId"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+        return
+    }
+
+    var req services.CategoryRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    category, err := h.gradebookService.UpdateCategory(userID, uint(courseID), uint(categoryID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Grade category not found"})
+        return
+    }
+    if errors.Is(err, services.ErrWeightExceeded) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update grade category"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":  "Grade category updated successfully",
+        "category": category,
+    })
+}
+
+func (h *GradebookHandler) DeleteCategory(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+        return
+    }
+    categoryID, err := strconv.ParseUint(c.Param("categoryId"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+        return
+    }
+
+    err = h.gradebookService.DeleteCategory(userID, uint(courseID), uint(categoryID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Grade category not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete grade category"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Grade category deleted successfully"})
+}
+
+func (h *GradebookHandler) GradeAssignment(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+        return
+    }
+
+    var req services.GradeAssignmentRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    assignment, err := h.gradebookService.GradeAssignment(userID, uint(assignmentID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidGrade) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not grade assignment"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":    "Assignment graded successfully",
+        "assignment": assignment,
+    })
+}
+
+func (h *GradebookHandler) GetCourseGrade(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+        return
+    }
+
+    grade, err := h.gradebookService.GetCourseGrade(userID, uint(courseID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not compute course grade"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"grade": grade})
+}
+
+func (h *GradebookHandler) WhatIf(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+        return
+    }
+
+    var req services.WhatIfRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    result, err := h.gradebookService.WhatIf(userID, uint(courseID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidWhatIf) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not compute projection"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"what_if": result})
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+    "encoding/json"
+    "errors"
+    "io"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+)
+
+// Upper bound on uploaded import files.
+const maxImportSize = 5 << 20
+
+type ImportHandler struct {
+    importService *services.ImportService
+    config        *configs.Config
+}
+
+func NewImportHandler(config *configs.Config) *ImportHandler {
+    return &ImportHandler{
+        importService: services.NewImportService(),
+        config:        config,
+    }
+}
+
+// ImportICS accepts an .ics file either as the "file" field of a multipart
+// form or as a raw text/calendar body. course_id and dry_run are query
+// parameters, or form fields of a multipart upload.
+func (h *ImportHandler) ImportICS(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+    var opts services.ICSImportOptions
+    if value := importOption(c, "course_id"); value != "" {
+        id, err := strconv.ParseUint(value, 10, 32)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+            return
+        }
+        courseID := uint(id)
+        opts.CourseID = &courseID
+    }
+    opts.DryRun, _ = strconv.ParseBool(importOption(c, "dry_run"))
+
+    body, _, err := importBody(c)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    defer body.Close()
+
+    result, err := h.importService.ImportICS(userID, body, opts)
+    if err != nil {
+        var blocked *services.BlockedError
+        var tooLarge *http.MaxBytesError
+        switch {
+        case errors.Is(err, services.ErrCourseNotFound):
+            c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+        case errors.As(err, &tooLarge):
+            c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File is too large"})
+        case errors.Is(err, services.ErrInvalidCalendar):
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        case errors.As(err, &blocked):
+            c.JSON(http.StatusConflict, gin.H{
+                "error":    blocked.Error(),
+                "blockers": blocked.Blockers,
+            })
+        default:
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not import calendar"})
+        }
+        return
+    }
+
+    message := "Calendar imported successfully"
+    if result.DryRun {
+        message = "Dry run: nothing was saved"
+    }
+    c.JSON(http.StatusOK, gin.H{
+        "message": message,
+        "import":  result,
+    })
+}
+
+// ImportRecords bulk-imports courses and assignments from CSV or JSON.
+// Options (query parameters, or form fields of a multipart upload):
+//   format    csv or json; guessed from the file name or content type
+//   type      courses or assignments; required for CSV
+//   mode      atomic (default) or best_effort
+//   mapping   JSON object of field name to source column, e.g. {"title":"Task"}
+//   time_zone IANA zone for due dates without an offset
+func (h *ImportHandler) ImportRecords(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+    opts := services.BulkImportOptions{
+        Format: strings.ToLower(importOption(c, "format")),
+        Entity: importOption(c, "type"),
+        Mode:   importOption(c, "mode"),
+    }
+    if value := importOption(c, "mapping"); value != "" {
+        if err := json.Unmarshal([]byte(value), &opts.Mapping); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "mapping must be a JSON object of field names to column names"})
+            return
+        }
+    }
+    if value := importOption(c, "time_zone"); value != "" {
+        loc, err := time.LoadLocation(value)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time zone"})
+            return
+        }
+        opts.Location = loc
+    }
+
+    body, filename, err := importBody(c)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    defer body.Close()
+
+    if opts.Format == "" {
+        switch {
+        case strings.HasSuffix(strings.ToLower(filename), ".csv"), strings.Contains(c.ContentType(), "csv"):
+            opts.Format = "csv"
+        case strings.HasSuffix(strings.ToLower(filename), ".json"), strings.Contains(c.ContentType(), "json"):
+            opts.Format = "json"
+        }
+    }
+
+    result, err := h.importService.ImportRecords(userID, body, opts)
+    if err != nil {
+        var blocked *services.BlockedError
+        var tooLarge *http.MaxBytesError
+        switch {
+        case errors.As(err, &tooLarge):
+            c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File is too large"})
+        case errors.Is(err, services.ErrInvalidImport):
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        case errors.As(err, &blocked):
+            c.JSON(http.StatusConflict, gin.H{
+                "error":    blocked.Error(),
+                "blockers": blocked.Blockers,
+            })
+        default:
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not import records"})
+        }
+        return
+    }
+
+    if !result.Committed {
+        c.JSON(http.StatusUnprocessableEntity, gin.H{
+            "error":  "Import failed validation; nothing was saved",
+            "import": result,
+        })
+        return
+    }
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Import completed successfully",
+        "import":  result,
+    })
+}
+
+// importOption reads an import option from the query string, or from the
+// form of a multipart upload. Any other body is the file itself and is
+// never parsed as a form, whatever its content type.
+func importOption(c *gin.Context, name string) string {
+    if strings.HasPrefix(c.ContentType(), "multipart/") {
+        return c.DefaultPostForm(name, c.Query(name))
+    }
+    return c.Query(name)
+}
+
+// importBody returns the "file" field of a multipart form, or the raw
+// request body for other content types.
+func importBody(c *gin.Context) (io.ReadCloser, string, error) {
+    if !strings.HasPrefix(c.ContentType(), "multipart/") {
+        return c.Request.Body, "", nil
+    }
+    file, err := c.FormFile("file")
+    if err != nil {
+        return nil, "", errors.New("a file is required in the file field")
+    }
+    f, err := file.Open()
+    if err != nil {
+        return nil, "", errors.New("could not read uploaded file")
+    }
+    return f, file.Filename, nil
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+type MeetingHandler struct {
+    meetingService *services.MeetingService
+    config         *configs.Config
+}
+
+func NewMeetingHandler(config *configs.Config) *MeetingHandler {
+    return &MeetingHandler{
+        meetingService: services.NewMeetingService(),
+        config:         config,
+    }
+}
+
+func (h *MeetingHandler) GetMeetings(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+        return
+    }
+
+    meetings, err := h.meetingService.GetCourseMeetings(userID, uint(courseID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch meetings"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"meetings": meetings})
+}
+
+func (h *MeetingHandler) CreateMeeting(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+        return
+    }
+
+    var req services.CreateMeetingRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    meeting, clashes, err := h.meetingService.CreateMeeting(userID, uint(courseID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidMeeting) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create meeting"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Meeting created successfully",
+        "meeting": meeting,
+        "clashes": clashes,
+    })
+}
+
+func (h *MeetingHandler) UpdateMeeting(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+        return
+    }
+    meetingID, err := strconv.ParseUint(c.Param("meetingId"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+        return
+    }
+
+    var updates map[string]interface{}
+    if err := c.ShouldBindJSON(&updates); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    meeting, clashes, err := h.meetingService.UpdateMeeting(userID, uint(courseID), uint(meetingID), updates)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidMeeting) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update meeting"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Meeting updated successfully",
+        "meeting": meeting,
+        "clashes": clashes,
+    })
+}
+
+func (h *MeetingHandler) DeleteMeeting(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+        return
+    }
+    meetingID, err := strconv.ParseUint(c.Param("meetingId"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid meeting ID"})
+        return
+    }
+
+    err = h.meetingService.DeleteMeeting(userID, uint(courseID), uint(meetingID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete meeting"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Meeting deleted successfully"})
+}
+
+func (h *MeetingHandler) GetTimetable(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    timetable, err := h.meetingService.GetTimetable(userID, c.Query("semester"))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not build timetable"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"timetable": timetable})
+}
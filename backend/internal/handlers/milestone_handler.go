@@ -0,0 +1,206 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+type MilestoneHandler struct {
+    milestoneService *services.MilestoneService
+    config           *configs.Config
+}
+
+func NewMilestoneHandler(config *configs.Config) *MilestoneHandler {
+    return &MilestoneHandler{
+        milestoneService: services.NewMilestoneService(),
+        config:           config,
+    }
+}
+
+func (h *MilestoneHandler) GetMilestones(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    milestones, err := h.milestoneService.GetMilestones(userID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch milestones"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"milestones": milestones})
+}
+
+func (h *MilestoneHandler) GetMilestone(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    milestoneID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid milestone ID"})
+        return
+    }
+
+    milestone, err := h.milestoneService.GetMilestone(userID, uint(milestoneID))
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Milestone not found"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"milestone": milestone})
+}
+
+func (h *MilestoneHandler) CreateMilestone(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var req services.CreateMilestoneRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    milestone, err := h.milestoneService.CreateMilestone(userID, req)
+    if errors.Is(err, services.ErrInvalidMilestone) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create milestone"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message":   "Milestone created successfully",
+        "milestone": milestone,
+    })
+}
+
+func (h *MilestoneHandler) UpdateMilestone(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    milestoneID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid milestone ID"})
+        return
+    }
+
+    var req services.UpdateMilestoneRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    milestone, err := h.milestoneService.UpdateMilestone(userID, uint(milestoneID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Milestone not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidMilestone) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update milestone"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":   "Milestone updated successfully",
+        "milestone": milestone,
+    })
+}
+
+func (h *MilestoneHandler) DeleteMilestone(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    milestoneID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid milestone ID"})
+        return
+    }
+
+    err = h.milestoneService.DeleteMilestone(userID, uint(milestoneID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Milestone not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete milestone"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Milestone deleted successfully"})
+}
+
+func (h *MilestoneHandler) LinkAssignment(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    milestoneID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid milestone ID"})
+        return
+    }
+
+    var req services.LinkAssignmentRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    milestone, err := h.milestoneService.LinkAssignment(userID, uint(milestoneID), req.AssignmentID)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Milestone not found"})
+        return
+    }
+    if errors.Is(err, services.ErrAssignmentNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not link assignment"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":   "Assignment linked successfully",
+        "milestone": milestone,
+    })
+}
+
+func (h *MilestoneHandler) UnlinkAssignment(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    milestoneID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid milestone ID"})
+        return
+    }
+    assignmentID, err := strconv.ParseUint(c.Param("assignmentId"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+        return
+    }
+
+    err = h.milestoneService.UnlinkAssignment(userID, uint(milestoneID), uint(assignmentID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Assignment is not linked to this milestone"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not unlink assignment"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Assignment unlinked successfully"})
+}
+
+func (h *MilestoneHandler) GetTimeline(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    timeline, err := h.milestoneService.Timeline(userID, time.Now())
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not build milestone timeline"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"timeline": timeline})
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/mail"
+    "github.com/anayy09/academiaflow-backend/internal/notify"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+const (
+    defaultNotificationLimit = 50
+    maxNotificationLimit     = 200
+)
+
+type NotificationHandler struct {
+    notificationService *services.NotificationService
+    config              *configs.Config
+}
+
+func NewNotificationHandler(config *configs.Config) *NotificationHandler {
+    return &NotificationHandler{
+        notificationService: services.NewNotificationService(notify.NewChannels(mail.NewMailer(config.Mail))),
+        config:              config,
+    }
+}
+
+// GetNotifications lists in-app notifications, newest first. ?unread=true
+// leaves out those already read and ?limit= caps the count (default 50).
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    limit := defaultNotificationLimit
+    if value := c.Query("limit"); value != "" {
+        n, err := strconv.Atoi(value)
+        if err != nil || n < 1 {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+            return
+        }
+        if n > maxNotificationLimit {
+            n = maxNotificationLimit
+        }
+        limit = n
+    }
+
+    notifications, unread, err := h.notificationService.GetNotifications(userID, c.Query("unread") == "true", limit)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch notifications"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "notifications": notifications,
+        "unread":        unread,
+    })
+}
+
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    notificationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+        return
+    }
+
+    notification, err := h.notificationService.MarkRead(userID, uint(notificationID), time.Now())
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not mark notification read"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"notification": notification})
+}
+
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    marked, err := h.notificationService.MarkAllRead(userID, time.Now())
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not mark notifications read"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"marked": marked})
+}
+
+// SendTest queues a test notification to each of the user's channels.
+func (h *NotificationHandler) SendTest(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    notification, err := h.notificationService.SendTest(userID, time.Now())
+    if errors.Is(err, services.ErrInvalidPreference) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not send test notification"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message":      "Test notification queued",
+        "notification": notification,
+    })
+}
+
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    pref, err := h.notificationService.GetPreferences(userID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch notification preferences"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"preferences": pref})
+}
+
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var req services.UpdateNotificationPreferenceRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    pref, err := h.notificationService.UpdatePreferences(userID, req)
+    if errors.Is(err, services.ErrInvalidPreference) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update notification preferences"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":     "Notification preferences updated successfully",
+        "preferences": pref,
+    })
+}
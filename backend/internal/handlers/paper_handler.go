@@ -0,0 +1,232 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+type PaperHandler struct {
+    paperService *services.PaperService
+    config       *configs.Config
+}
+
+func NewPaperHandler(config *configs.Config) *PaperHandler {
+    return &PaperHandler{
+        paperService: services.NewPaperService(),
+        config:       config,
+    }
+}
+
+func (h *PaperHandler) GetPapers(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    papers, err := h.paperService.GetPapers(userID, c.Query("status"))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch papers"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"papers": papers})
+}
+
+func (h *PaperHandler) GetPaper(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    paperID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid paper ID"})
+        return
+    }
+
+    paper, err := h.paperService.GetPaper(userID, uint(paperID))
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Paper not found"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"paper": paper})
+}
+
+func (h *PaperHandler) CreatePaper(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var req services.CreatePaperRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    paper, err := h.paperService.CreatePaper(userID, req)
+    if errors.Is(err, services.ErrInvalidPaper) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create paper"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Paper created successfully",
+        "paper":   paper,
+    })
+}
+
+func (h *PaperHandler) UpdatePaper(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    paperID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid paper ID"})
+        return
+    }
+
+    var req services.UpdatePaperRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    paper, err := h.paperService.UpdatePaper(userID, uint(paperID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Paper not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidPaper) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update paper"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Paper updated successfully",
+        "paper":   paper,
+    })
+}
+
+func (h *PaperHandler) DeletePaper(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    paperID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid paper ID"})
+        return
+    }
+
+    err = h.paperService.DeletePaper(userID, uint(paperID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Paper not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete paper"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Paper deleted successfully"})
+}
+
+func (h *PaperHandler) CreateRound(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    paperID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid paper ID"})
+        return
+    }
+
+    var req services.CreateRoundRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    round, err := h.paperService.CreateRound(userID, uint(paperID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Paper not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidPaper) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not record submission round"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Submission round recorded successfully",
+        "round":   round,
+    })
+}
+
+func (h *PaperHandler) UpdateRound(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    paperID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid paper ID"})
+        return
+    }
+    roundID, err := strconv.ParseUint(c.Param("roundId"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid round ID"})
+        return
+    }
+
+    var req services.UpdateRoundRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    round, err := h.paperService.UpdateRound(userID, uint(paperID), uint(roundID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Submission round not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidPaper) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update submission round"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Submission round updated successfully",
+        "round":   round,
+    })
+}
+
+func (h *PaperHandler) DeleteRound(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    paperID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid paper ID"})
+        return
+    }
+    roundID, err := strconv.ParseUint(c.Param("roundId"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid round ID"})
+        return
+    }
+
+    err = h.paperService.DeleteRound(userID, uint(paperID), uint(roundID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Submission round not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete submission round"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Submission round deleted successfully"})
+}
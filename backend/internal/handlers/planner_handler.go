@@ -0,0 +1,97 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+)
+
+type PlannerHandler struct {
+    plannerService *services.PlannerService
+    config         *configs.Config
+}
+
+func NewPlannerHandler(config *configs.Config) *PlannerHandler {
+    return &PlannerHandler{
+        plannerService: services.NewPlannerService(),
+        config:         config,
+    }
+}
+
+func (h *PlannerHandler) GetPlan(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    // Dates are days in the user's time zone; defaults to the next two weeks
+    loc := h.plannerService.Location(userID)
+    from := time.Now().In(loc)
+    if value := c.Query("from"); value != "" {
+        parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, expected YYYY-MM-DD"})
+            return
+        }
+        from = parsed
+    }
+    to := from.AddDate(0, 0, 13)
+    if value := c.Query("to"); value != "" {
+        parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, expected YYYY-MM-DD"})
+            return
+        }
+        to = parsed
+    }
+
+    plan, err := h.plannerService.Plan(userID, from, to)
+    if errors.Is(err, services.ErrInvalidPlanRange) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not build plan"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"plan": plan})
+}
+
+func (h *PlannerHandler) GetAvailability(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    availability, err := h.plannerService.GetAvailability(userID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch availability"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"availability": availability})
+}
+
+func (h *PlannerHandler) UpdateAvailability(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var req services.UpdateAvailabilityRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    availability, err := h.plannerService.UpdateAvailability(userID, req)
+    if errors.Is(err, services.ErrInvalidAvailability) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update availability"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":      "Availability updated successfully",
+        "availability": availability,
+    })
+}
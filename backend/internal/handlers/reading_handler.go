@@ -0,0 +1,252 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/bibtex"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+type ReadingHandler struct {
+    readingService *services.ReadingService
+    config         *configs.Config
+}
+
+func NewReadingHandler(config *configs.Config) *ReadingHandler {
+    return &ReadingHandler{
+        readingService: services.NewReadingService(),
+        config:         config,
+    }
+}
+
+func (h *ReadingHandler) GetReadings(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    filter, ok := readingFilter(c)
+    if !ok {
+        return
+    }
+    readings, err := h.readingService.GetReadings(userID, filter)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch readings"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"readings": readings})
+}
+
+func (h *ReadingHandler) GetReading(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    readingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reading ID"})
+        return
+    }
+
+    reading, err := h.readingService.GetReading(userID, uint(readingID))
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Reading not found"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"reading": reading})
+}
+
+func (h *ReadingHandler) CreateReading(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var req services.CreateReadingRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    reading, err := h.readingService.CreateReading(userID, req)
+    if errors.Is(err, services.ErrReadingLinkNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidReading) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create reading"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Reading created successfully",
+        "reading": reading,
+    })
+}
+
+func (h *ReadingHandler) UpdateReading(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    readingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reading ID"})
+        return
+    }
+
+    var req services.UpdateReadingRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    reading, err := h.readingService.UpdateReading(userID, uint(readingID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Reading not found"})
+        return
+    }
+    if errors.Is(err, services.ErrReadingLinkNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidReading) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update reading"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Reading updated successfully",
+        "reading": reading,
+    })
+}
+
+func (h *ReadingHandler) DeleteReading(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    readingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reading ID"})
+        return
+    }
+
+    err = h.readingService.DeleteReading(userID, uint(readingID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Reading not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete reading"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Reading deleted successfully"})
+}
+
+// ImportReadings accepts a BibTeX or RIS file, as the "file" field of a
+// multipart form or as the raw body. format (bibtex or ris), course_id,
+// assignment_id, paper_id and dry_run can be given as form fields or query
+// parameters; the format is otherwise guessed from the file.
+func (h *ReadingHandler) ImportReadings(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+    opts := services.ReadingImportOptions{Format: strings.ToLower(importOption(c, "format"))}
+    for name, dest := range map[string]**uint{"course_id": &opts.CourseID, "assignment_id": &opts.AssignmentID, "paper_id": &opts.PaperID} {
+        if value := importOption(c, name); value != "" {
+            id, err := strconv.ParseUint(value, 10, 32)
+            if err != nil {
+                c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name})
+                return
+            }
+            linked := uint(id)
+            *dest = &linked
+        }
+    }
+    opts.DryRun, _ = strconv.ParseBool(importOption(c, "dry_run"))
+
+    body, filename, err := importBody(c)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    defer body.Close()
+
+    if opts.Format == "" {
+        switch lower := strings.ToLower(filename); {
+        case strings.HasSuffix(lower, ".bib"), strings.Contains(c.ContentType(), "bibtex"):
+            opts.Format = services.FormatBibTeX
+        case strings.HasSuffix(lower, ".ris"), strings.Contains(c.ContentType(), "research-info-systems"):
+            opts.Format = services.FormatRIS
+        }
+    }
+
+    result, err := h.readingService.Import(userID, body, opts)
+    if errors.Is(err, services.ErrReadingLinkNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidReading) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not import readings"})
+        return
+    }
+
+    message := "Readings imported successfully"
+    if result.DryRun {
+        message = "Dry run: nothing was saved"
+    }
+    c.JSON(http.StatusOK, gin.H{
+        "message": message,
+        "import":  result,
+    })
+}
+
+// ExportReadings downloads the reading list as a .bib file. The list
+// filters (status, course_id, assignment_id, paper_id) narrow it down, e.g.
+// to the references of one paper.
+func (h *ReadingHandler) ExportReadings(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    if format := c.DefaultQuery("format", services.FormatBibTeX); format != services.FormatBibTeX {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "format must be bibtex"})
+        return
+    }
+    filter, ok := readingFilter(c)
+    if !ok {
+        return
+    }
+
+    entries, err := h.readingService.Export(userID, filter)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not export readings"})
+        return
+    }
+
+    c.Header("Content-Type", "application/x-bibtex; charset=utf-8")
+    c.Header("Content-Disposition", `attachment; filename="academiaflow-readings.bib"`)
+    c.Status(http.StatusOK)
+    bibtex.Encode(c.Writer, entries)
+}
+
+func readingFilter(c *gin.Context) (services.ReadingFilter, bool) {
+    filter := services.ReadingFilter{Status: c.Query("status")}
+    for name, dest := range map[string]**uint{"course_id": &filter.CourseID, "assignment_id": &filter.AssignmentID, "paper_id": &filter.PaperID} {
+        if value := c.Query(name); value != "" {
+            id, err := strconv.ParseUint(value, 10, 32)
+            if err != nil {
+                c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name})
+                return filter, false
+            }
+            linked := uint(id)
+            *dest = &linked
+        }
+    }
+    return filter, true
+}
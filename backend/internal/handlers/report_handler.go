@@ -0,0 +1,170 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/mail"
+    "github.com/anayy09/academiaflow-backend/internal/report"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+)
+
+type ReportHandler struct {
+    reportService *services.ReportService
+    config        *configs.Config
+}
+
+func NewReportHandler(config *configs.Config) *ReportHandler {
+    return &ReportHandler{
+        reportService: services.NewReportService(mail.NewMailer(config.Mail)),
+        config:        config,
+    }
+}
+
+type SendReportRequest struct {
+    Week       string   `json:"week"`
+    TimeZone   string   `json:"tz"`
+    Recipients []string `json:"recipients"` // Defaults to the schedule's, then the advisor's email
+}
+
+var reportContentTypes = map[string]string{
+    report.FormatMarkdown: "text/markdown; charset=utf-8",
+    report.FormatHTML:     "text/html; charset=utf-8",
+    report.FormatText:     "text/plain; charset=utf-8",
+}
+
+// GetWeekly builds the progress report for ?week= (YYYY-Www or any date in
+// the week, default this week) as JSON or rendered with ?format=.
+func (h *ReportHandler) GetWeekly(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    format := c.DefaultQuery("format", "json")
+    contentType, ok := reportContentTypes[format]
+    if !ok && format != "json" {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json, markdown, html or text"})
+        return
+    }
+    now := time.Now()
+    start, ok := h.reportWeek(c, userID, c.Query("week"), c.Query("tz"), now)
+    if !ok {
+        return
+    }
+
+    weekly, err := h.reportService.Weekly(userID, start, now)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not build weekly report"})
+        return
+    }
+    if format == "json" {
+        c.JSON(http.StatusOK, gin.H{"report": weekly})
+        return
+    }
+
+    body, err := weekly.Render(format)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not render weekly report"})
+        return
+    }
+    c.Data(http.StatusOK, contentType, []byte(body))
+}
+
+// SendWeekly emails the report now instead of waiting for the schedule.
+func (h *ReportHandler) SendWeekly(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    // The body is optional; an empty one sends this week's report
+    var req SendReportRequest
+    if c.Request.ContentLength != 0 {
+        if err := c.ShouldBindJSON(&req); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+    }
+    now := time.Now()
+    start, ok := h.reportWeek(c, userID, req.Week, req.TimeZone, now)
+    if !ok {
+        return
+    }
+
+    weekly, recipients, err := h.reportService.SendNow(userID, start, req.Recipients, now)
+    if err != nil {
+        switch {
+        case errors.Is(err, mail.ErrNotConfigured):
+            c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Email is not configured on this server"})
+        case errors.Is(err, services.ErrNoRecipients), errors.Is(err, services.ErrInvalidRecipient):
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        case errors.Is(err, services.ErrReportRateLimited):
+            c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+        case errors.Is(err, services.ErrAdvisorEmailNew):
+            c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+        default:
+            c.JSON(http.StatusBadGateway, gin.H{"error": "Could not send weekly report"})
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":    "Weekly report sent successfully",
+        "week":       weekly.Week,
+        "recipients": recipients,
+    })
+}
+
+func (h *ReportHandler) GetSchedule(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    schedule, err := h.reportService.GetSchedule(userID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch report schedule"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"schedule": schedule})
+}
+
+func (h *ReportHandler) UpdateSchedule(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var req services.UpdateReportScheduleRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    schedule, err := h.reportService.UpdateSchedule(userID, req, time.Now())
+    if err != nil {
+        if errors.Is(err, services.ErrInvalidSchedule) {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update report schedule"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":  "Report schedule updated successfully",
+        "schedule": schedule,
+    })
+}
+
+// reportWeek resolves the week the caller asked for in the given time zone,
+// or the user's own.
+func (h *ReportHandler) reportWeek(c *gin.Context, userID uint, week, tz string, now time.Time) (time.Time, bool) {
+    loc := h.reportService.ReportLocation(userID)
+    if tz != "" {
+        var err error
+        if loc, err = time.LoadLocation(tz); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown time zone"})
+            return time.Time{}, false
+        }
+    }
+    start, err := services.ParseWeek(week, loc, now)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return time.Time{}, false
+    }
+    return start, true
+}
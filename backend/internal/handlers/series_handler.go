@@ -0,0 +1,142 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+type SeriesHandler struct {
+    recurrenceService *services.RecurrenceService
+    config            *configs.Config
+}
+
+func NewSeriesHandler(config *configs.Config) *SeriesHandler {
+    return &SeriesHandler{
+        recurrenceService: services.NewRecurrenceService(),
+        config:            config,
+    }
+}
+
+func (h *SeriesHandler) GetAllSeries(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    series, err := h.recurrenceService.GetUserSeries(userID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch assignment series"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"series": series})
+}
+
+func (h *SeriesHandler) CreateSeries(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var req services.CreateSeriesRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    series, err := h.recurrenceService.CreateSeries(userID, req)
+    if isRecurrenceInputError(err) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create assignment series"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Assignment series created successfully",
+        "series":  series,
+    })
+}
+
+func (h *SeriesHandler) GetSeries(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    seriesID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+        return
+    }
+
+    series, err := h.recurrenceService.GetSeries(userID, uint(seriesID))
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Assignment series not found"})
+        return
+    }
+
+    occurrences, err := h.recurrenceService.GetSeriesOccurrences(userID, series.ID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch occurrences"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "series":      series,
+        "occurrences": occurrences,
+    })
+}
+
+func (h *SeriesHandler) UpdateSeries(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    seriesID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+        return
+    }
+
+    var updates map[string]interface{}
+    if err := c.ShouldBindJSON(&updates); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    series, err := h.recurrenceService.UpdateSeries(userID, uint(seriesID), updates)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Assignment series not found"})
+        return
+    }
+    if isRecurrenceInputError(err) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update assignment series"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Assignment series updated successfully",
+        "series":  series,
+    })
+}
+
+func (h *SeriesHandler) DeleteSeries(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    seriesID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+        return
+    }
+
+    err = h.recurrenceService.DeleteSeries(userID, uint(seriesID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Assignment series not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete assignment series"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Assignment series deleted successfully"})
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+type TermHandler struct {
+    termService *services.TermService
+    config      *configs.Config
+}
+
+func NewTermHandler(config *configs.Config) *TermHandler {
+    return &TermHandler{
+        termService: services.NewTermService(),
+        config:      config,
+    }
+}
+
+func (h *TermHandler) GetTerms(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    terms, err := h.termService.GetTerms(userID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch terms"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"terms": terms})
+}
+
+func (h *TermHandler) GetCurrentTerm(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    current, err := h.termService.GetCurrentTerm(userID, time.Now())
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "No current or upcoming term"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch current term"})
+        return
+    }
+
+    c.JSON(http.StatusOK, current)
+}
+
+func (h *TermHandler) GetTerm(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    termID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term ID"})
+        return
+    }
+
+    term, err := h.termService.GetTerm(userID, uint(termID))
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Term not found"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"term": term})
+}
+
+func (h *TermHandler) CreateTerm(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var req services.CreateTermRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    term, err := h.termService.CreateTerm(userID, req)
+    if errors.Is(err, services.ErrInvalidTerm) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if errors.Is(err, gorm.ErrDuplicatedKey) {
+        c.JSON(http.StatusConflict, gin.H{"error": "A term with this name already exists"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create term"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Term created successfully",
+        "term":    term,
+    })
+}
+
+func (h *TermHandler) UpdateTerm(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    termID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term ID"})
+        return
+    }
+
+    var req services.UpdateTermRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    term, err := h.termService.UpdateTerm(userID, uint(termID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Term not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidTerm) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if errors.Is(err, gorm.ErrDuplicatedKey) {
+        c.JSON(http.StatusConflict, gin.H{"error": "A term with this name already exists"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update term"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Term updated successfully",
+        "term":    term,
+    })
+}
+
+func (h *TermHandler) DeleteTerm(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    termID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term ID"})
+        return
+    }
+
+    err = h.termService.DeleteTerm(userID, uint(termID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Term not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete term"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Term deleted successfully"})
+}
@@ -0,0 +1,342 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+type ThesisHandler struct {
+    thesisService *services.ThesisService
+    config        *configs.Config
+}
+
+func NewThesisHandler(config *configs.Config) *ThesisHandler {
+    return &ThesisHandler{
+        thesisService: services.NewThesisService(),
+        config:        config,
+    }
+}
+
+func (h *ThesisHandler) pushURL(token string) string {
+    return strings.TrimRight(h.config.Server.PublicURL, "/") + "/api/v1/thesis/push/" + token
+}
+
+func (h *ThesisHandler) GetTheses(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    theses, err := h.thesisService.GetTheses(userID)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch theses"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"theses": theses})
+}
+
+func (h *ThesisHandler) GetThesis(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    thesisID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thesis ID"})
+        return
+    }
+
+    thesis, err := h.thesisService.GetThesis(userID, uint(thesisID))
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Thesis not found"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "thesis":   thesis,
+        "push_url": h.pushURL(thesis.PushToken),
+    })
+}
+
+func (h *ThesisHandler) CreateThesis(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    var req services.CreateThesisRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    thesis, err := h.thesisService.CreateThesis(userID, req, time.Now())
+    if isThesisInputError(err) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create thesis"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message":  "Thesis created successfully",
+        "thesis":   thesis,
+        "push_url": h.pushURL(thesis.PushToken),
+    })
+}
+
+func (h *ThesisHandler) UpdateThesis(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    thesisID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thesis ID"})
+        return
+    }
+
+    var req services.UpdateThesisRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    thesis, err := h.thesisService.UpdateThesis(userID, uint(thesisID), req)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Thesis not found"})
+        return
+    }
+    if isThesisInputError(err) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update thesis"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Thesis updated successfully",
+        "thesis":  thesis,
+    })
+}
+
+func (h *ThesisHandler) DeleteThesis(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    thesisID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thesis ID"})
+        return
+    }
+
+    err = h.thesisService.DeleteThesis(userID, uint(thesisID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Thesis not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete thesis"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Thesis deleted successfully"})
+}
+
+func (h *ThesisHandler) RotatePushToken(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    thesisID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thesis ID"})
+        return
+    }
+
+    thesis, err := h.thesisService.RotatePushToken(userID, uint(thesisID))
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Thesis not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not rotate push token"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":  "Push token rotated; the previous URL no longer works",
+        "push_url": h.pushURL(thesis.PushToken),
+    })
+}
+
+func (h *ThesisHandler) CreateChapter(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    thesisID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thesis ID"})
+        return
+    }
+
+    var req services.ChapterRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    chapter, err := h.thesisService.CreateChapter(userID, uint(thesisID), req, time.Now())
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Thesis not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidThesis) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create chapter"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Chapter created successfully",
+        "chapter": chapter,
+    })
+}
+
+func (h *ThesisHandler) UpdateChapter(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    thesisID, chapterID, ok := chapterIDs(c)
+    if !ok {
+        return
+    }
+
+    var req services.UpdateChapterRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    chapter, err := h.thesisService.UpdateChapter(userID, thesisID, chapterID, req, time.Now())
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Chapter not found"})
+        return
+    }
+    if errors.Is(err, services.ErrInvalidThesis) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update chapter"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Chapter updated successfully",
+        "chapter": chapter,
+    })
+}
+
+func (h *ThesisHandler) DeleteChapter(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    thesisID, chapterID, ok := chapterIDs(c)
+    if !ok {
+        return
+    }
+
+    err := h.thesisService.DeleteChapter(userID, thesisID, chapterID)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Chapter not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete chapter"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Chapter deleted successfully"})
+}
+
+func (h *ThesisHandler) PushWordCounts(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    thesisID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thesis ID"})
+        return
+    }
+
+    var req services.WordCountPush
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    result, err := h.thesisService.PushWordCounts(userID, uint(thesisID), req, time.Now())
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Thesis not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update word counts"})
+        return
+    }
+
+    c.JSON(http.StatusOK, result)
+}
+
+// PushWordCountsByToken takes counts from editor plugins and git hooks. It
+// is public: the token in the URL is the credential.
+func (h *ThesisHandler) PushWordCountsByToken(c *gin.Context) {
+    var req services.WordCountPush
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    result, err := h.thesisService.PushWordCountsByToken(c.Param("token"), req, time.Now())
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Thesis not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update word counts"})
+        return
+    }
+
+    c.JSON(http.StatusOK, result)
+}
+
+func (h *ThesisHandler) GetBurnUp(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    thesisID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thesis ID"})
+        return
+    }
+
+    burnUp, err := h.thesisService.BurnUp(userID, uint(thesisID), time.Now())
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Thesis not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not compute burn-up chart"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"burnup": burnUp})
+}
+
+func isThesisInputError(err error) bool {
+    return errors.Is(err, services.ErrInvalidThesis) || errors.Is(err, services.ErrMilestoneNotFound)
+}
+
+func chapterIDs(c *gin.Context) (uint, uint, bool) {
+    thesisID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thesis ID"})
+        return 0, 0, false
+    }
+    chapterID, err := strconv.ParseUint(c.Param("chapterId"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid chapter ID"})
+        return 0, 0, false
+    }
+    return uint(thesisID), uint(chapterID), true
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/services"
+    "gorm.io/gorm"
+)
+
+type TimeEntryHandler struct {
+    timeEntryService *services.TimeEntryService
+    config           *configs.Config
+}
+
+func NewTimeEntryHandler(config *configs.Config) *TimeEntryHandler {
+    return &TimeEntryHandler{
+        timeEntryService: services.NewTimeEntryService(),
+        config:           config,
+    }
+}
+
+func (h *TimeEntryHandler) GetRunningTimer(c *gin.Context) {
+    userID := c.GetUint("user_id")
+
+    entry, err := h.timeEntryService.GetRunningTimer(userID)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        c.JSON(http.StatusOK, gin.H{"timer": nil})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch timer"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"timer": entry})
+}
+
+func (h *TimeEntryHandler) StartTimer(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+        return
+    }
+
+    // The body is optional
+    var req services.TimerRequest
+    if c.Request.ContentLength > 0 {
+        if err := c.ShouldBindJSON(&req); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+    }
+
+    entry, err := h.timeEntryService.StartTimer(userID, uint(assignmentID), req)
+    if err != nil {
+        switch {
+        case errors.Is(err, gorm.ErrRecordNotFound):
+            c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
+        case errors.Is(err, services.ErrTimerRunning):
+            c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+        default:
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not start timer"})
+        }
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "Timer started",
+        "timer":   entry,
+    })
+}
+
+func (h *TimeEntryHandler) StopTimer(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+        return
+    }
+
+    var req services.TimerRequest
+    if c.Request.ContentLength > 0 {
+        if err := c.ShouldBindJSON(&req); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+    }
+
+    entry, err := h.timeEntryService.StopTimer(userID, uint(assignmentID), req)
+    if errors.Is(err, services.ErrNoTimerRunning) {
+        c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not stop timer"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":    "Timer stopped",
+        "time_entry": entry,
+    })
+}
+
+func (h *TimeEntryHandler) GetTimeEntries(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+        return
+    }
+
+    entries, err := h.timeEntryService.GetTimeEntries(userID, uint(assignmentID))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch time entries"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"time_entries": entries})
+}
+
+func (h *TimeEntryHandler) CreateTimeEntry(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+        return
+    }
+
+    var req services.CreateTimeEntryRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    entry, err := h.timeEntryService.CreateTimeEntry(userID, uint(assignmentID), req)
+    if err != nil {
+        switch {
+        case errors.Is(err, gorm.ErrRecordNotFound):
+            c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
+        case errors.Is(err, services.ErrInvalidTimeSpan):
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        default:
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create time entry"})
+        }
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message":    "Time entry created successfully",
+        "time_entry": entry,
+    })
+}
+
+func (h *TimeEntryHandler) UpdateTimeEntry(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    entryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time entry ID"})
+        return
+    }
+
+    var req services.UpdateTimeEntryRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    entry, err := h.timeEntryService.UpdateTimeEntry(userID, uint(entryID), req)
+    if err != nil {
+        switch {
+        case errors.Is(err, gorm.ErrRecordNotFound):
+            c.JSON(http.StatusNotFound, gin.H{"error": "Time entry not found"})
+        case errors.Is(err, services.ErrInvalidTimeSpan):
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        default:
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update time entry"})
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":    "Time entry updated successfully",
+        "time_entry": entry,
+    })
+}
+
+func (h *TimeEntryHandler) DeleteTimeEntry(c *gin.Context) {
+    userID := c.GetUint("user_id")
+    entryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time entry ID"})
+        return
+    }
+
+    err = h.timeEntryService.DeleteTimeEntry(userID, uint(entryID))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete time entry"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Time entry deleted successfully"})
+}
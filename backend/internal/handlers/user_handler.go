@@ -2,6 +2,7 @@ package handlers
 
 import (
     "net/http"
+    "time"
 
     "github.com/gin-gonic/gin"
     "github.com/anayy09/academiaflow-backend/configs"
@@ -21,11 +22,13 @@ func NewUserHandler(config *configs.Config) *UserHandler {
 }
 
 type UpdateProfileRequest struct {
-    FirstName string `json:"first_name"`
-    LastName  string `json:"last_name"`
-    Program   string `json:"program"`
-    Year      int    `json:"year"`
-    Advisor   string `json:"advisor"`
+    FirstName        string     `json:"first_name"`
+    LastName         string     `json:"last_name"`
+    Program          string     `json:"program"`
+    Year             int        `json:"year"`
+    Advisor          string     `json:"advisor"`
+    AdvisorEmail     *string    `json:"advisor_email" binding:"omitempty,email|eq="` // Left alone when omitted; "" clears it
+    ProgramStartDate *time.Time `json:"program_start_date"`                          // Left alone when omitted
 }
 
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
@@ -44,6 +47,12 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
         "year":       req.Year,
         "advisor":    req.Advisor,
     }
+    if req.AdvisorEmail != nil {
+        updates["advisor_email"] = *req.AdvisorEmail
+    }
+    if req.ProgramStartDate != nil {
+        updates["program_start_date"] = req.ProgramStartDate
+    }
 
     user, err := h.userService.UpdateUser(userID, updates)
     if err != nil {
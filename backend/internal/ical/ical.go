@@ -0,0 +1,330 @@
+package ical
+
+import (
+    "bytes"
+    "fmt"
+    "io"
+    "strconv"
+    "strings"
+    "time"
+    "unicode/utf8"
+)
+
+const (
+    dateTimeUTCLayout   = "20060102T150405Z"
+    dateTimeLocalLayout = "20060102T150405"
+    dateLayout          = "20060102"
+    // Content lines are folded at 75 octets (RFC 5545 section 3.1).
+    maxLineOctets = 75
+)
+
+type Param struct {
+    Name  string
+    Value string
+}
+
+// Property is a single content line. Value is stored unescaped for TEXT
+// values; Encode takes care of escaping when Text is set.
+type Property struct {
+    Name   string
+    Params []Param
+    Value  string
+    Text   bool
+}
+
+// Component is a BEGIN/END block such as VCALENDAR, VEVENT or VALARM.
+type Component struct {
+    Name       string
+    Properties []Property
+    Components []*Component
+}
+
+func NewComponent(name string) *Component {
+    return &Component{Name: name}
+}
+
+// NewCalendar returns a VCALENDAR with the required VERSION and PRODID.
+func NewCalendar(name string) *Component {
+    cal := NewComponent("VCALENDAR")
+    cal.Set("VERSION", "2.0")
+    cal.Set("PRODID", "-//AcademiaFlow//AcademiaFlow//EN")
+    cal.Set("CALSCALE", "GREGORIAN")
+    if name != "" {
+        cal.SetText("X-WR-CALNAME", name)
+    }
+    return cal
+}
+
+// Set adds a property whose value is written verbatim.
+func (c *Component) Set(name, value string, params ...Param) {
+    c.Properties = append(c.Properties, Property{Name: name, Params: params, Value: value})
+}
+
+// SetText adds a TEXT property, escaped on output.
+func (c *Component) SetText(name, value string, params ...Param) {
+    c.Properties = append(c.Properties, Property{Name: name, Params: params, Value: value, Text: true})
+}
+
+func (c *Component) SetDateTime(name string, t time.Time) {
+    c.Set(name, FormatDateTime(t))
+}
+
+// SetLocalDateTime adds one or more DATE-TIME values in their own time
+// zone with a TZID parameter, so a weekly event keeps its wall-clock time
+// across DST changes. UTC values are written in UTC form. All values must
+// share a location.
+func (c *Component) SetLocalDateTime(name string, times ...time.Time) {
+    if len(times) == 0 {
+        return
+    }
+    loc := times[0].Location()
+    if loc == time.UTC {
+        values := make([]string, len(times))
+        for i, t := range times {
+            values[i] = FormatDateTime(t)
+        }
+        c.Set(name, strings.Join(values, ","))
+        return
+    }
+    values := make([]string, len(times))
+    for i, t := range times {
+        values[i] = t.In(loc).Format(dateTimeLocalLayout)
+    }
+    c.Set(name, strings.Join(values, ","), Param{Name: "TZID", Value: loc.String()})
+}
+
+// Timezone returns a VTIMEZONE for loc covering from to to, for the TZID
+// that SetLocalDateTime writes. Go doesn't expose a zone's rules, so each
+// offset change in the span is found by probing and written as its own
+// observance.
+func Timezone(loc *time.Location, from, to time.Time) *Component {
+    tz := NewComponent("VTIMEZONE")
+    tz.Set("TZID", loc.String())
+
+    at := from.In(loc)
+    _, offset := at.Zone()
+    tz.Add(observance(at, offset, offset))
+    for day := at; day.Before(to); {
+        next := day.AddDate(0, 0, 1)
+        if _, nextOffset := next.In(loc).Zone(); nextOffset != offset {
+            // Narrow the change down to the second
+            lo, hi := day, next
+            for hi.Sub(lo) > time.Second {
+                mid := lo.Add(hi.Sub(lo) / 2)
+                if _, o := mid.In(loc).Zone(); o == offset {
+                    lo = mid
+                } else {
+                    hi = mid
+                }
+            }
+            tz.Add(observance(hi.In(loc), offset, nextOffset))
+            offset = nextOffset
+        }
+        day = next
+    }
+    return tz
+}
+
+// observance is a STANDARD or DAYLIGHT block for an offset change at t.
+// Its DTSTART is the local time just before the change.
+func observance(t time.Time, from, to int) *Component {
+    name := "STANDARD"
+    if t.IsDST() {
+        name = "DAYLIGHT"
+    }
+    c := NewComponent(name)
+    c.Set("DTSTART", t.In(time.FixedZone("", from)).Format(dateTimeLocalLayout))
+    c.Set("TZOFFSETFROM", formatOffset(from))
+    c.Set("TZOFFSETTO", formatOffset(to))
+    if abbr, _ := t.Zone(); abbr != "" && abbr[0] != '+' && abbr[0] != '-' {
+        c.Set("TZNAME", abbr)
+    }
+    return c
+}
+
+// formatOffset renders a UTC offset in seconds as +HHMM, or +HHMMSS when
+// it isn't a whole minute.
+func formatOffset(seconds int) string {
+    sign := "+"
+    if seconds < 0 {
+        sign = "-"
+        seconds = -seconds
+    }
+    s := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds/60%60)
+    if seconds%60 != 0 {
+        s += fmt.Sprintf("%02d", seconds%60)
+    }
+    return s
+}
+
+func (c *Component) SetDate(name string, t time.Time) {
+    c.Set(name, FormatDate(t), Param{Name: "VALUE", Value: "DATE"})
+}
+
+func (c *Component) Add(child *Component) {
+    c.Components = append(c.Components, child)
+}
+
+// Get returns the first property with the given name, or nil.
+func (c *Component) Get(name string) *Property {
+    for i := range c.Properties {
+        if c.Properties[i].Name == name {
+            return &c.Properties[i]
+        }
+    }
+    return nil
+}
+
+// GetAll returns every property with the given name.
+func (c *Component) GetAll(name string) []Property {
+    var result []Property
+    for _, p := range c.Properties {
+        if p.Name == name {
+            result = append(result, p)
+        }
+    }
+    return result
+}
+
+// Value returns the value of the first property with the given name.
+func (c *Component) Value(name string) string {
+    if p := c.Get(name); p != nil {
+        return p.Value
+    }
+    return ""
+}
+
+// Children returns the nested components with the given name.
+func (c *Component) Children(name string) []*Component {
+    var result []*Component
+    for _, child := range c.Components {
+        if child.Name == name {
+            result = append(result, child)
+        }
+    }
+    return result
+}
+
+func (p *Property) Param(name string) string {
+    for _, param := range p.Params {
+        if param.Name == name {
+            return param.Value
+        }
+    }
+    return ""
+}
+
+func (c *Component) Encode(w io.Writer) error {
+    var buf bytes.Buffer
+    c.encode(&buf)
+    _, err := w.Write(buf.Bytes())
+    return err
+}
+
+func (c *Component) Bytes() []byte {
+    var buf bytes.Buffer
+    c.encode(&buf)
+    return buf.Bytes()
+}
+
+func (c *Component) encode(buf *bytes.Buffer) {
+    writeLine(buf, "BEGIN:"+c.Name)
+    for _, p := range c.Properties {
+        var line strings.Builder
+        line.WriteString(p.Name)
+        for _, param := range p.Params {
+            line.WriteString(";" + param.Name + "=" + quoteParam(param.Value))
+        }
+        line.WriteString(":")
+        if p.Text {
+            line.WriteString(EscapeText(p.Value))
+        } else {
+            line.WriteString(p.Value)
+        }
+        writeLine(buf, line.String())
+    }
+    for _, child := range c.Components {
+        child.encode(buf)
+    }
+    writeLine(buf, "END:"+c.Name)
+}
+
+// writeLine folds a content line into 75-octet chunks without splitting a
+// UTF-8 sequence, continuing each chunk with a single space.
+func writeLine(buf *bytes.Buffer, line string) {
+    limit := maxLineOctets
+    for len(line) > limit {
+        cut := limit
+        for cut > 0 && !utf8.RuneStart(line[cut]) {
+            cut--
+        }
+        buf.WriteString(line[:cut])
+        buf.WriteString("\r\n ")
+        line = line[cut:]
+        limit = maxLineOctets - 1
+    }
+    buf.WriteString(line)
+    buf.WriteString("\r\n")
+}
+
+func quoteParam(value string) string {
+    if strings.ContainsAny(value, ":;,") {
+        return `"` + strings.ReplaceAll(value, `"`, "") + `"`
+    }
+    return value
+}
+
+// EscapeText escapes a TEXT value (RFC 5545 section 3.3.11).
+func EscapeText(s string) string {
+    s = strings.ReplaceAll(s, `\`, `\\`)
+    s = strings.ReplaceAll(s, ";", `\;`)
+    s = strings.ReplaceAll(s, ",", `\,`)
+    s = strings.ReplaceAll(s, "\r\n", `\n`)
+    s = strings.ReplaceAll(s, "\n", `\n`)
+    return s
+}
+
+func FormatDateTime(t time.Time) string {
+    return t.UTC().Format(dateTimeUTCLayout)
+}
+
+func FormatDate(t time.Time) string {
+    return t.Format(dateLayout)
+}
+
+// FormatDuration renders a duration as an RFC 5545 DURATION value such as
+// -PT30M or P1D.
+func FormatDuration(d time.Duration) string {
+    sign := ""
+    if d < 0 {
+        sign = "-"
+        d = -d
+    }
+    if d == 0 {
+        return "PT0S"
+    }
+
+    var b strings.Builder
+    b.WriteString(sign + "P")
+    if days := d / (24 * time.Hour); days > 0 && d%(24*time.Hour) == 0 {
+        if days%7 == 0 {
+            b.WriteString(strconv.Itoa(int(days/7)) + "W")
+        } else {
+            b.WriteString(strconv.Itoa(int(days)) + "D")
+        }
+        return b.String()
+    }
+    b.WriteString("T")
+    if h := d / time.Hour; h > 0 {
+        b.WriteString(strconv.Itoa(int(h)) + "H")
+        d -= h * time.Hour
+    }
+    if m := d / time.Minute; m > 0 {
+        b.WriteString(strconv.Itoa(int(m)) + "M")
+        d -= m * time.Minute
+    }
+    if s := d / time.Second; s > 0 {
+        b.WriteString(strconv.Itoa(int(s)) + "S")
+    }
+    return b.String()
+}
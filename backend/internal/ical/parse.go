@@ -0,0 +1,237 @@
+package ical
+
+import (
+    "bufio"
+    "errors"
+    "fmt"
+    "io"
+    "strings"
+    "time"
+)
+
+// Parse reads an iCalendar stream and returns its top-level component,
+// normally a VCALENDAR. Folded lines are joined and both CRLF and bare LF
+// line endings are accepted.
+func Parse(r io.Reader) (*Component, error) {
+    lines, err := unfold(r)
+    if err != nil {
+        return nil, err
+    }
+
+    var root *Component
+    var stack []*Component
+    for n, line := range lines {
+        if strings.TrimSpace(line) == "" {
+            continue
+        }
+        prop, err := parseLine(line)
+        if err != nil {
+            return nil, fmt.Errorf("line %d: %w", n+1, err)
+        }
+
+        switch prop.Name {
+        case "BEGIN":
+            c := NewComponent(strings.ToUpper(prop.Value))
+            if len(stack) > 0 {
+                stack[len(stack)-1].Add(c)
+            } else if root == nil {
+                root = c
+            } else {
+                return nil, errors.New("multiple top-level components")
+            }
+            stack = append(stack, c)
+        case "END":
+            if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(prop.Value) {
+                return nil, fmt.Errorf("line %d: unexpected END:%s", n+1, prop.Value)
+            }
+            stack = stack[:len(stack)-1]
+        default:
+            if len(stack) == 0 {
+                return nil, fmt.Errorf("line %d: property outside of a component", n+1)
+            }
+            current := stack[len(stack)-1]
+            current.Properties = append(current.Properties, prop)
+        }
+    }
+
+    if root == nil {
+        return nil, errors.New("no calendar data found")
+    }
+    if len(stack) > 0 {
+        return nil, fmt.Errorf("missing END:%s", stack[len(stack)-1].Name)
+    }
+    return root, nil
+}
+
+func unfold(r io.Reader) ([]string, error) {
+    scanner := bufio.NewScanner(r)
+    scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+    var lines []string
+    for scanner.Scan() {
+        line := strings.TrimRight(scanner.Text(), "\r")
+        if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+            lines[len(lines)-1] += line[1:]
+            continue
+        }
+        lines = append(lines, line)
+    }
+    return lines, scanner.Err()
+}
+
+// parseLine splits "NAME;PARAM=value;PARAM="quoted":value".
+func parseLine(line string) (Property, error) {
+    var prop Property
+
+    i := strings.IndexAny(line, ";:")
+    if i <= 0 {
+        return prop, fmt.Errorf("malformed content line %q", line)
+    }
+    prop.Name = strings.ToUpper(line[:i])
+    rest := line[i:]
+
+    for len(rest) > 0 && rest[0] == ';' {
+        rest = rest[1:]
+        eq := strings.IndexByte(rest, '=')
+        if eq <= 0 {
+            return prop, fmt.Errorf("malformed parameter in %q", line)
+        }
+        name := strings.ToUpper(rest[:eq])
+        rest = rest[eq+1:]
+
+        var value string
+        if strings.HasPrefix(rest, `"`) {
+            end := strings.IndexByte(rest[1:], '"')
+            if end < 0 {
+                return prop, fmt.Errorf("unterminated quoted parameter in %q", line)
+            }
+            value = rest[1 : end+1]
+            rest = rest[end+2:]
+        } else {
+            end := strings.IndexAny(rest, ";:")
+            if end < 0 {
+                return prop, fmt.Errorf("malformed parameter in %q", line)
+            }
+            value = rest[:end]
+            rest = rest[end:]
+        }
+        prop.Params = append(prop.Params, Param{Name: name, Value: value})
+    }
+
+    if len(rest) == 0 || rest[0] != ':' {
+        return prop, fmt.Errorf("missing value in %q", line)
+    }
+    prop.Value = rest[1:]
+    return prop, nil
+}
+
+// TextValue returns the value with TEXT escapes removed.
+func (p *Property) TextValue() string {
+    if p.Text {
+        return p.Value
+    }
+    return UnescapeText(p.Value)
+}
+
+// UnescapeText reverses EscapeText.
+func UnescapeText(s string) string {
+    if !strings.Contains(s, `\`) {
+        return s
+    }
+    var b strings.Builder
+    for i := 0; i < len(s); i++ {
+        if s[i] != '\\' || i == len(s)-1 {
+            b.WriteByte(s[i])
+            continue
+        }
+        i++
+        switch s[i] {
+        case 'n', 'N':
+            b.WriteByte('\n')
+        default:
+            b.WriteByte(s[i])
+        }
+    }
+    return b.String()
+}
+
+// Time interprets a DATE or DATE-TIME property. allDay is true for
+// VALUE=DATE values, which are returned as midnight in fallback. TZID
+// parameters are resolved through the IANA database, with the common
+// Windows zone names Outlook emits mapped as well.
+func (p *Property) Time(fallback *time.Location) (t time.Time, allDay bool, err error) {
+    loc := fallback
+    if tzid := p.Param("TZID"); tzid != "" {
+        loc = LoadLocation(tzid, fallback)
+    }
+
+    value := strings.TrimSpace(p.Value)
+    allDay = p.Param("VALUE") == "DATE" || (len(value) == 8 && !strings.Contains(value, "T"))
+    t, err = parseDateTime(value, loc)
+    return t, allDay, err
+}
+
+// Times interprets a multi-valued DATE/DATE-TIME property such as EXDATE.
+func (p *Property) Times(fallback *time.Location) ([]time.Time, error) {
+    loc := fallback
+    if tzid := p.Param("TZID"); tzid != "" {
+        loc = LoadLocation(tzid, fallback)
+    }
+
+    var result []time.Time
+    for _, value := range strings.Split(p.Value, ",") {
+        t, err := parseDateTime(strings.TrimSpace(value), loc)
+        if err != nil {
+            return nil, err
+        }
+        result = append(result, t)
+    }
+    return result, nil
+}
+
+func parseDateTime(value string, loc *time.Location) (time.Time, error) {
+    switch {
+    case strings.HasSuffix(value, "Z"):
+        return time.Parse(dateTimeUTCLayout, value)
+    case strings.Contains(value, "T"):
+        return time.ParseInLocation("20060102T150405", value, loc)
+    default:
+        return time.ParseInLocation(dateLayout, value, loc)
+    }
+}
+
+var windowsZones = map[string]string{
+    "Eastern Standard Time":        "America/New_York",
+    "Central Standard Time":        "America/Chicago",
+    "Mountain Standard Time":       "America/Denver",
+    "Pacific Standard Time":        "America/Los_Angeles",
+    "GMT Standard Time":            "Europe/London",
+    "W. Europe Standard Time":      "Europe/Berlin",
+    "Romance Standard Time":        "Europe/Paris",
+    "Central Europe Standard Time": "Europe/Budapest",
+    "India Standard Time":          "Asia/Kolkata",
+    "China Standard Time":          "Asia/Shanghai",
+    "Tokyo Standard Time":          "Asia/Tokyo",
+    "AUS Eastern Standard Time":    "Australia/Sydney",
+    "UTC":                          "UTC",
+}
+
+// LoadLocation resolves a TZID, falling back when it is unknown.
+func LoadLocation(tzid string, fallback *time.Location) *time.Location {
+    tzid = strings.Trim(tzid, `"`)
+    // Some producers prefix the ID with a vendor path, e.g. "/citadel.org/.../Europe/Paris"
+    candidates := []string{tzid}
+    if name, ok := windowsZones[tzid]; ok {
+        candidates = append([]string{name}, candidates...)
+    }
+    if parts := strings.Split(tzid, "/"); len(parts) >= 2 {
+        candidates = append(candidates, strings.Join(parts[len(parts)-2:], "/"))
+    }
+
+    for _, name := range candidates {
+        if loc, err := time.LoadLocation(name); err == nil {
+            return loc
+        }
+    }
+    return fallback
+}
@@ -0,0 +1,291 @@
+// Package jobs runs background work stored in the jobs table. Any number
+// of server instances can run a Scheduler against the same database: each
+// job is leased to one worker at a time with SELECT ... FOR UPDATE SKIP
+// LOCKED, and a job whose worker died is picked up again once its lease
+// expires.
+package jobs
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "log"
+    "os"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/configs"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+const (
+    StatusPending = "pending"
+    StatusRunning = "running"
+    StatusDone    = "done"
+    StatusFailed  = "failed"
+
+    DefaultMaxAttempts = 5
+
+    kindCleanup = "jobs.cleanup"
+
+    batchSize      = 10
+    backoffBase    = 30 * time.Second
+    backoffMax     = time.Hour
+    finishedMaxAge = 7 * 24 * time.Hour
+)
+
+// Handler runs one job. Returning an error retries the job with backoff
+// unless it is wrapped with Permanent.
+type Handler func(ctx context.Context, job *models.Job) error
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error that retrying won't fix, failing the job at
+// once.
+func Permanent(err error) error {
+    if err == nil {
+        return nil
+    }
+    return permanentError{err}
+}
+
+type Scheduler struct {
+    db       *gorm.DB
+    worker   string
+    poll     time.Duration
+    lease    time.Duration
+    handlers map[string]Handler
+}
+
+func NewScheduler(db *gorm.DB, config configs.JobsConfig) *Scheduler {
+    s := &Scheduler{
+        db:       db,
+        worker:   config.WorkerID,
+        poll:     config.PollInterval,
+        lease:    config.Lease,
+        handlers: map[string]Handler{},
+    }
+    if s.worker == "" {
+        host, _ := os.Hostname()
+        s.worker = fmt.Sprintf("%s:%d", host, os.Getpid())
+    }
+    if s.poll <= 0 {
+        s.poll = 15 * time.Second
+    }
+    if s.lease <= 0 {
+        s.lease = 5 * time.Minute
+    }
+    s.handlers[kindCleanup] = s.cleanup
+    return s
+}
+
+// Register sets the handler for a kind of job. Jobs of kinds no instance
+// has registered stay pending.
+func (s *Scheduler) Register(kind string, handler Handler) {
+    s.handlers[kind] = handler
+}
+
+// Enqueue adds a job to run at runAt. Payload is encoded as JSON. A job
+// with a dedup key that is already queued is left alone, and the existing
+// job is not changed.
+func Enqueue(db *gorm.DB, kind, dedupKey string, payload interface{}, runAt time.Time) error {
+    data, err := json.Marshal(payload)
+    if err != nil {
+        return err
+    }
+    job := models.Job{
+        Kind:        kind,
+        DedupKey:    dedupKey,
+        Payload:     string(data),
+        Status:      StatusPending,
+        RunAt:       runAt,
+        MaxAttempts: DefaultMaxAttempts,
+    }
+    return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&job).Error
+}
+
+// Every makes sure a periodic job of the kind exists, running every
+// interval from now on. Only one row per kind is kept however many
+// instances call it.
+func (s *Scheduler) Every(kind string, interval time.Duration, now time.Time) error {
+    job := models.Job{
+        Kind:        kind,
+        DedupKey:    "every:" + kind,
+        Payload:     "{}",
+        Status:      StatusPending,
+        RunAt:       now,
+        Interval:    int(interval / time.Second),
+        MaxAttempts: DefaultMaxAttempts,
+    }
+    err := s.db.Clauses(clause.OnConflict{
+        Columns:     []clause.Column{{Name: "dedup_key"}},
+        TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Neq{Column: "dedup_key", Value: ""}}},
+        DoUpdates:   clause.Assignments(map[string]interface{}{"interval": job.Interval, "updated_at": now}),
+    }).Create(&job).Error
+    if err != nil {
+        return err
+    }
+    // A periodic job that used up its attempts is given another go
+    return s.db.Model(&models.Job{}).
+        Where("dedup_key = ? AND status = ?", job.DedupKey, StatusFailed).
+        Updates(map[string]interface{}{"status": StatusPending, "attempts": 0, "run_at": now}).Error
+}
+
+// Run polls for due jobs until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+    if err := s.Every(kindCleanup, 24*time.Hour, time.Now()); err != nil {
+        log.Printf("jobs: scheduling cleanup: %v", err)
+    }
+    ticker := time.NewTicker(s.poll)
+    defer ticker.Stop()
+    for {
+        // Keep going while there's a backlog, then wait for the next tick
+        for {
+            n, err := s.RunDue(ctx, time.Now())
+            if err != nil {
+                log.Printf("jobs: %v", err)
+            }
+            if n < batchSize || err != nil || ctx.Err() != nil {
+                break
+            }
+        }
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+    }
+}
+
+// RunDue leases a batch of due jobs and runs them one after another. It
+// returns how many jobs it ran.
+func (s *Scheduler) RunDue(ctx context.Context, now time.Time) (int, error) {
+    jobs, err := s.claim(now)
+    if err != nil {
+        return 0, err
+    }
+    for i := range jobs {
+        s.run(ctx, &jobs[i])
+    }
+    return len(jobs), nil
+}
+
+// claim leases due jobs of registered kinds to this worker, along with
+// running jobs whose lease has expired.
+func (s *Scheduler) claim(now time.Time) ([]models.Job, error) {
+    kinds := make([]string, 0, len(s.handlers))
+    for kind := range s.handlers {
+        kinds = append(kinds, kind)
+    }
+
+    var jobs []models.Job
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+            Where("kind IN ?", kinds).
+            Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?)", StatusPending, now, StatusRunning, now).
+            Order("run_at ASC").
+            Limit(batchSize).
+            Find(&jobs).Error
+        if err != nil || len(jobs) == 0 {
+            return err
+        }
+        ids := make([]uint, 0, len(jobs))
+        until := now.Add(s.lease)
+        for i := range jobs {
+            ids = append(ids, jobs[i].ID)
+            jobs[i].Status = StatusRunning
+            jobs[i].Attempts++
+            jobs[i].LockedBy = s.worker
+            jobs[i].LockedUntil = &until
+        }
+        return tx.Model(&models.Job{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+            "status":       StatusRunning,
+            "attempts":     gorm.Expr("attempts + 1"),
+            "locked_by":    s.worker,
+            "locked_until": until,
+        }).Error
+    })
+    return jobs, err
+}
+
+// run calls the job's handler within its lease and records the outcome.
+// The outcome is only saved if this worker still holds the lease.
+func (s *Scheduler) run(ctx context.Context, job *models.Job) {
+    ctx, cancel := context.WithDeadline(ctx, *job.LockedUntil)
+    err := s.call(ctx, job)
+    cancel()
+
+    now := time.Now()
+    updates := map[string]interface{}{"locked_by": "", "locked_until": nil, "last_error": ""}
+    var permanent permanentError
+    switch {
+    case err == nil && job.Interval > 0:
+        updates["status"] = StatusPending
+        updates["attempts"] = 0
+        updates["run_at"] = now.Add(time.Duration(job.Interval) * time.Second)
+    case err == nil:
+        updates["status"] = StatusDone
+        updates["finished_at"] = now
+    case errors.As(err, &permanent) || job.Attempts >= job.MaxAttempts:
+        updates["status"] = StatusFailed
+        updates["last_error"] = err.Error()
+        updates["finished_at"] = now
+        log.Printf("jobs: %s job %d failed: %v", job.Kind, job.ID, err)
+    default:
+        updates["status"] = StatusPending
+        updates["last_error"] = err.Error()
+        updates["run_at"] = now.Add(Backoff(job.Attempts))
+    }
+
+    result := s.db.Model(&models.Job{}).
+        Where("id = ? AND status = ? AND locked_by = ?", job.ID, StatusRunning, s.worker).
+        Updates(updates)
+    if result.Error != nil {
+        log.Printf("jobs: saving %s job %d: %v", job.Kind, job.ID, result.Error)
+    }
+}
+
+// call runs the handler, turning a panic into an error so one bad job
+// can't take the worker down.
+func (s *Scheduler) call(ctx context.Context, job *models.Job) (err error) {
+    defer func() {
+        if r := recover(); r != nil {
+            err = fmt.Errorf("panic: %v", r)
+        }
+    }()
+    return s.handlers[job.Kind](ctx, job)
+}
+
+// cleanup deletes finished jobs after a week.
+func (s *Scheduler) cleanup(ctx context.Context, job *models.Job) error {
+    cutoff := time.Now().Add(-finishedMaxAge)
+    return s.db.WithContext(ctx).
+        Where("status IN ? AND finished_at < ?", []string{StatusDone, StatusFailed}, cutoff).
+        Delete(&models.Job{}).Error
+}
+
+// Backoff is the wait before retrying a job that has failed attempts
+// times: 30s, 1m, 2m and so on, capped at an hour.
+func Backoff(attempts int) time.Duration {
+    wait := backoffBase
+    for i := 1; i < attempts && wait < backoffMax; i++ {
+        wait *= 2
+    }
+    if wait > backoffMax {
+        wait = backoffMax
+    }
+    return wait
+}
+
+// Decode reads a job's JSON payload into v.
+func Decode(job *models.Job, v interface{}) error {
+    if err := json.Unmarshal([]byte(job.Payload), v); err != nil {
+        return Permanent(fmt.Errorf("decoding %s payload: %w", job.Kind, err))
+    }
+    return nil
+}
@@ -0,0 +1,156 @@
+package mail
+
+import (
+    "bytes"
+    "crypto/rand"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "mime"
+    "mime/quotedprintable"
+    netmail "net/mail"
+    "net/smtp"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/configs"
+)
+
+var ErrNotConfigured = errors.New("outgoing mail is not configured")
+
+// Message is an email with a plain-text body and an optional HTML
+// alternative.
+type Message struct {
+    To      []string
+    Subject string
+    Text    string
+    HTML    string
+}
+
+type Mailer interface {
+    Send(msg Message) error
+}
+
+// SMTPMailer sends through an SMTP relay, upgrading to TLS with STARTTLS
+// when the server offers it.
+type SMTPMailer struct {
+    addr string
+    host string
+    auth smtp.Auth
+    from *netmail.Address
+}
+
+// NewMailer returns an SMTP mailer for the config, or one that refuses to
+// send when no SMTP host is set.
+func NewMailer(config configs.MailConfig) Mailer {
+    if config.Host == "" {
+        return disabledMailer{}
+    }
+    from, err := netmail.ParseAddress(config.From)
+    if err != nil {
+        from = &netmail.Address{Address: config.From}
+    }
+    m := &SMTPMailer{
+        addr: config.Host + ":" + strconv.Itoa(config.Port),
+        host: config.Host,
+        from: from,
+    }
+    if config.Username != "" {
+        m.auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+    }
+    return m
+}
+
+func (m *SMTPMailer) Send(msg Message) error {
+    if len(msg.To) == 0 {
+        return errors.New("message has no recipients")
+    }
+    body, rcpt, err := compose(m.from, msg)
+    if err != nil {
+        return err
+    }
+    return smtp.SendMail(m.addr, m.auth, m.from.Address, rcpt, body)
+}
+
+type disabledMailer struct{}
+
+func (disabledMailer) Send(Message) error {
+    return ErrNotConfigured
+}
+
+// compose builds the RFC 5322 message, text/plain alone or
+// multipart/alternative when there is an HTML part, and returns it with
+// the bare recipient addresses for the envelope.
+func compose(from *netmail.Address, msg Message) ([]byte, []string, error) {
+    var buf bytes.Buffer
+    header := func(name, value string) {
+        buf.WriteString(name + ": " + value + "\r\n")
+    }
+
+    to := make([]string, 0, len(msg.To))
+    rcpt := make([]string, 0, len(msg.To))
+    for _, addr := range msg.To {
+        parsed, err := netmail.ParseAddress(addr)
+        if err != nil {
+            return nil, nil, fmt.Errorf("invalid recipient %q", addr)
+        }
+        to = append(to, parsed.String())
+        rcpt = append(rcpt, parsed.Address)
+    }
+    header("From", from.String())
+    header("To", strings.Join(to, ", "))
+    header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+    header("Date", time.Now().Format(time.RFC1123Z))
+    header("Message-ID", "<"+randomToken()+"@"+messageDomain(from.Address)+">")
+    header("MIME-Version", "1.0")
+
+    if msg.HTML == "" {
+        header("Content-Type", "text/plain; charset=utf-8")
+        header("Content-Transfer-Encoding", "quoted-printable")
+        buf.WriteString("\r\n")
+        if err := writeQuotedPrintable(&buf, msg.Text); err != nil {
+            return nil, nil, err
+        }
+        return buf.Bytes(), rcpt, nil
+    }
+
+    boundary := "alt-" + randomToken()
+    header("Content-Type", `multipart/alternative; boundary="`+boundary+`"`)
+    buf.WriteString("\r\n")
+    for _, part := range []struct{ contentType, body string }{
+        {"text/plain; charset=utf-8", msg.Text},
+        {"text/html; charset=utf-8", msg.HTML},
+    } {
+        buf.WriteString("--" + boundary + "\r\n")
+        buf.WriteString("Content-Type: " + part.contentType + "\r\n")
+        buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+        if err := writeQuotedPrintable(&buf, part.body); err != nil {
+            return nil, nil, err
+        }
+        buf.WriteString("\r\n")
+    }
+    buf.WriteString("--" + boundary + "--\r\n")
+    return buf.Bytes(), rcpt, nil
+}
+
+func writeQuotedPrintable(buf *bytes.Buffer, text string) error {
+    w := quotedprintable.NewWriter(buf)
+    if _, err := w.Write([]byte(strings.ReplaceAll(text, "\n", "\r\n"))); err != nil {
+        return err
+    }
+    return w.Close()
+}
+
+func randomToken() string {
+    b := make([]byte, 12)
+    rand.Read(b)
+    return hex.EncodeToString(b)
+}
+
+func messageDomain(address string) string {
+    if i := strings.LastIndex(address, "@"); i >= 0 && i+1 < len(address) {
+        return address[i+1:]
+    }
+    return "localhost"
+}
@@ -1,12 +1,17 @@
 package middleware
 
 import (
+    "crypto/sha256"
+    "fmt"
     "net/http"
     "strings"
+    "sync"
+    "time"
 
     "github.com/gin-gonic/gin"
     "github.com/anayy09/academiaflow-backend/configs"
     "github.com/anayy09/academiaflow-backend/internal/auth"
+    "github.com/anayy09/academiaflow-backend/internal/services"
 )
 
 func AuthMiddleware(config *configs.Config) gin.HandlerFunc {
@@ -38,4 +43,77 @@ func AuthMiddleware(config *configs.Config) gin.HandlerFunc {
         c.Set("username", claims.Username)
         c.Next()
     }
-}
\ No newline at end of file
+}
+
+// How long verified Basic credentials are trusted without another bcrypt
+// check.
+const basicAuthCacheTTL = 5 * time.Minute
+
+// BasicAuthMiddleware authenticates with the account email and password.
+// It is used for CalDAV, whose clients cannot obtain a bearer token.
+// Clients send the credentials on every request, so verified ones are
+// remembered briefly and a sync of many items costs one bcrypt check.
+func BasicAuthMiddleware() gin.HandlerFunc {
+    userService := services.NewUserService()
+    verified := &credentialCache{ttl: basicAuthCacheTTL, entries: map[[32]byte]time.Time{}}
+
+    return func(c *gin.Context) {
+        email, password, ok := c.Request.BasicAuth()
+        if !ok {
+            c.Header("WWW-Authenticate", `Basic realm="AcademiaFlow", charset="UTF-8"`)
+            c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
+            c.Abort()
+            return
+        }
+
+        user, err := userService.GetUserByEmail(email)
+        if err != nil || !verified.check(user.ID, user.Password, password) {
+            c.Header("WWW-Authenticate", `Basic realm="AcademiaFlow", charset="UTF-8"`)
+            c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+            c.Abort()
+            return
+        }
+
+        // Set user information in context
+        c.Set("user_id", user.ID)
+        c.Set("user_email", user.Email)
+        c.Set("username", user.Username)
+        c.Next()
+    }
+}
+
+// credentialCache remembers passwords that recently matched a user's
+// stored hash. Entries are keyed on a digest of the user, the stored hash
+// and the password, so a password change invalidates them and the
+// password itself is never kept.
+type credentialCache struct {
+    mu      sync.Mutex
+    ttl     time.Duration
+    entries map[[32]byte]time.Time
+}
+
+func (c *credentialCache) check(userID uint, hash, password string) bool {
+    key := sha256.Sum256([]byte(fmt.Sprintf("%d\x00%s\x00%s", userID, hash, password)))
+
+    now := time.Now()
+    c.mu.Lock()
+    expires, ok := c.entries[key]
+    c.mu.Unlock()
+    if ok && now.Before(expires) {
+        return true
+    }
+
+    if !auth.CheckPasswordHash(password, hash) {
+        return false
+    }
+
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    for k, exp := range c.entries {
+        if !now.Before(exp) {
+            delete(c.entries, k)
+        }
+    }
+    c.entries[key] = now.Add(c.ttl)
+    return true
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+    "time"
+    "gorm.io/gorm"
+)
+
+// AdvisorMeeting is a meeting with the user's advisor or committee and
+// the action items that came out of it.
+type AdvisorMeeting struct {
+    ID          uint           `json:"id" gorm:"primaryKey"`
+    UserID      uint           `json:"user_id" gorm:"not null;index"`
+    User        User           `json:"-" gorm:"foreignKey:UserID"`
+    Date        time.Time      `json:"date" gorm:"not null;index"`
+    Title       string         `json:"title"`
+    Attendees   []string       `json:"attendees" gorm:"serializer:json"`
+    Agenda      []string       `json:"agenda" gorm:"serializer:json"`
+    Notes       string         `json:"notes"` // Markdown
+    ActionItems []ActionItem   `json:"action_items,omitempty" gorm:"foreignKey:MeetingID"`
+    CreatedAt   time.Time      `json:"created_at"`
+    UpdatedAt   time.Time      `json:"updated_at"`
+    DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type ActionItem struct {
+    ID           uint           `json:"id" gorm:"primaryKey"`
+    UserID       uint           `json:"user_id" gorm:"not null;index"`
+    MeetingID    uint           `json:"meeting_id" gorm:"not null;index"`
+    Description  string         `json:"description" gorm:"not null"`
+    Owner        string         `json:"owner"` // Who took it on; empty means the user
+    DueDate      *time.Time     `json:"due_date"`
+    Status       string         `json:"status"` // open, done, dropped
+    CompletedAt  *time.Time     `json:"completed_at"`
+    AssignmentID *uint          `json:"assignment_id,omitempty" gorm:"index"` // Set once promoted
+    Assignment   *Assignment    `json:"assignment,omitempty" gorm:"foreignKey:AssignmentID"`
+    CreatedAt    time.Time      `json:"created_at"`
+    UpdatedAt    time.Time      `json:"updated_at"`
+    DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
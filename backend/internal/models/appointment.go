@@ -0,0 +1,51 @@
+package models
+
+import (
+    "time"
+    "gorm.io/gorm"
+)
+
+// Appointment is a teaching or research assistantship with the weekly
+// hours it is contracted for.
+type Appointment struct {
+    ID              uint           `json:"id" gorm:"primaryKey"`
+    UserID          uint           `json:"user_id" gorm:"not null;index"`
+    User            User           `json:"-" gorm:"foreignKey:UserID"`
+    Kind            string         `json:"kind"` // ta, ra
+    Title           string         `json:"title" gorm:"not null"`
+    CourseID        *uint          `json:"course_id,omitempty" gorm:"index"` // Course being TA'd
+    Course          *Course        `json:"course,omitempty" gorm:"foreignKey:CourseID"`
+    Project         string         `json:"project"` // RA project or grant
+    Supervisor      string         `json:"supervisor"`
+    StartDate       time.Time      `json:"start_date" gorm:"not null"`
+    EndDate         time.Time      `json:"end_date" gorm:"not null"` // Inclusive
+    WeeklyHours     float64        `json:"weekly_hours"`
+    PayPeriod       string         `json:"pay_period"`                  // weekly, biweekly, semimonthly, monthly
+    PayPeriodAnchor *time.Time     `json:"pay_period_anchor,omitempty"` // First day of any weekly or biweekly period; defaults to StartDate
+    Notes           string         `json:"notes"`
+    Duties          []Duty         `json:"duties,omitempty" gorm:"foreignKey:AppointmentID"`
+    CreatedAt       time.Time      `json:"created_at"`
+    UpdatedAt       time.Time      `json:"updated_at"`
+    DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Duty is work owed under an appointment: either a weekly shift at fixed
+// times (office hours, lab sections) or a one-off task with a due date and
+// estimate (a grading batch). Each duty is backed by an assignment so it
+// can be timed and, for tasks, planned.
+type Duty struct {
+    ID             uint           `json:"id" gorm:"primaryKey"`
+    UserID         uint           `json:"user_id" gorm:"not null;index"`
+    AppointmentID  uint           `json:"appointment_id" gorm:"not null;index"`
+    Kind           string         `json:"kind"` // grading, office_hours, lab, meeting, prep, other
+    Title          string         `json:"title" gorm:"not null"`
+    DayOfWeek      *int           `json:"day_of_week"` // Shifts only; 0 = Sunday
+    StartTime      string         `json:"start_time"`  // Shifts only, HH:MM local time
+    EndTime        string         `json:"end_time"`
+    DueDate        *time.Time     `json:"due_date"` // Tasks only
+    EstimatedHours int            `json:"estimated_hours"`
+    Assignment     *Assignment    `json:"assignment,omitempty" gorm:"foreignKey:DutyID"`
+    CreatedAt      time.Time      `json:"created_at"`
+    UpdatedAt      time.Time      `json:"updated_at"`
+    DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+}
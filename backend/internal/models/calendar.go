@@ -0,0 +1,17 @@
+package models
+
+import (
+    "time"
+)
+
+// CalendarFeed holds the secret token that grants read access to a user's
+// iCalendar feed. Rotating the token revokes every previously shared URL.
+type CalendarFeed struct {
+    ID             uint       `json:"id" gorm:"primaryKey"`
+    UserID         uint       `json:"user_id" gorm:"uniqueIndex;not null"`
+    User           User       `json:"-" gorm:"foreignKey:UserID"`
+    Token          string     `json:"-" gorm:"uniqueIndex;not null"`
+    LastAccessedAt *time.Time `json:"last_accessed_at"`
+    CreatedAt      time.Time  `json:"created_at"`
+    UpdatedAt      time.Time  `json:"updated_at"`
+}
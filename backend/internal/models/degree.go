@@ -0,0 +1,36 @@
+package models
+
+import (
+    "time"
+    "gorm.io/gorm"
+)
+
+// DegreeProgram is a template of degree requirements. A user attaches one
+// to their profile to audit their courses against it.
+type DegreeProgram struct {
+    ID              uint           `json:"id" gorm:"primaryKey"`
+    UserID          uint           `json:"user_id" gorm:"not null;index"`
+    User            User           `json:"-" gorm:"foreignKey:UserID"`
+    Name            string         `json:"name" gorm:"not null"` // PhD in Computer Science
+    Level           string         `json:"level"`                // PhD, MS, etc., as in User.Program
+    Institution     string         `json:"institution"`
+    MinCredits      int            `json:"min_credits"`
+    MinGPA          float64        `json:"min_gpa"`
+    RequiredCourses []string       `json:"required_courses" gorm:"serializer:json"` // Course codes
+    ElectivePools   []ElectivePool `json:"elective_pools" gorm:"serializer:json"`
+    Milestones      []string       `json:"milestones" gorm:"serializer:json"` // Qualifying exam, proposal, ...
+    MilestoneNorms  map[string]int `json:"milestone_norms" gorm:"serializer:json"` // Milestone type or name -> expected months after program start
+    CreatedAt       time.Time      `json:"created_at"`
+    UpdatedAt       time.Time      `json:"updated_at"`
+    DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// ElectivePool is a set of courses to choose from. A course code ending in
+// "*" matches every code with that prefix, so "CS 7*" covers 7000-level
+// CS courses.
+type ElectivePool struct {
+    Name       string   `json:"name"`
+    Courses    []string `json:"courses"`
+    MinCourses int      `json:"min_courses"`
+    MinCredits int      `json:"min_credits"`
+}
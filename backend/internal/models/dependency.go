@@ -0,0 +1,17 @@
+package models
+
+import (
+    "time"
+)
+
+// AssignmentDependency records that an assignment cannot start until
+// BlockedBy is completed.
+type AssignmentDependency struct {
+    ID           uint       `json:"id" gorm:"primaryKey"`
+    UserID       uint       `json:"user_id" gorm:"not null;index"`
+    AssignmentID uint       `json:"assignment_id" gorm:"not null;uniqueIndex:idx_assignment_blocker"`
+    BlockedByID  uint       `json:"blocked_by_id" gorm:"not null;uniqueIndex:idx_assignment_blocker;index"`
+    Assignment   Assignment `json:"-" gorm:"foreignKey:AssignmentID"`
+    BlockedBy    *Assignment `json:"blocked_by,omitempty" gorm:"foreignKey:BlockedByID"`
+    CreatedAt    time.Time  `json:"created_at"`
+}
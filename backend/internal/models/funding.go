@@ -0,0 +1,56 @@
+package models
+
+import (
+    "time"
+    "gorm.io/gorm"
+)
+
+// Funding is a fellowship, grant, scholarship or other source of support,
+// with the conditions the user must keep meeting to hold it.
+type Funding struct {
+    ID           uint              `json:"id" gorm:"primaryKey"`
+    UserID       uint              `json:"user_id" gorm:"not null;index"`
+    User         User              `json:"-" gorm:"foreignKey:UserID"`
+    Name         string            `json:"name" gorm:"not null"`
+    Source       string            `json:"source"` // Sponsor or agency
+    Kind         string            `json:"kind"`   // fellowship, grant, scholarship, assistantship, stipend, other
+    Status       string            `json:"status"` // applying, awarded, active, ended, declined
+    Amount       float64           `json:"amount"`
+    Currency     string            `json:"currency"`
+    AmountPer    string            `json:"amount_per"` // total, year, term, month
+    StartDate    *time.Time        `json:"start_date"`
+    EndDate      *time.Time        `json:"end_date"`
+    Conditions   FundingConditions `json:"conditions" gorm:"serializer:json"`
+    ReminderDays int               `json:"reminder_days"` // Reminder assignments fall due this long before each deadline
+    Notes        string            `json:"notes"`
+    Deadlines    []FundingDeadline `json:"deadlines,omitempty" gorm:"foreignKey:FundingID"`
+    CreatedAt    time.Time         `json:"created_at"`
+    UpdatedAt    time.Time         `json:"updated_at"`
+    DeletedAt    gorm.DeletedAt    `json:"-" gorm:"index"`
+}
+
+// FundingConditions are eligibility requirements checked against the
+// user's courses. Zero values mean no requirement.
+type FundingConditions struct {
+    MinGPA     *float64 `json:"min_gpa,omitempty"`
+    GPAScaleID *uint    `json:"gpa_scale_id,omitempty"` // Scale the GPA is read from; defaults to the one with most graded credits
+    MinCredits int      `json:"min_credits,omitempty"`  // Enrolled in the current term
+    MinCourses int      `json:"min_courses,omitempty"`
+    Other      []string `json:"other,omitempty"` // Conditions that can't be checked automatically
+}
+
+// FundingDeadline is an application, renewal or report due date. Repeating
+// deadlines schedule their next occurrence when completed.
+type FundingDeadline struct {
+    ID           uint           `json:"id" gorm:"primaryKey"`
+    UserID       uint           `json:"user_id" gorm:"not null;index"`
+    FundingID    uint           `json:"funding_id" gorm:"not null;index"`
+    Kind         string         `json:"kind"` // application, renewal, report, other
+    Title        string         `json:"title"`
+    DueDate      time.Time      `json:"due_date" gorm:"not null"`
+    RepeatMonths int            `json:"repeat_months"` // 0 for a one-off deadline
+    CompletedAt  *time.Time     `json:"completed_at"`
+    CreatedAt    time.Time      `json:"created_at"`
+    UpdatedAt    time.Time      `json:"updated_at"`
+    DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+    "time"
+    "gorm.io/gorm"
+)
+
+// Goal is an objective for a week, a term or a custom period, measured by
+// its key results.
+type Goal struct {
+    ID          uint           `json:"id" gorm:"primaryKey"`
+    UserID      uint           `json:"user_id" gorm:"not null;index"`
+    User        User           `json:"-" gorm:"foreignKey:UserID"`
+    Title       string         `json:"title" gorm:"not null"`
+    Description string         `json:"description"`
+    Period      string         `json:"period"` // week, term, custom
+    TermID      *uint          `json:"term_id,omitempty" gorm:"index"`
+    StartDate   time.Time      `json:"start_date" gorm:"not null"`
+    EndDate     time.Time      `json:"end_date" gorm:"not null;index"` // Last day of the period
+    Status      string         `json:"status"`                         // active, achieved, missed, dropped
+    ReviewNotes string         `json:"review_notes"`
+    KeyResults  []KeyResult    `json:"key_results,omitempty" gorm:"foreignKey:GoalID"`
+    CheckIns    []GoalCheckIn  `json:"check_ins,omitempty" gorm:"foreignKey:GoalID"`
+    CreatedAt   time.Time      `json:"created_at"`
+    UpdatedAt   time.Time      `json:"updated_at"`
+    DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// KeyResult measures a goal, either through a linked assignment's status
+// or as progress towards a target entered by hand.
+type KeyResult struct {
+    ID           uint           `json:"id" gorm:"primaryKey"`
+    UserID       uint           `json:"user_id" gorm:"not null;index"`
+    GoalID       uint           `json:"goal_id" gorm:"not null;index"`
+    Title        string         `json:"title" gorm:"not null"`
+    AssignmentID *uint          `json:"assignment_id,omitempty" gorm:"index"`
+    Assignment   *Assignment    `json:"assignment,omitempty" gorm:"foreignKey:AssignmentID"`
+    Target       float64        `json:"target"` // Unused when an assignment is linked
+    Current      float64        `json:"current"`
+    Unit         string         `json:"unit"` // e.g. chapters, pages
+    Weight       float64        `json:"weight"`
+    CreatedAt    time.Time      `json:"created_at"`
+    UpdatedAt    time.Time      `json:"updated_at"`
+    DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// GoalCheckIn records how confident the user is of reaching a goal, with
+// the progress at the time.
+type GoalCheckIn struct {
+    ID         uint           `json:"id" gorm:"primaryKey"`
+    UserID     uint           `json:"user_id" gorm:"not null;index"`
+    GoalID     uint           `json:"goal_id" gorm:"not null;index"`
+    Confidence int            `json:"confidence"` // 1 (won't happen) to 10 (certain)
+    Progress   float64        `json:"progress"`
+    Note       string         `json:"note"`
+    CreatedAt  time.Time      `json:"created_at"`
+    DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+    "time"
+    "gorm.io/gorm"
+)
+
+// GradeCategory is a weighted component of a course grade, such as
+// homework at 30%. Graded assignments are attached to a category.
+type GradeCategory struct {
+    ID        uint           `json:"id" gorm:"primaryKey"`
+    UserID    uint           `json:"user_id" gorm:"not null;index"`
+    CourseID  uint           `json:"course_id" gorm:"not null;index"`
+    User      User           `json:"-" gorm:"foreignKey:UserID"`
+    Name      string         `json:"name" gorm:"not null"`
+    Weight    float64        `json:"weight"` // Percent of the course grade
+    CreatedAt time.Time      `json:"created_at"`
+    UpdatedAt time.Time      `json:"updated_at"`
+    DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
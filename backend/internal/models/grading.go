@@ -0,0 +1,94 @@
+package models
+
+import (
+    "strings"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// GradingScale maps letter grades to grade points for one institution. A
+// user's default scale applies to courses that don't name one.
+type GradingScale struct {
+    ID          uint           `json:"id" gorm:"primaryKey"`
+    UserID      uint           `json:"user_id" gorm:"not null;index"`
+    User        User           `json:"-" gorm:"foreignKey:UserID"`
+    Name        string         `json:"name" gorm:"not null"`
+    Institution string         `json:"institution"`
+    IsDefault   bool           `json:"is_default"`
+    MaxPoints   float64        `json:"max_points"` // 4.0, 4.3, 10, ...
+    Grades      []GradeMapping `json:"grades" gorm:"serializer:json"`
+    CreatedAt   time.Time      `json:"created_at"`
+    UpdatedAt   time.Time      `json:"updated_at"`
+    DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// GradeMapping is one grade on a scale. Points is nil for grades that
+// don't count towards GPA, such as P, W or I.
+type GradeMapping struct {
+    Letter      string   `json:"letter"`
+    Points      *float64 `json:"points"`
+    MinPercent  *float64 `json:"min_percent,omitempty"` // Lowest course percentage earning this grade
+    EarnsCredit bool     `json:"earns_credit"`
+    Attempted   bool     `json:"attempted"`            // Counts towards credits attempted
+    Incomplete  bool     `json:"incomplete,omitempty"` // Not final yet, like I or IP
+}
+
+// Lookup finds a grade on the scale, ignoring case. When the scale has no
+// plus/minus grades, "B+" falls back to "B".
+func (s *GradingScale) Lookup(grade string) (*GradeMapping, bool) {
+    grade = strings.ToUpper(strings.TrimSpace(grade))
+    for _, candidate := range []string{grade, strings.TrimRight(grade, "+-")} {
+        for i := range s.Grades {
+            if strings.ToUpper(s.Grades[i].Letter) == candidate {
+                return &s.Grades[i], true
+            }
+        }
+    }
+    return nil, false
+}
+
+// LetterFor returns the best grade whose min_percent the percentage
+// reaches. ok is false when the scale has no percentage cutoffs.
+func (s *GradingScale) LetterFor(percent float64) (letter string, ok bool) {
+    best := -1.0
+    for _, g := range s.Grades {
+        if g.MinPercent == nil || g.Points == nil {
+            continue
+        }
+        if percent >= *g.MinPercent && *g.MinPercent > best {
+            letter, best = g.Letter, *g.MinPercent
+        }
+    }
+    return letter, best >= 0
+}
+
+// DefaultGradingScale is the common US 4.0 scale with plus/minus grades,
+// used until a user sets up their own.
+func DefaultGradingScale(userID uint) *GradingScale {
+    graded := func(letter string, points, minPercent float64) GradeMapping {
+        return GradeMapping{Letter: letter, Points: &points, MinPercent: &minPercent, EarnsCredit: points > 0, Attempted: true}
+    }
+    ungraded := func(letter string, earnsCredit, attempted bool) GradeMapping {
+        return GradeMapping{Letter: letter, EarnsCredit: earnsCredit, Attempted: attempted}
+    }
+    incomplete := func(letter string) GradeMapping {
+        return GradeMapping{Letter: letter, Incomplete: true}
+    }
+    return &GradingScale{
+        UserID:    userID,
+        Name:      "US 4.0",
+        IsDefault: true,
+        MaxPoints: 4,
+        Grades: []GradeMapping{
+            graded("A+", 4.0, 97), graded("A", 4.0, 93), graded("A-", 3.7, 90),
+            graded("B+", 3.3, 87), graded("B", 3.0, 83), graded("B-", 2.7, 80),
+            graded("C+", 2.3, 77), graded("C", 2.0, 73), graded("C-", 1.7, 70),
+            graded("D+", 1.3, 67), graded("D", 1.0, 63), graded("D-", 0.7, 60),
+            graded("F", 0, 0),
+            ungraded("P", true, true), ungraded("S", true, true),
+            ungraded("NP", false, true), ungraded("U", false, true),
+            ungraded("W", false, false), incomplete("I"), incomplete("IP"),
+        },
+    }
+}
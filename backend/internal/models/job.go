@@ -0,0 +1,26 @@
+package models
+
+import (
+    "time"
+)
+
+// Job is a unit of background work. Workers lease pending jobs whose RunAt
+// has passed; failed jobs are retried with backoff until MaxAttempts, and
+// jobs with an Interval are rescheduled instead of finishing.
+type Job struct {
+    ID          uint       `json:"id" gorm:"primaryKey"`
+    Kind        string     `json:"kind" gorm:"not null;index"`
+    DedupKey    string     `json:"dedup_key" gorm:"uniqueIndex:idx_job_dedup_key,where:dedup_key <> ''"` // At most one job per key
+    Payload     string     `json:"payload"`                                                              // JSON
+    Status      string     `json:"status" gorm:"not null;index:idx_job_due,priority:1"`                  // pending, running, done, failed
+    RunAt       time.Time  `json:"run_at" gorm:"not null;index:idx_job_due,priority:2"`
+    Interval    int        `json:"interval"` // Seconds between runs of a periodic job; 0 runs once
+    Attempts    int        `json:"attempts"`
+    MaxAttempts int        `json:"max_attempts"`
+    LockedBy    string     `json:"locked_by"`
+    LockedUntil *time.Time `json:"locked_until"`
+    LastError   string     `json:"last_error"`
+    FinishedAt  *time.Time `json:"finished_at"`
+    CreatedAt   time.Time  `json:"created_at"`
+    UpdatedAt   time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+    "time"
+    "gorm.io/gorm"
+)
+
+// CourseMeeting is a weekly recurring class slot. Times are wall-clock
+// "HH:MM" in the user's local time.
+type CourseMeeting struct {
+    ID        uint           `json:"id" gorm:"primaryKey"`
+    UserID    uint           `json:"user_id" gorm:"not null;index"`
+    CourseID  uint           `json:"course_id" gorm:"not null;index"`
+    User      User           `json:"-" gorm:"foreignKey:UserID"`
+    DayOfWeek int            `json:"day_of_week"` // 0 = Sunday
+    StartTime string         `json:"start_time" gorm:"not null"`
+    EndTime   string         `json:"end_time" gorm:"not null"`
+    Location  string         `json:"location"`
+    Type      string         `json:"type"` // lecture, lab, office_hours
+    CreatedAt time.Time      `json:"created_at"`
+    UpdatedAt time.Time      `json:"updated_at"`
+    DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
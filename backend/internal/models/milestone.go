@@ -0,0 +1,38 @@
+package models
+
+import (
+    "time"
+    "gorm.io/gorm"
+)
+
+// Milestone is a major graduate program event such as the qualifying
+// exam, proposal, candidacy or defense.
+type Milestone struct {
+    ID            uint               `json:"id" gorm:"primaryKey"`
+    UserID        uint               `json:"user_id" gorm:"not null;index"`
+    User          User               `json:"-" gorm:"foreignKey:UserID"`
+    Name          string             `json:"name" gorm:"not null"`
+    Type          string             `json:"type"`   // qualifying_exam, proposal, candidacy, defense, other
+    Status        string             `json:"status"` // planned, scheduled, completed, deferred
+    TargetDate    *time.Time         `json:"target_date"`
+    CompletedDate *time.Time         `json:"completed_date"`
+    Committee     []CommitteeMember  `json:"committee" gorm:"serializer:json"`
+    Documents     []RequiredDocument `json:"documents" gorm:"serializer:json"`
+    Notes         string             `json:"notes"`
+    Assignments   []Assignment       `json:"assignments,omitempty" gorm:"foreignKey:MilestoneID"`
+    CreatedAt     time.Time          `json:"created_at"`
+    UpdatedAt     time.Time          `json:"updated_at"`
+    DeletedAt     gorm.DeletedAt     `json:"-" gorm:"index"`
+}
+
+type CommitteeMember struct {
+    Name  string `json:"name"`
+    Role  string `json:"role"` // chair, member, external
+    Email string `json:"email,omitempty"`
+}
+
+type RequiredDocument struct {
+    Name      string `json:"name"`
+    Submitted bool   `json:"submitted"`
+    URL       string `json:"url,omitempty"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+    "time"
+    "gorm.io/gorm"
+)
+
+// NotificationPreference controls which reminders a user gets and where
+// they are delivered. Reminders due during quiet hours are held until
+// they end.
+type NotificationPreference struct {
+    ID              uint           `json:"id" gorm:"primaryKey"`
+    UserID          uint           `json:"user_id" gorm:"not null;uniqueIndex:idx_notification_preference_user,where:deleted_at IS NULL"`
+    User            User           `json:"-" gorm:"foreignKey:UserID"`
+    Enabled         bool           `json:"enabled"`
+    Channels        []string       `json:"channels" gorm:"serializer:json"`         // in_app, email, webhook
+    ReminderMinutes []int          `json:"reminder_minutes" gorm:"serializer:json"` // Before each assignment's due date
+    QuietStart      string         `json:"quiet_start"`                             // HH:MM; empty for no quiet hours
+    QuietEnd        string         `json:"quiet_end"`
+    TimeZone        string         `json:"time_zone"`
+    Email           string         `json:"email"` // Defaults to the account email
+    WebhookURL      string         `json:"webhook_url"`
+    CreatedAt       time.Time      `json:"created_at"`
+    UpdatedAt       time.Time      `json:"updated_at"`
+    DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Notification is a reminder scheduled for, or already sent to, a user.
+// Assignment reminders are unique per assignment, offset and due date, so
+// moving the due date schedules fresh ones.
+type Notification struct {
+    ID            uint       `json:"id" gorm:"primaryKey"`
+    UserID        uint       `json:"user_id" gorm:"not null;index"`
+    User          User       `json:"-" gorm:"foreignKey:UserID"`
+    Kind          string     `json:"kind"` // assignment_due, test
+    AssignmentID  *uint      `json:"assignment_id,omitempty" gorm:"uniqueIndex:idx_notification_reminder"`
+    OffsetMinutes int        `json:"offset_minutes" gorm:"uniqueIndex:idx_notification_reminder"`
+    DueDate       *time.Time `json:"due_date,omitempty" gorm:"uniqueIndex:idx_notification_reminder"`
+    Title         string     `json:"title"`
+    Body          string     `json:"body"`
+    Channels      []string   `json:"channels" gorm:"serializer:json"`
+    Delivered     []string   `json:"delivered" gorm:"serializer:json"` // Channels it has gone out on
+    InApp         bool       `json:"in_app" gorm:"index"`              // Delivered to the in-app list
+    SendAt        time.Time  `json:"send_at" gorm:"index"`
+    Status        string     `json:"status"` // scheduled, sent, failed, cancelled
+    SentAt        *time.Time `json:"sent_at"`
+    ReadAt        *time.Time `json:"read_at"`
+    LastError     string     `json:"last_error"`
+    CreatedAt     time.Time  `json:"created_at"`
+    UpdatedAt     time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+    "time"
+)
+
+// Availability is how much time a user can put towards assignment work,
+// used by the workload planner.
+type Availability struct {
+    ID             uint      `json:"id" gorm:"primaryKey"`
+    UserID         uint      `json:"user_id" gorm:"uniqueIndex;not null"`
+    User           User      `json:"-" gorm:"foreignKey:UserID"`
+    WeeklyHours    []float64 `json:"weekly_hours" gorm:"serializer:json"`   // Indexed by weekday, Sunday first
+    MaxHoursPerDay float64   `json:"max_hours_per_day"`
+    BlackoutDates  []string  `json:"blackout_dates" gorm:"serializer:json"` // YYYY-MM-DD
+    TimeZone       string    `json:"timezone"`
+    CreatedAt      time.Time `json:"created_at"`
+    UpdatedAt      time.Time `json:"updated_at"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+    "time"
+    "gorm.io/gorm"
+)
+
+// Paper is a conference or journal submission followed from first draft
+// through publication.
+type Paper struct {
+    ID                  uint           `json:"id" gorm:"primaryKey"`
+    UserID              uint           `json:"user_id" gorm:"not null;index"`
+    User                User           `json:"-" gorm:"foreignKey:UserID"`
+    Title               string         `json:"title" gorm:"not null"`
+    Authors             []PaperAuthor  `json:"authors" gorm:"serializer:json"`
+    Venue               string         `json:"venue"`      // Current target, e.g. NeurIPS 2025
+    VenueType           string         `json:"venue_type"` // conference, journal, workshop
+    Status              string         `json:"status"`     // drafting, submitted, under_review, revise, accepted, published
+    SubmissionDeadline  *time.Time     `json:"submission_deadline"`
+    RebuttalDeadline    *time.Time     `json:"rebuttal_deadline"`
+    CameraReadyDeadline *time.Time     `json:"camera_ready_deadline"`
+    URL                 string         `json:"url"`
+    Notes               string         `json:"notes"`
+    Rounds              []PaperRound   `json:"rounds,omitempty" gorm:"foreignKey:PaperID"`
+    Assignments         []Assignment   `json:"assignments,omitempty" gorm:"foreignKey:PaperID"`
+    CreatedAt           time.Time      `json:"created_at"`
+    UpdatedAt           time.Time      `json:"updated_at"`
+    DeletedAt           gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type PaperAuthor struct {
+    Name          string `json:"name"`
+    Affiliation   string `json:"affiliation,omitempty"`
+    Self          bool   `json:"self"` // The account owner
+    Corresponding bool   `json:"corresponding"`
+}
+
+// PaperRound is one submission of a paper to a venue together with the
+// reviews and decision it received.
+type PaperRound struct {
+    ID           uint           `json:"id" gorm:"primaryKey"`
+    UserID       uint           `json:"user_id" gorm:"not null;index"`
+    PaperID      uint           `json:"paper_id" gorm:"not null;uniqueIndex:idx_paper_round_number"`
+    Number       int            `json:"number" gorm:"not null;uniqueIndex:idx_paper_round_number"` // Deleted rounds keep theirs, so numbers are never reused
+    Venue        string         `json:"venue"`
+    SubmittedAt  *time.Time     `json:"submitted_at"`
+    Decision     string         `json:"decision"` // pending, accept, minor_revision, major_revision, reject, withdrawn
+    DecisionDate *time.Time     `json:"decision_date"`
+    Reviews      []PaperReview  `json:"reviews" gorm:"serializer:json"`
+    Notes        string         `json:"notes"`
+    CreatedAt    time.Time      `json:"created_at"`
+    UpdatedAt    time.Time      `json:"updated_at"`
+    DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type PaperReview struct {
+    Reviewer       string   `json:"reviewer"` // Reviewer number or meta-reviewer
+    Score          *float64 `json:"score,omitempty"`
+    Confidence     *float64 `json:"confidence,omitempty"`
+    Recommendation string   `json:"recommendation,omitempty"`
+    Summary        string   `json:"summary,omitempty"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+    "time"
+    "gorm.io/gorm"
+)
+
+// Reading is an entry on the user's reading list, optionally tied to the
+// course, assignment or paper it is read for.
+type Reading struct {
+    ID           uint              `json:"id" gorm:"primaryKey"`
+    UserID       uint              `json:"user_id" gorm:"not null;index"`
+    User         User              `json:"-" gorm:"foreignKey:UserID"`
+    CiteKey      string            `json:"cite_key" gorm:"index"`
+    EntryType    string            `json:"entry_type"` // BibTeX type: article, inproceedings, book, ...
+    Title        string            `json:"title" gorm:"not null"`
+    Authors      []string          `json:"authors" gorm:"serializer:json"`
+    Year         int               `json:"year"`
+    Venue        string            `json:"venue"` // Journal, proceedings or publisher
+    DOI          string            `json:"doi" gorm:"index"`
+    URL          string            `json:"url"`
+    Abstract     string            `json:"abstract"`
+    BibFields    map[string]string `json:"bib_fields,omitempty" gorm:"serializer:json"` // Fields as imported, kept for export
+    Status       string            `json:"status"`                                      // to_read, reading, read, skipped
+    Notes        string            `json:"notes"`
+    CourseID     *uint             `json:"course_id,omitempty" gorm:"index"`
+    AssignmentID *uint             `json:"assignment_id,omitempty" gorm:"index"`
+    PaperID      *uint             `json:"paper_id,omitempty" gorm:"index"` // One of the user's own papers that cites it
+    ReadAt       *time.Time        `json:"read_at"`
+    CreatedAt    time.Time         `json:"created_at"`
+    UpdatedAt    time.Time         `json:"updated_at"`
+    DeletedAt    gorm.DeletedAt    `json:"-" gorm:"index"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+    "time"
+    "gorm.io/gorm"
+)
+
+// AssignmentSeries is the template for a recurring assignment. Concrete
+// Assignment rows are materialized from it ahead of time.
+type AssignmentSeries struct {
+    ID                uint           `json:"id" gorm:"primaryKey"`
+    UserID            uint           `json:"user_id" gorm:"not null;index"`
+    CourseID          *uint          `json:"course_id"`
+    User              User           `json:"-" gorm:"foreignKey:UserID"`
+    Course            *Course        `json:"course,omitempty" gorm:"foreignKey:CourseID"`
+    Title             string         `json:"title" gorm:"not null"`
+    Description       string         `json:"description"`
+    Priority          string         `json:"priority"`
+    EstimatedHours    int            `json:"estimated_hours"`
+    RRule             string         `json:"rrule" gorm:"column:rrule;not null"` // FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=...
+    DTStart           time.Time      `json:"dtstart" gorm:"column:dtstart;not null"` // Due date of the first occurrence
+    TimeZone          string         `json:"timezone"`                // IANA zone the rule is expanded in
+    ExDates           []time.Time    `json:"exdates" gorm:"column:exdates;serializer:json"`
+    MaterializedUntil time.Time      `json:"materialized_until"`
+    CreatedAt         time.Time      `json:"created_at"`
+    UpdatedAt         time.Time      `json:"updated_at"`
+    DeletedAt         gorm.DeletedAt `json:"-" gorm:"index"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+    "time"
+    "gorm.io/gorm"
+)
+
+// ReportSchedule controls when the weekly progress report is emailed. The
+// report for the week just ended goes out at Hour on Weekday in TimeZone.
+type ReportSchedule struct {
+    ID         uint           `json:"id" gorm:"primaryKey"`
+    UserID     uint           `json:"user_id" gorm:"not null;uniqueIndex:idx_report_schedule_user,where:deleted_at IS NULL"`
+    User       User           `json:"-" gorm:"foreignKey:UserID"`
+    Enabled    bool           `json:"enabled"`
+    Weekday    int            `json:"weekday"` // 0 = Sunday
+    Hour       int            `json:"hour"`
+    TimeZone   string         `json:"time_zone"`
+    Recipients []string       `json:"recipients" gorm:"serializer:json"` // Empty means the advisor's email
+    Format     string         `json:"format"`                            // html or text
+    NextRunAt  *time.Time     `json:"next_run_at" gorm:"index"`
+    LastSentAt *time.Time     `json:"last_sent_at"`
+    LastError  string         `json:"last_error"`
+    CreatedAt  time.Time      `json:"created_at"`
+    UpdatedAt  time.Time      `json:"updated_at"`
+    DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+}
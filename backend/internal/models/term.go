@@ -0,0 +1,99 @@
+package models
+
+import (
+    "strconv"
+    "strings"
+    "time"
+
+    "gorm.io/gorm"
+)
+
+// Season order within a calendar year, used for sorting terms.
+var termSeasons = map[string]int{"winter": 0, "spring": 1, "summer": 2, "fall": 3, "autumn": 3}
+
+// Term is an academic term. Course.Semester mirrors the name of the
+// course's term so older clients keep working.
+type Term struct {
+    ID                 uint           `json:"id" gorm:"primaryKey"`
+    UserID             uint           `json:"user_id" gorm:"not null;uniqueIndex:idx_term_name"`
+    User               User           `json:"-" gorm:"foreignKey:UserID"`
+    Name               string         `json:"name" gorm:"not null;uniqueIndex:idx_term_name,where:deleted_at IS NULL"` // Fall 2024
+    Season             string         `json:"season"` // winter, spring, summer, fall; empty for custom terms
+    Year               int            `json:"year"`
+    StartDate          time.Time      `json:"start_date" gorm:"not null"`
+    EndDate            time.Time      `json:"end_date" gorm:"not null"`
+    AddDropDeadline    *time.Time     `json:"add_drop_deadline,omitempty"`
+    WithdrawalDeadline *time.Time     `json:"withdrawal_deadline,omitempty"`
+    Breaks             []TermBreak    `json:"breaks" gorm:"serializer:json"`
+    DatesEstimated     bool           `json:"dates_estimated"` // Created from a semester string; dates are a guess until edited
+    CreatedAt          time.Time      `json:"created_at"`
+    UpdatedAt          time.Time      `json:"updated_at"`
+    DeletedAt          gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type TermBreak struct {
+    Name      string    `json:"name"`
+    StartDate time.Time `json:"start_date"`
+    EndDate   time.Time `json:"end_date"`
+}
+
+// ParseTermName reads names like "Fall 2024" or "spring 2025". The season
+// comes back lower case with "autumn" folded into "fall".
+func ParseTermName(name string) (season string, year int, ok bool) {
+    fields := strings.Fields(strings.ToLower(name))
+    if len(fields) != 2 {
+        return "", 0, false
+    }
+    year, err := strconv.Atoi(fields[1])
+    if _, known := termSeasons[fields[0]]; !known || err != nil || year < 1900 || year > 2200 {
+        return "", 0, false
+    }
+    season = fields[0]
+    if season == "autumn" {
+        season = "fall"
+    }
+    return season, year, true
+}
+
+// TermSortKey orders terms by year then season; unparseable names sort
+// last.
+func TermSortKey(name string) int {
+    season, year, ok := ParseTermName(name)
+    if !ok {
+        return 1<<31 - 1
+    }
+    return year*10 + termSeasons[season]
+}
+
+// NewEstimatedTerm builds an unsaved term for a semester string, with
+// typical US dates for the season. ok is false when the name can't be
+// parsed.
+func NewEstimatedTerm(userID uint, name string) (term *Term, ok bool) {
+    season, year, ok := ParseTermName(name)
+    if !ok {
+        return nil, false
+    }
+
+    date := func(year int, month time.Month, day int) time.Time {
+        return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+    }
+    term = &Term{
+        UserID:         userID,
+        Name:           strings.ToUpper(season[:1]) + season[1:] + " " + strconv.Itoa(year),
+        Season:         season,
+        Year:           year,
+        Breaks:         []TermBreak{},
+        DatesEstimated: true,
+    }
+    switch season {
+    case "winter":
+        term.StartDate, term.EndDate = date(year, time.January, 3), date(year, time.March, 22)
+    case "spring":
+        term.StartDate, term.EndDate = date(year, time.January, 10), date(year, time.May, 10)
+    case "summer":
+        term.StartDate, term.EndDate = date(year, time.May, 15), date(year, time.August, 10)
+    case "fall":
+        term.StartDate, term.EndDate = date(year, time.August, 20), date(year, time.December, 15)
+    }
+    return term, true
+}
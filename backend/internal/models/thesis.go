@@ -0,0 +1,53 @@
+package models
+
+import (
+    "time"
+    "gorm.io/gorm"
+)
+
+// Thesis is a thesis or dissertation written chapter by chapter towards
+// the defense.
+type Thesis struct {
+    ID          uint            `json:"id" gorm:"primaryKey"`
+    UserID      uint            `json:"user_id" gorm:"not null;index"`
+    User        User            `json:"-" gorm:"foreignKey:UserID"`
+    Title       string          `json:"title" gorm:"not null"`
+    StartDate   *time.Time      `json:"start_date"`                          // Start of the burn-up; defaults to when it was created
+    DefenseDate *time.Time      `json:"defense_date"`                        // Falls back to the defense milestone's date
+    MilestoneID *uint           `json:"milestone_id,omitempty" gorm:"index"` // Defense milestone
+    PushToken   string          `json:"-" gorm:"uniqueIndex;not null"`       // Lets editors and hooks push word counts
+    Chapters    []ThesisChapter `json:"chapters,omitempty" gorm:"foreignKey:ThesisID"`
+    CreatedAt   time.Time       `json:"created_at"`
+    UpdatedAt   time.Time       `json:"updated_at"`
+    DeletedAt   gorm.DeletedAt  `json:"-" gorm:"index"`
+}
+
+type ThesisChapter struct {
+    ID             uint           `json:"id" gorm:"primaryKey"`
+    UserID         uint           `json:"user_id" gorm:"not null;index"`
+    ThesisID       uint           `json:"thesis_id" gorm:"not null;index"`
+    Position       int            `json:"position"`
+    Key            string         `json:"key"` // Matched by word count pushes, e.g. a file name
+    Title          string         `json:"title" gorm:"not null"`
+    TargetWords    int            `json:"target_words"`
+    CurrentWords   int            `json:"current_words"`
+    Status         string         `json:"status"` // draft, review, final
+    Notes          string         `json:"notes"`
+    WordsUpdatedAt *time.Time     `json:"words_updated_at"`
+    CreatedAt      time.Time      `json:"created_at"`
+    UpdatedAt      time.Time      `json:"updated_at"`
+    DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// WordCountSample records a chapter's word count, at most one per chapter
+// per day, for the burn-up chart.
+type WordCountSample struct {
+    ID         uint      `json:"id" gorm:"primaryKey"`
+    UserID     uint      `json:"user_id" gorm:"not null;index"`
+    ThesisID   uint      `json:"thesis_id" gorm:"not null;index"`
+    ChapterID  uint      `json:"chapter_id" gorm:"not null;uniqueIndex:idx_word_count_day"`
+    Day        string    `json:"day" gorm:"not null;uniqueIndex:idx_word_count_day"` // YYYY-MM-DD
+    Words      int       `json:"words"`
+    Source     string    `json:"source"` // api, or whatever the pushing tool calls itself
+    RecordedAt time.Time `json:"recorded_at"`
+}
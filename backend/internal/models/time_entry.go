@@ -0,0 +1,23 @@
+package models
+
+import (
+    "time"
+    "gorm.io/gorm"
+)
+
+// TimeEntry is a span of work on an assignment. EndedAt is nil while the
+// timer is running; a user can only have one running entry at a time.
+type TimeEntry struct {
+    ID           uint           `json:"id" gorm:"primaryKey"`
+    UserID       uint           `json:"user_id" gorm:"not null;index;uniqueIndex:idx_running_timer,where:ended_at IS NULL AND deleted_at IS NULL"`
+    AssignmentID uint           `json:"assignment_id" gorm:"not null;index"`
+    User         User           `json:"-" gorm:"foreignKey:UserID"`
+    Assignment   *Assignment    `json:"assignment,omitempty" gorm:"foreignKey:AssignmentID"`
+    StartedAt    time.Time      `json:"started_at" gorm:"not null"`
+    EndedAt      *time.Time     `json:"ended_at"`
+    Minutes      int            `json:"minutes"` // 0 while running
+    Note         string         `json:"note"`
+    CreatedAt    time.Time      `json:"created_at"`
+    UpdatedAt    time.Time      `json:"updated_at"`
+    DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
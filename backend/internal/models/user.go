@@ -15,6 +15,11 @@ type User struct {
     Program   string         `json:"program"`   // PhD, MS, etc.
     Year      int            `json:"year"`      // Year in program
     Advisor   string         `json:"advisor"`
+    AdvisorEmail string      `json:"advisor_email"` // Default recipient of the weekly report
+    AdvisorEmailChangedAt *time.Time `json:"-"`     // Reports to a new advisor email wait out a cooldown
+    ReportSentAt *time.Time  `json:"-"`             // Last weekly report the user sent by hand
+    DegreeProgramID *uint    `json:"degree_program_id,omitempty"` // Requirements the degree audit checks
+    ProgramStartDate *time.Time `json:"program_start_date,omitempty"` // For time-to-degree projections
     CreatedAt time.Time      `json:"created_at"`
     UpdatedAt time.Time      `json:"updated_at"`
     DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
@@ -22,15 +27,20 @@ type User struct {
 
 type Course struct {
     ID          uint           `json:"id" gorm:"primaryKey"`
-    UserID      uint           `json:"user_id" gorm:"not null"`
+    UserID      uint           `json:"user_id" gorm:"not null;uniqueIndex:idx_course_external_key"`
     User        User           `json:"-" gorm:"foreignKey:UserID"`
+    ExternalKey string         `json:"external_key,omitempty" gorm:"uniqueIndex:idx_course_external_key,where:external_key <> '' AND deleted_at IS NULL"` // Key from a bulk import source
     CourseName  string         `json:"course_name" gorm:"not null"`
     CourseCode  string         `json:"course_code" gorm:"not null"`
     Instructor  string         `json:"instructor"`
     Credits     int            `json:"credits"`
     Semester    string         `json:"semester"` // Fall 2024, Spring 2025, etc.
+    TermID      *uint          `json:"term_id,omitempty" gorm:"index"`
+    Term        *Term          `json:"term,omitempty" gorm:"foreignKey:TermID"`
     Grade       string         `json:"grade"`
+    GradingScaleID *uint       `json:"grading_scale_id,omitempty" gorm:"index"` // Defaults to the user's default scale
     Status      string         `json:"status"` // enrolled, completed, dropped
+    Meetings    []CourseMeeting `json:"meetings,omitempty" gorm:"foreignKey:CourseID"`
     CreatedAt   time.Time      `json:"created_at"`
     UpdatedAt   time.Time      `json:"updated_at"`
     DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
@@ -38,7 +48,7 @@ type Course struct {
 
 type Assignment struct {
     ID          uint           `json:"id" gorm:"primaryKey"`
-    UserID      uint           `json:"user_id" gorm:"not null"`
+    UserID      uint           `json:"user_id" gorm:"not null;uniqueIndex:idx_assignment_external_key"`
     CourseID    *uint          `json:"course_id"` // Optional - can be independent
     User        User           `json:"-" gorm:"foreignKey:UserID"`
     Course      *Course        `json:"course,omitempty" gorm:"foreignKey:CourseID"`
@@ -47,8 +57,27 @@ type Assignment struct {
     DueDate     time.Time      `json:"due_date"`
     Priority    string         `json:"priority"` // high, medium, low
     Status      string         `json:"status"`   // pending, in_progress, completed
+    CompletedAt *time.Time     `json:"completed_at,omitempty" gorm:"index"` // When the status last became completed
+    Tags        []string       `json:"tags,omitempty" gorm:"serializer:json"` // Free-form labels, lower case
     EstimatedHours int         `json:"estimated_hours"`
-    ActualHours    int         `json:"actual_hours"`
+    RawEstimatedHours int      `json:"raw_estimated_hours,omitempty"` // User's own estimate when a correction factor was applied
+    ActualHours    int         `json:"actual_hours"`    // Derived from time entries once any are logged
+    TrackedMinutes int         `json:"tracked_minutes"` // Sum of completed time entries
+    SeriesID       *uint       `json:"series_id,omitempty" gorm:"index"` // Set when generated from a recurring series
+    OccurrenceDate *time.Time  `json:"occurrence_date,omitempty"`        // Original due date in the series
+    IsException    bool        `json:"is_exception"`                     // Edited individually; series edits skip it
+    ICalUID        string      `json:"ical_uid,omitempty" gorm:"index"`  // UID of the calendar entry it was imported from
+    CalDAVName     string      `json:"-" gorm:"column:caldav_name;index"` // Resource name chosen by a CalDAV client
+    ExternalKey    string      `json:"external_key,omitempty" gorm:"uniqueIndex:idx_assignment_external_key,where:external_key <> '' AND deleted_at IS NULL"` // Key from a bulk import source
+    CategoryID     *uint       `json:"category_id,omitempty" gorm:"index"` // Grade category it counts towards
+    MilestoneID    *uint       `json:"milestone_id,omitempty" gorm:"index"` // Research milestone it works towards
+    PaperID        *uint       `json:"paper_id,omitempty" gorm:"index"`     // Set when generated from a paper deadline
+    PaperDeadline  string      `json:"paper_deadline,omitempty"`            // submission, rebuttal or camera_ready
+    AdvisorMeetingID *uint     `json:"advisor_meeting_id,omitempty" gorm:"index"` // Meeting whose action item it was promoted from
+    DutyID         *uint       `json:"duty_id,omitempty" gorm:"index"`      // Set when generated from a TA/RA duty
+    FundingDeadlineID *uint    `json:"funding_deadline_id,omitempty" gorm:"index"` // Reminder ahead of a funding deadline
+    Score          *float64    `json:"score,omitempty"`                     // nil until graded
+    MaxScore       *float64    `json:"max_score,omitempty"`
     CreatedAt   time.Time      `json:"created_at"`
     UpdatedAt   time.Time      `json:"updated_at"`
     DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
@@ -0,0 +1,207 @@
+// Package notify delivers notifications to users over pluggable channels.
+// A Channel only has to know how to reach one recipient; scheduling,
+// retries and preferences are handled by the caller.
+package notify
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net"
+    "net/http"
+    "net/netip"
+    "sort"
+    "syscall"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/mail"
+)
+
+const (
+    ChannelInApp   = "in_app"
+    ChannelEmail   = "email"
+    ChannelWebhook = "webhook"
+)
+
+// ErrNoAddress means the recipient hasn't given an address for the
+// channel. Retrying won't help.
+var ErrNoAddress = errors.New("no address for this channel")
+
+// ErrBlockedAddress means a webhook resolved to an address on the server's
+// own networks. Retrying won't help.
+var ErrBlockedAddress = errors.New("webhook address is not publicly routable")
+
+type Message struct {
+    ID    uint      `json:"id"`
+    Kind  string    `json:"kind"`
+    Title string    `json:"title"`
+    Body  string    `json:"body"`
+    Link  string    `json:"link,omitempty"`
+    At    time.Time `json:"at"`
+}
+
+// Recipient holds the addresses a user can be reached at.
+type Recipient struct {
+    UserID     uint
+    Name       string
+    Email      string
+    WebhookURL string
+}
+
+type Channel interface {
+    Name() string
+    Send(ctx context.Context, to Recipient, msg Message) error
+}
+
+// Channels looks channels up by name.
+type Channels map[string]Channel
+
+// NewChannels returns the built-in channels: in-app, email through mailer
+// and webhooks.
+func NewChannels(mailer mail.Mailer) Channels {
+    channels := Channels{}
+    channels.Register(InApp{})
+    channels.Register(Email{Mailer: mailer})
+    channels.Register(Webhook{Client: NewWebhookClient(10 * time.Second)})
+    return channels
+}
+
+// Register adds a channel, replacing any with the same name.
+func (c Channels) Register(channel Channel) {
+    c[channel.Name()] = channel
+}
+
+func (c Channels) Names() []string {
+    names := make([]string, 0, len(c))
+    for name := range c {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// InApp delivers by doing nothing: the stored notification is what the app
+// shows.
+type InApp struct{}
+
+func (InApp) Name() string { return ChannelInApp }
+
+func (InApp) Send(ctx context.Context, to Recipient, msg Message) error { return nil }
+
+type Email struct {
+    Mailer mail.Mailer
+}
+
+func (Email) Name() string { return ChannelEmail }
+
+func (e Email) Send(ctx context.Context, to Recipient, msg Message) error {
+    if to.Email == "" {
+        return ErrNoAddress
+    }
+    text := msg.Body
+    if msg.Link != "" {
+        text += "\n\n" + msg.Link
+    }
+    return e.Mailer.Send(mail.Message{
+        To:      []string{to.Email},
+        Subject: msg.Title,
+        Text:    text + "\n",
+    })
+}
+
+// NewWebhookClient returns a client for user-supplied URLs. It only
+// connects to public addresses, checked after DNS resolution so a hostname
+// can't point it at the server's own network, and doesn't follow
+// redirects. Proxies are bypassed for the same reason.
+func NewWebhookClient(timeout time.Duration) *http.Client {
+    dialer := &net.Dialer{
+        Timeout: timeout,
+        Control: func(network, address string, _ syscall.RawConn) error {
+            addr, err := netip.ParseAddrPort(address)
+            if err != nil {
+                return err
+            }
+            if !isPublic(addr.Addr()) {
+                return fmt.Errorf("%w: %s", ErrBlockedAddress, addr.Addr())
+            }
+            return nil
+        },
+    }
+    return &http.Client{
+        Timeout: timeout,
+        Transport: &http.Transport{
+            DialContext:         dialer.DialContext,
+            TLSHandshakeTimeout: timeout,
+            MaxIdleConns:        10,
+            IdleConnTimeout:     90 * time.Second,
+        },
+        CheckRedirect: func(*http.Request, []*http.Request) error {
+            return http.ErrUseLastResponse
+        },
+    }
+}
+
+// isPublic reports whether addr is a unicast address outside loopback,
+// private (including IPv6 unique local), link-local and other reserved
+// ranges.
+func isPublic(addr netip.Addr) bool {
+    addr = addr.Unmap()
+    if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+        return false
+    }
+    for _, prefix := range reservedPrefixes {
+        if prefix.Contains(addr) {
+            return false
+        }
+    }
+    return true
+}
+
+// reservedPrefixes are the ranges IsGlobalUnicast and IsPrivate let
+// through that still don't reach the public internet.
+var reservedPrefixes = []netip.Prefix{
+    netip.MustParsePrefix("0.0.0.0/8"),      // This network
+    netip.MustParsePrefix("100.64.0.0/10"),  // Carrier-grade NAT
+    netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+    netip.MustParsePrefix("198.18.0.0/15"),  // Benchmarking
+    netip.MustParsePrefix("240.0.0.0/4"),    // Reserved
+    netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, which can embed any IPv4 address
+    netip.MustParsePrefix("64:ff9b:1::/48"), // Local-use NAT64
+    netip.MustParsePrefix("2001:db8::/32"),  // Documentation
+}
+
+// Webhook POSTs the message as JSON, e.g. to a Slack or Discord bridge.
+// Any 2xx response counts as delivered; a redirect does not. Build the
+// client with NewWebhookClient.
+type Webhook struct {
+    Client *http.Client
+}
+
+func (Webhook) Name() string { return ChannelWebhook }
+
+func (w Webhook) Send(ctx context.Context, to Recipient, msg Message) error {
+    if to.WebhookURL == "" {
+        return ErrNoAddress
+    }
+    body, err := json.Marshal(msg)
+    if err != nil {
+        return err
+    }
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, to.WebhookURL, bytes.NewReader(body))
+    if err != nil {
+        return err
+    }
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("User-Agent", "AcademiaFlow-Webhook/1.0")
+    resp, err := w.Client.Do(req)
+    if err != nil {
+        return err
+    }
+    resp.Body.Close()
+    if resp.StatusCode < 200 || resp.StatusCode > 299 {
+        return fmt.Errorf("webhook returned %s", resp.Status)
+    }
+    return nil
+}
@@ -0,0 +1,51 @@
+package notify
+
+import (
+    "net/netip"
+    "testing"
+)
+
+func TestIsPublic(t *testing.T) {
+    tests := []struct {
+        addr string
+        want bool
+    }{
+        {"8.8.8.8", true},
+        {"1.1.1.1", true},
+        {"10.0.0.1", false},
+        {"172.16.0.1", false},
+        {"192.168.1.1", false},
+        {"127.0.0.1", false},
+        {"169.254.169.254", false}, // Cloud metadata
+        {"100.64.0.1", false},
+        {"0.0.0.0", false},
+        {"198.18.0.1", false},
+        {"224.0.0.1", false},
+        {"255.255.255.255", false},
+
+        {"2606:4700:4700::1111", true},
+        {"::1", false},
+        {"::", false},
+        {"fe80::1", false},
+        {"fc00::1", false},
+        {"fd12:3456::1", false},
+        {"ff02::1", false},
+        {"2001:db8::1", false},
+
+        // NAT64 can reach any IPv4 address, so it is refused outright
+        {"64:ff9b::7f00:1", false},
+        {"64:ff9b::808:808", false},
+        {"64:ff9b:1::a00:1", false},
+
+        // IPv4-mapped addresses are judged by the IPv4 address
+        {"::ffff:127.0.0.1", false},
+        {"::ffff:10.0.0.1", false},
+        {"::ffff:169.254.169.254", false},
+        {"::ffff:8.8.8.8", true},
+    }
+    for _, tt := range tests {
+        if got := isPublic(netip.MustParseAddr(tt.addr)); got != tt.want {
+            t.Errorf("isPublic(%s) = %v, want %v", tt.addr, got, tt.want)
+        }
+    }
+}
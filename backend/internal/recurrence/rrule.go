@@ -0,0 +1,397 @@
+package recurrence
+
+import (
+    "errors"
+    "fmt"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+)
+
+type Frequency string
+
+const (
+    Daily   Frequency = "DAILY"
+    Weekly  Frequency = "WEEKLY"
+    Monthly Frequency = "MONTHLY"
+)
+
+// Upper bound on generated occurrences so a rule without UNTIL/COUNT
+// can never expand forever.
+const MaxOccurrences = 1000
+
+// WeekdayNum is a BYDAY entry. Ordinal is only meaningful for MONTHLY
+// rules (1MO = first Monday, -1FR = last Friday); 0 means every such day.
+type WeekdayNum struct {
+    Ordinal int
+    Weekday time.Weekday
+}
+
+// Rule is the supported subset of an RFC 5545 RRULE.
+type Rule struct {
+    Freq       Frequency
+    Interval   int
+    ByDay      []WeekdayNum
+    ByMonthDay []int
+    Until      *time.Time
+    Count      int
+}
+
+var weekdayCodes = map[string]time.Weekday{
+    "SU": time.Sunday,
+    "MO": time.Monday,
+    "TU": time.Tuesday,
+    "WE": time.Wednesday,
+    "TH": time.Thursday,
+    "FR": time.Friday,
+    "SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Parse reads an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10".
+// A leading "RRULE:" prefix is accepted.
+func Parse(value string) (*Rule, error) {
+    value = strings.TrimSpace(value)
+    value = strings.TrimPrefix(value, "RRULE:")
+    if value == "" {
+        return nil, errors.New("empty recurrence rule")
+    }
+
+    rule := &Rule{Interval: 1}
+    for _, part := range strings.Split(value, ";") {
+        if part == "" {
+            continue
+        }
+        kv := strings.SplitN(part, "=", 2)
+        if len(kv) != 2 {
+            return nil, fmt.Errorf("invalid rule part %q", part)
+        }
+        key, val := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+        switch key {
+        case "FREQ":
+            switch Frequency(val) {
+            case Daily, Weekly, Monthly:
+                rule.Freq = Frequency(val)
+            default:
+                return nil, fmt.Errorf("unsupported frequency %q", val)
+            }
+        case "INTERVAL":
+            n, err := strconv.Atoi(val)
+            if err != nil || n < 1 {
+                return nil, fmt.Errorf("invalid interval %q", val)
+            }
+            rule.Interval = n
+        case "COUNT":
+            n, err := strconv.Atoi(val)
+            if err != nil || n < 1 {
+                return nil, fmt.Errorf("invalid count %q", val)
+            }
+            rule.Count = n
+        case "UNTIL":
+            t, err := ParseDateTime(val, time.UTC)
+            if err != nil {
+                return nil, fmt.Errorf("invalid until %q", val)
+            }
+            rule.Until = &t
+        case "BYDAY":
+            for _, code := range strings.Split(val, ",") {
+                wd, err := parseWeekdayNum(code)
+                if err != nil {
+                    return nil, err
+                }
+                rule.ByDay = append(rule.ByDay, wd)
+            }
+        case "BYMONTHDAY":
+            for _, s := range strings.Split(val, ",") {
+                n, err := strconv.Atoi(s)
+                if err != nil || n == 0 || n < -31 || n > 31 {
+                    return nil, fmt.Errorf("invalid month day %q", s)
+                }
+                rule.ByMonthDay = append(rule.ByMonthDay, n)
+            }
+        case "WKST":
+            // Weeks always start on Monday here; accept but ignore.
+        default:
+            return nil, fmt.Errorf("unsupported rule part %q", key)
+        }
+    }
+
+    if rule.Freq == "" {
+        return nil, errors.New("recurrence rule requires FREQ")
+    }
+    if rule.Count > 0 && rule.Until != nil {
+        return nil, errors.New("COUNT and UNTIL cannot both be set")
+    }
+    if rule.Freq != Monthly {
+        for _, wd := range rule.ByDay {
+            if wd.Ordinal != 0 {
+                return nil, errors.New("ordinal BYDAY is only supported for MONTHLY rules")
+            }
+        }
+        if len(rule.ByMonthDay) > 0 {
+            return nil, errors.New("BYMONTHDAY is only supported for MONTHLY rules")
+        }
+    }
+
+    return rule, nil
+}
+
+func parseWeekdayNum(code string) (WeekdayNum, error) {
+    code = strings.TrimSpace(code)
+    if len(code) < 2 {
+        return WeekdayNum{}, fmt.Errorf("invalid weekday %q", code)
+    }
+    wd, ok := weekdayCodes[code[len(code)-2:]]
+    if !ok {
+        return WeekdayNum{}, fmt.Errorf("invalid weekday %q", code)
+    }
+    ordinal := 0
+    if prefix := code[:len(code)-2]; prefix != "" {
+        n, err := strconv.Atoi(prefix)
+        if err != nil || n == 0 || n < -5 || n > 5 {
+            return WeekdayNum{}, fmt.Errorf("invalid weekday %q", code)
+        }
+        ordinal = n
+    }
+    return WeekdayNum{Ordinal: ordinal, Weekday: wd}, nil
+}
+
+// String renders the rule back to its RRULE value form.
+func (r *Rule) String() string {
+    parts := []string{"FREQ=" + string(r.Freq)}
+    if r.Interval > 1 {
+        parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+    }
+    if len(r.ByDay) > 0 {
+        codes := make([]string, len(r.ByDay))
+        for i, wd := range r.ByDay {
+            codes[i] = weekdayNames[wd.Weekday]
+            if wd.Ordinal != 0 {
+                codes[i] = strconv.Itoa(wd.Ordinal) + codes[i]
+            }
+        }
+        parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+    }
+    if len(r.ByMonthDay) > 0 {
+        days := make([]string, len(r.ByMonthDay))
+        for i, d := range r.ByMonthDay {
+            days[i] = strconv.Itoa(d)
+        }
+        parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+    }
+    if r.Until != nil {
+        parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+    }
+    if r.Count > 0 {
+        parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+    }
+    return strings.Join(parts, ";")
+}
+
+// Between returns the occurrences of the rule anchored at dtstart that fall
+// within [from, to), skipping any exdates. COUNT is applied before exdates
+// are removed, as RFC 5545 requires. Wall-clock times are kept in dtstart's
+// location so a 9am deadline stays at 9am across DST changes.
+func (r *Rule) Between(dtstart time.Time, exdates []time.Time, from, to time.Time) []time.Time {
+    var result []time.Time
+    r.iterate(dtstart, func(t time.Time) bool {
+        if !t.Before(to) {
+            return false
+        }
+        if !t.Before(from) && !containsTime(exdates, t) {
+            result = append(result, t)
+        }
+        return true
+    })
+    return result
+}
+
+// All returns every occurrence of a bounded rule, capped at MaxOccurrences.
+func (r *Rule) All(dtstart time.Time, exdates []time.Time) []time.Time {
+    var result []time.Time
+    r.iterate(dtstart, func(t time.Time) bool {
+        if !containsTime(exdates, t) {
+            result = append(result, t)
+        }
+        return true
+    })
+    return result
+}
+
+// IndexOf reports how many occurrences (including exdated ones) come before
+// t, which is what COUNT needs when a series is split.
+func (r *Rule) IndexOf(dtstart, t time.Time) int {
+    n := 0
+    r.iterate(dtstart, func(occ time.Time) bool {
+        if !occ.Before(t) {
+            return false
+        }
+        n++
+        return true
+    })
+    return n
+}
+
+func (r *Rule) iterate(dtstart time.Time, yield func(time.Time) bool) {
+    emitted := 0
+    emit := func(t time.Time) bool {
+        if t.Before(dtstart) {
+            return true
+        }
+        if r.Until != nil && t.After(*r.Until) {
+            return false
+        }
+        if r.Count > 0 && emitted >= r.Count {
+            return false
+        }
+        emitted++
+        if emitted > MaxOccurrences {
+            return false
+        }
+        return yield(t)
+    }
+
+    interval := r.Interval
+    if interval < 1 {
+        interval = 1
+    }
+    loc := dtstart.Location()
+    hour, min, sec := dtstart.Clock()
+
+    // Periods that produce nothing (e.g. BYMONTHDAY=31 in short months)
+    // still count towards this guard so a bad rule cannot spin.
+    for period := 0; period < MaxOccurrences*4; period++ {
+        var candidates []time.Time
+
+        switch r.Freq {
+        case Daily:
+            day := dtstart.AddDate(0, 0, period*interval)
+            if len(r.ByDay) == 0 || r.matchesWeekday(day.Weekday()) {
+                candidates = append(candidates, day)
+            }
+        case Weekly:
+            // Weeks start on Monday (WKST=MO).
+            offset := (int(dtstart.Weekday()) + 6) % 7
+            weekStart := time.Date(dtstart.Year(), dtstart.Month(), dtstart.Day()-offset+period*7*interval, hour, min, sec, 0, loc)
+            if len(r.ByDay) == 0 {
+                candidates = append(candidates, weekStart.AddDate(0, 0, offset))
+            } else {
+                for i := 0; i < 7; i++ {
+                    day := weekStart.AddDate(0, 0, i)
+                    if r.matchesWeekday(day.Weekday()) {
+                        candidates = append(candidates, day)
+                    }
+                }
+            }
+        case Monthly:
+            first := time.Date(dtstart.Year(), dtstart.Month()+time.Month(period*interval), 1, hour, min, sec, 0, loc)
+            candidates = r.monthlyCandidates(first, dtstart.Day())
+        default:
+            return
+        }
+
+        for _, t := range candidates {
+            if !emit(t) {
+                return
+            }
+        }
+    }
+}
+
+func (r *Rule) matchesWeekday(wd time.Weekday) bool {
+    for _, d := range r.ByDay {
+        if d.Weekday == wd {
+            return true
+        }
+    }
+    return false
+}
+
+func (r *Rule) monthlyCandidates(first time.Time, defaultDay int) []time.Time {
+    daysInMonth := first.AddDate(0, 1, -1).Day()
+    seen := map[int]bool{}
+
+    if len(r.ByMonthDay) > 0 {
+        for _, d := range r.ByMonthDay {
+            if d < 0 {
+                d = daysInMonth + d + 1
+            }
+            if d >= 1 && d <= daysInMonth {
+                seen[d] = true
+            }
+        }
+    }
+
+    if len(r.ByDay) > 0 {
+        byDay := map[int]bool{}
+        for _, wd := range r.ByDay {
+            var matches []int
+            for d := 1; d <= daysInMonth; d++ {
+                if first.AddDate(0, 0, d-1).Weekday() == wd.Weekday {
+                    matches = append(matches, d)
+                }
+            }
+            switch {
+            case wd.Ordinal == 0:
+                for _, d := range matches {
+                    byDay[d] = true
+                }
+            case wd.Ordinal > 0 && wd.Ordinal <= len(matches):
+                byDay[matches[wd.Ordinal-1]] = true
+            case wd.Ordinal < 0 && -wd.Ordinal <= len(matches):
+                byDay[matches[len(matches)+wd.Ordinal]] = true
+            }
+        }
+        if len(r.ByMonthDay) > 0 {
+            // Both parts present: a day must satisfy both.
+            for d := range seen {
+                if !byDay[d] {
+                    delete(seen, d)
+                }
+            }
+        } else {
+            seen = byDay
+        }
+    }
+
+    if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 && defaultDay <= daysInMonth {
+        seen[defaultDay] = true
+    }
+
+    days := make([]int, 0, len(seen))
+    for d := range seen {
+        days = append(days, d)
+    }
+    sort.Ints(days)
+
+    result := make([]time.Time, len(days))
+    for i, d := range days {
+        result[i] = first.AddDate(0, 0, d-1)
+    }
+    return result
+}
+
+func containsTime(list []time.Time, t time.Time) bool {
+    for _, x := range list {
+        if x.Equal(t) {
+            return true
+        }
+    }
+    return false
+}
+
+// ParseDateTime parses the iCalendar DATE and DATE-TIME forms. Values with
+// a trailing Z are UTC; floating values are interpreted in loc.
+func ParseDateTime(value string, loc *time.Location) (time.Time, error) {
+    switch {
+    case strings.HasSuffix(value, "Z"):
+        return time.Parse("20060102T150405Z", value)
+    case strings.Contains(value, "T"):
+        return time.ParseInLocation("20060102T150405", value, loc)
+    default:
+        return time.ParseInLocation("20060102", value, loc)
+    }
+}
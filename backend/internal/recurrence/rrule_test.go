@@ -0,0 +1,181 @@
+package recurrence
+
+import (
+    "testing"
+    "time"
+)
+
+func TestParse(t *testing.T) {
+    tests := []struct {
+        value string
+        want  string
+        err   bool
+    }{
+        {value: "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10", want: "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10"},
+        {value: "RRULE:freq=daily;interval=2", want: "FREQ=DAILY;INTERVAL=2"},
+        {value: "FREQ=MONTHLY;BYDAY=-1FR", want: "FREQ=MONTHLY;BYDAY=-1FR"},
+        {value: "FREQ=MONTHLY;UNTIL=20250131T235959Z", want: "FREQ=MONTHLY;UNTIL=20250131T235959Z"},
+        {value: "", err: true},
+        {value: "BYDAY=MO", err: true},
+        {value: "FREQ=YEARLY", err: true},
+        {value: "FREQ=DAILY;COUNT=0", err: true},
+        {value: "FREQ=DAILY;COUNT=3;UNTIL=20250101T000000Z", err: true},
+        {value: "FREQ=WEEKLY;BYDAY=1MO", err: true},
+        {value: "FREQ=WEEKLY;BYMONTHDAY=1", err: true},
+        {value: "FREQ=MONTHLY;BYMONTHDAY=32", err: true},
+        {value: "FREQ=MONTHLY;BYDAY=XX", err: true},
+    }
+    for _, tt := range tests {
+        rule, err := Parse(tt.value)
+        if tt.err {
+            if err == nil {
+                t.Errorf("Parse(%q) = %q, want error", tt.value, rule.String())
+            }
+            continue
+        }
+        if err != nil {
+            t.Errorf("Parse(%q): %v", tt.value, err)
+            continue
+        }
+        if got := rule.String(); got != tt.want {
+            t.Errorf("Parse(%q).String() = %q, want %q", tt.value, got, tt.want)
+        }
+    }
+}
+
+func TestAll(t *testing.T) {
+    ny, err := time.LoadLocation("America/New_York")
+    if err != nil {
+        t.Skip("no tzdata:", err)
+    }
+    at := func(loc *time.Location, year int, month time.Month, day, hour int) time.Time {
+        return time.Date(year, month, day, hour, 0, 0, 0, loc)
+    }
+
+    tests := []struct {
+        name    string
+        rule    string
+        dtstart time.Time
+        exdates []time.Time
+        want    []time.Time
+    }{
+        {
+            name:    "daily count",
+            rule:    "FREQ=DAILY;COUNT=3",
+            dtstart: at(time.UTC, 2025, 1, 30, 9),
+            want:    []time.Time{at(time.UTC, 2025, 1, 30, 9), at(time.UTC, 2025, 1, 31, 9), at(time.UTC, 2025, 2, 1, 9)},
+        },
+        {
+            name:    "count includes exdates",
+            rule:    "FREQ=DAILY;COUNT=3",
+            dtstart: at(time.UTC, 2025, 1, 1, 9),
+            exdates: []time.Time{at(time.UTC, 2025, 1, 2, 9)},
+            want:    []time.Time{at(time.UTC, 2025, 1, 1, 9), at(time.UTC, 2025, 1, 3, 9)},
+        },
+        {
+            name:    "until is inclusive",
+            rule:    "FREQ=WEEKLY;UNTIL=20250115T090000Z",
+            dtstart: at(time.UTC, 2025, 1, 1, 9),
+            want:    []time.Time{at(time.UTC, 2025, 1, 1, 9), at(time.UTC, 2025, 1, 8, 9), at(time.UTC, 2025, 1, 15, 9)},
+        },
+        {
+            name:    "weekly byday starting midweek",
+            rule:    "FREQ=WEEKLY;BYDAY=MO,FR;COUNT=4",
+            dtstart: at(time.UTC, 2025, 1, 8, 17), // Wednesday
+            want: []time.Time{
+                at(time.UTC, 2025, 1, 10, 17), at(time.UTC, 2025, 1, 13, 17),
+                at(time.UTC, 2025, 1, 17, 17), at(time.UTC, 2025, 1, 20, 17),
+            },
+        },
+        {
+            name:    "biweekly byday",
+            rule:    "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;COUNT=3",
+            dtstart: at(time.UTC, 2025, 1, 7, 9),
+            want:    []time.Time{at(time.UTC, 2025, 1, 7, 9), at(time.UTC, 2025, 1, 21, 9), at(time.UTC, 2025, 2, 4, 9)},
+        },
+        {
+            name:    "daily byday skips weekends",
+            rule:    "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;COUNT=3",
+            dtstart: at(time.UTC, 2025, 1, 9, 9), // Thursday
+            want:    []time.Time{at(time.UTC, 2025, 1, 9, 9), at(time.UTC, 2025, 1, 10, 9), at(time.UTC, 2025, 1, 13, 9)},
+        },
+        {
+            name:    "monthly on the 31st skips short months",
+            rule:    "FREQ=MONTHLY;BYMONTHDAY=31;COUNT=3",
+            dtstart: at(time.UTC, 2025, 1, 31, 9),
+            want:    []time.Time{at(time.UTC, 2025, 1, 31, 9), at(time.UTC, 2025, 3, 31, 9), at(time.UTC, 2025, 5, 31, 9)},
+        },
+        {
+            name:    "monthly on the last day",
+            rule:    "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=4",
+            dtstart: at(time.UTC, 2024, 1, 31, 9),
+            want: []time.Time{
+                at(time.UTC, 2024, 1, 31, 9), at(time.UTC, 2024, 2, 29, 9),
+                at(time.UTC, 2024, 3, 31, 9), at(time.UTC, 2024, 4, 30, 9),
+            },
+        },
+        {
+            name:    "monthly without byday keeps dtstart's day",
+            rule:    "FREQ=MONTHLY;COUNT=2",
+            dtstart: at(time.UTC, 2025, 1, 30, 9),
+            want:    []time.Time{at(time.UTC, 2025, 1, 30, 9), at(time.UTC, 2025, 3, 30, 9)},
+        },
+        {
+            name:    "monthly last friday",
+            rule:    "FREQ=MONTHLY;BYDAY=-1FR;COUNT=2",
+            dtstart: at(time.UTC, 2025, 1, 1, 12),
+            want:    []time.Time{at(time.UTC, 2025, 1, 31, 12), at(time.UTC, 2025, 2, 28, 12)},
+        },
+        {
+            name:    "wall clock kept across dst",
+            rule:    "FREQ=WEEKLY;COUNT=2",
+            dtstart: at(ny, 2025, 3, 5, 9),
+            want:    []time.Time{at(ny, 2025, 3, 5, 9), at(ny, 2025, 3, 12, 9)},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            rule, err := Parse(tt.rule)
+            if err != nil {
+                t.Fatalf("Parse(%q): %v", tt.rule, err)
+            }
+            got := rule.All(tt.dtstart, tt.exdates)
+            if len(got) != len(tt.want) {
+                t.Fatalf("got %d occurrences %v, want %d %v", len(got), got, len(tt.want), tt.want)
+            }
+            for i := range got {
+                if !got[i].Equal(tt.want[i]) {
+                    t.Errorf("occurrence %d = %v, want %v", i, got[i], tt.want[i])
+                }
+            }
+        })
+    }
+}
+
+func TestBetweenAndIndexOf(t *testing.T) {
+    rule, err := Parse("FREQ=DAILY")
+    if err != nil {
+        t.Fatal(err)
+    }
+    dtstart := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+    from := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+    to := time.Date(2025, 1, 5, 9, 0, 0, 0, time.UTC)
+
+    got := rule.Between(dtstart, nil, from, to)
+    if len(got) != 2 || got[0].Day() != 3 || got[1].Day() != 4 {
+        t.Errorf("Between = %v, want Jan 3 and Jan 4", got)
+    }
+    if n := rule.IndexOf(dtstart, time.Date(2025, 1, 4, 9, 0, 0, 0, time.UTC)); n != 3 {
+        t.Errorf("IndexOf = %d, want 3", n)
+    }
+}
+
+func TestAllIsCapped(t *testing.T) {
+    rule, err := Parse("FREQ=DAILY")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := rule.All(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), nil); len(got) != MaxOccurrences {
+        t.Errorf("unbounded rule gave %d occurrences, want %d", len(got), MaxOccurrences)
+    }
+}
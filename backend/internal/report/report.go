@@ -0,0 +1,114 @@
+package report
+
+import (
+    "bytes"
+    "embed"
+    "fmt"
+    htmltemplate "html/template"
+    "strconv"
+    "strings"
+    texttemplate "text/template"
+    "time"
+)
+
+const (
+    FormatMarkdown = "markdown"
+    FormatHTML     = "html"
+    FormatText     = "text"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var funcs = map[string]interface{}{
+    "date":  formatDate,
+    "hours": formatHours,
+    "last":  func(t time.Time) time.Time { return t.AddDate(0, 0, -1) },
+}
+
+var (
+    markdownTemplate = texttemplate.Must(texttemplate.New("weekly.md.tmpl").Funcs(funcs).ParseFS(templateFS, "templates/weekly.md.tmpl"))
+    textTemplate     = texttemplate.Must(texttemplate.New("weekly.txt.tmpl").Funcs(funcs).ParseFS(templateFS, "templates/weekly.txt.tmpl"))
+    htmlTemplate     = htmltemplate.Must(htmltemplate.New("weekly.html.tmpl").Funcs(funcs).ParseFS(templateFS, "templates/weekly.html.tmpl"))
+)
+
+// Weekly summarizes a student's progress over [Start, End) for their
+// advisor.
+type Weekly struct {
+    Student       string            `json:"student"`
+    Program       string            `json:"program,omitempty"`
+    Advisor       string            `json:"advisor,omitempty"`
+    Week          string            `json:"week"` // ISO week, e.g. 2025-W14
+    Start         time.Time         `json:"start"`
+    End           time.Time         `json:"end"` // Exclusive
+    Completed     []Item            `json:"completed"`
+    Hours         float64           `json:"hours"`
+    HoursByCourse []CourseHours     `json:"hours_by_course"`
+    Upcoming      []Item            `json:"upcoming"` // Due within UpcomingDays of End
+    UpcomingDays  int               `json:"upcoming_days"`
+    Overdue       []Item            `json:"overdue"`
+    Milestones    []MilestoneChange `json:"milestones"`
+}
+
+type Item struct {
+    ID       uint      `json:"id"`
+    Title    string    `json:"title"`
+    Course   string    `json:"course,omitempty"`
+    DueDate  time.Time `json:"due_date"`
+    Priority string    `json:"priority"`
+    Status   string    `json:"status"`
+    Hours    float64   `json:"hours,omitempty"` // Hours logged in the period
+}
+
+type CourseHours struct {
+    Course string  `json:"course"` // Empty for work outside any course
+    Hours  float64 `json:"hours"`
+}
+
+type MilestoneChange struct {
+    ID            uint       `json:"id"`
+    Name          string     `json:"name"`
+    Type          string     `json:"type"`
+    Status        string     `json:"status"`
+    TargetDate    *time.Time `json:"target_date,omitempty"`
+    CompletedDate *time.Time `json:"completed_date,omitempty"`
+    Completed     bool       `json:"completed"` // Completed within the period
+}
+
+// Subject is the email subject line for the report.
+func (r *Weekly) Subject() string {
+    return fmt.Sprintf("Weekly progress: %s, %s to %s", r.Student, formatDate(r.Start), formatDate(r.End.AddDate(0, 0, -1)))
+}
+
+// Render writes the report in the given format.
+func (r *Weekly) Render(format string) (string, error) {
+    var buf bytes.Buffer
+    var err error
+    switch format {
+    case FormatMarkdown:
+        err = markdownTemplate.Execute(&buf, r)
+    case FormatText:
+        err = textTemplate.Execute(&buf, r)
+    case FormatHTML:
+        err = htmlTemplate.Execute(&buf, r)
+    default:
+        return "", fmt.Errorf("unknown report format %q", format)
+    }
+    return strings.TrimSpace(buf.String()) + "\n", err
+}
+
+func formatDate(t interface{}) string {
+    switch v := t.(type) {
+    case time.Time:
+        return v.Format("Mon Jan 2, 2006")
+    case *time.Time:
+        if v != nil {
+            return v.Format("Mon Jan 2, 2006")
+        }
+    }
+    return "-"
+}
+
+func formatHours(h float64) string {
+    return strconv.FormatFloat(h, 'f', -1, 64) + "h"
+}
@@ -0,0 +1,396 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+var (
+    ErrInvalidGradingScale  = errors.New("invalid grading scale")
+    ErrGradingScaleNotFound = errors.New("grading scale not found")
+)
+
+type AcademicsService struct {
+    db *gorm.DB
+}
+
+func NewAcademicsService() *AcademicsService {
+    return &AcademicsService{
+        db: database.GetDB(),
+    }
+}
+
+type GradingScaleRequest struct {
+    Name        string                `json:"name" binding:"required"`
+    Institution string                `json:"institution"`
+    IsDefault   bool                  `json:"is_default"`
+    MaxPoints   float64               `json:"max_points" binding:"gt=0"`
+    Grades      []models.GradeMapping `json:"grades" binding:"required,min=1"`
+}
+
+// TermGPA is one term's results on one grading scale.
+type TermGPA struct {
+    TermID            *uint    `json:"term_id,omitempty"`
+    Term              string   `json:"term"`
+    CreditsAttempted  int      `json:"credits_attempted"`
+    CreditsEarned     int      `json:"credits_earned"`
+    CreditsInProgress int      `json:"credits_in_progress"`
+    GPACredits        int      `json:"gpa_credits"` // Credits with a grade that counts towards GPA
+    QualityPoints     float64  `json:"quality_points"`
+    GPA               *float64 `json:"gpa"`            // nil until a graded course counts
+    CumulativeGPA     *float64 `json:"cumulative_gpa"` // Through the end of this term
+}
+
+// InstitutionSummary covers the courses graded on one scale. GPAs from
+// different scales aren't comparable, so each gets its own.
+type InstitutionSummary struct {
+    ScaleID          *uint     `json:"scale_id"` // nil for the built-in scale
+    ScaleName        string    `json:"scale_name"`
+    Institution      string    `json:"institution"`
+    MaxPoints        float64   `json:"max_points"`
+    Terms            []TermGPA `json:"terms"`
+    CreditsAttempted int       `json:"credits_attempted"`
+    CreditsEarned    int       `json:"credits_earned"`
+    GPACredits       int       `json:"gpa_credits"`
+    QualityPoints    float64   `json:"quality_points"`
+    CumulativeGPA    *float64  `json:"cumulative_gpa"`
+}
+
+type CourseGradeNote struct {
+    CourseID   uint   `json:"course_id"`
+    CourseCode string `json:"course_code"`
+    Term       string `json:"term"`
+    Grade      string `json:"grade"`
+}
+
+type AcademicSummary struct {
+    Institutions      []InstitutionSummary `json:"institutions"`
+    CreditsAttempted  int                  `json:"credits_attempted"`
+    CreditsEarned     int                  `json:"credits_earned"`
+    CreditsInProgress int                  `json:"credits_in_progress"` // Enrolled without a grade, or incomplete
+    Incomplete        []CourseGradeNote    `json:"incomplete"`
+    Unrecognized      []CourseGradeNote    `json:"unrecognized"` // Grades missing from the course's scale
+}
+
+// GetScales lists the user's grading scales. With none saved the built-in
+// US 4.0 scale is returned so clients can show what is being used.
+func (s *AcademicsService) GetScales(userID uint) ([]models.GradingScale, error) {
+    var scales []models.GradingScale
+    if err := s.db.Where("user_id = ?", userID).Order("is_default DESC, name ASC").Find(&scales).Error; err != nil {
+        return nil, err
+    }
+    if len(scales) == 0 {
+        scales = append(scales, *models.DefaultGradingScale(userID))
+    }
+    return scales, nil
+}
+
+// CreateScale saves a grading scale. The first scale a user saves becomes
+// their default.
+func (s *AcademicsService) CreateScale(userID uint, req GradingScaleRequest) (*models.GradingScale, error) {
+    scale := models.GradingScale{UserID: userID}
+    if err := applyScaleRequest(&scale, req); err != nil {
+        return nil, err
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        var count int64
+        if err := tx.Model(&models.GradingScale{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+            return err
+        }
+        if count == 0 {
+            scale.IsDefault = true
+        }
+        if err := clearDefaultScale(tx, userID, scale.IsDefault); err != nil {
+            return err
+        }
+        return tx.Create(&scale).Error
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &scale, nil
+}
+
+func (s *AcademicsService) UpdateScale(userID, scaleID uint, req GradingScaleRequest) (*models.GradingScale, error) {
+    var scale models.GradingScale
+    if err := s.db.Where("id = ? AND user_id = ?", scaleID, userID).First(&scale).Error; err != nil {
+        return nil, err
+    }
+    if err := applyScaleRequest(&scale, req); err != nil {
+        return nil, err
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := clearDefaultScale(tx, userID, scale.IsDefault); err != nil {
+            return err
+        }
+        return tx.Save(&scale).Error
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &scale, nil
+}
+
+// DeleteScale removes a scale. Courses that used it fall back to the
+// default scale.
+func (s *AcademicsService) DeleteScale(userID, scaleID uint) error {
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("id = ? AND user_id = ?", scaleID, userID).Delete(&models.GradingScale{})
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return gorm.ErrRecordNotFound
+        }
+        return tx.Model(&models.Course{}).Where("grading_scale_id = ? AND user_id = ?", scaleID, userID).
+            Update("grading_scale_id", nil).Error
+    })
+}
+
+// Summary computes term and cumulative GPA and credit totals from course
+// grades. Dropped courses are ignored, and courses without a grade count
+// as in progress.
+func (s *AcademicsService) Summary(userID uint) (*AcademicSummary, error) {
+    var courses []models.Course
+    if err := s.db.Where("user_id = ? AND status <> ?", userID, "dropped").Preload("Term").Find(&courses).Error; err != nil {
+        return nil, err
+    }
+    scales, defaultScale, err := s.userScales(userID)
+    if err != nil {
+        return nil, err
+    }
+
+    // Order courses by term so cumulative GPAs accumulate chronologically
+    sort.SliceStable(courses, func(i, j int) bool {
+        return courseTermBefore(&courses[i], &courses[j])
+    })
+
+    summary := &AcademicSummary{
+        Institutions: []InstitutionSummary{},
+        Incomplete:   []CourseGradeNote{},
+        Unrecognized: []CourseGradeNote{},
+    }
+    institutions := map[*models.GradingScale]*InstitutionSummary{}
+    var order []*models.GradingScale
+
+    for i := range courses {
+        course := &courses[i]
+        scale := defaultScale
+        if course.GradingScaleID != nil && scales[*course.GradingScaleID] != nil {
+            scale = scales[*course.GradingScaleID]
+        }
+        institution := institutions[scale]
+        if institution == nil {
+            institution = &InstitutionSummary{
+                ScaleID:     scaleRef(scale),
+                ScaleName:   scale.Name,
+                Institution: scale.Institution,
+                MaxPoints:   scale.MaxPoints,
+                Terms:       []TermGPA{},
+            }
+            institutions[scale] = institution
+            order = append(order, scale)
+        }
+        term := institutionTerm(institution, course)
+        note := CourseGradeNote{CourseID: course.ID, CourseCode: course.CourseCode, Term: term.Term, Grade: course.Grade}
+
+        if strings.TrimSpace(course.Grade) == "" {
+            term.CreditsInProgress += course.Credits
+            summary.CreditsInProgress += course.Credits
+            continue
+        }
+        mapping, ok := scale.Lookup(course.Grade)
+        if !ok {
+            summary.Unrecognized = append(summary.Unrecognized, note)
+            continue
+        }
+        if mapping.Incomplete {
+            term.CreditsInProgress += course.Credits
+            summary.CreditsInProgress += course.Credits
+            summary.Incomplete = append(summary.Incomplete, note)
+            continue
+        }
+
+        if mapping.Attempted {
+            term.CreditsAttempted += course.Credits
+            institution.CreditsAttempted += course.Credits
+            summary.CreditsAttempted += course.Credits
+        }
+        if mapping.EarnsCredit {
+            term.CreditsEarned += course.Credits
+            institution.CreditsEarned += course.Credits
+            summary.CreditsEarned += course.Credits
+        }
+        if mapping.Points != nil {
+            points := *mapping.Points * float64(course.Credits)
+            term.GPACredits += course.Credits
+            term.QualityPoints += points
+            institution.GPACredits += course.Credits
+            institution.QualityPoints += points
+        }
+    }
+
+    for _, scale := range order {
+        institution := institutions[scale]
+        gpaCredits, qualityPoints := 0, 0.0
+        for i := range institution.Terms {
+            term := &institution.Terms[i]
+            gpaCredits += term.GPACredits
+            qualityPoints += term.QualityPoints
+            term.GPA = gpa(term.QualityPoints, term.GPACredits)
+            term.CumulativeGPA = gpa(qualityPoints, gpaCredits)
+            term.QualityPoints = round2(term.QualityPoints)
+        }
+        institution.CumulativeGPA = gpa(institution.QualityPoints, institution.GPACredits)
+        institution.QualityPoints = round2(institution.QualityPoints)
+        summary.Institutions = append(summary.Institutions, *institution)
+    }
+    return summary, nil
+}
+
+// userScales loads the user's scales by ID along with the one that
+// applies to courses without a scale of their own.
+func (s *AcademicsService) userScales(userID uint) (map[uint]*models.GradingScale, *models.GradingScale, error) {
+    var saved []models.GradingScale
+    if err := s.db.Where("user_id = ?", userID).Find(&saved).Error; err != nil {
+        return nil, nil, err
+    }
+    scales := map[uint]*models.GradingScale{}
+    var defaultScale *models.GradingScale
+    for i := range saved {
+        scales[saved[i].ID] = &saved[i]
+        if saved[i].IsDefault {
+            defaultScale = &saved[i]
+        }
+    }
+    if defaultScale == nil {
+        defaultScale = models.DefaultGradingScale(userID)
+    }
+    return scales, defaultScale, nil
+}
+
+// courseScale is the scale a course is graded on.
+func (s *AcademicsService) courseScale(course *models.Course) (*models.GradingScale, error) {
+    scales, defaultScale, err := s.userScales(course.UserID)
+    if err != nil {
+        return nil, err
+    }
+    if course.GradingScaleID != nil && scales[*course.GradingScaleID] != nil {
+        return scales[*course.GradingScaleID], nil
+    }
+    return defaultScale, nil
+}
+
+// checkGradingScale verifies a course's grading scale belongs to the user.
+func checkGradingScale(tx *gorm.DB, userID uint, scaleID *uint) error {
+    if scaleID == nil {
+        return nil
+    }
+    var count int64
+    if err := tx.Model(&models.GradingScale{}).Where("id = ? AND user_id = ?", *scaleID, userID).Count(&count).Error; err != nil {
+        return err
+    }
+    if count == 0 {
+        return ErrGradingScaleNotFound
+    }
+    return nil
+}
+
+func clearDefaultScale(tx *gorm.DB, userID uint, isDefault bool) error {
+    if !isDefault {
+        return nil
+    }
+    return tx.Model(&models.GradingScale{}).Where("user_id = ? AND is_default = ?", userID, true).
+        Update("is_default", false).Error
+}
+
+func applyScaleRequest(scale *models.GradingScale, req GradingScaleRequest) error {
+    seen := map[string]bool{}
+    for i := range req.Grades {
+        grade := &req.Grades[i]
+        grade.Letter = strings.TrimSpace(grade.Letter)
+        letter := strings.ToUpper(grade.Letter)
+        if letter == "" {
+            return fmt.Errorf("%w: every grade needs a letter", ErrInvalidGradingScale)
+        }
+        if seen[letter] {
+            return fmt.Errorf("%w: grade %q is listed twice", ErrInvalidGradingScale, grade.Letter)
+        }
+        seen[letter] = true
+        if grade.Points != nil && (*grade.Points < 0 || *grade.Points > req.MaxPoints) {
+            return fmt.Errorf("%w: points for %q must be between 0 and max_points", ErrInvalidGradingScale, grade.Letter)
+        }
+        if grade.MinPercent != nil && (*grade.MinPercent < 0 || *grade.MinPercent > 100) {
+            return fmt.Errorf("%w: min_percent for %q must be between 0 and 100", ErrInvalidGradingScale, grade.Letter)
+        }
+        if grade.Incomplete && (grade.Points != nil || grade.EarnsCredit) {
+            return fmt.Errorf("%w: incomplete grade %q can't carry points or credit", ErrInvalidGradingScale, grade.Letter)
+        }
+    }
+
+    scale.Name = strings.TrimSpace(req.Name)
+    scale.Institution = req.Institution
+    scale.IsDefault = req.IsDefault
+    scale.MaxPoints = req.MaxPoints
+    scale.Grades = req.Grades
+    return nil
+}
+
+// institutionTerm returns the entry for the course's term, adding it when
+// this is the first course seen from that term.
+func institutionTerm(institution *InstitutionSummary, course *models.Course) *TermGPA {
+    name := course.Semester
+    if course.Term != nil {
+        name = course.Term.Name
+    }
+    for i := range institution.Terms {
+        t := &institution.Terms[i]
+        if (course.TermID != nil && t.TermID != nil && *t.TermID == *course.TermID) ||
+            (course.TermID == nil && t.TermID == nil && strings.EqualFold(t.Term, name)) {
+            return t
+        }
+    }
+    institution.Terms = append(institution.Terms, TermGPA{TermID: course.TermID, Term: name})
+    return &institution.Terms[len(institution.Terms)-1]
+}
+
+// courseTermBefore orders courses by their term's start date. Courses
+// without a term use the usual dates for their semester string, and
+// anything unparseable sorts last.
+func courseTermBefore(a, b *models.Course) bool {
+    return courseTermStart(a).Before(courseTermStart(b))
+}
+
+func courseTermStart(course *models.Course) time.Time {
+    if course.Term != nil {
+        return course.Term.StartDate
+    }
+    if term, ok := models.NewEstimatedTerm(course.UserID, course.Semester); ok {
+        return term.StartDate
+    }
+    return time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+}
+
+func scaleRef(scale *models.GradingScale) *uint {
+    if scale.ID == 0 {
+        return nil
+    }
+    return &scale.ID
+}
+
+func gpa(qualityPoints float64, credits int) *float64 {
+    if credits == 0 {
+        return nil
+    }
+    value := round2(qualityPoints / float64(credits))
+    return &value
+}
@@ -0,0 +1,436 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+const (
+    ActionItemOpen    = "open"
+    ActionItemDone    = "done"
+    ActionItemDropped = "dropped"
+
+    // Due date given to promoted items that have none of their own
+    defaultActionItemDays = 7
+)
+
+var (
+    ErrInvalidAdvisorMeeting = errors.New("invalid advisor meeting")
+    ErrAlreadyPromoted       = errors.New("action item is already an assignment")
+)
+
+type AdvisorService struct {
+    db *gorm.DB
+}
+
+func NewAdvisorService() *AdvisorService {
+    return &AdvisorService{
+        db: database.GetDB(),
+    }
+}
+
+type ActionItemRequest struct {
+    Description string     `json:"description" binding:"required"`
+    Owner       string     `json:"owner"`
+    DueDate     *time.Time `json:"due_date"`
+}
+
+type CreateAdvisorMeetingRequest struct {
+    Date        time.Time           `json:"date" binding:"required"`
+    Title       string              `json:"title"`
+    Attendees   []string            `json:"attendees"`
+    Agenda      []string            `json:"agenda"`
+    Notes       string              `json:"notes"`
+    ActionItems []ActionItemRequest `json:"action_items"`
+}
+
+type UpdateAdvisorMeetingRequest struct {
+    Date      *time.Time `json:"date"`
+    Title     *string    `json:"title"`
+    Attendees *[]string  `json:"attendees"`
+    Agenda    *[]string  `json:"agenda"`
+    Notes     *string    `json:"notes"`
+}
+
+type UpdateActionItemRequest struct {
+    Description *string    `json:"description"`
+    Owner       *string    `json:"owner"`
+    DueDate     *time.Time `json:"due_date"`
+    Status      *string    `json:"status"`
+}
+
+type PromoteActionItemRequest struct {
+    CourseID       *uint      `json:"course_id"`
+    DueDate        *time.Time `json:"due_date"` // Defaults to the item's due date, else a week after the meeting
+    Priority       string     `json:"priority"`
+    EstimatedHours int        `json:"estimated_hours"`
+}
+
+type OpenActionItem struct {
+    models.ActionItem
+    MeetingDate  time.Time `json:"meeting_date"`
+    MeetingTitle string    `json:"meeting_title"`
+    DaysOpen     int       `json:"days_open"`
+    Overdue      bool      `json:"overdue"`
+}
+
+type OpenActionItems struct {
+    Before  time.Time        `json:"before"` // Only meetings before this are included
+    Items   []OpenActionItem `json:"items"`
+    Overdue int              `json:"overdue"`
+}
+
+func (s *AdvisorService) GetMeetings(userID uint) ([]models.AdvisorMeeting, error) {
+    var meetings []models.AdvisorMeeting
+    err := s.db.Where("user_id = ?", userID).
+        Preload("ActionItems", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+        Preload("ActionItems.Assignment").
+        Order("date DESC").Find(&meetings).Error
+    return meetings, err
+}
+
+func (s *AdvisorService) GetMeeting(userID, meetingID uint) (*models.AdvisorMeeting, error) {
+    var meeting models.AdvisorMeeting
+    err := s.db.Where("id = ? AND user_id = ?", meetingID, userID).
+        Preload("ActionItems", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+        Preload("ActionItems.Assignment").
+        First(&meeting).Error
+    return &meeting, err
+}
+
+func (s *AdvisorService) CreateMeeting(userID uint, req CreateAdvisorMeetingRequest) (*models.AdvisorMeeting, error) {
+    meeting := models.AdvisorMeeting{
+        UserID:    userID,
+        Date:      req.Date,
+        Title:     strings.TrimSpace(req.Title),
+        Attendees: req.Attendees,
+        Agenda:    req.Agenda,
+        Notes:     req.Notes,
+    }
+    normalizeAdvisorMeeting(&meeting)
+
+    items := make([]models.ActionItem, 0, len(req.ActionItems))
+    for _, itemReq := range req.ActionItems {
+        item, err := newActionItem(userID, itemReq)
+        if err != nil {
+            return nil, err
+        }
+        items = append(items, *item)
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(&meeting).Error; err != nil {
+            return err
+        }
+        for i := range items {
+            items[i].MeetingID = meeting.ID
+            if err := tx.Create(&items[i]).Error; err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return s.GetMeeting(userID, meeting.ID)
+}
+
+func (s *AdvisorService) UpdateMeeting(userID, meetingID uint, req UpdateAdvisorMeetingRequest) (*models.AdvisorMeeting, error) {
+    var meeting models.AdvisorMeeting
+    if err := s.db.Where("id = ? AND user_id = ?", meetingID, userID).First(&meeting).Error; err != nil {
+        return nil, err
+    }
+
+    if req.Date != nil {
+        meeting.Date = *req.Date
+    }
+    if req.Title != nil {
+        meeting.Title = strings.TrimSpace(*req.Title)
+    }
+    if req.Attendees != nil {
+        meeting.Attendees = *req.Attendees
+    }
+    if req.Agenda != nil {
+        meeting.Agenda = *req.Agenda
+    }
+    if req.Notes != nil {
+        meeting.Notes = *req.Notes
+    }
+    normalizeAdvisorMeeting(&meeting)
+
+    if err := s.db.Save(&meeting).Error; err != nil {
+        return nil, err
+    }
+    return s.GetMeeting(userID, meeting.ID)
+}
+
+// DeleteMeeting removes a meeting with its action items. Assignments
+// promoted from them are kept.
+func (s *AdvisorService) DeleteMeeting(userID, meetingID uint) error {
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("id = ? AND user_id = ?", meetingID, userID).Delete(&models.AdvisorMeeting{})
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return gorm.ErrRecordNotFound
+        }
+        if err := tx.Where("meeting_id = ? AND user_id = ?", meetingID, userID).Delete(&models.ActionItem{}).Error; err != nil {
+            return err
+        }
+        return tx.Model(&models.Assignment{}).Where("advisor_meeting_id = ? AND user_id = ?", meetingID, userID).
+            Update("advisor_meeting_id", nil).Error
+    })
+}
+
+func (s *AdvisorService) CreateActionItem(userID, meetingID uint, req ActionItemRequest) (*models.ActionItem, error) {
+    var meeting models.AdvisorMeeting
+    if err := s.db.Where("id = ? AND user_id = ?", meetingID, userID).First(&meeting).Error; err != nil {
+        return nil, err
+    }
+    item, err := newActionItem(userID, req)
+    if err != nil {
+        return nil, err
+    }
+    item.MeetingID = meeting.ID
+    if err := s.db.Create(item).Error; err != nil {
+        return nil, err
+    }
+    return item, nil
+}
+
+func (s *AdvisorService) UpdateActionItem(userID, meetingID, itemID uint, req UpdateActionItemRequest) (*models.ActionItem, error) {
+    var item models.ActionItem
+    if err := s.db.Where("id = ? AND meeting_id = ? AND user_id = ?", itemID, meetingID, userID).First(&item).Error; err != nil {
+        return nil, err
+    }
+
+    if req.Description != nil {
+        item.Description = strings.TrimSpace(*req.Description)
+    }
+    if req.Owner != nil {
+        item.Owner = strings.TrimSpace(*req.Owner)
+    }
+    if req.DueDate != nil {
+        item.DueDate = req.DueDate
+    }
+    if req.Status != nil {
+        item.Status = *req.Status
+    }
+    if err := normalizeActionItem(&item, time.Now()); err != nil {
+        return nil, err
+    }
+
+    if err := s.db.Save(&item).Error; err != nil {
+        return nil, err
+    }
+    s.db.Preload("Assignment").First(&item, item.ID)
+    return &item, nil
+}
+
+func (s *AdvisorService) DeleteActionItem(userID, meetingID, itemID uint) error {
+    result := s.db.Where("id = ? AND meeting_id = ? AND user_id = ?", itemID, meetingID, userID).Delete(&models.ActionItem{})
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return gorm.ErrRecordNotFound
+    }
+    return nil
+}
+
+// PromoteActionItem turns an action item into an assignment linked back to
+// the meeting. The item then follows the assignment: it counts as done
+// once the assignment is completed.
+func (s *AdvisorService) PromoteActionItem(userID, meetingID, itemID uint, req PromoteActionItemRequest) (*models.ActionItem, error) {
+    var item models.ActionItem
+    if err := s.db.Where("id = ? AND meeting_id = ? AND user_id = ?", itemID, meetingID, userID).First(&item).Error; err != nil {
+        return nil, err
+    }
+    if item.AssignmentID != nil {
+        return nil, ErrAlreadyPromoted
+    }
+    var meeting models.AdvisorMeeting
+    if err := s.db.Where("id = ? AND user_id = ?", meetingID, userID).First(&meeting).Error; err != nil {
+        return nil, err
+    }
+    if req.CourseID != nil {
+        var count int64
+        if err := s.db.Model(&models.Course{}).Where("id = ? AND user_id = ?", *req.CourseID, userID).Count(&count).Error; err != nil {
+            return nil, err
+        }
+        if count == 0 {
+            return nil, ErrCourseNotFound
+        }
+    }
+
+    due := meeting.Date.AddDate(0, 0, defaultActionItemDays)
+    switch {
+    case req.DueDate != nil:
+        due = *req.DueDate
+    case item.DueDate != nil:
+        due = *item.DueDate
+    }
+    description := "Action item from the advisor meeting on " + meeting.Date.Format("Jan 2, 2006")
+    if meeting.Title != "" {
+        description += " (" + meeting.Title + ")"
+    }
+
+    assignment := models.Assignment{
+        UserID:           userID,
+        CourseID:         req.CourseID,
+        Title:            item.Description,
+        Description:      description,
+        DueDate:          due,
+        Priority:         req.Priority,
+        Status:           "pending",
+        EstimatedHours:   req.EstimatedHours,
+        AdvisorMeetingID: &meeting.ID,
+    }
+    if assignment.Priority == "" {
+        assignment.Priority = "medium"
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(&assignment).Error; err != nil {
+            return err
+        }
+        // Claim the item only if it is still unpromoted, so two concurrent
+        // promotions can't both create an assignment
+        result := tx.Model(&models.ActionItem{}).
+            Where("id = ? AND assignment_id IS NULL", item.ID).
+            Updates(map[string]interface{}{
+                "assignment_id": assignment.ID,
+                "due_date":      gorm.Expr("COALESCE(due_date, ?)", due),
+            })
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected != 1 {
+            return ErrAlreadyPromoted
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    s.db.Preload("Assignment").First(&item, item.ID)
+    return &item, nil
+}
+
+// OpenItems lists action items still open from meetings before the given
+// time, oldest meeting first, for review at the next meeting. Promoted
+// items whose assignment has been completed are left out.
+func (s *AdvisorService) OpenItems(userID uint, before, now time.Time) (*OpenActionItems, error) {
+    var items []models.ActionItem
+    err := s.db.Joins("JOIN advisor_meetings ON advisor_meetings.id = action_items.meeting_id AND advisor_meetings.deleted_at IS NULL").
+        Where("action_items.user_id = ? AND action_items.status = ? AND advisor_meetings.date < ?", userID, ActionItemOpen, before).
+        Preload("Assignment").
+        Order("advisor_meetings.date ASC, action_items.id ASC").
+        Find(&items).Error
+    if err != nil {
+        return nil, err
+    }
+
+    meetingIDs := make([]uint, 0, len(items))
+    for _, item := range items {
+        meetingIDs = append(meetingIDs, item.MeetingID)
+    }
+    var meetings []models.AdvisorMeeting
+    if err := s.db.Where("id IN ?", meetingIDs).Find(&meetings).Error; err != nil {
+        return nil, err
+    }
+    byID := make(map[uint]*models.AdvisorMeeting, len(meetings))
+    for i := range meetings {
+        byID[meetings[i].ID] = &meetings[i]
+    }
+
+    result := &OpenActionItems{Before: before, Items: []OpenActionItem{}}
+    for _, item := range items {
+        if item.Assignment != nil && item.Assignment.Status == "completed" {
+            continue
+        }
+        meeting := byID[item.MeetingID]
+        if meeting == nil {
+            continue
+        }
+        due := item.DueDate
+        if item.Assignment != nil {
+            due = &item.Assignment.DueDate
+        }
+        open := OpenActionItem{
+            ActionItem:   item,
+            MeetingDate:  meeting.Date,
+            MeetingTitle: meeting.Title,
+            DaysOpen:     int(now.Sub(meeting.Date).Hours() / 24),
+            Overdue:      due != nil && due.Before(now),
+        }
+        if open.Overdue {
+            result.Overdue++
+        }
+        result.Items = append(result.Items, open)
+    }
+    return result, nil
+}
+
+// MeetingDate returns when a meeting took place, for reviewing the items
+// left open before it.
+func (s *AdvisorService) MeetingDate(userID, meetingID uint) (time.Time, error) {
+    var meeting models.AdvisorMeeting
+    if err := s.db.Where("id = ? AND user_id = ?", meetingID, userID).First(&meeting).Error; err != nil {
+        return time.Time{}, err
+    }
+    return meeting.Date, nil
+}
+
+func newActionItem(userID uint, req ActionItemRequest) (*models.ActionItem, error) {
+    item := &models.ActionItem{
+        UserID:      userID,
+        Description: strings.TrimSpace(req.Description),
+        Owner:       strings.TrimSpace(req.Owner),
+        DueDate:     req.DueDate,
+    }
+    if err := normalizeActionItem(item, time.Now()); err != nil {
+        return nil, err
+    }
+    return item, nil
+}
+
+func normalizeActionItem(item *models.ActionItem, now time.Time) error {
+    if item.Description == "" {
+        return fmt.Errorf("%w: action item description is required", ErrInvalidAdvisorMeeting)
+    }
+    if item.Status == "" {
+        item.Status = ActionItemOpen
+    }
+    switch item.Status {
+    case ActionItemOpen:
+        item.CompletedAt = nil
+    case ActionItemDone, ActionItemDropped:
+        if item.CompletedAt == nil {
+            item.CompletedAt = &now
+        }
+    default:
+        return fmt.Errorf("%w: status must be open, done or dropped", ErrInvalidAdvisorMeeting)
+    }
+    return nil
+}
+
+func normalizeAdvisorMeeting(meeting *models.AdvisorMeeting) {
+    if meeting.Title == "" {
+        meeting.Title = "Advisor meeting"
+    }
+    if meeting.Attendees == nil {
+        meeting.Attendees = []string{}
+    }
+    if meeting.Agenda == nil {
+        meeting.Agenda = []string{}
+    }
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+    "math"
+    "sort"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+const (
+    // Pseudo-samples at a ratio of 1.0 blended into the correction factor so
+    // a couple of outliers don't swing new estimates wildly.
+    correctionPriorWeight = 5
+    // A course needs this many samples before its own factor is preferred
+    // over the user's overall one.
+    minCourseSamples = 3
+    minCorrection    = 0.25
+    maxCorrection    = 4.0
+)
+
+type AnalyticsService struct {
+    db *gorm.DB
+}
+
+func NewAnalyticsService() *AnalyticsService {
+    return &AnalyticsService{
+        db: database.GetDB(),
+    }
+}
+
+type EstimationStats struct {
+    Samples          int     `json:"samples"`
+    EstimatedHours   float64 `json:"estimated_hours"`
+    ActualHours      float64 `json:"actual_hours"`
+    Ratio            float64 `json:"ratio"` // actual / estimated; above 1 means underestimating
+    MedianRatio      float64 `json:"median_ratio"`
+    CorrectionFactor float64 `json:"correction_factor"`
+    ratios           []float64
+}
+
+type CourseEstimation struct {
+    CourseID   *uint  `json:"course_id"`
+    CourseName string `json:"course_name"`
+    CourseCode string `json:"course_code"`
+    EstimationStats
+}
+
+type SemesterEstimation struct {
+    Semester string   `json:"semester"`
+    Change   *float64 `json:"change"` // Ratio difference from the previous semester
+    EstimationStats
+}
+
+type PriorityEstimation struct {
+    Priority string `json:"priority"`
+    EstimationStats
+}
+
+type EstimationReport struct {
+    Overall    EstimationStats      `json:"overall"`
+    ByCourse   []CourseEstimation   `json:"by_course"`
+    BySemester []SemesterEstimation `json:"by_semester"`
+    ByPriority []PriorityEstimation `json:"by_priority"`
+}
+
+// GetEstimationReport compares estimated and actual hours on the user's
+// completed assignments.
+func (s *AnalyticsService) GetEstimationReport(userID uint) (*EstimationReport, error) {
+    assignments, err := estimationSamples(s.db, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    report := &EstimationReport{}
+    courses := map[uint]*CourseEstimation{}
+    semesters := map[string]*SemesterEstimation{}
+    priorities := map[string]*PriorityEstimation{}
+    var unassigned *CourseEstimation
+
+    for _, a := range assignments {
+        estimated, actual := sampleHours(&a)
+        report.Overall.add(estimated, actual)
+
+        priority := a.Priority
+        if priority == "" {
+            priority = "medium"
+        }
+        if priorities[priority] == nil {
+            priorities[priority] = &PriorityEstimation{Priority: priority}
+        }
+        priorities[priority].add(estimated, actual)
+
+        if a.Course == nil {
+            if unassigned == nil {
+                unassigned = &CourseEstimation{CourseName: "No course"}
+            }
+            unassigned.add(estimated, actual)
+            continue
+        }
+
+        if courses[a.Course.ID] == nil {
+            courseID := a.Course.ID
+            courses[courseID] = &CourseEstimation{
+                CourseID:   &courseID,
+                CourseName: a.Course.CourseName,
+                CourseCode: a.Course.CourseCode,
+            }
+        }
+        courses[a.Course.ID].add(estimated, actual)
+
+        if a.Course.Semester != "" {
+            if semesters[a.Course.Semester] == nil {
+                semesters[a.Course.Semester] = &SemesterEstimation{Semester: a.Course.Semester}
+            }
+            semesters[a.Course.Semester].add(estimated, actual)
+        }
+    }
+
+    report.Overall.finish()
+    for _, c := range courses {
+        c.finish()
+        report.ByCourse = append(report.ByCourse, *c)
+    }
+    if unassigned != nil {
+        unassigned.finish()
+        report.ByCourse = append(report.ByCourse, *unassigned)
+    }
+    sort.Slice(report.ByCourse, func(i, j int) bool {
+        return report.ByCourse[i].Samples > report.ByCourse[j].Samples
+    })
+
+    for _, p := range priorities {
+        p.finish()
+        report.ByPriority = append(report.ByPriority, *p)
+    }
+    sort.Slice(report.ByPriority, func(i, j int) bool {
+        return priorityRank(report.ByPriority[i].Priority) < priorityRank(report.ByPriority[j].Priority)
+    })
+
+    for _, sem := range semesters {
+        sem.finish()
+        report.BySemester = append(report.BySemester, *sem)
+    }
+    sort.Slice(report.BySemester, func(i, j int) bool {
+        return semesterSortKey(report.BySemester[i].Semester) < semesterSortKey(report.BySemester[j].Semester)
+    })
+    for i := 1; i < len(report.BySemester); i++ {
+        change := report.BySemester[i].Ratio - report.BySemester[i-1].Ratio
+        report.BySemester[i].Change = &change
+    }
+
+    return report, nil
+}
+
+// correctionFactor suggests how much to scale a new estimate, preferring
+// the course's own history when there is enough of it.
+func correctionFactor(db *gorm.DB, userID uint, courseID *uint) (float64, error) {
+    assignments, err := estimationSamples(db, userID)
+    if err != nil {
+        return 1, err
+    }
+
+    var overall, course EstimationStats
+    for _, a := range assignments {
+        estimated, actual := sampleHours(&a)
+        overall.add(estimated, actual)
+        if courseID != nil && a.CourseID != nil && *a.CourseID == *courseID {
+            course.add(estimated, actual)
+        }
+    }
+
+    if course.Samples >= minCourseSamples {
+        course.finish()
+        return course.CorrectionFactor, nil
+    }
+    overall.finish()
+    return overall.CorrectionFactor, nil
+}
+
+func estimationSamples(db *gorm.DB, userID uint) ([]models.Assignment, error) {
+    var assignments []models.Assignment
+    err := db.Where("user_id = ? AND status = ? AND estimated_hours > 0 AND (actual_hours > 0 OR tracked_minutes > 0)", userID, "completed").
+        Preload("Course").
+        Find(&assignments).Error
+    return assignments, err
+}
+
+// sampleHours prefers the user's original guess over a corrected estimate
+// and tracked minutes over hand-entered hours.
+func sampleHours(a *models.Assignment) (float64, float64) {
+    estimated := float64(a.EstimatedHours)
+    if a.RawEstimatedHours > 0 {
+        estimated = float64(a.RawEstimatedHours)
+    }
+    actual := float64(a.ActualHours)
+    if a.TrackedMinutes > 0 {
+        actual = float64(a.TrackedMinutes) / 60
+    }
+    return estimated, actual
+}
+
+func (e *EstimationStats) add(estimated, actual float64) {
+    e.Samples++
+    e.EstimatedHours += estimated
+    e.ActualHours += actual
+    e.ratios = append(e.ratios, actual/estimated)
+}
+
+func (e *EstimationStats) finish() {
+    e.CorrectionFactor = 1
+    if e.Samples == 0 || e.EstimatedHours == 0 {
+        return
+    }
+
+    e.Ratio = round2(e.ActualHours / e.EstimatedHours)
+
+    sort.Float64s(e.ratios)
+    mid := len(e.ratios) / 2
+    if len(e.ratios)%2 == 0 {
+        e.MedianRatio = round2((e.ratios[mid-1] + e.ratios[mid]) / 2)
+    } else {
+        e.MedianRatio = round2(e.ratios[mid])
+    }
+
+    factor := (float64(e.Samples)*e.ActualHours/e.EstimatedHours + correctionPriorWeight) / float64(e.Samples+correctionPriorWeight)
+    e.CorrectionFactor = round2(math.Max(minCorrection, math.Min(maxCorrection, factor)))
+    e.EstimatedHours = round2(e.EstimatedHours)
+    e.ActualHours = round2(e.ActualHours)
+}
+
+func priorityRank(priority string) int {
+    switch priority {
+    case "high":
+        return 0
+    case "medium":
+        return 1
+    case "low":
+        return 2
+    default:
+        return 3
+    }
+}
+
+// semesterSortKey orders free-text semesters like "Fall 2024" by year and
+// season; anything unparseable sorts last.
+func semesterSortKey(semester string) int {
+    return models.TermSortKey(semester)
+}
+
+func round2(v float64) float64 {
+    return math.Round(v*100) / 100
+}
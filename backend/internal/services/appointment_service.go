@@ -0,0 +1,796 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+const (
+    AppointmentTA = "ta"
+    AppointmentRA = "ra"
+
+    PayPeriodWeekly      = "weekly"
+    PayPeriodBiweekly    = "biweekly"
+    PayPeriodSemimonthly = "semimonthly"
+    PayPeriodMonthly     = "monthly"
+
+    DutyGrading     = "grading"
+    DutyOfficeHours = "office_hours"
+    DutyLab         = "lab"
+    DutyMeeting     = "meeting"
+    DutyPrep        = "prep"
+    DutyOther       = "other"
+)
+
+var (
+    ErrInvalidAppointment = errors.New("invalid appointment")
+    ErrInvalidDuty        = errors.New("invalid duty")
+)
+
+var dutyKinds = []string{DutyGrading, DutyOfficeHours, DutyLab, DutyMeeting, DutyPrep, DutyOther}
+
+type AppointmentService struct {
+    db *gorm.DB
+}
+
+func NewAppointmentService() *AppointmentService {
+    return &AppointmentService{
+        db: database.GetDB(),
+    }
+}
+
+type DutyRequest struct {
+    Kind           string     `json:"kind"`
+    Title          string     `json:"title" binding:"required"`
+    DayOfWeek      *int       `json:"day_of_week"` // Set with start and end time for a weekly shift
+    StartTime      string     `json:"start_time"`
+    EndTime        string     `json:"end_time"`
+    DueDate        *time.Time `json:"due_date"` // Set for a one-off task
+    EstimatedHours int        `json:"estimated_hours"`
+}
+
+type UpdateDutyRequest struct {
+    Kind           *string    `json:"kind"`
+    Title          *string    `json:"title"`
+    DayOfWeek      *int       `json:"day_of_week"`
+    StartTime      *string    `json:"start_time"`
+    EndTime        *string    `json:"end_time"`
+    DueDate        *time.Time `json:"due_date"`
+    EstimatedHours *int       `json:"estimated_hours"`
+}
+
+type CreateAppointmentRequest struct {
+    Kind            string        `json:"kind" binding:"required"`
+    Title           string        `json:"title" binding:"required"`
+    CourseID        *uint         `json:"course_id"`
+    Project         string        `json:"project"`
+    Supervisor      string        `json:"supervisor"`
+    StartDate       string        `json:"start_date" binding:"required"` // YYYY-MM-DD
+    EndDate         string        `json:"end_date" binding:"required"`
+    WeeklyHours     float64       `json:"weekly_hours" binding:"gte=0,lte=168"`
+    PayPeriod       string        `json:"pay_period"`
+    PayPeriodAnchor string        `json:"pay_period_anchor"`
+    Notes           string        `json:"notes"`
+    Duties          []DutyRequest `json:"duties" binding:"dive"`
+}
+
+type UpdateAppointmentRequest struct {
+    Kind            *string  `json:"kind"`
+    Title           *string  `json:"title"`
+    CourseID        *uint    `json:"course_id"`
+    Project         *string  `json:"project"`
+    Supervisor      *string  `json:"supervisor"`
+    StartDate       *string  `json:"start_date"`
+    EndDate         *string  `json:"end_date"`
+    WeeklyHours     *float64 `json:"weekly_hours" binding:"omitempty,gte=0,lte=168"`
+    PayPeriod       *string  `json:"pay_period"`
+    PayPeriodAnchor *string  `json:"pay_period_anchor"` // "" resets it to the start date
+    Notes           *string  `json:"notes"`
+}
+
+type PayPeriodHours struct {
+    Start           string  `json:"start"`
+    End             string  `json:"end"` // Inclusive
+    ContractedHours float64 `json:"contracted_hours"`
+    ScheduledHours  float64 `json:"scheduled_hours"` // Shifts plus task estimates due in the period
+    ActualHours     float64 `json:"actual_hours"`    // Tracked time on the appointment's duties
+    DifferenceHours float64 `json:"difference_hours"` // Actual minus contracted; positive means over
+}
+
+type AppointmentHours struct {
+    AppointmentID uint             `json:"appointment_id"`
+    Title         string           `json:"title"`
+    PayPeriod     string           `json:"pay_period"`
+    Periods       []PayPeriodHours `json:"periods"`
+    Total         PayPeriodHours   `json:"total"`
+}
+
+// FixedBlock is a duty shift occupying part of a day in the plan.
+type FixedBlock struct {
+    DutyID        uint    `json:"duty_id"`
+    AppointmentID uint    `json:"appointment_id"`
+    Title         string  `json:"title"`
+    StartTime     string  `json:"start_time"`
+    EndTime       string  `json:"end_time"`
+    Hours         float64 `json:"hours"`
+}
+
+func (s *AppointmentService) GetAppointments(userID uint) ([]models.Appointment, error) {
+    var appointments []models.Appointment
+    err := s.db.Where("user_id = ?", userID).
+        Preload("Course").
+        Preload("Duties", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+        Preload("Duties.Assignment").
+        Order("start_date DESC").
+        Find(&appointments).Error
+    return appointments, err
+}
+
+func (s *AppointmentService) GetAppointment(userID, appointmentID uint) (*models.Appointment, error) {
+    var appointment models.Appointment
+    err := s.db.Where("id = ? AND user_id = ?", appointmentID, userID).
+        Preload("Course").
+        Preload("Duties", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+        Preload("Duties.Assignment").
+        First(&appointment).Error
+    return &appointment, err
+}
+
+func (s *AppointmentService) CreateAppointment(userID uint, req CreateAppointmentRequest) (*models.Appointment, error) {
+    appointment := models.Appointment{
+        UserID:      userID,
+        Kind:        req.Kind,
+        Title:       strings.TrimSpace(req.Title),
+        CourseID:    req.CourseID,
+        Project:     req.Project,
+        Supervisor:  req.Supervisor,
+        WeeklyHours: req.WeeklyHours,
+        PayPeriod:   req.PayPeriod,
+        Notes:       req.Notes,
+    }
+    var err error
+    if appointment.StartDate, err = parseAppointmentDate("start_date", req.StartDate); err != nil {
+        return nil, err
+    }
+    if appointment.EndDate, err = parseAppointmentDate("end_date", req.EndDate); err != nil {
+        return nil, err
+    }
+    if req.PayPeriodAnchor != "" {
+        anchor, err := parseAppointmentDate("pay_period_anchor", req.PayPeriodAnchor)
+        if err != nil {
+            return nil, err
+        }
+        appointment.PayPeriodAnchor = &anchor
+    }
+    if err := normalizeAppointment(&appointment); err != nil {
+        return nil, err
+    }
+
+    duties := make([]models.Duty, 0, len(req.Duties))
+    for _, d := range req.Duties {
+        duty := newDuty(userID, d)
+        if err := normalizeDuty(&duty); err != nil {
+            return nil, err
+        }
+        duties = append(duties, duty)
+    }
+
+    err = s.db.Transaction(func(tx *gorm.DB) error {
+        if err := checkAppointmentCourse(tx, userID, appointment.CourseID); err != nil {
+            return err
+        }
+        if err := tx.Create(&appointment).Error; err != nil {
+            return err
+        }
+        for i := range duties {
+            duties[i].AppointmentID = appointment.ID
+            if err := tx.Create(&duties[i]).Error; err != nil {
+                return err
+            }
+            if err := syncDutyAssignment(tx, &appointment, &duties[i]); err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return s.GetAppointment(userID, appointment.ID)
+}
+
+// UpdateAppointment saves the changes and carries them through to the
+// assignments of its duties.
+func (s *AppointmentService) UpdateAppointment(userID, appointmentID uint, req UpdateAppointmentRequest) (*models.Appointment, error) {
+    var appointment models.Appointment
+    if err := s.db.Where("id = ? AND user_id = ?", appointmentID, userID).First(&appointment).Error; err != nil {
+        return nil, err
+    }
+
+    if req.Kind != nil {
+        appointment.Kind = *req.Kind
+    }
+    if req.Title != nil {
+        appointment.Title = strings.TrimSpace(*req.Title)
+    }
+    if req.CourseID != nil {
+        appointment.CourseID = req.CourseID
+    }
+    if req.Project != nil {
+        appointment.Project = *req.Project
+    }
+    if req.Supervisor != nil {
+        appointment.Supervisor = *req.Supervisor
+    }
+    if req.WeeklyHours != nil {
+        appointment.WeeklyHours = *req.WeeklyHours
+    }
+    if req.PayPeriod != nil {
+        appointment.PayPeriod = *req.PayPeriod
+    }
+    if req.Notes != nil {
+        appointment.Notes = *req.Notes
+    }
+    var err error
+    if req.StartDate != nil {
+        if appointment.StartDate, err = parseAppointmentDate("start_date", *req.StartDate); err != nil {
+            return nil, err
+        }
+    }
+    if req.EndDate != nil {
+        if appointment.EndDate, err = parseAppointmentDate("end_date", *req.EndDate); err != nil {
+            return nil, err
+        }
+    }
+    if req.PayPeriodAnchor != nil {
+        appointment.PayPeriodAnchor = nil
+        if *req.PayPeriodAnchor != "" {
+            anchor, err := parseAppointmentDate("pay_period_anchor", *req.PayPeriodAnchor)
+            if err != nil {
+                return nil, err
+            }
+            appointment.PayPeriodAnchor = &anchor
+        }
+    }
+    if err := normalizeAppointment(&appointment); err != nil {
+        return nil, err
+    }
+
+    err = s.db.Transaction(func(tx *gorm.DB) error {
+        if req.CourseID != nil {
+            if err := checkAppointmentCourse(tx, userID, appointment.CourseID); err != nil {
+                return err
+            }
+        }
+        appointment.Course, appointment.Duties = nil, nil
+        if err := tx.Save(&appointment).Error; err != nil {
+            return err
+        }
+        var duties []models.Duty
+        if err := tx.Where("appointment_id = ? AND user_id = ?", appointment.ID, userID).Find(&duties).Error; err != nil {
+            return err
+        }
+        for i := range duties {
+            if err := syncDutyAssignment(tx, &appointment, &duties[i]); err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return s.GetAppointment(userID, appointment.ID)
+}
+
+func (s *AppointmentService) DeleteAppointment(userID, appointmentID uint) error {
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("id = ? AND user_id = ?", appointmentID, userID).Delete(&models.Appointment{})
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return gorm.ErrRecordNotFound
+        }
+        var dutyIDs []uint
+        if err := tx.Model(&models.Duty{}).Where("appointment_id = ? AND user_id = ?", appointmentID, userID).
+            Pluck("id", &dutyIDs).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("appointment_id = ? AND user_id = ?", appointmentID, userID).Delete(&models.Duty{}).Error; err != nil {
+            return err
+        }
+        return deleteDutyAssignments(tx, userID, dutyIDs)
+    })
+}
+
+func (s *AppointmentService) CreateDuty(userID, appointmentID uint, req DutyRequest) (*models.Duty, error) {
+    var appointment models.Appointment
+    if err := s.db.Where("id = ? AND user_id = ?", appointmentID, userID).First(&appointment).Error; err != nil {
+        return nil, err
+    }
+    duty := newDuty(userID, req)
+    duty.AppointmentID = appointment.ID
+    if err := normalizeDuty(&duty); err != nil {
+        return nil, err
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(&duty).Error; err != nil {
+            return err
+        }
+        return syncDutyAssignment(tx, &appointment, &duty)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return s.getDuty(userID, appointmentID, duty.ID)
+}
+
+func (s *AppointmentService) UpdateDuty(userID, appointmentID, dutyID uint, req UpdateDutyRequest) (*models.Duty, error) {
+    var appointment models.Appointment
+    if err := s.db.Where("id = ? AND user_id = ?", appointmentID, userID).First(&appointment).Error; err != nil {
+        return nil, err
+    }
+    duty, err := s.getDuty(userID, appointmentID, dutyID)
+    if err != nil {
+        return nil, err
+    }
+
+    if req.Kind != nil {
+        duty.Kind = *req.Kind
+    }
+    if req.Title != nil {
+        duty.Title = *req.Title
+    }
+    // A shift and a task are exclusive, so setting one clears the other
+    if req.DayOfWeek != nil && req.DueDate != nil {
+        return nil, fmt.Errorf("%w: give either day_of_week or due_date, not both", ErrInvalidDuty)
+    }
+    if req.DayOfWeek != nil {
+        duty.DayOfWeek, duty.DueDate = req.DayOfWeek, nil
+    }
+    if req.StartTime != nil {
+        duty.StartTime = *req.StartTime
+    }
+    if req.EndTime != nil {
+        duty.EndTime = *req.EndTime
+    }
+    if req.DueDate != nil {
+        duty.DueDate, duty.DayOfWeek, duty.StartTime, duty.EndTime = req.DueDate, nil, "", ""
+    }
+    if req.EstimatedHours != nil {
+        duty.EstimatedHours = *req.EstimatedHours
+    }
+    if err := normalizeDuty(duty); err != nil {
+        return nil, err
+    }
+
+    err = s.db.Transaction(func(tx *gorm.DB) error {
+        duty.Assignment = nil
+        if err := tx.Save(duty).Error; err != nil {
+            return err
+        }
+        return syncDutyAssignment(tx, &appointment, duty)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return s.getDuty(userID, appointmentID, dutyID)
+}
+
+// DeleteDuty removes the duty and its open assignment. A completed
+// assignment stays linked so its tracked hours still count.
+func (s *AppointmentService) DeleteDuty(userID, appointmentID, dutyID uint) error {
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("id = ? AND appointment_id = ? AND user_id = ?", dutyID, appointmentID, userID).Delete(&models.Duty{})
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return gorm.ErrRecordNotFound
+        }
+        return deleteDutyAssignments(tx, userID, []uint{dutyID})
+    })
+}
+
+// Hours compares contracted, scheduled and tracked hours for each pay
+// period of the appointment between from and to (inclusive dates). They
+// default to the start of the appointment and the earlier of its end and
+// today, in the planner's time zone.
+func (s *AppointmentService) Hours(userID, appointmentID uint, from, to *time.Time, now time.Time) (*AppointmentHours, error) {
+    var appointment models.Appointment
+    if err := s.db.Where("id = ? AND user_id = ?", appointmentID, userID).First(&appointment).Error; err != nil {
+        return nil, err
+    }
+    availability, err := NewPlannerService().GetAvailability(userID)
+    if err != nil {
+        return nil, err
+    }
+    loc, err := time.LoadLocation(availability.TimeZone)
+    if err != nil {
+        loc = time.UTC
+    }
+    first, last := appointment.StartDate, appointment.EndDate
+    if today := now.In(loc); today.Before(last) {
+        last = today
+    }
+    if from != nil {
+        first = *from
+    }
+    if to != nil {
+        last = *to
+    }
+    // Deleted duties still count towards the hours already worked
+    var duties []models.Duty
+    if err := s.db.Unscoped().Where("appointment_id = ? AND user_id = ?", appointment.ID, userID).Find(&duties).Error; err != nil {
+        return nil, err
+    }
+
+    result := &AppointmentHours{
+        AppointmentID: appointment.ID,
+        Title:         appointment.Title,
+        PayPeriod:     appointment.PayPeriod,
+        Periods:       []PayPeriodHours{},
+    }
+    periods := payPeriods(&appointment, first, last, loc)
+    if len(periods) == 0 {
+        return result, nil
+    }
+
+    dutyIDs := make([]uint, 0, len(duties))
+    for _, d := range duties {
+        dutyIDs = append(dutyIDs, d.ID)
+    }
+    var entries []models.TimeEntry
+    if len(dutyIDs) > 0 {
+        if err := s.db.Joins("JOIN assignments ON assignments.id = time_entries.assignment_id").
+            Where("time_entries.user_id = ? AND assignments.duty_id IN ? AND time_entries.ended_at IS NOT NULL", userID, dutyIDs).
+            Where("time_entries.started_at >= ? AND time_entries.started_at < ?", periods[0][0], periods[len(periods)-1][1]).
+            Find(&entries).Error; err != nil {
+            return nil, err
+        }
+    }
+
+    var total PayPeriodHours
+    for _, p := range periods {
+        start, end := p[0], p[1]
+        days := int(math.Round(end.Sub(start).Hours() / 24))
+
+        contracted := appointment.WeeklyHours * float64(days) / 7
+        scheduled := 0
+        for i := range duties {
+            if duties[i].DeletedAt.Valid {
+                continue
+            }
+            scheduled += dutyMinutesBetween(&duties[i], start, end, loc)
+        }
+        actual := 0
+        for _, e := range entries {
+            if !e.StartedAt.Before(start) && e.StartedAt.Before(end) {
+                actual += e.Minutes
+            }
+        }
+
+        period := PayPeriodHours{
+            Start:           start.Format(dateLayout),
+            End:             end.AddDate(0, 0, -1).Format(dateLayout),
+            ContractedHours: math.Round(contracted*100) / 100,
+            ScheduledHours:  minutesToHours(scheduled),
+            ActualHours:     minutesToHours(actual),
+        }
+        period.DifferenceHours = math.Round((period.ActualHours-period.ContractedHours)*100) / 100
+        result.Periods = append(result.Periods, period)
+
+        total.ContractedHours += period.ContractedHours
+        total.ScheduledHours += period.ScheduledHours
+        total.ActualHours += period.ActualHours
+    }
+    total.Start, total.End = result.Periods[0].Start, result.Periods[len(result.Periods)-1].End
+    total.ContractedHours = math.Round(total.ContractedHours*100) / 100
+    total.ScheduledHours = math.Round(total.ScheduledHours*100) / 100
+    total.ActualHours = math.Round(total.ActualHours*100) / 100
+    total.DifferenceHours = math.Round((total.ActualHours-total.ContractedHours)*100) / 100
+    result.Total = total
+    return result, nil
+}
+
+// FixedBlocks lists the duty shifts falling on each day from from up to
+// to, keyed by date. The planner treats them as time already taken.
+func (s *AppointmentService) FixedBlocks(userID uint, from, to time.Time, loc *time.Location) (map[string][]FixedBlock, error) {
+    var appointments []models.Appointment
+    if err := s.db.Where("user_id = ? AND end_date >= ? AND start_date <= ?", userID,
+        time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC),
+        time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)).
+        Preload("Duties", "day_of_week IS NOT NULL").
+        Find(&appointments).Error; err != nil {
+        return nil, err
+    }
+
+    blocks := map[string][]FixedBlock{}
+    for _, appointment := range appointments {
+        first, last := localDate(appointment.StartDate, loc), localDate(appointment.EndDate, loc)
+        for _, duty := range appointment.Duties {
+            for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+                if day.Before(first) || day.After(last) || int(day.Weekday()) != *duty.DayOfWeek {
+                    continue
+                }
+                key := day.Format(dateLayout)
+                blocks[key] = append(blocks[key], FixedBlock{
+                    DutyID:        duty.ID,
+                    AppointmentID: appointment.ID,
+                    Title:         duty.Title,
+                    StartTime:     duty.StartTime,
+                    EndTime:       duty.EndTime,
+                    Hours:         minutesToHours(clockMinutes(duty.EndTime) - clockMinutes(duty.StartTime)),
+                })
+            }
+        }
+    }
+    return blocks, nil
+}
+
+func (s *AppointmentService) getDuty(userID, appointmentID, dutyID uint) (*models.Duty, error) {
+    var duty models.Duty
+    err := s.db.Where("id = ? AND appointment_id = ? AND user_id = ?", dutyID, appointmentID, userID).
+        Preload("Assignment").
+        First(&duty).Error
+    return &duty, err
+}
+
+// syncDutyAssignment creates or updates the assignment behind a duty. A
+// task is planned from its estimate; a shift has no estimate, since the
+// planner already blocks out its hours, and stays open until the
+// appointment ends so time can be tracked against it.
+func syncDutyAssignment(tx *gorm.DB, appointment *models.Appointment, duty *models.Duty) error {
+    title := duty.Title + " (" + appointment.Title + ")"
+    dueDate := time.Date(appointment.EndDate.Year(), appointment.EndDate.Month(), appointment.EndDate.Day(), 23, 59, 0, 0, time.UTC)
+    estimate := 0
+    if duty.DueDate != nil {
+        dueDate, estimate = *duty.DueDate, duty.EstimatedHours
+    }
+
+    var existing []models.Assignment
+    if err := tx.Where("duty_id = ? AND user_id = ?", duty.ID, duty.UserID).Find(&existing).Error; err != nil {
+        return err
+    }
+    if len(existing) == 0 {
+        assignment := models.Assignment{
+            UserID:         duty.UserID,
+            CourseID:       appointment.CourseID,
+            Title:          title,
+            Description:    "Generated from a " + strings.ToUpper(appointment.Kind) + " duty",
+            DueDate:        dueDate,
+            Priority:       "medium",
+            Status:         "pending",
+            EstimatedHours: estimate,
+            DutyID:         &duty.ID,
+        }
+        return tx.Create(&assignment).Error
+    }
+    for _, a := range existing {
+        if a.Status == "completed" {
+            continue
+        }
+        if err := tx.Model(&models.Assignment{}).Where("id = ?", a.ID).Updates(map[string]interface{}{
+            "title":           title,
+            "course_id":       appointment.CourseID,
+            "due_date":        dueDate,
+            "estimated_hours": estimate,
+        }).Error; err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func deleteDutyAssignments(tx *gorm.DB, userID uint, dutyIDs []uint) error {
+    if len(dutyIDs) == 0 {
+        return nil
+    }
+    return tx.Where("duty_id IN ? AND user_id = ? AND status <> ?", dutyIDs, userID, "completed").
+        Delete(&models.Assignment{}).Error
+}
+
+// payPeriods splits the overlap of the appointment and [from, to] into pay
+// periods, each as [start, end) in loc. Periods cut by either range are
+// clipped.
+func payPeriods(appointment *models.Appointment, from, to time.Time, loc *time.Location) [][2]time.Time {
+    first := localDate(appointment.StartDate, loc)
+    last := localDate(appointment.EndDate, loc).AddDate(0, 0, 1)
+    if from = localDate(from, loc); from.After(first) {
+        first = from
+    }
+    if to = localDate(to, loc).AddDate(0, 0, 1); to.Before(last) {
+        last = to
+    }
+
+    var periods [][2]time.Time
+    for start := periodStart(appointment, first, loc); start.Before(last); {
+        end := nextPeriodStart(appointment, start)
+        clippedStart, clippedEnd := start, end
+        if clippedStart.Before(first) {
+            clippedStart = first
+        }
+        if clippedEnd.After(last) {
+            clippedEnd = last
+        }
+        if clippedStart.Before(clippedEnd) {
+            periods = append(periods, [2]time.Time{clippedStart, clippedEnd})
+        }
+        start = end
+    }
+    return periods
+}
+
+// periodStart is the first day of the pay period containing day.
+func periodStart(appointment *models.Appointment, day time.Time, loc *time.Location) time.Time {
+    switch appointment.PayPeriod {
+    case PayPeriodSemimonthly:
+        if day.Day() > 15 {
+            return time.Date(day.Year(), day.Month(), 16, 0, 0, 0, 0, loc)
+        }
+        return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, loc)
+    case PayPeriodMonthly:
+        return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, loc)
+    }
+    length := 7
+    if appointment.PayPeriod == PayPeriodBiweekly {
+        length = 14
+    }
+    anchor := localDate(appointment.StartDate, loc)
+    if appointment.PayPeriodAnchor != nil {
+        anchor = localDate(*appointment.PayPeriodAnchor, loc)
+    }
+    offset := int(math.Round(day.Sub(anchor).Hours()/24)) % length
+    if offset < 0 {
+        offset += length
+    }
+    return day.AddDate(0, 0, -offset)
+}
+
+func nextPeriodStart(appointment *models.Appointment, start time.Time) time.Time {
+    switch appointment.PayPeriod {
+    case PayPeriodSemimonthly:
+        if start.Day() < 16 {
+            return time.Date(start.Year(), start.Month(), 16, 0, 0, 0, 0, start.Location())
+        }
+        return time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, start.Location())
+    case PayPeriodMonthly:
+        return time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, start.Location())
+    case PayPeriodBiweekly:
+        return start.AddDate(0, 0, 14)
+    }
+    return start.AddDate(0, 0, 7)
+}
+
+// dutyMinutesBetween is the time a duty is scheduled to take in [start,
+// end): every shift falling in the range, or the task's estimate if it is
+// due in the range.
+func dutyMinutesBetween(duty *models.Duty, start, end time.Time, loc *time.Location) int {
+    if duty.DueDate != nil {
+        if !duty.DueDate.Before(start) && duty.DueDate.Before(end) {
+            return duty.EstimatedHours * 60
+        }
+        return 0
+    }
+    if duty.DayOfWeek == nil {
+        return 0
+    }
+    length := clockMinutes(duty.EndTime) - clockMinutes(duty.StartTime)
+    minutes := 0
+    for day := localDate(start, loc); day.Before(end); day = day.AddDate(0, 0, 1) {
+        if int(day.Weekday()) == *duty.DayOfWeek {
+            minutes += length
+        }
+    }
+    return minutes
+}
+
+// localDate is midnight in loc on the calendar date of t as stored.
+func localDate(t time.Time, loc *time.Location) time.Time {
+    return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+func newDuty(userID uint, req DutyRequest) models.Duty {
+    return models.Duty{
+        UserID:         userID,
+        Kind:           req.Kind,
+        Title:          req.Title,
+        DayOfWeek:      req.DayOfWeek,
+        StartTime:      req.StartTime,
+        EndTime:        req.EndTime,
+        DueDate:        req.DueDate,
+        EstimatedHours: req.EstimatedHours,
+    }
+}
+
+func checkAppointmentCourse(tx *gorm.DB, userID uint, courseID *uint) error {
+    if courseID == nil {
+        return nil
+    }
+    var count int64
+    if err := tx.Model(&models.Course{}).Where("id = ? AND user_id = ?", *courseID, userID).Count(&count).Error; err != nil {
+        return err
+    }
+    if count == 0 {
+        return ErrCourseNotFound
+    }
+    return nil
+}
+
+func parseAppointmentDate(field, value string) (time.Time, error) {
+    t, err := time.Parse(dateLayout, value)
+    if err != nil {
+        return time.Time{}, fmt.Errorf("%w: %s must be YYYY-MM-DD", ErrInvalidAppointment, field)
+    }
+    return t, nil
+}
+
+func normalizeAppointment(appointment *models.Appointment) error {
+    appointment.Kind = strings.ToLower(strings.TrimSpace(appointment.Kind))
+    if appointment.Kind != AppointmentTA && appointment.Kind != AppointmentRA {
+        return fmt.Errorf("%w: kind must be ta or ra", ErrInvalidAppointment)
+    }
+    if appointment.Title == "" {
+        return fmt.Errorf("%w: title is required", ErrInvalidAppointment)
+    }
+    if appointment.EndDate.Before(appointment.StartDate) {
+        return fmt.Errorf("%w: end_date must not be before start_date", ErrInvalidAppointment)
+    }
+    if appointment.WeeklyHours < 0 {
+        return fmt.Errorf("%w: weekly_hours must not be negative", ErrInvalidAppointment)
+    }
+    switch appointment.PayPeriod {
+    case "":
+        appointment.PayPeriod = PayPeriodBiweekly
+    case PayPeriodWeekly, PayPeriodBiweekly, PayPeriodSemimonthly, PayPeriodMonthly:
+    default:
+        return fmt.Errorf("%w: pay_period must be weekly, biweekly, semimonthly or monthly", ErrInvalidAppointment)
+    }
+    return nil
+}
+
+// normalizeDuty checks that a duty is either a weekly shift or a one-off
+// task and tidies its fields.
+func normalizeDuty(duty *models.Duty) error {
+    duty.Title = strings.TrimSpace(duty.Title)
+    if duty.Title == "" {
+        return fmt.Errorf("%w: title is required", ErrInvalidDuty)
+    }
+    if duty.Kind == "" {
+        duty.Kind = DutyOther
+    }
+    if !containsString(dutyKinds, duty.Kind) {
+        return fmt.Errorf("%w: kind must be one of %s", ErrInvalidDuty, strings.Join(dutyKinds, ", "))
+    }
+    if duty.EstimatedHours < 0 {
+        return fmt.Errorf("%w: estimated_hours must not be negative", ErrInvalidDuty)
+    }
+
+    switch {
+    case duty.DayOfWeek != nil && duty.DueDate != nil:
+        return fmt.Errorf("%w: give either day_of_week for a weekly shift or due_date for a task, not both", ErrInvalidDuty)
+    case duty.DueDate != nil:
+        duty.StartTime, duty.EndTime = "", ""
+        return nil
+    case duty.DayOfWeek == nil:
+        return fmt.Errorf("%w: day_of_week or due_date is required", ErrInvalidDuty)
+    }
+
+    // Shifts are checked the same way as class meetings
+    shift := models.CourseMeeting{DayOfWeek: *duty.DayOfWeek, StartTime: duty.StartTime, EndTime: duty.EndTime, Type: MeetingLab}
+    if err := normalizeMeeting(&shift); err != nil {
+        return fmt.Errorf("%w: %s", ErrInvalidDuty, strings.TrimPrefix(err.Error(), ErrInvalidMeeting.Error()+": "))
+    }
+    duty.StartTime, duty.EndTime = shift.StartTime, shift.EndTime
+    duty.EstimatedHours = 0
+    return nil
+}
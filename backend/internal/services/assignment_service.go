@@ -1,6 +1,11 @@
 package services
 
 import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "math"
+    "strings"
     "time"
 
     "github.com/anayy09/academiaflow-backend/internal/database"
@@ -18,6 +23,8 @@ func NewAssignmentService() *AssignmentService {
     }
 }
 
+var ErrInvalidAssignment = errors.New("invalid assignment")
+
 type CreateAssignmentRequest struct {
     CourseID       *uint     `json:"course_id"`
     Title          string    `json:"title" binding:"required"`
@@ -25,6 +32,8 @@ type CreateAssignmentRequest struct {
     DueDate        time.Time `json:"due_date" binding:"required"`
     Priority       string    `json:"priority"`
     EstimatedHours int       `json:"estimated_hours"`
+    Tags           []string  `json:"tags"`
+    ApplyCorrection bool     `json:"apply_correction"` // Scale EstimatedHours by the user's historical accuracy
 }
 
 func (s *AssignmentService) GetUserAssignments(userID uint, status, priority string) ([]models.Assignment, error) {
@@ -52,12 +61,22 @@ func (s *AssignmentService) CreateAssignment(userID uint, req CreateAssignmentRe
         Priority:       req.Priority,
         EstimatedHours: req.EstimatedHours,
         Status:         "pending",
+        Tags:           normalizeTags(req.Tags),
     }
 
     if assignment.Priority == "" {
         assignment.Priority = "medium"
     }
 
+    if req.ApplyCorrection && req.EstimatedHours > 0 {
+        factor, err := correctionFactor(s.db, userID, req.CourseID)
+        if err != nil {
+            return nil, err
+        }
+        assignment.RawEstimatedHours = req.EstimatedHours
+        assignment.EstimatedHours = int(math.Round(float64(req.EstimatedHours) * factor))
+    }
+
     err := s.db.Create(&assignment).Error
     if err != nil {
         return nil, err
@@ -83,6 +102,64 @@ func (s *AssignmentService) UpdateAssignment(userID, assignmentID uint, updates
         return nil, err
     }
 
+    // Ownership and identity never change
+    delete(updates, "id")
+    delete(updates, "user_id")
+    // Tracked time is derived from time entries, not edited directly
+    delete(updates, "tracked_minutes")
+    // Grades go through the gradebook so they are validated against the course
+    delete(updates, "category_id")
+    delete(updates, "score")
+    delete(updates, "max_score")
+    // Milestone links are managed from the milestone
+    delete(updates, "milestone_id")
+    // Paper deadlines are kept in sync from the paper
+    delete(updates, "paper_id")
+    delete(updates, "paper_deadline")
+    // Promoted action items stay linked to their meeting
+    delete(updates, "advisor_meeting_id")
+    // Duty assignments are kept in sync from the duty
+    delete(updates, "duty_id")
+    // Funding reminders follow their deadline
+    delete(updates, "funding_deadline_id")
+    // Series membership is managed by the recurrence service
+    delete(updates, "series_id")
+    delete(updates, "occurrence_date")
+    delete(updates, "is_exception")
+    // Sync identities belong to the calendar client or import source
+    delete(updates, "ical_uid")
+    delete(updates, "caldav_name")
+    delete(updates, "external_key")
+    if assignment.TrackedMinutes > 0 {
+        delete(updates, "actual_hours")
+    }
+    // A map update skips the JSON serializer, so tags are encoded here
+    if value, ok := updates["tags"]; ok {
+        tags, err := toTags(value)
+        if err != nil {
+            return nil, err
+        }
+        data, err := json.Marshal(tags)
+        if err != nil {
+            return nil, err
+        }
+        updates["tags"] = string(data)
+    }
+
+    // Work cannot start or finish while blockers are still open
+    if status, ok := updates["status"]; ok {
+        if err := checkStatusChange(s.db, assignment.ID, assignment.Status, status); err != nil {
+            return nil, err
+        }
+    }
+
+    setCompletedAt(updates, assignment.Status, time.Now())
+
+    // Editing a single occurrence detaches it from later series-wide edits
+    if assignment.SeriesID != nil && touchesSeriesFields(updates) {
+        updates["is_exception"] = true
+    }
+
     err := s.db.Model(&assignment).Updates(updates).Error
     if err != nil {
         return nil, err
@@ -95,5 +172,63 @@ func (s *AssignmentService) UpdateAssignment(userID, assignmentID uint, updates
 }
 
 func (s *AssignmentService) DeleteAssignment(userID, assignmentID uint) error {
-    return s.db.Where("id = ? AND user_id = ?", assignmentID, userID).Delete(&models.Assignment{}).Error
-}
\ No newline at end of file
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("id = ? AND user_id = ?", assignmentID, userID).Delete(&models.Assignment{})
+        if result.Error != nil || result.RowsAffected == 0 {
+            return result.Error
+        }
+
+        // Drop dependency edges pointing at or from the deleted assignment
+        return tx.Where("assignment_id = ? OR blocked_by_id = ?", assignmentID, assignmentID).
+            Delete(&models.AssignmentDependency{}).Error
+    })
+}
+
+// setCompletedAt keeps completed_at in step with a status change in
+// updates: stamped when the assignment becomes completed, cleared when it
+// is reopened.
+func setCompletedAt(updates map[string]interface{}, from string, now time.Time) {
+    status, ok := updates["status"]
+    if !ok {
+        return
+    }
+    switch {
+    case status == "completed" && from != "completed":
+        updates["completed_at"] = now
+    case status != "completed":
+        updates["completed_at"] = nil
+    }
+}
+
+// normalizeTags lower-cases and trims tags, dropping blanks and repeats.
+func normalizeTags(values []string) []string {
+    var tags []string
+    for _, value := range values {
+        tag := strings.ToLower(strings.TrimSpace(value))
+        if tag != "" && !containsString(tags, tag) {
+            tags = append(tags, tag)
+        }
+    }
+    return tags
+}
+
+func toTags(value interface{}) ([]string, error) {
+    switch v := value.(type) {
+    case nil:
+        return nil, nil
+    case []string:
+        return normalizeTags(v), nil
+    case []interface{}:
+        values := make([]string, 0, len(v))
+        for _, item := range v {
+            str, ok := item.(string)
+            if !ok {
+                return nil, fmt.Errorf("%w: tags must be a list of strings", ErrInvalidAssignment)
+            }
+            values = append(values, str)
+        }
+        return normalizeTags(values), nil
+    default:
+        return nil, fmt.Errorf("%w: tags must be a list of strings", ErrInvalidAssignment)
+    }
+}
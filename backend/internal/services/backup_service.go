@@ -0,0 +1,783 @@
+package services
+
+import (
+    "archive/zip"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+const (
+    BackupFormat = "academiaflow-backup"
+    // Bump when a data file changes shape. Files added later are optional,
+    // so older archives still restore.
+    BackupVersion = 1
+    // Caps on the decompressed size of restored data, per file and in
+    // total, since a small archive can inflate to any size
+    maxBackupEntrySize = 32 << 20
+    maxBackupDataSize  = 128 << 20
+)
+
+var (
+    ErrAccountNotEmpty = errors.New("account already has data; restore into a new account")
+    ErrInvalidBackup   = errors.New("invalid backup archive")
+)
+
+// BackupManifest is manifest.json, listing each data file and its record
+// count.
+type BackupManifest struct {
+    Format       string         `json:"format"`
+    Version      int            `json:"version"`
+    CreatedAt    time.Time      `json:"created_at"`
+    SourceUserID uint           `json:"source_user_id"`
+    Files        map[string]int `json:"files"`
+}
+
+// backupData is the archive content. IDs are those of the source server
+// and are remapped on restore.
+type backupData struct {
+    Profile          UserResponse
+    Terms            []models.Term
+    Scales           []models.GradingScale
+    Programs         []models.DegreeProgram
+    Milestones       []models.Milestone
+    Papers           []models.Paper
+    PaperRounds      []models.PaperRound
+    Courses          []models.Course
+    Meetings         []models.CourseMeeting
+    Categories       []models.GradeCategory
+    Series           []models.AssignmentSeries
+    Assignments      []models.Assignment
+    Tombstones       []models.Assignment // Deleted series occurrences; they keep their dates from being regenerated
+    CalDAVNames      map[uint]string     // Assignment ID to CalDAV resource name, which the API doesn't expose
+    Dependencies     []models.AssignmentDependency
+    TimeEntries      []models.TimeEntry
+    Availability     []models.Availability
+    Readings         []models.Reading
+    AdvisorMeetings  []models.AdvisorMeeting
+    ActionItems      []models.ActionItem
+    ReportSchedules  []models.ReportSchedule
+    Preferences      []models.NotificationPreference
+    Appointments     []models.Appointment
+    Duties           []models.Duty
+    Fundings         []models.Funding
+    FundingDeadlines []models.FundingDeadline
+    Theses           []models.Thesis
+    ThesisChapters   []models.ThesisChapter
+    WordCountSamples []models.WordCountSample
+    Goals            []models.Goal
+    KeyResults       []models.KeyResult
+    GoalCheckIns     []models.GoalCheckIn
+}
+
+type backupFile struct {
+    name  string
+    value interface{}
+    count int
+}
+
+// files maps archive file names onto the fields they hold, in restore
+// order.
+func (d *backupData) files() []backupFile {
+    return []backupFile{
+        {"profile.json", &d.Profile, 1},
+        {"terms.json", &d.Terms, len(d.Terms)},
+        {"grading_scales.json", &d.Scales, len(d.Scales)},
+        {"degree_programs.json", &d.Programs, len(d.Programs)},
+        {"milestones.json", &d.Milestones, len(d.Milestones)},
+        {"papers.json", &d.Papers, len(d.Papers)},
+        {"paper_rounds.json", &d.PaperRounds, len(d.PaperRounds)},
+        {"advisor_meetings.json", &d.AdvisorMeetings, len(d.AdvisorMeetings)},
+        {"courses.json", &d.Courses, len(d.Courses)},
+        {"course_meetings.json", &d.Meetings, len(d.Meetings)},
+        {"grade_categories.json", &d.Categories, len(d.Categories)},
+        {"appointments.json", &d.Appointments, len(d.Appointments)},
+        {"duties.json", &d.Duties, len(d.Duties)},
+        {"funding.json", &d.Fundings, len(d.Fundings)},
+        {"funding_deadlines.json", &d.FundingDeadlines, len(d.FundingDeadlines)},
+        {"theses.json", &d.Theses, len(d.Theses)},
+        {"thesis_chapters.json", &d.ThesisChapters, len(d.ThesisChapters)},
+        {"word_count_samples.json", &d.WordCountSamples, len(d.WordCountSamples)},
+        {"assignment_series.json", &d.Series, len(d.Series)},
+        {"assignments.json", &d.Assignments, len(d.Assignments)},
+        {"deleted_occurrences.json", &d.Tombstones, len(d.Tombstones)},
+        {"caldav_names.json", &d.CalDAVNames, len(d.CalDAVNames)},
+        {"assignment_dependencies.json", &d.Dependencies, len(d.Dependencies)},
+        {"time_entries.json", &d.TimeEntries, len(d.TimeEntries)},
+        {"availability.json", &d.Availability, len(d.Availability)},
+        {"readings.json", &d.Readings, len(d.Readings)},
+        {"action_items.json", &d.ActionItems, len(d.ActionItems)},
+        {"goals.json", &d.Goals, len(d.Goals)},
+        {"key_results.json", &d.KeyResults, len(d.KeyResults)},
+        {"goal_check_ins.json", &d.GoalCheckIns, len(d.GoalCheckIns)},
+        {"report_schedule.json", &d.ReportSchedules, len(d.ReportSchedules)},
+        {"notification_preferences.json", &d.Preferences, len(d.Preferences)},
+    }
+}
+
+type RestoreResult struct {
+    Manifest BackupManifest `json:"manifest"`
+    Restored map[string]int `json:"restored"`
+    Skipped  map[string]int `json:"skipped,omitempty"`
+}
+
+type BackupService struct {
+    db *gorm.DB
+}
+
+func NewBackupService() *BackupService {
+    return &BackupService{
+        db: database.GetDB(),
+    }
+}
+
+// Export writes a zip archive of everything the user owns. Secrets such as
+// the password hash, calendar feed token and thesis push tokens are left
+// out.
+func (s *BackupService) Export(userID uint, w io.Writer) error {
+    var user models.User
+    if err := s.db.First(&user, userID).Error; err != nil {
+        return err
+    }
+
+    data := backupData{Profile: ToUserResponse(&user)}
+    queries := []interface{}{&data.Terms, &data.Scales, &data.Programs, &data.Milestones, &data.Papers, &data.PaperRounds, &data.Courses, &data.Meetings, &data.Categories, &data.Series, &data.Assignments, &data.Dependencies, &data.TimeEntries, &data.Availability, &data.Readings, &data.AdvisorMeetings, &data.ActionItems, &data.ReportSchedules, &data.Preferences, &data.Appointments, &data.Duties, &data.Fundings, &data.FundingDeadlines, &data.Theses, &data.ThesisChapters, &data.WordCountSamples, &data.Goals, &data.KeyResults, &data.GoalCheckIns}
+    for _, dest := range queries {
+        if err := s.db.Where("user_id = ?", userID).Order("id ASC").Find(dest).Error; err != nil {
+            return err
+        }
+    }
+    if err := s.db.Unscoped().Where("user_id = ? AND series_id IS NOT NULL AND deleted_at IS NOT NULL", userID).
+        Order("id ASC").Find(&data.Tombstones).Error; err != nil {
+        return err
+    }
+    data.CalDAVNames = map[uint]string{}
+    for _, assignment := range data.Assignments {
+        if assignment.CalDAVName != "" {
+            data.CalDAVNames[assignment.ID] = assignment.CalDAVName
+        }
+    }
+
+    manifest := BackupManifest{
+        Format:       BackupFormat,
+        Version:      BackupVersion,
+        CreatedAt:    time.Now().UTC(),
+        SourceUserID: userID,
+        Files:        map[string]int{},
+    }
+    for _, f := range data.files() {
+        manifest.Files[f.name] = f.count
+    }
+
+    archive := zip.NewWriter(w)
+    if err := writeZipJSON(archive, "manifest.json", manifest); err != nil {
+        return err
+    }
+    for _, f := range data.files() {
+        if err := writeZipJSON(archive, f.name, f.value); err != nil {
+            return err
+        }
+    }
+    return archive.Close()
+}
+
+// Restore loads an archive into userID's account, which must not have any
+// terms, courses or assignments yet. Every record gets a new ID and references
+// between records are rewritten to match. Profile details are copied but
+// email, username and password stay as they are.
+func (s *BackupService) Restore(userID uint, r io.ReaderAt, size int64) (*RestoreResult, error) {
+    archive, err := zip.NewReader(r, size)
+    if err != nil {
+        return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+    }
+
+    budget := int64(maxBackupDataSize)
+    var manifest BackupManifest
+    if err := readZipJSON(archive, "manifest.json", &manifest, &budget); err != nil {
+        return nil, err
+    }
+    if manifest.Format != BackupFormat {
+        return nil, fmt.Errorf("%w: not an AcademiaFlow backup", ErrInvalidBackup)
+    }
+    if manifest.Version < 1 || manifest.Version > BackupVersion {
+        return nil, fmt.Errorf("%w: version %d is not supported by this server (max %d)", ErrInvalidBackup, manifest.Version, BackupVersion)
+    }
+
+    var data backupData
+    for _, f := range data.files() {
+        if _, listed := manifest.Files[f.name]; !listed {
+            continue
+        }
+        if err := readZipJSON(archive, f.name, f.value, &budget); err != nil {
+            return nil, err
+        }
+    }
+
+    result := &RestoreResult{Manifest: manifest, Restored: map[string]int{}, Skipped: map[string]int{}}
+    err = s.db.Transaction(func(tx *gorm.DB) error {
+        for _, model := range []interface{}{&models.Term{}, &models.Course{}, &models.Assignment{}, &models.AssignmentSeries{}} {
+            var count int64
+            if err := tx.Model(model).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+                return err
+            }
+            if count > 0 {
+                return ErrAccountNotEmpty
+            }
+        }
+
+        profile := map[string]interface{}{
+            "first_name":         data.Profile.FirstName,
+            "last_name":          data.Profile.LastName,
+            "program":            data.Profile.Program,
+            "year":               data.Profile.Year,
+            "advisor":            data.Profile.Advisor,
+            "advisor_email":      data.Profile.AdvisorEmail,
+            "program_start_date": data.Profile.ProgramStartDate,
+        }
+        var user models.User
+        if err := tx.First(&user, userID).Error; err != nil {
+            return err
+        }
+        stampAdvisorEmailChange(profile, &user, time.Now())
+        if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(profile).Error; err != nil {
+            return err
+        }
+
+        termIDs := map[uint]uint{}
+        for _, term := range data.Terms {
+            oldID := term.ID
+            term.ID, term.UserID = 0, userID
+            if err := tx.Create(&term).Error; err != nil {
+                return err
+            }
+            termIDs[oldID] = term.ID
+        }
+        result.Restored["terms"] = len(termIDs)
+
+        scaleIDs := map[uint]uint{}
+        for _, scale := range data.Scales {
+            oldID := scale.ID
+            scale.ID, scale.UserID = 0, userID
+            if err := tx.Create(&scale).Error; err != nil {
+                return err
+            }
+            scaleIDs[oldID] = scale.ID
+        }
+        result.Restored["grading_scales"] = len(scaleIDs)
+
+        programIDs := map[uint]uint{}
+        for _, program := range data.Programs {
+            oldID := program.ID
+            program.ID, program.UserID = 0, userID
+            if err := tx.Create(&program).Error; err != nil {
+                return err
+            }
+            programIDs[oldID] = program.ID
+        }
+        result.Restored["degree_programs"] = len(programIDs)
+        if programID := remapID(data.Profile.DegreeProgramID, programIDs); programID != nil {
+            if err := tx.Model(&models.User{}).Where("id = ?", userID).Update("degree_program_id", *programID).Error; err != nil {
+                return err
+            }
+        }
+
+        milestoneIDs := map[uint]uint{}
+        for _, milestone := range data.Milestones {
+            oldID := milestone.ID
+            milestone.ID, milestone.UserID, milestone.Assignments = 0, userID, nil
+            if err := tx.Create(&milestone).Error; err != nil {
+                return err
+            }
+            milestoneIDs[oldID] = milestone.ID
+        }
+        result.Restored["milestones"] = len(milestoneIDs)
+
+        paperIDs := map[uint]uint{}
+        for _, paper := range data.Papers {
+            oldID := paper.ID
+            paper.ID, paper.UserID, paper.Rounds, paper.Assignments = 0, userID, nil, nil
+            if err := tx.Create(&paper).Error; err != nil {
+                return err
+            }
+            paperIDs[oldID] = paper.ID
+        }
+        result.Restored["papers"] = len(paperIDs)
+
+        for _, round := range data.PaperRounds {
+            paperID, ok := paperIDs[round.PaperID]
+            if !ok {
+                result.Skipped["paper_rounds"]++
+                continue
+            }
+            round.ID, round.UserID, round.PaperID = 0, userID, paperID
+            if err := tx.Create(&round).Error; err != nil {
+                return err
+            }
+            result.Restored["paper_rounds"]++
+        }
+
+        advisorMeetingIDs := map[uint]uint{}
+        for _, meeting := range data.AdvisorMeetings {
+            oldID := meeting.ID
+            meeting.ID, meeting.UserID, meeting.ActionItems = 0, userID, nil
+            if err := tx.Create(&meeting).Error; err != nil {
+                return err
+            }
+            advisorMeetingIDs[oldID] = meeting.ID
+        }
+        result.Restored["advisor_meetings"] = len(advisorMeetingIDs)
+
+        courseIDs := map[uint]uint{}
+        for _, course := range data.Courses {
+            oldID := course.ID
+            course.ID, course.UserID, course.Term = 0, userID, nil
+            course.TermID = remapID(course.TermID, termIDs)
+            course.GradingScaleID = remapID(course.GradingScaleID, scaleIDs)
+            if err := tx.Create(&course).Error; err != nil {
+                return err
+            }
+            courseIDs[oldID] = course.ID
+        }
+        result.Restored["courses"] = len(courseIDs)
+
+        for _, meeting := range data.Meetings {
+            courseID, ok := courseIDs[meeting.CourseID]
+            if !ok {
+                result.Skipped["course_meetings"]++
+                continue
+            }
+            meeting.ID, meeting.UserID, meeting.CourseID = 0, userID, courseID
+            if err := tx.Create(&meeting).Error; err != nil {
+                return err
+            }
+            result.Restored["course_meetings"]++
+        }
+
+        categoryIDs := map[uint]uint{}
+        for _, category := range data.Categories {
+            oldID := category.ID
+            courseID, ok := courseIDs[category.CourseID]
+            if !ok {
+                result.Skipped["grade_categories"]++
+                continue
+            }
+            category.ID, category.UserID, category.CourseID = 0, userID, courseID
+            if err := tx.Create(&category).Error; err != nil {
+                return err
+            }
+            categoryIDs[oldID] = category.ID
+        }
+        result.Restored["grade_categories"] = len(categoryIDs)
+
+        appointmentIDs := map[uint]uint{}
+        for _, appointment := range data.Appointments {
+            oldID := appointment.ID
+            appointment.ID, appointment.UserID, appointment.Course, appointment.Duties = 0, userID, nil, nil
+            appointment.CourseID = remapID(appointment.CourseID, courseIDs)
+            if err := tx.Create(&appointment).Error; err != nil {
+                return err
+            }
+            appointmentIDs[oldID] = appointment.ID
+        }
+        result.Restored["appointments"] = len(appointmentIDs)
+
+        dutyIDs := map[uint]uint{}
+        for _, duty := range data.Duties {
+            oldID := duty.ID
+            appointmentID, ok := appointmentIDs[duty.AppointmentID]
+            if !ok {
+                result.Skipped["duties"]++
+                continue
+            }
+            duty.ID, duty.UserID, duty.AppointmentID, duty.Assignment = 0, userID, appointmentID, nil
+            if err := tx.Create(&duty).Error; err != nil {
+                return err
+            }
+            dutyIDs[oldID] = duty.ID
+        }
+        result.Restored["duties"] = len(dutyIDs)
+
+        fundingIDs := map[uint]uint{}
+        for _, funding := range data.Fundings {
+            oldID := funding.ID
+            funding.ID, funding.UserID, funding.Deadlines = 0, userID, nil
+            funding.Conditions.GPAScaleID = remapID(funding.Conditions.GPAScaleID, scaleIDs)
+            if err := tx.Create(&funding).Error; err != nil {
+                return err
+            }
+            fundingIDs[oldID] = funding.ID
+        }
+        result.Restored["funding"] = len(fundingIDs)
+
+        fundingDeadlineIDs := map[uint]uint{}
+        for _, deadline := range data.FundingDeadlines {
+            oldID := deadline.ID
+            fundingID, ok := fundingIDs[deadline.FundingID]
+            if !ok {
+                result.Skipped["funding_deadlines"]++
+                continue
+            }
+            deadline.ID, deadline.UserID, deadline.FundingID = 0, userID, fundingID
+            if err := tx.Create(&deadline).Error; err != nil {
+                return err
+            }
+            fundingDeadlineIDs[oldID] = deadline.ID
+        }
+        result.Restored["funding_deadlines"] = len(fundingDeadlineIDs)
+
+        // Push tokens aren't exported, so restored theses get new ones
+        thesisIDs := map[uint]uint{}
+        for _, thesis := range data.Theses {
+            oldID := thesis.ID
+            token, err := generateToken()
+            if err != nil {
+                return err
+            }
+            thesis.ID, thesis.UserID, thesis.Chapters, thesis.PushToken = 0, userID, nil, token
+            thesis.MilestoneID = remapID(thesis.MilestoneID, milestoneIDs)
+            if err := tx.Create(&thesis).Error; err != nil {
+                return err
+            }
+            thesisIDs[oldID] = thesis.ID
+        }
+        result.Restored["theses"] = len(thesisIDs)
+
+        chapterIDs := map[uint]uint{}
+        for _, chapter := range data.ThesisChapters {
+            oldID := chapter.ID
+            thesisID, ok := thesisIDs[chapter.ThesisID]
+            if !ok {
+                result.Skipped["thesis_chapters"]++
+                continue
+            }
+            chapter.ID, chapter.UserID, chapter.ThesisID = 0, userID, thesisID
+            if err := tx.Create(&chapter).Error; err != nil {
+                return err
+            }
+            chapterIDs[oldID] = chapter.ID
+        }
+        result.Restored["thesis_chapters"] = len(chapterIDs)
+
+        for _, sample := range data.WordCountSamples {
+            thesisID, ok1 := thesisIDs[sample.ThesisID]
+            chapterID, ok2 := chapterIDs[sample.ChapterID]
+            if !ok1 || !ok2 {
+                result.Skipped["word_count_samples"]++
+                continue
+            }
+            sample.ID, sample.UserID, sample.ThesisID, sample.ChapterID = 0, userID, thesisID, chapterID
+            // Older archives can hold more than one sample for a day
+            created := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&sample)
+            if created.Error != nil {
+                return created.Error
+            }
+            if created.RowsAffected == 0 {
+                result.Skipped["word_count_samples"]++
+                continue
+            }
+            result.Restored["word_count_samples"]++
+        }
+
+        seriesIDs := map[uint]uint{}
+        for _, series := range data.Series {
+            oldID := series.ID
+            series.ID, series.UserID, series.Course = 0, userID, nil
+            series.CourseID = remapID(series.CourseID, courseIDs)
+            if err := tx.Create(&series).Error; err != nil {
+                return err
+            }
+            seriesIDs[oldID] = series.ID
+        }
+        result.Restored["assignment_series"] = len(seriesIDs)
+
+        assignmentIDs := map[uint]uint{}
+        for _, assignment := range data.Assignments {
+            oldID := assignment.ID
+            assignment.ID, assignment.UserID, assignment.Course = 0, userID, nil
+            assignment.CourseID = remapID(assignment.CourseID, courseIDs)
+            assignment.SeriesID = remapID(assignment.SeriesID, seriesIDs)
+            assignment.CategoryID = remapID(assignment.CategoryID, categoryIDs)
+            assignment.MilestoneID = remapID(assignment.MilestoneID, milestoneIDs)
+            assignment.PaperID = remapID(assignment.PaperID, paperIDs)
+            if assignment.PaperID == nil {
+                assignment.PaperDeadline = ""
+            }
+            assignment.AdvisorMeetingID = remapID(assignment.AdvisorMeetingID, advisorMeetingIDs)
+            assignment.DutyID = remapID(assignment.DutyID, dutyIDs)
+            assignment.FundingDeadlineID = remapID(assignment.FundingDeadlineID, fundingDeadlineIDs)
+            assignment.CalDAVName = data.CalDAVNames[oldID]
+            // Backups from before completion times were kept
+            if assignment.Status == "completed" && assignment.CompletedAt == nil {
+                completedAt := assignment.UpdatedAt
+                assignment.CompletedAt = &completedAt
+            }
+            if err := tx.Create(&assignment).Error; err != nil {
+                return err
+            }
+            assignmentIDs[oldID] = assignment.ID
+        }
+        result.Restored["assignments"] = len(assignmentIDs)
+
+        // Deleted occurrences go back in deleted, so expanding the series
+        // doesn't bring them back to life
+        for _, tombstone := range data.Tombstones {
+            seriesID := remapID(tombstone.SeriesID, seriesIDs)
+            if seriesID == nil || tombstone.OccurrenceDate == nil {
+                result.Skipped["deleted_occurrences"]++
+                continue
+            }
+            restored := models.Assignment{
+                UserID:         userID,
+                CourseID:       remapID(tombstone.CourseID, courseIDs),
+                Title:          tombstone.Title,
+                DueDate:        tombstone.DueDate,
+                Priority:       tombstone.Priority,
+                Status:         tombstone.Status,
+                SeriesID:       seriesID,
+                OccurrenceDate: tombstone.OccurrenceDate,
+                IsException:    tombstone.IsException,
+                DeletedAt:      gorm.DeletedAt{Time: tombstone.UpdatedAt, Valid: true},
+            }
+            if err := tx.Create(&restored).Error; err != nil {
+                return err
+            }
+            result.Restored["deleted_occurrences"]++
+        }
+
+        for _, dep := range data.Dependencies {
+            assignmentID, ok1 := assignmentIDs[dep.AssignmentID]
+            blockedByID, ok2 := assignmentIDs[dep.BlockedByID]
+            if !ok1 || !ok2 {
+                result.Skipped["assignment_dependencies"]++
+                continue
+            }
+            dep.ID, dep.UserID, dep.BlockedBy = 0, userID, nil
+            dep.AssignmentID, dep.BlockedByID = assignmentID, blockedByID
+            if err := tx.Create(&dep).Error; err != nil {
+                return err
+            }
+            result.Restored["assignment_dependencies"]++
+        }
+
+        for _, entry := range data.TimeEntries {
+            assignmentID, ok := assignmentIDs[entry.AssignmentID]
+            if !ok {
+                result.Skipped["time_entries"]++
+                continue
+            }
+            entry.ID, entry.UserID, entry.Assignment = 0, userID, nil
+            entry.AssignmentID = assignmentID
+            if err := tx.Create(&entry).Error; err != nil {
+                return err
+            }
+            result.Restored["time_entries"]++
+        }
+
+        // Only one availability per user; replace whatever the new account has
+        for _, availability := range data.Availability {
+            if err := tx.Where("user_id = ?", userID).Delete(&models.Availability{}).Error; err != nil {
+                return err
+            }
+            availability.ID, availability.UserID = 0, userID
+            if err := tx.Create(&availability).Error; err != nil {
+                return err
+            }
+            result.Restored["availability"] = 1
+        }
+
+        for _, reading := range data.Readings {
+            reading.ID, reading.UserID = 0, userID
+            reading.CourseID = remapID(reading.CourseID, courseIDs)
+            reading.AssignmentID = remapID(reading.AssignmentID, assignmentIDs)
+            reading.PaperID = remapID(reading.PaperID, paperIDs)
+            if err := tx.Create(&reading).Error; err != nil {
+                return err
+            }
+            result.Restored["readings"]++
+        }
+
+        for _, item := range data.ActionItems {
+            meetingID, ok := advisorMeetingIDs[item.MeetingID]
+            if !ok {
+                result.Skipped["action_items"]++
+                continue
+            }
+            item.ID, item.UserID, item.MeetingID, item.Assignment = 0, userID, meetingID, nil
+            item.AssignmentID = remapID(item.AssignmentID, assignmentIDs)
+            if err := tx.Create(&item).Error; err != nil {
+                return err
+            }
+            result.Restored["action_items"]++
+        }
+
+        goalIDs := map[uint]uint{}
+        for _, goal := range data.Goals {
+            oldID := goal.ID
+            goal.ID, goal.UserID, goal.KeyResults, goal.CheckIns = 0, userID, nil, nil
+            goal.TermID = remapID(goal.TermID, termIDs)
+            if goal.TermID == nil && goal.Period == GoalPeriodTerm {
+                goal.Period = GoalPeriodCustom
+            }
+            if err := tx.Create(&goal).Error; err != nil {
+                return err
+            }
+            goalIDs[oldID] = goal.ID
+        }
+        result.Restored["goals"] = len(goalIDs)
+
+        for _, keyResult := range data.KeyResults {
+            goalID, ok := goalIDs[keyResult.GoalID]
+            if !ok {
+                result.Skipped["key_results"]++
+                continue
+            }
+            keyResult.ID, keyResult.UserID, keyResult.GoalID, keyResult.Assignment = 0, userID, goalID, nil
+            keyResult.AssignmentID = remapID(keyResult.AssignmentID, assignmentIDs)
+            if err := tx.Create(&keyResult).Error; err != nil {
+                return err
+            }
+            result.Restored["key_results"]++
+        }
+
+        for _, checkIn := range data.GoalCheckIns {
+            goalID, ok := goalIDs[checkIn.GoalID]
+            if !ok {
+                result.Skipped["goal_check_ins"]++
+                continue
+            }
+            checkIn.ID, checkIn.UserID, checkIn.GoalID = 0, userID, goalID
+            if err := tx.Create(&checkIn).Error; err != nil {
+                return err
+            }
+            result.Restored["goal_check_ins"]++
+        }
+
+        // Like availability, one schedule per user. Send history belongs to
+        // the old server, so the next run is worked out afresh.
+        for _, schedule := range data.ReportSchedules {
+            if err := tx.Where("user_id = ?", userID).Delete(&models.ReportSchedule{}).Error; err != nil {
+                return err
+            }
+            schedule.ID, schedule.UserID = 0, userID
+            schedule.LastSentAt, schedule.LastError, schedule.NextRunAt = nil, "", nil
+            if schedule.Enabled {
+                next := nextReportRun(&schedule, time.Now())
+                schedule.NextRunAt = &next
+            }
+            if err := tx.Create(&schedule).Error; err != nil {
+                return err
+            }
+            result.Restored["report_schedule"] = 1
+        }
+
+        // Notification preferences are also one per user. Past
+        // notifications and jobs aren't carried over.
+        for _, pref := range data.Preferences {
+            if err := tx.Where("user_id = ?", userID).Delete(&models.NotificationPreference{}).Error; err != nil {
+                return err
+            }
+            pref.ID, pref.UserID = 0, userID
+            if err := tx.Create(&pref).Error; err != nil {
+                return err
+            }
+            result.Restored["notification_preferences"] = 1
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    if len(result.Skipped) == 0 {
+        result.Skipped = nil
+    }
+    return result, nil
+}
+
+// remapID translates a reference to an ID from the archive. References to
+// records that were not in the archive are dropped.
+func remapID(id *uint, ids map[uint]uint) *uint {
+    if id == nil {
+        return nil
+    }
+    newID, ok := ids[*id]
+    if !ok {
+        return nil
+    }
+    return &newID
+}
+
+func writeZipJSON(archive *zip.Writer, name string, value interface{}) error {
+    w, err := archive.Create(name)
+    if err != nil {
+        return err
+    }
+    encoder := json.NewEncoder(w)
+    encoder.SetIndent("", "  ")
+    return encoder.Encode(value)
+}
+
+// ReadProfile returns the profile saved in an archive, for creating the
+// account it is restored into.
+func (s *BackupService) ReadProfile(r io.ReaderAt, size int64) (*UserResponse, error) {
+    archive, err := zip.NewReader(r, size)
+    if err != nil {
+        return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+    }
+    var profile UserResponse
+    budget := int64(maxBackupEntrySize)
+    if err := readZipJSON(archive, "profile.json", &profile, &budget); err != nil {
+        return nil, err
+    }
+    return &profile, nil
+}
+
+// readZipJSON decodes one file of the archive, counting its size against
+// budget. Neither the file nor the running total may exceed the caps.
+func readZipJSON(archive *zip.Reader, name string, value interface{}, budget *int64) error {
+    f, err := archive.Open(name)
+    if err != nil {
+        return fmt.Errorf("%w: missing %s", ErrInvalidBackup, name)
+    }
+    defer f.Close()
+    info, err := f.Stat()
+    if err != nil {
+        return fmt.Errorf("%w: %s: %v", ErrInvalidBackup, name, err)
+    }
+    limit := int64(maxBackupEntrySize)
+    if *budget < limit {
+        limit = *budget
+    }
+    if info.Size() > limit {
+        return fmt.Errorf("%w: %s is too large", ErrInvalidBackup, name)
+    }
+    // The declared size can lie, so the read itself is capped too
+    counted := &countingReader{r: io.LimitReader(f, limit+1)}
+    err = json.NewDecoder(counted).Decode(value)
+    *budget -= counted.n
+    if counted.n > limit {
+        return fmt.Errorf("%w: %s is too large", ErrInvalidBackup, name)
+    }
+    if err != nil {
+        return fmt.Errorf("%w: %s: %v", ErrInvalidBackup, name, err)
+    }
+    return nil
+}
+
+type countingReader struct {
+    r io.Reader
+    n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+    n, err := c.r.Read(p)
+    c.n += int64(n)
+    return n, err
+}
@@ -0,0 +1,801 @@
+package services
+
+import (
+    "bytes"
+    "encoding/csv"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+const (
+    ImportAtomic     = "atomic"
+    ImportBestEffort = "best_effort"
+)
+
+var ErrInvalidImport = errors.New("invalid import")
+
+// BulkImportOptions controls ImportRecords. Mapping renames source
+// columns (or JSON keys) onto our field names, e.g. {"due_date": "Deadline"}.
+type BulkImportOptions struct {
+    Format   string // csv or json
+    Entity   string // required for CSV and for a bare JSON array
+    Mode     string // atomic (default) or best_effort
+    Mapping  map[string]string
+    Location *time.Location // for due dates without an offset; UTC when nil
+}
+
+type RowError struct {
+    Entity      string `json:"entity"`
+    Row         int    `json:"row"` // CSV line number, or 1-based index in a JSON array
+    ExternalKey string `json:"external_key,omitempty"`
+    Field       string `json:"field,omitempty"`
+    Message     string `json:"message"`
+}
+
+type BulkImportCounts struct {
+    Created   int `json:"created"`
+    Updated   int `json:"updated"`
+    Unchanged int `json:"unchanged"`
+    Failed    int `json:"failed"`
+}
+
+type BulkImportResult struct {
+    Mode        string           `json:"mode"`
+    Committed   bool             `json:"committed"`
+    Courses     BulkImportCounts `json:"courses"`
+    Assignments BulkImportCounts `json:"assignments"`
+    Errors      []RowError       `json:"errors"`
+}
+
+// importRow holds the mapped values of one source row, keyed by our field
+// names. Columns absent from the source are absent from values, so an
+// upsert only touches what the file provides.
+type importRow struct {
+    line   int
+    values map[string]string
+}
+
+// plannedRow is a validated row ready to be written.
+type plannedRow struct {
+    entity     string
+    row        int
+    key        string
+    create     bool
+    columns    []string
+    course     *models.Course
+    assignment *models.Assignment
+    courseKey  string // course created by this same import, resolved on write
+}
+
+// ImportRecords creates or updates courses and assignments from a CSV or
+// JSON file. Rows with an external_key update the record imported with
+// that key before, so running the same import twice changes nothing. In
+// atomic mode any invalid row aborts the whole import; in best-effort
+// mode valid rows are saved and the rest reported.
+func (s *ImportService) ImportRecords(userID uint, r io.Reader, opts BulkImportOptions) (*BulkImportResult, error) {
+    if opts.Mode == "" {
+        opts.Mode = ImportAtomic
+    }
+    if opts.Mode != ImportAtomic && opts.Mode != ImportBestEffort {
+        return nil, fmt.Errorf("%w: mode must be atomic or best_effort", ErrInvalidImport)
+    }
+    if opts.Entity != "" && opts.Entity != EntityCourses && opts.Entity != EntityAssignments {
+        return nil, fmt.Errorf("%w: type must be courses or assignments", ErrInvalidImport)
+    }
+    if opts.Location == nil {
+        opts.Location = time.UTC
+    }
+    for field := range opts.Mapping {
+        if !containsString(CourseColumns, field) && !containsString(AssignmentColumns, field) {
+            return nil, fmt.Errorf("%w: unknown field %q in mapping", ErrInvalidImport, field)
+        }
+    }
+
+    var rows map[string][]importRow
+    var err error
+    switch opts.Format {
+    case "csv":
+        if opts.Entity == "" {
+            return nil, fmt.Errorf("%w: type is required for CSV imports", ErrInvalidImport)
+        }
+        rows, err = parseCSVRows(r, opts.Entity, opts.Mapping)
+    case "json":
+        rows, err = parseJSONRows(r, opts.Entity, opts.Mapping)
+    default:
+        return nil, fmt.Errorf("%w: format must be csv or json", ErrInvalidImport)
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    result := &BulkImportResult{Mode: opts.Mode, Errors: []RowError{}}
+
+    var courses []models.Course
+    if err := s.db.Where("user_id = ?", userID).Find(&courses).Error; err != nil {
+        return nil, err
+    }
+    coursesByKey := map[string]*models.Course{}
+    coursesByCode := map[string][]uint{}
+    for i := range courses {
+        if courses[i].ExternalKey != "" {
+            coursesByKey[courses[i].ExternalKey] = &courses[i]
+        }
+        code := strings.ToLower(courses[i].CourseCode)
+        coursesByCode[code] = append(coursesByCode[code], courses[i].ID)
+    }
+
+    var assignments []models.Assignment
+    if err := s.db.Where("user_id = ? AND external_key <> ''", userID).Find(&assignments).Error; err != nil {
+        return nil, err
+    }
+    assignmentsByKey := map[string]*models.Assignment{}
+    for i := range assignments {
+        assignmentsByKey[assignments[i].ExternalKey] = &assignments[i]
+    }
+
+    // Courses go first so assignments can refer to courses from the same file
+    var plan []*plannedRow
+    importedCourseKeys := map[string]bool{}
+    seen := map[string]bool{}
+    for _, row := range rows[EntityCourses] {
+        p, errs := planCourse(userID, row, coursesByKey, seen)
+        if len(errs) > 0 {
+            result.Errors = append(result.Errors, errs...)
+            result.Courses.Failed++
+            continue
+        }
+        if p.key != "" {
+            importedCourseKeys[p.key] = true
+        }
+        plan = append(plan, p)
+    }
+    seen = map[string]bool{}
+    for _, row := range rows[EntityAssignments] {
+        p, errs := planAssignment(userID, row, assignmentsByKey, coursesByKey, importedCourseKeys, coursesByCode, opts.Location, seen)
+        if len(errs) > 0 {
+            result.Errors = append(result.Errors, errs...)
+            result.Assignments.Failed++
+            continue
+        }
+        plan = append(plan, p)
+    }
+
+    if opts.Mode == ImportAtomic && len(result.Errors) > 0 {
+        return result, nil
+    }
+
+    courseIDs := map[string]uint{}
+    for key, course := range coursesByKey {
+        courseIDs[key] = course.ID
+    }
+
+    if opts.Mode == ImportAtomic {
+        var counts [2]BulkImportCounts
+        var failed *plannedRow
+        err := s.db.Transaction(func(tx *gorm.DB) error {
+            for _, p := range plan {
+                if err := writePlannedRow(tx, p, courseIDs); err != nil {
+                    failed = p
+                    return err
+                }
+                countWrite(&counts, p)
+            }
+            return nil
+        })
+        var blocked *BlockedError
+        if err != nil {
+            if failed == nil || errors.As(err, &blocked) {
+                return nil, err
+            }
+            result.Errors = append(result.Errors, writeError(failed, err))
+            if failed.entity == EntityCourses {
+                result.Courses.Failed++
+            } else {
+                result.Assignments.Failed++
+            }
+            return result, nil
+        }
+        result.Courses, result.Assignments = addCounts(result.Courses, counts[0]), addCounts(result.Assignments, counts[1])
+        result.Committed = true
+        return result, nil
+    }
+
+    for _, p := range plan {
+        err := s.db.Transaction(func(tx *gorm.DB) error {
+            return writePlannedRow(tx, p, courseIDs)
+        })
+        if err != nil {
+            result.Errors = append(result.Errors, writeError(p, err))
+            if p.entity == EntityCourses {
+                result.Courses.Failed++
+            } else {
+                result.Assignments.Failed++
+            }
+            continue
+        }
+        var counts [2]BulkImportCounts
+        countWrite(&counts, p)
+        result.Courses, result.Assignments = addCounts(result.Courses, counts[0]), addCounts(result.Assignments, counts[1])
+    }
+    result.Committed = true
+    return result, nil
+}
+
+func planCourse(userID uint, row importRow, existing map[string]*models.Course, seen map[string]bool) (*plannedRow, []RowError) {
+    key := row.values["external_key"]
+    p := &plannedRow{entity: EntityCourses, row: row.line, key: key}
+    var errs []RowError
+    fail := func(field, message string) {
+        errs = append(errs, RowError{Entity: EntityCourses, Row: row.line, ExternalKey: key, Field: field, Message: message})
+    }
+
+    if key != "" {
+        if seen[key] {
+            fail("external_key", "duplicate external_key in this import")
+        }
+        seen[key] = true
+    }
+    current, ok := existing[key]
+    if ok && key != "" {
+        copied := *current
+        p.course = &copied
+    } else {
+        p.create = true
+        p.course = &models.Course{UserID: userID, ExternalKey: key, Status: "enrolled"}
+    }
+    course := p.course
+
+    set := func(field string, apply func(string) string) {
+        value, present := row.values[field]
+        if !present {
+            if p.create && (field == "course_name" || field == "course_code" || field == "semester") {
+                fail(field, "is required")
+            }
+            return
+        }
+        if message := apply(value); message != "" {
+            fail(field, message)
+            return
+        }
+        p.columns = append(p.columns, field)
+    }
+    required := func(target *string) func(string) string {
+        return func(v string) string {
+            if v == "" {
+                return "is required"
+            }
+            *target = v
+            return ""
+        }
+    }
+    optional := func(target *string) func(string) string {
+        return func(v string) string {
+            *target = v
+            return ""
+        }
+    }
+
+    set("course_name", required(&course.CourseName))
+    set("course_code", required(&course.CourseCode))
+    set("semester", required(&course.Semester))
+    set("instructor", optional(&course.Instructor))
+    set("grade", optional(&course.Grade))
+    set("credits", func(v string) string {
+        n, ok := parseCount(v)
+        if !ok {
+            return "must be a non-negative whole number"
+        }
+        course.Credits = n
+        return ""
+    })
+    set("status", func(v string) string {
+        if v == "" {
+            v = "enrolled"
+        }
+        if v != "enrolled" && v != "completed" && v != "dropped" {
+            return "must be enrolled, completed or dropped"
+        }
+        course.Status = v
+        return ""
+    })
+
+    if !p.create {
+        p.columns = changedColumns(p.columns, func(column string) bool {
+            switch column {
+            case "course_name":
+                return current.CourseName != course.CourseName
+            case "course_code":
+                return current.CourseCode != course.CourseCode
+            case "semester":
+                return current.Semester != course.Semester
+            case "instructor":
+                return current.Instructor != course.Instructor
+            case "grade":
+                return current.Grade != course.Grade
+            case "credits":
+                return current.Credits != course.Credits
+            case "status":
+                return current.Status != course.Status
+            }
+            return true
+        })
+    }
+    return p, errs
+}
+
+func planAssignment(userID uint, row importRow, existing map[string]*models.Assignment, coursesByKey map[string]*models.Course,
+    importedCourseKeys map[string]bool, coursesByCode map[string][]uint, loc *time.Location, seen map[string]bool) (*plannedRow, []RowError) {
+    key := row.values["external_key"]
+    p := &plannedRow{entity: EntityAssignments, row: row.line, key: key}
+    var errs []RowError
+    fail := func(field, message string) {
+        errs = append(errs, RowError{Entity: EntityAssignments, Row: row.line, ExternalKey: key, Field: field, Message: message})
+    }
+
+    if key != "" {
+        if seen[key] {
+            fail("external_key", "duplicate external_key in this import")
+        }
+        seen[key] = true
+    }
+    current, ok := existing[key]
+    if ok && key != "" {
+        copied := *current
+        p.assignment = &copied
+    } else {
+        p.create = true
+        p.assignment = &models.Assignment{UserID: userID, ExternalKey: key, Priority: "medium", Status: "pending"}
+    }
+    assignment := p.assignment
+
+    set := func(field string, apply func(string) string) {
+        value, present := row.values[field]
+        if !present {
+            if p.create && (field == "title" || field == "due_date") {
+                fail(field, "is required")
+            }
+            return
+        }
+        if message := apply(value); message != "" {
+            fail(field, message)
+            return
+        }
+        p.columns = append(p.columns, field)
+    }
+    hours := func(target *int) func(string) string {
+        return func(v string) string {
+            n, ok := parseCount(v)
+            if !ok {
+                return "must be a non-negative whole number"
+            }
+            *target = n
+            return ""
+        }
+    }
+
+    set("title", func(v string) string {
+        if v == "" {
+            return "is required"
+        }
+        assignment.Title = v
+        return ""
+    })
+    set("description", func(v string) string {
+        assignment.Description = v
+        return ""
+    })
+    set("due_date", func(v string) string {
+        t, err := parseImportTime(v, loc)
+        if err != nil {
+            return err.Error()
+        }
+        assignment.DueDate = t
+        return ""
+    })
+    set("priority", func(v string) string {
+        if v == "" {
+            v = "medium"
+        }
+        if v != "high" && v != "medium" && v != "low" {
+            return "must be high, medium or low"
+        }
+        assignment.Priority = v
+        return ""
+    })
+    set("status", func(v string) string {
+        if v == "" {
+            v = "pending"
+        }
+        if v != "pending" && v != "in_progress" && v != "completed" {
+            return "must be pending, in_progress or completed"
+        }
+        assignment.Status = v
+        return ""
+    })
+    set("estimated_hours", hours(&assignment.EstimatedHours))
+    // Actual hours come from time entries once any are logged
+    if assignment.TrackedMinutes == 0 {
+        set("actual_hours", hours(&assignment.ActualHours))
+    }
+
+    courseKey, hasKey := row.values["course_key"]
+    courseCode, hasCode := row.values["course_code"]
+    switch {
+    case courseKey != "":
+        if course, ok := coursesByKey[courseKey]; ok {
+            assignment.CourseID = &course.ID
+        } else if importedCourseKeys[courseKey] {
+            p.courseKey = courseKey
+        } else {
+            fail("course_key", "no course has this external_key")
+        }
+        p.columns = append(p.columns, "course_id")
+    case courseCode != "":
+        ids := coursesByCode[strings.ToLower(courseCode)]
+        switch len(ids) {
+        case 0:
+            fail("course_code", "no course has this code")
+        case 1:
+            id := ids[0]
+            assignment.CourseID = &id
+        default:
+            fail("course_code", "several courses share this code; use course_key")
+        }
+        p.columns = append(p.columns, "course_id")
+    case hasKey || hasCode:
+        assignment.CourseID = nil
+        p.columns = append(p.columns, "course_id")
+    }
+
+    if !p.create {
+        p.columns = changedColumns(p.columns, func(column string) bool {
+            switch column {
+            case "title":
+                return current.Title != assignment.Title
+            case "description":
+                return current.Description != assignment.Description
+            case "due_date":
+                return !current.DueDate.Truncate(time.Second).Equal(assignment.DueDate.Truncate(time.Second))
+            case "priority":
+                return current.Priority != assignment.Priority
+            case "status":
+                return current.Status != assignment.Status
+            case "estimated_hours":
+                return current.EstimatedHours != assignment.EstimatedHours
+            case "actual_hours":
+                return current.ActualHours != assignment.ActualHours
+            case "course_id":
+                // Resolved on write when the course comes from this import
+                return p.courseKey != "" || !sameCourse(current.CourseID, assignment.CourseID)
+            }
+            return true
+        })
+    }
+    return p, errs
+}
+
+func changedColumns(columns []string, changed func(string) bool) []string {
+    var result []string
+    for _, column := range columns {
+        if changed(column) {
+            result = append(result, column)
+        }
+    }
+    return result
+}
+
+func sameCourse(a, b *uint) bool {
+    if a == nil || b == nil {
+        return a == nil && b == nil
+    }
+    return *a == *b
+}
+
+func writePlannedRow(tx *gorm.DB, p *plannedRow, courseIDs map[string]uint) error {
+    if p.entity == EntityCourses {
+        if p.create || containsString(p.columns, "semester") {
+            if err := linkCourseTerm(tx, p.course); err != nil {
+                return err
+            }
+            if !p.create {
+                p.columns = append(p.columns, "term_id")
+            }
+        }
+        if p.create {
+            if err := tx.Create(p.course).Error; err != nil {
+                return err
+            }
+        } else if len(p.columns) > 0 {
+            if err := tx.Model(p.course).Select(append(p.columns, "updated_at")).Updates(p.course).Error; err != nil {
+                return err
+            }
+        }
+        if p.key != "" {
+            courseIDs[p.key] = p.course.ID
+        }
+        return nil
+    }
+
+    if p.courseKey != "" {
+        id, ok := courseIDs[p.courseKey]
+        if !ok {
+            return errors.New("the course with this course_key was not imported")
+        }
+        p.assignment.CourseID = &id
+    }
+    if p.create {
+        if p.assignment.Status == "completed" {
+            now := time.Now()
+            p.assignment.CompletedAt = &now
+        }
+        return tx.Create(p.assignment).Error
+    }
+    if len(p.columns) == 0 {
+        return nil
+    }
+    // The status column is only written when it changed
+    if containsString(p.columns, "status") {
+        if err := checkStatusChange(tx, p.assignment.ID, "", p.assignment.Status); err != nil {
+            return err
+        }
+        p.assignment.CompletedAt = nil
+        if p.assignment.Status == "completed" {
+            now := time.Now()
+            p.assignment.CompletedAt = &now
+        }
+        p.columns = append(p.columns, "completed_at")
+    }
+    return tx.Model(p.assignment).Select(append(p.columns, "updated_at")).Updates(p.assignment).Error
+}
+
+func writeError(p *plannedRow, err error) RowError {
+    message := err.Error()
+    if errors.Is(err, gorm.ErrDuplicatedKey) {
+        message = "external_key is already in use"
+    }
+    return RowError{Entity: p.entity, Row: p.row, ExternalKey: p.key, Message: message}
+}
+
+// countWrite tallies a written row; index 0 is courses, 1 assignments.
+func countWrite(counts *[2]BulkImportCounts, p *plannedRow) {
+    i := 0
+    if p.entity == EntityAssignments {
+        i = 1
+    }
+    switch {
+    case p.create:
+        counts[i].Created++
+    case len(p.columns) == 0:
+        counts[i].Unchanged++
+    default:
+        counts[i].Updated++
+    }
+}
+
+func addCounts(a, b BulkImportCounts) BulkImportCounts {
+    return BulkImportCounts{
+        Created:   a.Created + b.Created,
+        Updated:   a.Updated + b.Updated,
+        Unchanged: a.Unchanged + b.Unchanged,
+        Failed:    a.Failed + b.Failed,
+    }
+}
+
+func parseCSVRows(r io.Reader, entity string, mapping map[string]string) (map[string][]importRow, error) {
+    reader := csv.NewReader(r)
+    reader.FieldsPerRecord = -1
+    reader.TrimLeadingSpace = true
+
+    header, err := reader.Read()
+    if errors.Is(err, io.EOF) {
+        return nil, fmt.Errorf("%w: CSV file is empty", ErrInvalidImport)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("%w: invalid CSV: %w", ErrInvalidImport, err)
+    }
+    header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+    columns := entityColumns(entity)
+    index := map[string]int{}
+    for _, field := range columns {
+        source := sourceName(field, mapping)
+        for i, name := range header {
+            if strings.EqualFold(strings.TrimSpace(name), source) {
+                index[field] = i
+                break
+            }
+        }
+    }
+    if len(index) == 0 {
+        return nil, fmt.Errorf("%w: no known columns in CSV header; expected some of %s", ErrInvalidImport, strings.Join(columns, ", "))
+    }
+
+    var rows []importRow
+    for {
+        record, err := reader.Read()
+        if errors.Is(err, io.EOF) {
+            break
+        }
+        if err != nil {
+            return nil, fmt.Errorf("%w: invalid CSV: %w", ErrInvalidImport, err)
+        }
+        if isBlankRecord(record) {
+            continue
+        }
+        line, _ := reader.FieldPos(0)
+        row := importRow{line: line, values: map[string]string{}}
+        for field, i := range index {
+            if i < len(record) {
+                row.values[field] = fromCSVText(strings.TrimSpace(record[i]))
+            } else {
+                row.values[field] = ""
+            }
+        }
+        rows = append(rows, row)
+    }
+    return map[string][]importRow{entity: rows}, nil
+}
+
+// parseJSONRows accepts either {"courses": [...], "assignments": [...]},
+// the shape GET /export produces, or a bare array of one entity.
+func parseJSONRows(r io.Reader, entity string, mapping map[string]string) (map[string][]importRow, error) {
+    data, err := io.ReadAll(r)
+    if err != nil {
+        return nil, err
+    }
+    data = bytes.TrimSpace(data)
+    if len(data) == 0 {
+        return nil, fmt.Errorf("%w: JSON body is empty", ErrInvalidImport)
+    }
+
+    sections := map[string][]map[string]interface{}{}
+    if data[0] == '[' {
+        if entity == "" {
+            return nil, fmt.Errorf("%w: type is required when importing a JSON array", ErrInvalidImport)
+        }
+        var objects []map[string]interface{}
+        if err := decodeJSON(data, &objects); err != nil {
+            return nil, err
+        }
+        sections[entity] = objects
+    } else {
+        var document map[string][]map[string]interface{}
+        if err := decodeJSON(data, &document); err != nil {
+            return nil, err
+        }
+        for _, name := range []string{EntityCourses, EntityAssignments} {
+            if entity == "" || entity == name {
+                sections[name] = document[name]
+            }
+        }
+    }
+
+    rows := map[string][]importRow{}
+    for name, objects := range sections {
+        columns := entityColumns(name)
+        for i, object := range objects {
+            row := importRow{line: i + 1, values: map[string]string{}}
+            for _, field := range columns {
+                if value, ok := lookupKey(object, sourceName(field, mapping)); ok {
+                    row.values[field] = strings.TrimSpace(jsonScalar(value))
+                }
+            }
+            rows[name] = append(rows[name], row)
+        }
+    }
+    return rows, nil
+}
+
+func decodeJSON(data []byte, v interface{}) error {
+    decoder := json.NewDecoder(bytes.NewReader(data))
+    decoder.UseNumber()
+    if err := decoder.Decode(v); err != nil {
+        return fmt.Errorf("%w: invalid JSON: %w", ErrInvalidImport, err)
+    }
+    return nil
+}
+
+func entityColumns(entity string) []string {
+    if entity == EntityCourses {
+        return CourseColumns
+    }
+    return AssignmentColumns
+}
+
+func sourceName(field string, mapping map[string]string) string {
+    if source, ok := mapping[field]; ok && source != "" {
+        return source
+    }
+    return field
+}
+
+func lookupKey(object map[string]interface{}, key string) (interface{}, bool) {
+    if value, ok := object[key]; ok {
+        return value, true
+    }
+    for k, value := range object {
+        if strings.EqualFold(k, key) {
+            return value, true
+        }
+    }
+    return nil, false
+}
+
+func jsonScalar(value interface{}) string {
+    switch v := value.(type) {
+    case nil:
+        return ""
+    case string:
+        return v
+    case json.Number:
+        return v.String()
+    default:
+        return fmt.Sprint(v)
+    }
+}
+
+func isBlankRecord(record []string) bool {
+    for _, v := range record {
+        if strings.TrimSpace(v) != "" {
+            return false
+        }
+    }
+    return true
+}
+
+// parseCount reads a non-negative whole number; an empty value is 0.
+// Whole-valued decimals such as "3.0" are accepted.
+func parseCount(value string) (int, bool) {
+    if value == "" {
+        return 0, true
+    }
+    f, err := strconv.ParseFloat(value, 64)
+    if err != nil || f < 0 || f != float64(int(f)) {
+        return 0, false
+    }
+    return int(f), true
+}
+
+var importTimeLayouts = []string{
+    "2006-01-02T15:04:05",
+    "2006-01-02T15:04",
+    "2006-01-02 15:04:05",
+    "2006-01-02 15:04",
+}
+
+// parseImportTime accepts RFC 3339, a local date-time, or a bare date,
+// which is taken as the end of that day.
+func parseImportTime(value string, loc *time.Location) (time.Time, error) {
+    if value == "" {
+        return time.Time{}, errors.New("is required")
+    }
+    if t, err := time.Parse(time.RFC3339, value); err == nil {
+        return t, nil
+    }
+    for _, layout := range importTimeLayouts {
+        if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+            return t, nil
+        }
+    }
+    if t, err := time.ParseInLocation(dateLayout, value, loc); err == nil {
+        return endOfDay(t), nil
+    }
+    return time.Time{}, errors.New("must be an RFC 3339 timestamp or YYYY-MM-DD date")
+}
+
+func containsString(values []string, target string) bool {
+    for _, v := range values {
+        if v == target {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/caldav"
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/ical"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+var (
+    ErrPreconditionFailed   = errors.New("precondition failed")
+    ErrUnsupportedComponent = errors.New("only VTODO resources are supported")
+    ErrUIDConflict          = errors.New("UID is already used by another task")
+    ErrInvalidTask          = errors.New("invalid task")
+)
+
+// CalDAVService exposes a user's assignments as a collection of VTODO
+// resources. Writes go through AssignmentService so the same rules apply
+// as for edits made in the app.
+type CalDAVService struct {
+    db                *gorm.DB
+    assignmentService *AssignmentService
+    recurrenceService *RecurrenceService
+}
+
+func NewCalDAVService() *CalDAVService {
+    return &CalDAVService{
+        db:                database.GetDB(),
+        assignmentService: NewAssignmentService(),
+        recurrenceService: NewRecurrenceService(),
+    }
+}
+
+// TaskResourceName is the file name an assignment is served under in the
+// collection. Tasks created by a client keep the name the client chose.
+func TaskResourceName(a *models.Assignment) string {
+    if a.CalDAVName != "" {
+        return a.CalDAVName
+    }
+    return fmt.Sprintf("assignment-%d.ics", a.ID)
+}
+
+func TaskETag(a *models.Assignment) string {
+    return fmt.Sprintf(`"%d-%d"`, a.ID, a.UpdatedAt.UnixMicro())
+}
+
+// TaskUID is the VTODO UID: the client's own UID for tasks that came in
+// through CalDAV or an import, otherwise the one the feed uses.
+func TaskUID(a *models.Assignment) string {
+    if a.ICalUID != "" {
+        return a.ICalUID
+    }
+    return assignmentUID(a, "todo")
+}
+
+func RenderTask(a *models.Assignment) []byte {
+    cal := ical.NewCalendar("")
+    // Stamp with the last change so the body is stable for a given ETag
+    todo := assignmentTodo(a, nil, a.UpdatedAt)
+    todo.Get("UID").Value = TaskUID(a)
+    cal.Add(todo)
+    return cal.Bytes()
+}
+
+// CollectionTag changes whenever any assignment is created, edited or
+// deleted, which lets clients skip a full listing when nothing changed.
+func (s *CalDAVService) CollectionTag(userID uint) (string, error) {
+    var tag struct {
+        Count   int64
+        Updated *time.Time
+        Deleted *time.Time
+    }
+    err := s.db.Unscoped().Model(&models.Assignment{}).
+        Select("COUNT(*) AS count, MAX(updated_at) AS updated, MAX(deleted_at) AS deleted").
+        Where("user_id = ?", userID).
+        Scan(&tag).Error
+    if err != nil {
+        return "", err
+    }
+
+    var updated, deleted int64
+    if tag.Updated != nil {
+        updated = tag.Updated.UnixMicro()
+    }
+    if tag.Deleted != nil {
+        deleted = tag.Deleted.UnixMicro()
+    }
+    return fmt.Sprintf("%d-%d-%d", tag.Count, updated, deleted), nil
+}
+
+// QueryTasks lists the tasks matching a calendar-query filter. The zero
+// Filter returns every task.
+func (s *CalDAVService) QueryTasks(userID uint, filter caldav.Filter) ([]models.Assignment, error) {
+    if err := s.recurrenceService.MaterializeUpcoming(userID); err != nil {
+        return nil, err
+    }
+
+    assignments := []models.Assignment{}
+    if filter.Component != "" && filter.Component != "VCALENDAR" && filter.Component != "VTODO" {
+        return assignments, nil
+    }
+
+    query := s.db.Where("user_id = ?", userID).Preload("Course")
+    if filter.Start != nil {
+        query = query.Where("due_date >= ?", *filter.Start)
+    }
+    if filter.End != nil {
+        query = query.Where("due_date < ?", *filter.End)
+    }
+    if filter.Completed != nil {
+        if *filter.Completed {
+            query = query.Where("status = ?", "completed")
+        } else {
+            query = query.Where("status <> ?", "completed")
+        }
+    }
+    if err := query.Order("due_date ASC").Find(&assignments).Error; err != nil {
+        return nil, err
+    }
+
+    if filter.Status == "" {
+        return assignments, nil
+    }
+    matched := assignments[:0]
+    for _, a := range assignments {
+        contains := strings.Contains(todoStatus(a.Status), strings.ToUpper(filter.Status))
+        if contains != filter.StatusNegate {
+            matched = append(matched, a)
+        }
+    }
+    return matched, nil
+}
+
+// GetTask resolves a resource name. Unknown names yield
+// gorm.ErrRecordNotFound.
+func (s *CalDAVService) GetTask(userID uint, name string) (*models.Assignment, error) {
+    var assignment models.Assignment
+    err := s.db.Where("user_id = ? AND caldav_name = ?", userID, name).Preload("Course").First(&assignment).Error
+    if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+        return &assignment, err
+    }
+
+    // Fall back to the generated assignment-<id>.ics names
+    var id uint
+    if _, scanErr := fmt.Sscanf(name, "assignment-%d.ics", &id); scanErr != nil || fmt.Sprintf("assignment-%d.ics", id) != name {
+        return nil, gorm.ErrRecordNotFound
+    }
+    err = s.db.Where("id = ? AND user_id = ? AND COALESCE(caldav_name, '') = ''", id, userID).Preload("Course").First(&assignment).Error
+    if err != nil {
+        return nil, err
+    }
+    return &assignment, nil
+}
+
+// PutTask creates or replaces the task stored under name. ifMatch and
+// ifNoneMatch are the raw request headers; created reports whether a new
+// assignment was made.
+func (s *CalDAVService) PutTask(userID uint, name string, r io.Reader, ifMatch, ifNoneMatch string) (*models.Assignment, bool, error) {
+    cal, err := ical.Parse(r)
+    if err != nil {
+        return nil, false, fmt.Errorf("%w: %v", ErrInvalidTask, err)
+    }
+    if cal.Name != "VCALENDAR" {
+        return nil, false, fmt.Errorf("%w: expected VCALENDAR", ErrInvalidTask)
+    }
+    todos := cal.Children("VTODO")
+    if len(todos) == 0 {
+        return nil, false, ErrUnsupportedComponent
+    }
+    if len(todos) > 1 {
+        return nil, false, fmt.Errorf("%w: a resource must hold a single task", ErrInvalidTask)
+    }
+    todo := todos[0]
+    uid := strings.TrimSpace(todo.Value("UID"))
+    if uid == "" {
+        return nil, false, fmt.Errorf("%w: missing UID", ErrInvalidTask)
+    }
+
+    current, err := s.GetTask(userID, name)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        current = nil
+    } else if err != nil {
+        return nil, false, err
+    }
+    if !checkPreconditions(current, ifMatch, ifNoneMatch) {
+        return nil, false, ErrPreconditionFailed
+    }
+
+    entry, reason := icsEntryFrom(todo, time.UTC)
+    if reason != "" && reason != "no due date" {
+        return nil, false, fmt.Errorf("%w: %s", ErrInvalidTask, reason)
+    }
+    if todo.Get("COMPLETED") != nil || strings.TrimSpace(todo.Value("PERCENT-COMPLETE")) == "100" {
+        entry.status = "completed"
+    }
+
+    // The UID identifies the task for its whole life and must be unique
+    if current != nil && TaskUID(current) != uid {
+        return nil, false, ErrUIDConflict
+    }
+    var clashes int64
+    query := s.db.Model(&models.Assignment{}).Where("user_id = ? AND ical_uid = ?", userID, uid)
+    if current != nil {
+        query = query.Where("id <> ?", current.ID)
+    }
+    if err := query.Count(&clashes).Error; err != nil {
+        return nil, false, err
+    }
+    if clashes > 0 {
+        return nil, false, ErrUIDConflict
+    }
+
+    if current == nil {
+        // Tasks without a due date are due at the end of the day they were added
+        if reason == "no due date" {
+            entry.due = endOfDay(time.Now().UTC())
+        }
+        // One insert carries the UID and resource name, so a failed PUT
+        // never leaves a nameless assignment for the client's retry to
+        // duplicate
+        assignment := models.Assignment{
+            UserID:      userID,
+            Title:       entry.title,
+            Description: entry.description,
+            DueDate:     entry.due,
+            Priority:    entry.priority,
+            Status:      "pending",
+            ICalUID:     uid,
+            CalDAVName:  name,
+        }
+        if assignment.Priority == "" {
+            assignment.Priority = "medium"
+        }
+        if entry.status != "" {
+            assignment.Status = entry.status
+        }
+        if assignment.Status == "completed" {
+            now := time.Now()
+            assignment.CompletedAt = &now
+        }
+        if err := s.db.Create(&assignment).Error; err != nil {
+            return nil, false, err
+        }
+        created, err := s.GetTask(userID, name)
+        return created, true, err
+    }
+
+    // Our SUMMARY carries a course code prefix; don't save it into the title
+    if current.Course != nil && current.Course.CourseCode != "" {
+        entry.title = strings.TrimPrefix(entry.title, "["+current.Course.CourseCode+"] ")
+    }
+    updates := icsUpdates(current, entry, nil)
+    if reason == "no due date" || current.DueDate.Truncate(time.Second).Equal(entry.due) {
+        delete(updates, "due_date")
+    }
+    if len(updates) > 0 {
+        if _, err := s.assignmentService.UpdateAssignment(userID, current.ID, updates); err != nil {
+            return nil, false, err
+        }
+    }
+    assignment, err := s.GetTask(userID, name)
+    return assignment, false, err
+}
+
+func (s *CalDAVService) DeleteTask(userID uint, name, ifMatch string) error {
+    assignment, err := s.GetTask(userID, name)
+    if err != nil {
+        return err
+    }
+    if !checkPreconditions(assignment, ifMatch, "") {
+        return ErrPreconditionFailed
+    }
+    return s.assignmentService.DeleteAssignment(userID, assignment.ID)
+}
+
+// checkPreconditions evaluates If-Match and If-None-Match against the
+// current resource, which is nil when it does not exist yet.
+func checkPreconditions(current *models.Assignment, ifMatch, ifNoneMatch string) bool {
+    if ifMatch != "" {
+        if current == nil {
+            return false
+        }
+        if ifMatch != "*" && !etagListContains(ifMatch, TaskETag(current)) {
+            return false
+        }
+    }
+    if ifNoneMatch != "" && current != nil {
+        if ifNoneMatch == "*" || etagListContains(ifNoneMatch, TaskETag(current)) {
+            return false
+        }
+    }
+    return true
+}
+
+func etagListContains(header, etag string) bool {
+    for _, candidate := range strings.Split(header, ",") {
+        if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+            return true
+        }
+    }
+    return false
+}
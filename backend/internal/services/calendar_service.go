@@ -0,0 +1,372 @@
+package services
+
+import (
+    "crypto/rand"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/ical"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+const (
+    FeedEvents = "event"
+    FeedTodos  = "todo"
+    FeedBoth   = "both"
+)
+
+type CalendarService struct {
+    db *gorm.DB
+}
+
+func NewCalendarService() *CalendarService {
+    return &CalendarService{
+        db: database.GetDB(),
+    }
+}
+
+// FeedFilter narrows what goes into a feed. It is read from the feed URL's
+// query string so one token can back several differently filtered
+// subscriptions. Statuses and priorities only describe assignments, so
+// class meetings are left out when either is set.
+type FeedFilter struct {
+    CourseIDs    []uint
+    Statuses     []string
+    Priorities   []string
+    Tags         []string // Any of these; a meeting's tag is its type
+    Components   string
+    AlarmMinutes []int
+    SkipMeetings bool
+}
+
+// GetFeed returns the user's feed, creating it on first use.
+func (s *CalendarService) GetFeed(userID uint) (*models.CalendarFeed, error) {
+    var feed models.CalendarFeed
+    err := s.db.Where("user_id = ?", userID).First(&feed).Error
+    if err == nil {
+        return &feed, nil
+    }
+    if !errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil, err
+    }
+
+    token, err := generateToken()
+    if err != nil {
+        return nil, err
+    }
+    feed = models.CalendarFeed{UserID: userID, Token: token}
+    if err := s.db.Create(&feed).Error; err != nil {
+        return nil, err
+    }
+    return &feed, nil
+}
+
+// RotateFeedToken replaces the feed token, so the old URL stops working.
+func (s *CalendarService) RotateFeedToken(userID uint) (*models.CalendarFeed, error) {
+    feed, err := s.GetFeed(userID)
+    if err != nil {
+        return nil, err
+    }
+
+    token, err := generateToken()
+    if err != nil {
+        return nil, err
+    }
+    feed.Token = token
+    feed.LastAccessedAt = nil
+    if err := s.db.Save(feed).Error; err != nil {
+        return nil, err
+    }
+    return feed, nil
+}
+
+// RenderFeed builds the iCalendar document for the feed identified by
+// token. An unknown token yields gorm.ErrRecordNotFound.
+func (s *CalendarService) RenderFeed(token string, filter FeedFilter) ([]byte, error) {
+    var feed models.CalendarFeed
+    if err := s.db.Where("token = ?", token).First(&feed).Error; err != nil {
+        return nil, err
+    }
+
+    var user models.User
+    if err := s.db.First(&user, feed.UserID).Error; err != nil {
+        return nil, err
+    }
+
+    query := s.db.Where("user_id = ?", feed.UserID).Preload("Course")
+    if len(filter.CourseIDs) > 0 {
+        query = query.Where("course_id IN ?", filter.CourseIDs)
+    }
+    if len(filter.Statuses) > 0 {
+        query = query.Where("status IN ?", filter.Statuses)
+    }
+    if len(filter.Priorities) > 0 {
+        query = query.Where("priority IN ?", filter.Priorities)
+    }
+
+    var assignments []models.Assignment
+    if err := query.Order("due_date ASC").Find(&assignments).Error; err != nil {
+        return nil, err
+    }
+
+    now := time.Now()
+    cal := ical.NewCalendar(fmt.Sprintf("AcademiaFlow - %s %s", user.FirstName, user.LastName))
+    for i := range assignments {
+        if len(filter.Tags) > 0 && !hasAnyTag(assignments[i].Tags, filter.Tags) {
+            continue
+        }
+        if filter.Components != FeedTodos {
+            cal.Add(assignmentEvent(&assignments[i], filter.AlarmMinutes, now))
+        }
+        if filter.Components == FeedTodos || filter.Components == FeedBoth {
+            cal.Add(assignmentTodo(&assignments[i], filter.AlarmMinutes, now))
+        }
+    }
+
+    if filter.Components != FeedTodos && !filter.SkipMeetings && len(filter.Statuses) == 0 && len(filter.Priorities) == 0 {
+        query := s.db.Where("user_id = ? AND status <> ? AND term_id IS NOT NULL", feed.UserID, "dropped").
+            Preload("Term").Preload("Meetings")
+        if len(filter.CourseIDs) > 0 {
+            query = query.Where("id IN ?", filter.CourseIDs)
+        }
+        var courses []models.Course
+        if err := query.Find(&courses).Error; err != nil {
+            return nil, err
+        }
+        loc := plannerLocation(s.db, feed.UserID)
+        var from, to time.Time
+        for i := range courses {
+            for j := range courses[i].Meetings {
+                meeting := &courses[i].Meetings[j]
+                if len(filter.Tags) > 0 && !hasAnyTag([]string{meeting.Type}, filter.Tags) {
+                    continue
+                }
+                event := meetingEvent(meeting, &courses[i], loc, now)
+                if event == nil {
+                    continue
+                }
+                cal.Add(event)
+                term := courses[i].Term
+                if from.IsZero() || term.StartDate.Before(from) {
+                    from = term.StartDate
+                }
+                if term.EndDate.After(to) {
+                    to = term.EndDate
+                }
+            }
+        }
+        // Meetings carry a TZID, which strict clients only accept with a
+        // matching VTIMEZONE
+        if !from.IsZero() && loc != time.UTC {
+            y, m, d := from.Date()
+            start := time.Date(y, m, d, 0, 0, 0, 0, loc)
+            y, m, d = to.Date()
+            end := time.Date(y, m, d, 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+            cal.Components = append([]*ical.Component{ical.Timezone(loc, start, end)}, cal.Components...)
+        }
+    }
+
+    s.db.Model(&feed).Update("last_accessed_at", now)
+
+    return cal.Bytes(), nil
+}
+
+func assignmentEvent(a *models.Assignment, alarms []int, now time.Time) *ical.Component {
+    event := ical.NewComponent("VEVENT")
+    event.Set("UID", assignmentUID(a, "event"))
+    setCommonProperties(event, a, now)
+    event.SetDateTime("DTSTART", a.DueDate)
+    event.SetText("SUMMARY", assignmentSummary(a))
+    event.Set("TRANSP", "TRANSPARENT")
+
+    if a.Status != "completed" {
+        addAlarms(event, a, alarms)
+    }
+    return event
+}
+
+func assignmentTodo(a *models.Assignment, alarms []int, now time.Time) *ical.Component {
+    todo := ical.NewComponent("VTODO")
+    todo.Set("UID", assignmentUID(a, "todo"))
+    setCommonProperties(todo, a, now)
+    todo.SetText("SUMMARY", assignmentSummary(a))
+    todo.SetDateTime("DUE", a.DueDate)
+    todo.Set("PRIORITY", icalPriority(a.Priority))
+    todo.Set("STATUS", todoStatus(a.Status))
+    if a.Status == "completed" {
+        todo.SetDateTime("COMPLETED", a.UpdatedAt)
+        todo.Set("PERCENT-COMPLETE", "100")
+    }
+
+    if a.Status != "completed" {
+        addAlarms(todo, a, alarms)
+    }
+    return todo
+}
+
+func setCommonProperties(c *ical.Component, a *models.Assignment, now time.Time) {
+    c.SetDateTime("DTSTAMP", now)
+    c.SetDateTime("CREATED", a.CreatedAt)
+    c.SetDateTime("LAST-MODIFIED", a.UpdatedAt)
+    // Seconds since creation only ever grows, so clients treat each edit
+    // as a newer revision of the same UID.
+    c.Set("SEQUENCE", fmt.Sprint(int64(a.UpdatedAt.Sub(a.CreatedAt).Seconds())))
+    if a.Description != "" {
+        c.SetText("DESCRIPTION", a.Description)
+    }
+
+    categories := []string{}
+    if a.Course != nil {
+        categories = append(categories, a.Course.CourseCode)
+    }
+    if a.Priority != "" {
+        categories = append(categories, a.Priority)
+    }
+    categories = append(categories, a.Tags...)
+    if len(categories) > 0 {
+        c.Set("CATEGORIES", joinText(categories))
+    }
+}
+
+// meetingEvent is a weekly VEVENT for a class meeting, running from its
+// first day in the course's term to the term's last day, with days in
+// term breaks excluded. Times are the user's wall clock so the class stays
+// put across DST changes. Courses without a term get no event.
+func meetingEvent(m *models.CourseMeeting, course *models.Course, loc *time.Location, now time.Time) *ical.Component {
+    term := course.Term
+    if term == nil {
+        return nil
+    }
+    start, end := clockMinutes(m.StartTime), clockMinutes(m.EndTime)
+    y, mo, d := term.StartDate.Date()
+    first := time.Date(y, mo, d, 0, start, 0, 0, loc)
+    first = first.AddDate(0, 0, (m.DayOfWeek-int(first.Weekday())+7)%7)
+    y, mo, d = term.EndDate.Date()
+    last := time.Date(y, mo, d, 23, 59, 59, 0, loc)
+    if first.After(last) {
+        return nil
+    }
+
+    var skipped []time.Time
+    for day := first; !day.After(last); day = day.AddDate(0, 0, 7) {
+        date := day.Format(dateLayout)
+        for _, b := range term.Breaks {
+            if date >= b.StartDate.Format(dateLayout) && date <= b.EndDate.Format(dateLayout) {
+                skipped = append(skipped, day)
+                break
+            }
+        }
+    }
+
+    modified := m.UpdatedAt
+    if term.UpdatedAt.After(modified) {
+        modified = term.UpdatedAt
+    }
+    kind := m.Type
+    if kind == "" {
+        kind = "class"
+    }
+
+    event := ical.NewComponent("VEVENT")
+    event.Set("UID", fmt.Sprintf("meeting-%d@academiaflow", m.ID))
+    event.SetDateTime("DTSTAMP", now)
+    event.SetDateTime("CREATED", m.CreatedAt)
+    event.SetDateTime("LAST-MODIFIED", modified)
+    event.Set("SEQUENCE", fmt.Sprint(int64(modified.Sub(m.CreatedAt).Seconds())))
+    event.SetLocalDateTime("DTSTART", first)
+    event.SetLocalDateTime("DTEND", first.Add(time.Duration(end-start)*time.Minute))
+    event.Set("RRULE", "FREQ=WEEKLY;UNTIL="+ical.FormatDateTime(last))
+    event.SetLocalDateTime("EXDATE", skipped...)
+    event.SetText("SUMMARY", fmt.Sprintf("[%s] %s", course.CourseCode, strings.ReplaceAll(kind, "_", " ")))
+    if course.CourseName != "" {
+        event.SetText("DESCRIPTION", course.CourseName)
+    }
+    if m.Location != "" {
+        event.SetText("LOCATION", m.Location)
+    }
+    event.Set("CATEGORIES", joinText([]string{course.CourseCode, kind}))
+    return event
+}
+
+func addAlarms(c *ical.Component, a *models.Assignment, alarms []int) {
+    for _, minutes := range alarms {
+        alarm := ical.NewComponent("VALARM")
+        alarm.Set("ACTION", "DISPLAY")
+        alarm.Set("TRIGGER", ical.FormatDuration(-time.Duration(minutes)*time.Minute))
+        alarm.SetText("DESCRIPTION", assignmentSummary(a))
+        c.Add(alarm)
+    }
+}
+
+// assignmentUID is stable for the life of the assignment so calendar apps
+// replace an event in place when it changes.
+func assignmentUID(a *models.Assignment, kind string) string {
+    if kind == "todo" {
+        return fmt.Sprintf("assignment-%d-todo@academiaflow", a.ID)
+    }
+    return fmt.Sprintf("assignment-%d@academiaflow", a.ID)
+}
+
+func assignmentSummary(a *models.Assignment) string {
+    if a.Course != nil && a.Course.CourseCode != "" {
+        return fmt.Sprintf("[%s] %s", a.Course.CourseCode, a.Title)
+    }
+    return a.Title
+}
+
+// icalPriority maps our priorities onto the 1 (highest) to 9 scale.
+func icalPriority(priority string) string {
+    switch priority {
+    case "high":
+        return "1"
+    case "low":
+        return "9"
+    default:
+        return "5"
+    }
+}
+
+// todoStatus maps an assignment status onto VTODO STATUS.
+func todoStatus(status string) string {
+    switch status {
+    case "completed":
+        return "COMPLETED"
+    case "in_progress":
+        return "IN-PROCESS"
+    default:
+        return "NEEDS-ACTION"
+    }
+}
+
+// hasAnyTag reports whether tags holds any of want, ignoring case.
+func hasAnyTag(tags, want []string) bool {
+    for _, tag := range tags {
+        for _, w := range want {
+            if strings.EqualFold(tag, w) {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+func joinText(values []string) string {
+    escaped := make([]string, len(values))
+    for i, v := range values {
+        escaped[i] = ical.EscapeText(v)
+    }
+    return strings.Join(escaped, ",")
+}
+
+func generateToken() (string, error) {
+    b := make([]byte, 24)
+    if _, err := rand.Read(b); err != nil {
+        return "", err
+    }
+    return hex.EncodeToString(b), nil
+}
@@ -1,11 +1,20 @@
 package services
 
 import (
+    "errors"
+    "strings"
+
     "github.com/anayy09/academiaflow-backend/internal/database"
     "github.com/anayy09/academiaflow-backend/internal/models"
     "gorm.io/gorm"
 )
 
+var (
+    ErrTermRequired   = errors.New("either term_id or semester is required")
+    ErrTermNotFound   = errors.New("term not found")
+    ErrCourseNotFound = errors.New("course not found")
+)
+
 type CourseService struct {
     db *gorm.DB
 }
@@ -17,42 +26,53 @@ func NewCourseService() *CourseService {
 }
 
 type CreateCourseRequest struct {
-    CourseName string `json:"course_name" binding:"required"`
-    CourseCode string `json:"course_code" binding:"required"`
-    Instructor string `json:"instructor"`
-    Credits    int    `json:"credits"`
-    Semester   string `json:"semester" binding:"required"`
-    Status     string `json:"status"`
+    CourseName     string `json:"course_name" binding:"required"`
+    CourseCode     string `json:"course_code" binding:"required"`
+    Instructor     string `json:"instructor"`
+    Credits        int    `json:"credits"`
+    Semester       string `json:"semester"`
+    TermID         *uint  `json:"term_id"` // Takes precedence over semester
+    GradingScaleID *uint  `json:"grading_scale_id"`
+    Status         string `json:"status"`
 }
 
 func (s *CourseService) GetUserCourses(userID uint) ([]models.Course, error) {
     var courses []models.Course
-    err := s.db.Where("user_id = ?", userID).Find(&courses).Error
+    err := s.db.Where("user_id = ?", userID).Preload("Meetings").Preload("Term").Find(&courses).Error
     return courses, err
 }
 
 func (s *CourseService) CreateCourse(userID uint, req CreateCourseRequest) (*models.Course, error) {
     course := models.Course{
-        UserID:     userID,
-        CourseName: req.CourseName,
-        CourseCode: req.CourseCode,
-        Instructor: req.Instructor,
-        Credits:    req.Credits,
-        Semester:   req.Semester,
-        Status:     req.Status,
+        UserID:         userID,
+        CourseName:     req.CourseName,
+        CourseCode:     req.CourseCode,
+        Instructor:     req.Instructor,
+        Credits:        req.Credits,
+        Semester:       req.Semester,
+        Status:         req.Status,
+        GradingScaleID: req.GradingScaleID,
     }
 
     if course.Status == "" {
         course.Status = "enrolled"
     }
 
-    err := s.db.Create(&course).Error
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := checkGradingScale(tx, userID, course.GradingScaleID); err != nil {
+            return err
+        }
+        if err := s.setCourseTerm(tx, &course, req.TermID); err != nil {
+            return err
+        }
+        return tx.Create(&course).Error
+    })
     return &course, err
 }
 
 func (s *CourseService) GetCourse(userID, courseID uint) (*models.Course, error) {
     var course models.Course
-    err := s.db.Where("id = ? AND user_id = ?", courseID, userID).First(&course).Error
+    err := s.db.Where("id = ? AND user_id = ?", courseID, userID).Preload("Meetings").Preload("Term").First(&course).Error
     return &course, err
 }
 
@@ -62,10 +82,67 @@ func (s *CourseService) UpdateCourse(userID, courseID uint, updates map[string]i
         return nil, err
     }
 
-    err := s.db.Model(&course).Updates(updates).Error
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if v, ok := updates["grading_scale_id"].(float64); ok {
+            scaleID := uint(v)
+            if err := checkGradingScale(tx, userID, &scaleID); err != nil {
+                return err
+            }
+        }
+        _, hasTerm := updates["term_id"]
+        semester, hasSemester := updates["semester"].(string)
+        if hasTerm || hasSemester {
+            var termID *uint
+            if v, ok := updates["term_id"].(float64); ok {
+                id := uint(v)
+                termID = &id
+            }
+            if hasSemester {
+                course.Semester = semester
+            }
+            if err := s.setCourseTerm(tx, &course, termID); err != nil {
+                return err
+            }
+            updates["term_id"] = course.TermID
+            updates["semester"] = course.Semester
+        }
+        return tx.Model(&course).Updates(updates).Error
+    })
     return &course, err
 }
 
 func (s *CourseService) DeleteCourse(userID, courseID uint) error {
-    return s.db.Where("id = ? AND user_id = ?", courseID, userID).Delete(&models.Course{}).Error
-}
\ No newline at end of file
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("id = ? AND user_id = ?", courseID, userID).Delete(&models.Course{})
+        if result.Error != nil || result.RowsAffected == 0 {
+            return result.Error
+        }
+
+        // Meetings and grade categories have no meaning without their course
+        if err := tx.Where("course_id = ?", courseID).Delete(&models.CourseMeeting{}).Error; err != nil {
+            return err
+        }
+        return tx.Where("course_id = ?", courseID).Delete(&models.GradeCategory{}).Error
+    })
+}
+
+// setCourseTerm links the course to the given term, copying its name into
+// Semester, or else to the term its semester string names.
+func (s *CourseService) setCourseTerm(tx *gorm.DB, course *models.Course, termID *uint) error {
+    if termID != nil {
+        var term models.Term
+        err := tx.Where("id = ? AND user_id = ?", *termID, course.UserID).First(&term).Error
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return ErrTermNotFound
+        }
+        if err != nil {
+            return err
+        }
+        course.TermID, course.Semester = &term.ID, term.Name
+        return nil
+    }
+    if strings.TrimSpace(course.Semester) == "" {
+        return ErrTermRequired
+    }
+    return linkCourseTerm(tx, course)
+}
@@ -0,0 +1,490 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+const (
+    RequirementSatisfied  = "satisfied"
+    RequirementInProgress = "in_progress"
+    RequirementMissing    = "missing"
+)
+
+var (
+    ErrInvalidDegreeProgram = errors.New("invalid degree program")
+    ErrNoDegreeProgram      = errors.New("no degree program attached to the profile")
+)
+
+type DegreeService struct {
+    db               *gorm.DB
+    academicsService *AcademicsService
+}
+
+func NewDegreeService() *DegreeService {
+    return &DegreeService{
+        db:               database.GetDB(),
+        academicsService: NewAcademicsService(),
+    }
+}
+
+type DegreeProgramRequest struct {
+    Name            string                `json:"name" binding:"required"`
+    Level           string                `json:"level"`
+    Institution     string                `json:"institution"`
+    MinCredits      int                   `json:"min_credits" binding:"gte=0"`
+    MinGPA          float64               `json:"min_gpa" binding:"gte=0"`
+    RequiredCourses []string              `json:"required_courses"`
+    ElectivePools   []models.ElectivePool `json:"elective_pools"`
+    Milestones      []string              `json:"milestones"`
+    MilestoneNorms  map[string]int        `json:"milestone_norms"`
+}
+
+type AttachProgramRequest struct {
+    ProgramID *uint `json:"program_id"` // nil detaches
+}
+
+// AuditCourse is a course as the audit sees it.
+type AuditCourse struct {
+    CourseID   uint   `json:"course_id"`
+    CourseCode string `json:"course_code"`
+    CourseName string `json:"course_name"`
+    Term       string `json:"term"`
+    Credits    int    `json:"credits"`
+    Grade      string `json:"grade,omitempty"`
+    Status     string `json:"status"` // satisfied, in_progress or missing (failed)
+}
+
+type RequirementResult struct {
+    Type       string        `json:"type"` // required_course, elective_pool, credits, gpa, milestone
+    Name       string        `json:"name"`
+    Status     string        `json:"status"`
+    Required   float64       `json:"required,omitempty"`
+    Completed  float64       `json:"completed"`
+    InProgress float64       `json:"in_progress"`
+    Courses    []AuditCourse `json:"courses,omitempty"`
+    Note       string        `json:"note,omitempty"`
+}
+
+type DegreeAudit struct {
+    Program      *models.DegreeProgram `json:"program"`
+    Status       string                `json:"status"`
+    Satisfied    int                   `json:"satisfied"`
+    InProgress   int                   `json:"in_progress"`
+    Missing      int                   `json:"missing"`
+    Requirements []RequirementResult   `json:"requirements"`
+    Unused       []AuditCourse         `json:"unused_courses"` // Passed courses no requirement claimed
+}
+
+func (s *DegreeService) GetPrograms(userID uint) ([]models.DegreeProgram, error) {
+    var programs []models.DegreeProgram
+    err := s.db.Where("user_id = ?", userID).Order("name ASC").Find(&programs).Error
+    return programs, err
+}
+
+func (s *DegreeService) GetProgram(userID, programID uint) (*models.DegreeProgram, error) {
+    var program models.DegreeProgram
+    err := s.db.Where("id = ? AND user_id = ?", programID, userID).First(&program).Error
+    return &program, err
+}
+
+func (s *DegreeService) CreateProgram(userID uint, req DegreeProgramRequest) (*models.DegreeProgram, error) {
+    program := models.DegreeProgram{UserID: userID}
+    if err := applyProgramRequest(&program, req); err != nil {
+        return nil, err
+    }
+    if err := s.db.Create(&program).Error; err != nil {
+        return nil, err
+    }
+    return &program, nil
+}
+
+func (s *DegreeService) UpdateProgram(userID, programID uint, req DegreeProgramRequest) (*models.DegreeProgram, error) {
+    program, err := s.GetProgram(userID, programID)
+    if err != nil {
+        return nil, err
+    }
+    if err := applyProgramRequest(program, req); err != nil {
+        return nil, err
+    }
+    if err := s.db.Save(program).Error; err != nil {
+        return nil, err
+    }
+    return program, nil
+}
+
+func (s *DegreeService) DeleteProgram(userID, programID uint) error {
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("id = ? AND user_id = ?", programID, userID).Delete(&models.DegreeProgram{})
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return gorm.ErrRecordNotFound
+        }
+        return tx.Model(&models.User{}).Where("id = ? AND degree_program_id = ?", userID, programID).
+            Update("degree_program_id", nil).Error
+    })
+}
+
+// AttachProgram sets the program the audit checks. The profile's Program
+// takes the template's level so the two stay in step.
+func (s *DegreeService) AttachProgram(userID uint, programID *uint) (*models.User, error) {
+    updates := map[string]interface{}{"degree_program_id": programID}
+    if programID != nil {
+        program, err := s.GetProgram(userID, *programID)
+        if err != nil {
+            return nil, err
+        }
+        if program.Level != "" {
+            updates["program"] = program.Level
+        }
+    }
+
+    var user models.User
+    if err := s.db.First(&user, userID).Error; err != nil {
+        return nil, err
+    }
+    if err := s.db.Model(&user).Updates(updates).Error; err != nil {
+        return nil, err
+    }
+    return &user, nil
+}
+
+// Audit matches the user's courses against a program. With programID nil
+// the program attached to the profile is used. Each course counts towards
+// at most one course requirement, required courses first and then
+// elective pools in order.
+func (s *DegreeService) Audit(userID uint, programID *uint) (*DegreeAudit, error) {
+    if programID == nil {
+        var user models.User
+        if err := s.db.First(&user, userID).Error; err != nil {
+            return nil, err
+        }
+        if user.DegreeProgramID == nil {
+            return nil, ErrNoDegreeProgram
+        }
+        programID = user.DegreeProgramID
+    }
+    program, err := s.GetProgram(userID, *programID)
+    if err != nil {
+        return nil, err
+    }
+
+    var courses []models.Course
+    if err := s.db.Where("user_id = ? AND status <> ?", userID, "dropped").Preload("Term").Order("id ASC").Find(&courses).Error; err != nil {
+        return nil, err
+    }
+    scales, defaultScale, err := s.academicsService.userScales(userID)
+    if err != nil {
+        return nil, err
+    }
+
+    audited := make([]AuditCourse, len(courses))
+    for i := range courses {
+        scale := defaultScale
+        if id := courses[i].GradingScaleID; id != nil && scales[*id] != nil {
+            scale = scales[*id]
+        }
+        audited[i] = auditCourse(&courses[i], scale)
+    }
+    used := make([]bool, len(courses))
+
+    audit := &DegreeAudit{Program: program, Requirements: []RequirementResult{}, Unused: []AuditCourse{}}
+
+    for _, code := range program.RequiredCourses {
+        result := RequirementResult{Type: "required_course", Name: code, Status: RequirementMissing, Required: 1}
+        // Prefer a passed attempt over one still running or failed
+        best := -1
+        for i := range courses {
+            if used[i] || !courseCodeMatches(code, courses[i].CourseCode) {
+                continue
+            }
+            if best < 0 || statusRank(audited[i].Status) > statusRank(audited[best].Status) {
+                best = i
+            }
+        }
+        if best >= 0 {
+            used[best] = true
+            result.Courses = []AuditCourse{audited[best]}
+            result.Status = audited[best].Status
+            switch result.Status {
+            case RequirementSatisfied:
+                result.Completed = 1
+            case RequirementInProgress:
+                result.InProgress = 1
+            }
+        }
+        audit.Requirements = append(audit.Requirements, result)
+    }
+
+    for _, pool := range program.ElectivePools {
+        result := RequirementResult{Type: "elective_pool", Name: pool.Name, Courses: []AuditCourse{}}
+        completedCourses, completedCredits, runningCourses, runningCredits := 0, 0, 0, 0
+        // Passed courses are claimed before running ones, and claiming stops
+        // once the pool is met so the rest stay free for later pools
+        for _, status := range []string{RequirementSatisfied, RequirementInProgress} {
+            for i := range courses {
+                if completedCourses+runningCourses >= pool.MinCourses && completedCredits+runningCredits >= pool.MinCredits {
+                    break
+                }
+                if used[i] || audited[i].Status != status || !poolMatches(pool, courses[i].CourseCode) {
+                    continue
+                }
+                used[i] = true
+                result.Courses = append(result.Courses, audited[i])
+                if status == RequirementSatisfied {
+                    completedCourses++
+                    completedCredits += courses[i].Credits
+                } else {
+                    runningCourses++
+                    runningCredits += courses[i].Credits
+                }
+            }
+        }
+
+        // Measure the pool by credits when it has a credit minimum
+        if pool.MinCredits > 0 {
+            result.Required = float64(pool.MinCredits)
+            result.Completed, result.InProgress = float64(completedCredits), float64(runningCredits)
+        } else {
+            result.Required = float64(pool.MinCourses)
+            result.Completed, result.InProgress = float64(completedCourses), float64(runningCourses)
+        }
+        switch {
+        case completedCourses >= pool.MinCourses && completedCredits >= pool.MinCredits:
+            result.Status = RequirementSatisfied
+        case completedCourses+runningCourses >= pool.MinCourses && completedCredits+runningCredits >= pool.MinCredits:
+            result.Status = RequirementInProgress
+        default:
+            result.Status = RequirementMissing
+        }
+        audit.Requirements = append(audit.Requirements, result)
+    }
+
+    summary, err := s.academicsService.Summary(userID)
+    if err != nil {
+        return nil, err
+    }
+    if program.MinCredits > 0 {
+        result := RequirementResult{
+            Type:       "credits",
+            Name:       "Minimum credits",
+            Required:   float64(program.MinCredits),
+            Completed:  float64(summary.CreditsEarned),
+            InProgress: float64(summary.CreditsInProgress),
+        }
+        result.Status = progressStatus(result.Completed, result.InProgress, result.Required)
+        audit.Requirements = append(audit.Requirements, result)
+    }
+    if program.MinGPA > 0 {
+        audit.Requirements = append(audit.Requirements, gpaRequirement(program, summary, defaultScale))
+    }
+
+    if len(program.Milestones) > 0 {
+        var milestones []models.Milestone
+        if err := s.db.Where("user_id = ?", userID).Find(&milestones).Error; err != nil {
+            return nil, err
+        }
+        for _, name := range program.Milestones {
+            audit.Requirements = append(audit.Requirements, milestoneRequirement(name, milestones))
+        }
+    }
+
+    for i := range courses {
+        if !used[i] && audited[i].Status == RequirementSatisfied {
+            audit.Unused = append(audit.Unused, audited[i])
+        }
+    }
+
+    for _, r := range audit.Requirements {
+        switch r.Status {
+        case RequirementSatisfied:
+            audit.Satisfied++
+        case RequirementInProgress:
+            audit.InProgress++
+        case RequirementMissing:
+            audit.Missing++
+        }
+    }
+    switch {
+    case audit.Missing > 0:
+        audit.Status = RequirementMissing
+    case audit.InProgress > 0:
+        audit.Status = RequirementInProgress
+    default:
+        audit.Status = RequirementSatisfied
+    }
+    return audit, nil
+}
+
+// gpaRequirement checks the cumulative GPA on the user's default scale,
+// which is the home institution's.
+func gpaRequirement(program *models.DegreeProgram, summary *AcademicSummary, defaultScale *models.GradingScale) RequirementResult {
+    result := RequirementResult{Type: "gpa", Name: "Minimum GPA", Required: program.MinGPA, Status: RequirementInProgress}
+    home := scaleRef(defaultScale)
+    for _, institution := range summary.Institutions {
+        if (institution.ScaleID == nil) != (home == nil) || (home != nil && *institution.ScaleID != *home) {
+            continue
+        }
+        if institution.CumulativeGPA == nil {
+            result.Note = "no graded courses yet"
+            return result
+        }
+        result.Completed = *institution.CumulativeGPA
+        if result.Completed >= program.MinGPA {
+            result.Status = RequirementSatisfied
+        } else if summary.CreditsInProgress == 0 {
+            result.Status = RequirementMissing
+        }
+        return result
+    }
+    result.Note = "no graded courses yet"
+    return result
+}
+
+// milestoneRequirement matches a required milestone by name or type, so
+// "Qualifying exam" is met by a milestone of type qualifying_exam.
+func milestoneRequirement(name string, milestones []models.Milestone) RequirementResult {
+    result := RequirementResult{Type: "milestone", Name: name, Status: RequirementMissing, Required: 1}
+    for _, m := range milestones {
+        if key := milestoneKey(name); key != milestoneKey(m.Name) && key != milestoneKey(m.Type) {
+            continue
+        }
+        if m.Status == MilestoneCompleted {
+            result.Status, result.Completed, result.InProgress = RequirementSatisfied, 1, 0
+            return result
+        }
+        result.Status, result.InProgress = RequirementInProgress, 1
+        if m.TargetDate != nil {
+            result.Note = "target " + m.TargetDate.Format(dateLayout)
+        }
+    }
+    return result
+}
+
+// auditCourse classifies a course: passed, still running, or failed.
+func auditCourse(course *models.Course, scale *models.GradingScale) AuditCourse {
+    audited := AuditCourse{
+        CourseID:   course.ID,
+        CourseCode: course.CourseCode,
+        CourseName: course.CourseName,
+        Term:       course.Semester,
+        Credits:    course.Credits,
+        Grade:      course.Grade,
+    }
+    if course.Term != nil {
+        audited.Term = course.Term.Name
+    }
+
+    grade := strings.TrimSpace(course.Grade)
+    switch {
+    case grade == "":
+        // Completed without a recorded grade is taken at face value
+        if course.Status == "completed" {
+            audited.Status = RequirementSatisfied
+        } else {
+            audited.Status = RequirementInProgress
+        }
+    default:
+        mapping, ok := scale.Lookup(grade)
+        switch {
+        case !ok, mapping.Incomplete:
+            // A grade missing from the scale can't be judged either way
+            audited.Status = RequirementInProgress
+        case mapping.EarnsCredit:
+            audited.Status = RequirementSatisfied
+        default:
+            audited.Status = RequirementMissing
+        }
+    }
+    return audited
+}
+
+func statusRank(status string) int {
+    switch status {
+    case RequirementSatisfied:
+        return 2
+    case RequirementInProgress:
+        return 1
+    }
+    return 0
+}
+
+func progressStatus(completed, inProgress, required float64) string {
+    switch {
+    case completed >= required:
+        return RequirementSatisfied
+    case completed+inProgress >= required:
+        return RequirementInProgress
+    }
+    return RequirementMissing
+}
+
+func poolMatches(pool models.ElectivePool, code string) bool {
+    for _, pattern := range pool.Courses {
+        if courseCodeMatches(pattern, code) {
+            return true
+        }
+    }
+    return false
+}
+
+// courseCodeMatches compares course codes ignoring case, spaces and
+// hyphens, so "CS 6515", "cs6515" and "CS-6515" are the same course. A
+// pattern ending in "*" matches by prefix.
+func courseCodeMatches(pattern, code string) bool {
+    pattern, code = normalizeCourseCode(pattern), normalizeCourseCode(code)
+    if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+        return strings.HasPrefix(code, prefix)
+    }
+    return pattern != "" && pattern == code
+}
+
+func normalizeCourseCode(code string) string {
+    return strings.Map(func(r rune) rune {
+        if r == ' ' || r == '-' || r == '\t' {
+            return -1
+        }
+        return r
+    }, strings.ToUpper(code))
+}
+
+func applyProgramRequest(program *models.DegreeProgram, req DegreeProgramRequest) error {
+    if req.RequiredCourses == nil {
+        req.RequiredCourses = []string{}
+    }
+    if req.ElectivePools == nil {
+        req.ElectivePools = []models.ElectivePool{}
+    }
+    if req.Milestones == nil {
+        req.Milestones = []string{}
+    }
+    if req.MilestoneNorms == nil {
+        req.MilestoneNorms = map[string]int{}
+    }
+    for _, pool := range req.ElectivePools {
+        if strings.TrimSpace(pool.Name) == "" || len(pool.Courses) == 0 {
+            return fmt.Errorf("%w: every elective pool needs a name and at least one course", ErrInvalidDegreeProgram)
+        }
+        if pool.MinCourses < 0 || pool.MinCredits < 0 || (pool.MinCourses == 0 && pool.MinCredits == 0) {
+            return fmt.Errorf("%w: elective pool %q needs a positive min_courses or min_credits", ErrInvalidDegreeProgram, pool.Name)
+        }
+    }
+
+    program.Name = strings.TrimSpace(req.Name)
+    program.Level = req.Level
+    program.Institution = req.Institution
+    program.MinCredits = req.MinCredits
+    program.MinGPA = req.MinGPA
+    program.RequiredCourses = req.RequiredCourses
+    program.ElectivePools = req.ElectivePools
+    program.Milestones = req.Milestones
+    program.MilestoneNorms = req.MilestoneNorms
+    return nil
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "sort"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+var (
+    ErrInvalidDependency  = errors.New("an assignment cannot depend on itself")
+    ErrDependencyExists   = errors.New("dependency already exists")
+    ErrDependencyCycle    = errors.New("dependency would create a cycle")
+    ErrInvalidHoursPerDay = errors.New("hours_per_day must be between 0 and 24")
+)
+
+// BlockedError is returned when an assignment cannot be started or
+// completed because some of its blockers are still open.
+type BlockedError struct {
+    Blockers []models.Assignment
+}
+
+func (e *BlockedError) Error() string {
+    return fmt.Sprintf("assignment is blocked by %d open assignment(s)", len(e.Blockers))
+}
+
+type DependencyService struct {
+    db *gorm.DB
+}
+
+func NewDependencyService() *DependencyService {
+    return &DependencyService{
+        db: database.GetDB(),
+    }
+}
+
+type AddDependencyRequest struct {
+    BlockedByID uint `json:"blocked_by_id" binding:"required"`
+}
+
+type CriticalPathNode struct {
+    AssignmentID   uint      `json:"assignment_id"`
+    Title          string    `json:"title"`
+    DueDate        time.Time `json:"due_date"`
+    RemainingHours float64   `json:"remaining_hours"`
+    ChainHours     float64   `json:"chain_hours"` // Remaining hours including every open blocker upstream
+    EarliestFinish time.Time `json:"earliest_finish"`
+    SlackHours     float64   `json:"slack_hours"` // Negative when the deadline is already infeasible
+    Infeasible     bool      `json:"infeasible"`
+}
+
+type CriticalPathReport struct {
+    HoursPerDay     float64            `json:"hours_per_day"`
+    CriticalPath    []CriticalPathNode `json:"critical_path"`
+    TotalHours      float64            `json:"total_hours"`
+    ProjectedFinish *time.Time         `json:"projected_finish"`
+    Infeasible      []CriticalPathNode `json:"infeasible"`
+}
+
+func (s *DependencyService) GetDependencies(userID, assignmentID uint) ([]models.AssignmentDependency, []models.AssignmentDependency, error) {
+    var blockers, dependents []models.AssignmentDependency
+    if err := s.db.Where("user_id = ? AND assignment_id = ?", userID, assignmentID).
+        Preload("BlockedBy").
+        Find(&blockers).Error; err != nil {
+        return nil, nil, err
+    }
+    if err := s.db.Where("user_id = ? AND blocked_by_id = ?", userID, assignmentID).
+        Find(&dependents).Error; err != nil {
+        return nil, nil, err
+    }
+    return blockers, dependents, nil
+}
+
+func (s *DependencyService) AddDependency(userID, assignmentID uint, req AddDependencyRequest) (*models.AssignmentDependency, error) {
+    if assignmentID == req.BlockedByID {
+        return nil, ErrInvalidDependency
+    }
+
+    var count int64
+    if err := s.db.Model(&models.Assignment{}).
+        Where("id IN ? AND user_id = ?", []uint{assignmentID, req.BlockedByID}, userID).
+        Count(&count).Error; err != nil {
+        return nil, err
+    }
+    if count != 2 {
+        return nil, gorm.ErrRecordNotFound
+    }
+
+    graph, err := s.loadGraph(userID)
+    if err != nil {
+        return nil, err
+    }
+    for _, id := range graph[assignmentID] {
+        if id == req.BlockedByID {
+            return nil, ErrDependencyExists
+        }
+    }
+    // The new edge closes a cycle if the blocker already (transitively)
+    // depends on this assignment.
+    if reachable(graph, req.BlockedByID, assignmentID) {
+        return nil, ErrDependencyCycle
+    }
+
+    dependency := models.AssignmentDependency{
+        UserID:       userID,
+        AssignmentID: assignmentID,
+        BlockedByID:  req.BlockedByID,
+    }
+    if err := s.db.Create(&dependency).Error; err != nil {
+        // A concurrent request added the same edge
+        if errors.Is(err, gorm.ErrDuplicatedKey) {
+            return nil, ErrDependencyExists
+        }
+        return nil, err
+    }
+
+    s.db.Preload("BlockedBy").First(&dependency, dependency.ID)
+
+    return &dependency, nil
+}
+
+func (s *DependencyService) RemoveDependency(userID, assignmentID, blockedByID uint) error {
+    return s.db.Where("user_id = ? AND assignment_id = ? AND blocked_by_id = ?", userID, assignmentID, blockedByID).
+        Delete(&models.AssignmentDependency{}).Error
+}
+
+// CriticalPath finds the longest chain of remaining EstimatedHours through
+// the dependency graph and flags assignments whose deadline cannot be met
+// even if the user works hoursPerDay on nothing else but that chain.
+func (s *DependencyService) CriticalPath(userID uint, hoursPerDay float64, now time.Time) (*CriticalPathReport, error) {
+    if hoursPerDay <= 0 || hoursPerDay > 24 {
+        return nil, ErrInvalidHoursPerDay
+    }
+
+    var assignments []models.Assignment
+    if err := s.db.Where("user_id = ? AND status <> ?", userID, "completed").
+        Find(&assignments).Error; err != nil {
+        return nil, err
+    }
+
+    graph, err := s.loadGraph(userID)
+    if err != nil {
+        return nil, err
+    }
+
+    open := make(map[uint]*models.Assignment, len(assignments))
+    for i := range assignments {
+        open[assignments[i].ID] = &assignments[i]
+    }
+
+    // chain[id] is the longest remaining-hours path ending at id; next[id]
+    // is the blocker on that path.
+    chain := map[uint]float64{}
+    next := map[uint]uint{}
+    visiting := map[uint]bool{}
+    var visit func(id uint) float64
+    visit = func(id uint) float64 {
+        if hours, ok := chain[id]; ok {
+            return hours
+        }
+        if visiting[id] {
+            return 0
+        }
+        visiting[id] = true
+
+        best := 0.0
+        for _, blocker := range graph[id] {
+            if _, ok := open[blocker]; !ok {
+                continue
+            }
+            if h := visit(blocker); next[id] == 0 || h > best {
+                best = h
+                next[id] = blocker
+            }
+        }
+
+        visiting[id] = false
+        chain[id] = best + remainingHours(open[id])
+        return chain[id]
+    }
+
+    report := &CriticalPathReport{HoursPerDay: hoursPerDay}
+    nodes := make(map[uint]CriticalPathNode, len(assignments))
+    var tail uint
+    for _, a := range assignments {
+        hours := visit(a.ID)
+        finish := now.Add(time.Duration(hours / hoursPerDay * 24 * float64(time.Hour)))
+        available := a.DueDate.Sub(now).Hours() / 24 * hoursPerDay
+
+        node := CriticalPathNode{
+            AssignmentID:   a.ID,
+            Title:          a.Title,
+            DueDate:        a.DueDate,
+            RemainingHours: remainingHours(&a),
+            ChainHours:     hours,
+            EarliestFinish: finish,
+            SlackHours:     available - hours,
+            Infeasible:     finish.After(a.DueDate),
+        }
+        nodes[a.ID] = node
+        if node.Infeasible {
+            report.Infeasible = append(report.Infeasible, node)
+        }
+        if tail == 0 || hours > report.TotalHours || (hours == report.TotalHours && a.DueDate.Before(nodes[tail].DueDate)) {
+            report.TotalHours = hours
+            tail = a.ID
+        }
+    }
+
+    if tail != 0 {
+        for id := tail; id != 0; id = next[id] {
+            report.CriticalPath = append([]CriticalPathNode{nodes[id]}, report.CriticalPath...)
+        }
+        finish := nodes[tail].EarliestFinish
+        report.ProjectedFinish = &finish
+    }
+
+    sort.Slice(report.Infeasible, func(i, j int) bool {
+        return report.Infeasible[i].DueDate.Before(report.Infeasible[j].DueDate)
+    })
+
+    return report, nil
+}
+
+// loadGraph maps each assignment to the assignments blocking it.
+func (s *DependencyService) loadGraph(userID uint) (map[uint][]uint, error) {
+    var dependencies []models.AssignmentDependency
+    if err := s.db.Where("user_id = ?", userID).Find(&dependencies).Error; err != nil {
+        return nil, err
+    }
+
+    graph := map[uint][]uint{}
+    for _, d := range dependencies {
+        graph[d.AssignmentID] = append(graph[d.AssignmentID], d.BlockedByID)
+    }
+    return graph, nil
+}
+
+// checkStatusChange refuses to move an assignment from one status into
+// in_progress or completed while any of its blockers are open. Every write
+// of a user-supplied status goes through it.
+func checkStatusChange(db *gorm.DB, assignmentID uint, from string, to interface{}) error {
+    if to == from || (to != "in_progress" && to != "completed") {
+        return nil
+    }
+    blockers, err := openBlockers(db, assignmentID)
+    if err != nil {
+        return err
+    }
+    if len(blockers) > 0 {
+        return &BlockedError{Blockers: blockers}
+    }
+    return nil
+}
+
+// openBlockers returns the blockers of an assignment that are not completed.
+func openBlockers(db *gorm.DB, assignmentID uint) ([]models.Assignment, error) {
+    var blockers []models.Assignment
+    err := db.Joins("JOIN assignment_dependencies ON assignment_dependencies.blocked_by_id = assignments.id").
+        Where("assignment_dependencies.assignment_id = ? AND assignments.status <> ?", assignmentID, "completed").
+        Find(&blockers).Error
+    return blockers, err
+}
+
+func reachable(graph map[uint][]uint, from, to uint) bool {
+    seen := map[uint]bool{}
+    stack := []uint{from}
+    for len(stack) > 0 {
+        id := stack[len(stack)-1]
+        stack = stack[:len(stack)-1]
+        if id == to {
+            return true
+        }
+        if seen[id] {
+            continue
+        }
+        seen[id] = true
+        stack = append(stack, graph[id]...)
+    }
+    return false
+}
+
+func remainingHours(a *models.Assignment) float64 {
+    if a == nil || a.EstimatedHours <= a.ActualHours {
+        return 0
+    }
+    return float64(a.EstimatedHours - a.ActualHours)
+}
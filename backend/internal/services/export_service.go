@@ -0,0 +1,152 @@
+package services
+
+import (
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+const (
+    EntityCourses     = "courses"
+    EntityAssignments = "assignments"
+)
+
+// Column order for CSV export. These are also the field names bulk
+// import understands, so an export can be edited and imported again.
+var (
+    CourseColumns     = []string{"external_key", "course_name", "course_code", "instructor", "credits", "semester", "grade", "status"}
+    AssignmentColumns = []string{"external_key", "course_key", "course_code", "title", "description", "due_date", "priority", "status", "estimated_hours", "actual_hours"}
+)
+
+// Cells starting with one of these are formulas to Excel and Sheets.
+const csvFormulaPrefixes = "=+-@\t\r"
+
+type ExportService struct {
+    db *gorm.DB
+}
+
+func NewExportService() *ExportService {
+    return &ExportService{
+        db: database.GetDB(),
+    }
+}
+
+type CourseRecord struct {
+    ExternalKey string `json:"external_key"`
+    CourseName  string `json:"course_name"`
+    CourseCode  string `json:"course_code"`
+    Instructor  string `json:"instructor"`
+    Credits     int    `json:"credits"`
+    Semester    string `json:"semester"`
+    Grade       string `json:"grade"`
+    Status      string `json:"status"`
+}
+
+// AssignmentRecord refers to its course by external key when the course
+// has one, and always by course code.
+type AssignmentRecord struct {
+    ExternalKey    string    `json:"external_key"`
+    CourseKey      string    `json:"course_key"`
+    CourseCode     string    `json:"course_code"`
+    Title          string    `json:"title"`
+    Description    string    `json:"description"`
+    DueDate        time.Time `json:"due_date"`
+    Priority       string    `json:"priority"`
+    Status         string    `json:"status"`
+    EstimatedHours int       `json:"estimated_hours"`
+    ActualHours    int       `json:"actual_hours"`
+}
+
+type ExportData struct {
+    Courses     []CourseRecord     `json:"courses,omitempty"`
+    Assignments []AssignmentRecord `json:"assignments,omitempty"`
+}
+
+func (r CourseRecord) CSVRow() []string {
+    return []string{
+        csvText(r.ExternalKey), csvText(r.CourseName), csvText(r.CourseCode), csvText(r.Instructor),
+        strconv.Itoa(r.Credits), csvText(r.Semester), csvText(r.Grade), csvText(r.Status),
+    }
+}
+
+func (r AssignmentRecord) CSVRow() []string {
+    return []string{
+        csvText(r.ExternalKey), csvText(r.CourseKey), csvText(r.CourseCode), csvText(r.Title), csvText(r.Description),
+        r.DueDate.UTC().Format(time.RFC3339), csvText(r.Priority), csvText(r.Status),
+        strconv.Itoa(r.EstimatedHours), strconv.Itoa(r.ActualHours),
+    }
+}
+
+// csvText quotes a text cell that a spreadsheet would otherwise run as a
+// formula. Imports strip the quote again, see fromCSVText.
+func csvText(value string) string {
+    if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+        return "'" + value
+    }
+    return value
+}
+
+// fromCSVText undoes csvText.
+func fromCSVText(value string) string {
+    if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes, rune(value[1])) {
+        return value[1:]
+    }
+    return value
+}
+
+// Export collects the user's courses and/or assignments. An empty entity
+// exports both.
+func (s *ExportService) Export(userID uint, entity string) (*ExportData, error) {
+    data := &ExportData{}
+
+    if entity == "" || entity == EntityCourses {
+        var courses []models.Course
+        if err := s.db.Where("user_id = ?", userID).Order("id ASC").Find(&courses).Error; err != nil {
+            return nil, err
+        }
+        data.Courses = make([]CourseRecord, 0, len(courses))
+        for _, c := range courses {
+            data.Courses = append(data.Courses, CourseRecord{
+                ExternalKey: c.ExternalKey,
+                CourseName:  c.CourseName,
+                CourseCode:  c.CourseCode,
+                Instructor:  c.Instructor,
+                Credits:     c.Credits,
+                Semester:    c.Semester,
+                Grade:       c.Grade,
+                Status:      c.Status,
+            })
+        }
+    }
+
+    if entity == "" || entity == EntityAssignments {
+        var assignments []models.Assignment
+        if err := s.db.Where("user_id = ?", userID).Preload("Course").Order("due_date ASC, id ASC").Find(&assignments).Error; err != nil {
+            return nil, err
+        }
+        data.Assignments = make([]AssignmentRecord, 0, len(assignments))
+        for _, a := range assignments {
+            record := AssignmentRecord{
+                ExternalKey:    a.ExternalKey,
+                Title:          a.Title,
+                Description:    a.Description,
+                DueDate:        a.DueDate,
+                Priority:       a.Priority,
+                Status:         a.Status,
+                EstimatedHours: a.EstimatedHours,
+                ActualHours:    a.ActualHours,
+            }
+            if a.Course != nil {
+                record.CourseKey = a.Course.ExternalKey
+                record.CourseCode = a.Course.CourseCode
+            }
+            data.Assignments = append(data.Assignments, record)
+        }
+    }
+
+    return data, nil
+}
@@ -0,0 +1,709 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+const (
+    FundingApplying = "applying"
+    FundingAwarded  = "awarded"
+    FundingActive   = "active"
+    FundingEnded    = "ended"
+    FundingDeclined = "declined"
+
+    FundingDeadlineApplication = "application"
+    FundingDeadlineRenewal     = "renewal"
+    FundingDeadlineReport      = "report"
+    FundingDeadlineOther       = "other"
+
+    defaultFundingReminderDays = 14
+)
+
+var ErrInvalidFunding = errors.New("invalid funding")
+
+var (
+    fundingKinds         = []string{"fellowship", "grant", "scholarship", "assistantship", "stipend", "other"}
+    fundingStatuses      = []string{FundingApplying, FundingAwarded, FundingActive, FundingEnded, FundingDeclined}
+    fundingAmountPer     = []string{"total", "year", "term", "month"}
+    fundingDeadlineKinds = []string{FundingDeadlineApplication, FundingDeadlineRenewal, FundingDeadlineReport, FundingDeadlineOther}
+)
+
+var fundingDeadlineLabels = map[string]string{
+    FundingDeadlineApplication: "Submit application",
+    FundingDeadlineRenewal:     "Submit renewal",
+    FundingDeadlineReport:      "Submit report",
+    FundingDeadlineOther:       "Deadline",
+}
+
+type FundingService struct {
+    db *gorm.DB
+}
+
+func NewFundingService() *FundingService {
+    return &FundingService{
+        db: database.GetDB(),
+    }
+}
+
+type FundingDeadlineRequest struct {
+    Kind         string    `json:"kind"`
+    Title        string    `json:"title"` // Defaults to one based on the kind
+    DueDate      time.Time `json:"due_date" binding:"required"`
+    RepeatMonths int       `json:"repeat_months" binding:"gte=0,lte=60"`
+}
+
+type UpdateFundingDeadlineRequest struct {
+    Kind         *string    `json:"kind"`
+    Title        *string    `json:"title"`
+    DueDate      *time.Time `json:"due_date"`
+    RepeatMonths *int       `json:"repeat_months" binding:"omitempty,gte=0,lte=60"`
+}
+
+type CreateFundingRequest struct {
+    Name         string                   `json:"name" binding:"required"`
+    Source       string                   `json:"source"`
+    Kind         string                   `json:"kind"`
+    Status       string                   `json:"status"`
+    Amount       float64                  `json:"amount" binding:"gte=0"`
+    Currency     string                   `json:"currency"`
+    AmountPer    string                   `json:"amount_per"`
+    StartDate    *time.Time               `json:"start_date"`
+    EndDate      *time.Time               `json:"end_date"`
+    Conditions   models.FundingConditions `json:"conditions"`
+    ReminderDays int                      `json:"reminder_days" binding:"gte=0,lte=365"` // Defaults to 14
+    Notes        string                   `json:"notes"`
+    Deadlines    []FundingDeadlineRequest `json:"deadlines" binding:"dive"`
+}
+
+type UpdateFundingRequest struct {
+    Name         *string                   `json:"name"`
+    Source       *string                   `json:"source"`
+    Kind         *string                   `json:"kind"`
+    Status       *string                   `json:"status"`
+    Amount       *float64                  `json:"amount" binding:"omitempty,gte=0"`
+    Currency     *string                   `json:"currency"`
+    AmountPer    *string                   `json:"amount_per"`
+    StartDate    *time.Time                `json:"start_date"`
+    EndDate      *time.Time                `json:"end_date"`
+    ClearDates   bool                      `json:"clear_dates"` // Removes start and end dates
+    Conditions   *models.FundingConditions `json:"conditions"`
+    ReminderDays *int                      `json:"reminder_days" binding:"omitempty,gte=0,lte=365"`
+    Notes        *string                   `json:"notes"`
+}
+
+// EligibilityCheck is one funding condition compared with the user's
+// record. Met is nil when the condition can't be checked, either because
+// it is free text or because there is no data yet.
+type EligibilityCheck struct {
+    Condition   string   `json:"condition"` // min_gpa, min_credits, min_courses, other
+    Description string   `json:"description"`
+    Required    *float64 `json:"required,omitempty"`
+    Actual      *float64 `json:"actual,omitempty"`
+    Met         *bool    `json:"met"`
+}
+
+type FundingEligibility struct {
+    FundingID uint               `json:"funding_id"`
+    Name      string             `json:"name"`
+    Eligible  bool               `json:"eligible"` // No checkable condition is failing
+    Term      string             `json:"term,omitempty"` // Term the enrollment checks used
+    Checks    []EligibilityCheck `json:"checks"`
+}
+
+func (s *FundingService) GetFundings(userID uint, status string) ([]models.Funding, error) {
+    query := s.db.Where("user_id = ?", userID)
+    if status != "" {
+        query = query.Where("status = ?", status)
+    }
+    var fundings []models.Funding
+    err := query.Preload("Deadlines", func(db *gorm.DB) *gorm.DB { return db.Order("due_date ASC") }).
+        Order("created_at DESC").
+        Find(&fundings).Error
+    return fundings, err
+}
+
+func (s *FundingService) GetFunding(userID, fundingID uint) (*models.Funding, error) {
+    var funding models.Funding
+    err := s.db.Where("id = ? AND user_id = ?", fundingID, userID).
+        Preload("Deadlines", func(db *gorm.DB) *gorm.DB { return db.Order("due_date ASC") }).
+        First(&funding).Error
+    return &funding, err
+}
+
+func (s *FundingService) CreateFunding(userID uint, req CreateFundingRequest, now time.Time) (*models.Funding, error) {
+    funding := models.Funding{
+        UserID:       userID,
+        Name:         strings.TrimSpace(req.Name),
+        Source:       req.Source,
+        Kind:         req.Kind,
+        Status:       req.Status,
+        Amount:       req.Amount,
+        Currency:     req.Currency,
+        AmountPer:    req.AmountPer,
+        StartDate:    req.StartDate,
+        EndDate:      req.EndDate,
+        Conditions:   req.Conditions,
+        ReminderDays: req.ReminderDays,
+        Notes:        req.Notes,
+    }
+    if funding.ReminderDays == 0 {
+        funding.ReminderDays = defaultFundingReminderDays
+    }
+    if err := normalizeFunding(&funding); err != nil {
+        return nil, err
+    }
+    deadlines := make([]models.FundingDeadline, 0, len(req.Deadlines))
+    for _, d := range req.Deadlines {
+        deadline := models.FundingDeadline{UserID: userID, Kind: d.Kind, Title: d.Title, DueDate: d.DueDate, RepeatMonths: d.RepeatMonths}
+        if err := normalizeFundingDeadline(&deadline); err != nil {
+            return nil, err
+        }
+        deadlines = append(deadlines, deadline)
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := checkGradingScale(tx, userID, funding.Conditions.GPAScaleID); err != nil {
+            return err
+        }
+        if err := tx.Create(&funding).Error; err != nil {
+            return err
+        }
+        for i := range deadlines {
+            deadlines[i].FundingID = funding.ID
+            if err := tx.Create(&deadlines[i]).Error; err != nil {
+                return err
+            }
+            if err := syncFundingReminder(tx, &funding, &deadlines[i], now); err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return s.GetFunding(userID, funding.ID)
+}
+
+// UpdateFunding saves the changes and refreshes the reminders of its open
+// deadlines. Once funding has ended or been declined its open reminders
+// are removed.
+func (s *FundingService) UpdateFunding(userID, fundingID uint, req UpdateFundingRequest, now time.Time) (*models.Funding, error) {
+    var funding models.Funding
+    if err := s.db.Where("id = ? AND user_id = ?", fundingID, userID).First(&funding).Error; err != nil {
+        return nil, err
+    }
+
+    if req.Name != nil {
+        funding.Name = strings.TrimSpace(*req.Name)
+    }
+    if req.Source != nil {
+        funding.Source = *req.Source
+    }
+    if req.Kind != nil {
+        funding.Kind = *req.Kind
+    }
+    if req.Status != nil {
+        funding.Status = *req.Status
+    }
+    if req.Amount != nil {
+        funding.Amount = *req.Amount
+    }
+    if req.Currency != nil {
+        funding.Currency = *req.Currency
+    }
+    if req.AmountPer != nil {
+        funding.AmountPer = *req.AmountPer
+    }
+    if req.ClearDates {
+        funding.StartDate, funding.EndDate = nil, nil
+    }
+    if req.StartDate != nil {
+        funding.StartDate = req.StartDate
+    }
+    if req.EndDate != nil {
+        funding.EndDate = req.EndDate
+    }
+    if req.Conditions != nil {
+        funding.Conditions = *req.Conditions
+    }
+    if req.ReminderDays != nil {
+        funding.ReminderDays = *req.ReminderDays
+    }
+    if req.Notes != nil {
+        funding.Notes = *req.Notes
+    }
+    if err := normalizeFunding(&funding); err != nil {
+        return nil, err
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if req.Conditions != nil {
+            if err := checkGradingScale(tx, userID, funding.Conditions.GPAScaleID); err != nil {
+                return err
+            }
+        }
+        if err := tx.Save(&funding).Error; err != nil {
+            return err
+        }
+        var deadlines []models.FundingDeadline
+        if err := tx.Where("funding_id = ? AND user_id = ? AND completed_at IS NULL", funding.ID, userID).
+            Find(&deadlines).Error; err != nil {
+            return err
+        }
+        for i := range deadlines {
+            if err := syncFundingReminder(tx, &funding, &deadlines[i], now); err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return s.GetFunding(userID, funding.ID)
+}
+
+func (s *FundingService) DeleteFunding(userID, fundingID uint) error {
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("id = ? AND user_id = ?", fundingID, userID).Delete(&models.Funding{})
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return gorm.ErrRecordNotFound
+        }
+        var deadlineIDs []uint
+        if err := tx.Model(&models.FundingDeadline{}).Where("funding_id = ? AND user_id = ?", fundingID, userID).
+            Pluck("id", &deadlineIDs).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("funding_id = ? AND user_id = ?", fundingID, userID).Delete(&models.FundingDeadline{}).Error; err != nil {
+            return err
+        }
+        return removeFundingReminders(tx, userID, deadlineIDs)
+    })
+}
+
+func (s *FundingService) CreateDeadline(userID, fundingID uint, req FundingDeadlineRequest, now time.Time) (*models.FundingDeadline, error) {
+    var funding models.Funding
+    if err := s.db.Where("id = ? AND user_id = ?", fundingID, userID).First(&funding).Error; err != nil {
+        return nil, err
+    }
+    deadline := models.FundingDeadline{
+        UserID:       userID,
+        FundingID:    funding.ID,
+        Kind:         req.Kind,
+        Title:        req.Title,
+        DueDate:      req.DueDate,
+        RepeatMonths: req.RepeatMonths,
+    }
+    if err := normalizeFundingDeadline(&deadline); err != nil {
+        return nil, err
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(&deadline).Error; err != nil {
+            return err
+        }
+        return syncFundingReminder(tx, &funding, &deadline, now)
+    })
+    return &deadline, err
+}
+
+func (s *FundingService) UpdateDeadline(userID, fundingID, deadlineID uint, req UpdateFundingDeadlineRequest, now time.Time) (*models.FundingDeadline, error) {
+    var funding models.Funding
+    if err := s.db.Where("id = ? AND user_id = ?", fundingID, userID).First(&funding).Error; err != nil {
+        return nil, err
+    }
+    var deadline models.FundingDeadline
+    if err := s.db.Where("id = ? AND funding_id = ? AND user_id = ?", deadlineID, fundingID, userID).First(&deadline).Error; err != nil {
+        return nil, err
+    }
+
+    if req.Kind != nil {
+        deadline.Kind = *req.Kind
+    }
+    if req.Title != nil {
+        deadline.Title = *req.Title
+    }
+    if req.DueDate != nil {
+        deadline.DueDate = *req.DueDate
+    }
+    if req.RepeatMonths != nil {
+        deadline.RepeatMonths = *req.RepeatMonths
+    }
+    if err := normalizeFundingDeadline(&deadline); err != nil {
+        return nil, err
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Save(&deadline).Error; err != nil {
+            return err
+        }
+        return syncFundingReminder(tx, &funding, &deadline, now)
+    })
+    return &deadline, err
+}
+
+func (s *FundingService) DeleteDeadline(userID, fundingID, deadlineID uint) error {
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("id = ? AND funding_id = ? AND user_id = ?", deadlineID, fundingID, userID).Delete(&models.FundingDeadline{})
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return gorm.ErrRecordNotFound
+        }
+        return removeFundingReminders(tx, userID, []uint{deadlineID})
+    })
+}
+
+// CompleteDeadline marks the deadline met and completes its reminder. A
+// repeating deadline then gets its next occurrence, unless that falls
+// after the funding ends. The new occurrence is returned, if any.
+func (s *FundingService) CompleteDeadline(userID, fundingID, deadlineID uint, now time.Time) (*models.FundingDeadline, *models.FundingDeadline, error) {
+    var funding models.Funding
+    if err := s.db.Where("id = ? AND user_id = ?", fundingID, userID).First(&funding).Error; err != nil {
+        return nil, nil, err
+    }
+    var deadline models.FundingDeadline
+    if err := s.db.Where("id = ? AND funding_id = ? AND user_id = ?", deadlineID, fundingID, userID).First(&deadline).Error; err != nil {
+        return nil, nil, err
+    }
+    if deadline.CompletedAt != nil {
+        return &deadline, nil, nil
+    }
+
+    var next *models.FundingDeadline
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        deadline.CompletedAt = &now
+        if err := tx.Save(&deadline).Error; err != nil {
+            return err
+        }
+        if err := tx.Model(&models.Assignment{}).
+            Where("funding_deadline_id = ? AND user_id = ? AND status <> ?", deadline.ID, userID, "completed").
+            Updates(map[string]interface{}{"status": "completed", "completed_at": now}).Error; err != nil {
+            return err
+        }
+
+        if deadline.RepeatMonths == 0 || funding.Status == FundingEnded || funding.Status == FundingDeclined {
+            return nil
+        }
+        due := deadline.DueDate.AddDate(0, deadline.RepeatMonths, 0)
+        if funding.EndDate != nil && due.After(*funding.EndDate) {
+            return nil
+        }
+        next = &models.FundingDeadline{
+            UserID:       userID,
+            FundingID:    funding.ID,
+            Kind:         deadline.Kind,
+            Title:        deadline.Title,
+            DueDate:      due,
+            RepeatMonths: deadline.RepeatMonths,
+        }
+        if err := tx.Create(next).Error; err != nil {
+            return err
+        }
+        return syncFundingReminder(tx, &funding, next, now)
+    })
+    if err != nil {
+        return nil, nil, err
+    }
+    return &deadline, next, nil
+}
+
+// Eligibility checks the funding's conditions against the user's GPA and
+// the courses they are taking this term (or next, between terms).
+func (s *FundingService) Eligibility(userID, fundingID uint, now time.Time) (*FundingEligibility, error) {
+    funding, err := s.GetFunding(userID, fundingID)
+    if err != nil {
+        return nil, err
+    }
+    summary, load, err := s.academicRecord(userID, now)
+    if err != nil {
+        return nil, err
+    }
+    return checkEligibility(funding, summary, load), nil
+}
+
+// AllEligibility checks every funding source the user holds or is
+// applying for.
+func (s *FundingService) AllEligibility(userID uint, now time.Time) ([]FundingEligibility, error) {
+    var fundings []models.Funding
+    if err := s.db.Where("user_id = ? AND status IN ?", userID, []string{FundingApplying, FundingAwarded, FundingActive}).
+        Order("created_at DESC").
+        Find(&fundings).Error; err != nil {
+        return nil, err
+    }
+    result := []FundingEligibility{}
+    if len(fundings) == 0 {
+        return result, nil
+    }
+    summary, load, err := s.academicRecord(userID, now)
+    if err != nil {
+        return nil, err
+    }
+    for i := range fundings {
+        result = append(result, *checkEligibility(&fundings[i], summary, load))
+    }
+    return result, nil
+}
+
+func (s *FundingService) academicRecord(userID uint, now time.Time) (*AcademicSummary, *courseLoad, error) {
+    summary, err := NewAcademicsService().Summary(userID)
+    if err != nil {
+        return nil, nil, err
+    }
+    load, err := s.enrollment(userID, now)
+    if err != nil {
+        return nil, nil, err
+    }
+    return summary, load, nil
+}
+
+func checkEligibility(funding *models.Funding, summary *AcademicSummary, load *courseLoad) *FundingEligibility {
+    result := &FundingEligibility{FundingID: funding.ID, Name: funding.Name, Eligible: true, Term: load.term, Checks: []EligibilityCheck{}}
+    add := func(check EligibilityCheck) {
+        if check.Met != nil && !*check.Met {
+            result.Eligible = false
+        }
+        result.Checks = append(result.Checks, check)
+    }
+    conditions := funding.Conditions
+
+    if conditions.MinGPA != nil {
+        check := EligibilityCheck{
+            Condition:   "min_gpa",
+            Description: fmt.Sprintf("Cumulative GPA of at least %.2f", *conditions.MinGPA),
+            Required:    conditions.MinGPA,
+        }
+        if institution := gpaInstitution(summary, conditions.GPAScaleID); institution != nil && institution.CumulativeGPA != nil {
+            met := *institution.CumulativeGPA >= *conditions.MinGPA
+            check.Actual, check.Met = institution.CumulativeGPA, &met
+        }
+        add(check)
+    }
+    if conditions.MinCredits > 0 {
+        required, actual := float64(conditions.MinCredits), float64(load.credits)
+        met := load.credits >= conditions.MinCredits
+        add(EligibilityCheck{
+            Condition:   "min_credits",
+            Description: fmt.Sprintf("Enrolled in at least %d credits", conditions.MinCredits),
+            Required:    &required,
+            Actual:      &actual,
+            Met:         &met,
+        })
+    }
+    if conditions.MinCourses > 0 {
+        required, actual := float64(conditions.MinCourses), float64(load.courses)
+        met := load.courses >= conditions.MinCourses
+        add(EligibilityCheck{
+            Condition:   "min_courses",
+            Description: fmt.Sprintf("Enrolled in at least %d courses", conditions.MinCourses),
+            Required:    &required,
+            Actual:      &actual,
+            Met:         &met,
+        })
+    }
+    for _, other := range conditions.Other {
+        add(EligibilityCheck{Condition: "other", Description: other})
+    }
+    return result
+}
+
+type courseLoad struct {
+    term    string
+    credits int
+    courses int
+}
+
+// enrollment counts the courses the user is taking in the current term,
+// or the next one between terms. Without terms it falls back to every
+// course marked enrolled.
+func (s *FundingService) enrollment(userID uint, now time.Time) (*courseLoad, error) {
+    load := &courseLoad{}
+    query := s.db.Where("user_id = ? AND status <> ?", userID, "dropped")
+
+    current, err := NewTermService().GetCurrentTerm(userID, now)
+    switch {
+    case err == nil:
+        load.term = current.Term.Name
+        query = query.Where("term_id = ?", current.Term.ID)
+    case errors.Is(err, gorm.ErrRecordNotFound):
+        query = query.Where("status = ?", "enrolled")
+    default:
+        return nil, err
+    }
+
+    var courses []models.Course
+    if err := query.Find(&courses).Error; err != nil {
+        return nil, err
+    }
+    for _, course := range courses {
+        load.credits += course.Credits
+        load.courses++
+    }
+    return load, nil
+}
+
+// gpaInstitution picks the scale a GPA condition is read from: the one
+// named, else the one with the most graded credits.
+func gpaInstitution(summary *AcademicSummary, scaleID *uint) *InstitutionSummary {
+    var best *InstitutionSummary
+    for i := range summary.Institutions {
+        institution := &summary.Institutions[i]
+        if scaleID != nil {
+            if institution.ScaleID != nil && *institution.ScaleID == *scaleID {
+                return institution
+            }
+            continue
+        }
+        if best == nil || institution.GPACredits > best.GPACredits {
+            best = institution
+        }
+    }
+    return best
+}
+
+// syncFundingReminder creates or updates the reminder assignment for an
+// open deadline. It falls due ReminderDays before the deadline, or on the
+// deadline itself once that lead time has passed. Funding that has ended
+// or been declined keeps no open reminders.
+func syncFundingReminder(tx *gorm.DB, funding *models.Funding, deadline *models.FundingDeadline, now time.Time) error {
+    if deadline.CompletedAt != nil {
+        return nil
+    }
+    if funding.Status == FundingEnded || funding.Status == FundingDeclined {
+        return tx.Where("funding_deadline_id = ? AND user_id = ? AND status <> ?", deadline.ID, funding.UserID, "completed").
+            Delete(&models.Assignment{}).Error
+    }
+
+    title := deadline.Title + ": " + funding.Name
+    due := deadline.DueDate.AddDate(0, 0, -funding.ReminderDays)
+    if due.Before(now) {
+        due = deadline.DueDate
+    }
+    description := fmt.Sprintf("Reminder for the %s deadline on %s", deadline.Kind, deadline.DueDate.Format(dateLayout))
+    if funding.Source != "" {
+        description += " (" + funding.Source + ")"
+    }
+
+    var existing []models.Assignment
+    if err := tx.Where("funding_deadline_id = ? AND user_id = ?", deadline.ID, funding.UserID).Find(&existing).Error; err != nil {
+        return err
+    }
+    if len(existing) == 0 {
+        assignment := models.Assignment{
+            UserID:            funding.UserID,
+            Title:             title,
+            Description:       description,
+            DueDate:           due,
+            Priority:          "high",
+            Status:            "pending",
+            FundingDeadlineID: &deadline.ID,
+        }
+        return tx.Create(&assignment).Error
+    }
+    for _, a := range existing {
+        if a.Status == "completed" {
+            continue
+        }
+        if err := tx.Model(&models.Assignment{}).Where("id = ?", a.ID).
+            Updates(map[string]interface{}{"title": title, "description": description, "due_date": due}).Error; err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// removeFundingReminders deletes the open reminders of removed deadlines
+// and unlinks the completed ones.
+func removeFundingReminders(tx *gorm.DB, userID uint, deadlineIDs []uint) error {
+    if len(deadlineIDs) == 0 {
+        return nil
+    }
+    if err := tx.Where("funding_deadline_id IN ? AND user_id = ? AND status <> ?", deadlineIDs, userID, "completed").
+        Delete(&models.Assignment{}).Error; err != nil {
+        return err
+    }
+    return tx.Model(&models.Assignment{}).Where("funding_deadline_id IN ? AND user_id = ?", deadlineIDs, userID).
+        Update("funding_deadline_id", nil).Error
+}
+
+func normalizeFunding(funding *models.Funding) error {
+    if funding.Name == "" {
+        return fmt.Errorf("%w: name is required", ErrInvalidFunding)
+    }
+    if funding.Kind == "" {
+        funding.Kind = "other"
+    }
+    if !containsString(fundingKinds, funding.Kind) {
+        return fmt.Errorf("%w: kind must be one of %s", ErrInvalidFunding, strings.Join(fundingKinds, ", "))
+    }
+    if funding.Status == "" {
+        funding.Status = FundingActive
+    }
+    if !containsString(fundingStatuses, funding.Status) {
+        return fmt.Errorf("%w: status must be one of %s", ErrInvalidFunding, strings.Join(fundingStatuses, ", "))
+    }
+    if funding.AmountPer == "" {
+        funding.AmountPer = "total"
+    }
+    if !containsString(fundingAmountPer, funding.AmountPer) {
+        return fmt.Errorf("%w: amount_per must be one of %s", ErrInvalidFunding, strings.Join(fundingAmountPer, ", "))
+    }
+    funding.Currency = strings.ToUpper(strings.TrimSpace(funding.Currency))
+    if funding.Currency == "" {
+        funding.Currency = "USD"
+    }
+    if funding.Amount < 0 || funding.ReminderDays < 0 {
+        return fmt.Errorf("%w: amount and reminder_days must not be negative", ErrInvalidFunding)
+    }
+    if funding.StartDate != nil && funding.EndDate != nil && funding.EndDate.Before(*funding.StartDate) {
+        return fmt.Errorf("%w: end_date must not be before start_date", ErrInvalidFunding)
+    }
+
+    c := &funding.Conditions
+    if c.MinGPA != nil && *c.MinGPA < 0 {
+        return fmt.Errorf("%w: min_gpa must not be negative", ErrInvalidFunding)
+    }
+    if c.MinCredits < 0 || c.MinCourses < 0 {
+        return fmt.Errorf("%w: min_credits and min_courses must not be negative", ErrInvalidFunding)
+    }
+    other := []string{}
+    for _, o := range c.Other {
+        if o = strings.TrimSpace(o); o != "" {
+            other = append(other, o)
+        }
+    }
+    c.Other = other
+    return nil
+}
+
+func normalizeFundingDeadline(deadline *models.FundingDeadline) error {
+    if deadline.Kind == "" {
+        deadline.Kind = FundingDeadlineOther
+    }
+    if !containsString(fundingDeadlineKinds, deadline.Kind) {
+        return fmt.Errorf("%w: deadline kind must be one of %s", ErrInvalidFunding, strings.Join(fundingDeadlineKinds, ", "))
+    }
+    if deadline.DueDate.IsZero() {
+        return fmt.Errorf("%w: deadline due_date is required", ErrInvalidFunding)
+    }
+    if deadline.RepeatMonths < 0 {
+        return fmt.Errorf("%w: repeat_months must not be negative", ErrInvalidFunding)
+    }
+    deadline.Title = strings.TrimSpace(deadline.Title)
+    if deadline.Title == "" {
+        deadline.Title = fundingDeadlineLabels[deadline.Kind]
+    }
+    return nil
+}
@@ -0,0 +1,606 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+const (
+    GoalPeriodWeek   = "week"
+    GoalPeriodTerm   = "term"
+    GoalPeriodCustom = "custom"
+
+    GoalActive   = "active"
+    GoalAchieved = "achieved"
+    GoalMissed   = "missed"
+    GoalDropped  = "dropped"
+
+    // Review outcomes; open goals haven't been hit and their period is
+    // still running.
+    GoalHit  = "hit"
+    GoalMiss = "miss"
+    GoalOpen = "open"
+)
+
+var ErrInvalidGoal = errors.New("invalid goal")
+
+var (
+    goalPeriods  = []string{GoalPeriodWeek, GoalPeriodTerm, GoalPeriodCustom}
+    goalStatuses = []string{GoalActive, GoalAchieved, GoalMissed, GoalDropped}
+)
+
+// assignmentProgress is how far a linked assignment's status takes its
+// key result.
+var assignmentProgress = map[string]float64{
+    "pending":     0,
+    "in_progress": 0.5,
+    "completed":   1,
+}
+
+type GoalService struct {
+    db *gorm.DB
+}
+
+func NewGoalService() *GoalService {
+    return &GoalService{
+        db: database.GetDB(),
+    }
+}
+
+type KeyResultRequest struct {
+    Title        string  `json:"title"` // Defaults to the linked assignment's title
+    AssignmentID *uint   `json:"assignment_id"`
+    Target       float64 `json:"target" binding:"gte=0"` // Defaults to 1 without an assignment
+    Current      float64 `json:"current" binding:"gte=0"`
+    Unit         string  `json:"unit"`
+    Weight       float64 `json:"weight" binding:"gte=0"` // Defaults to 1
+}
+
+type UpdateKeyResultRequest struct {
+    Title        *string  `json:"title"`
+    AssignmentID *uint    `json:"assignment_id"` // 0 unlinks the assignment
+    Target       *float64 `json:"target" binding:"omitempty,gte=0"`
+    Current      *float64 `json:"current" binding:"omitempty,gte=0"`
+    Unit         *string  `json:"unit"`
+    Weight       *float64 `json:"weight" binding:"omitempty,gte=0"`
+}
+
+// CreateGoalRequest sets the period from week (YYYY-Www or a date in the
+// week, default this week), term_id (default the current term) or
+// start_date and end_date (YYYY-MM-DD), depending on period.
+type CreateGoalRequest struct {
+    Title       string             `json:"title" binding:"required"`
+    Description string             `json:"description"`
+    Period      string             `json:"period"` // Defaults to week
+    Week        string             `json:"week"`
+    TermID      *uint              `json:"term_id"`
+    StartDate   string             `json:"start_date"`
+    EndDate     string             `json:"end_date"`
+    KeyResults  []KeyResultRequest `json:"key_results" binding:"dive"`
+}
+
+type UpdateGoalRequest struct {
+    Title       *string `json:"title"`
+    Description *string `json:"description"`
+    StartDate   *string `json:"start_date"`
+    EndDate     *string `json:"end_date"`
+    Status      *string `json:"status"`
+    ReviewNotes *string `json:"review_notes"`
+}
+
+type CheckInRequest struct {
+    Confidence int    `json:"confidence" binding:"required,min=1,max=10"`
+    Note       string `json:"note"`
+}
+
+type KeyResultProgress struct {
+    models.KeyResult
+    Progress float64 `json:"progress"` // 0 to 1
+    Done     bool    `json:"done"`
+}
+
+// GoalProgress is a goal with the progress of each key result and their
+// weighted average.
+type GoalProgress struct {
+    models.Goal
+    KeyResults []KeyResultProgress `json:"key_results"`
+    Progress   float64             `json:"progress"`
+    Confidence *int                `json:"confidence"` // From the latest check-in
+    DaysLeft   int                 `json:"days_left"`
+    Ended      bool                `json:"ended"`
+}
+
+type ReviewedGoal struct {
+    GoalProgress
+    Outcome          string `json:"outcome"`                     // hit, miss, open, dropped
+    ConfidenceChange *int   `json:"confidence_change,omitempty"` // Latest check-in less the first
+}
+
+// GoalReview sums up the goals due in a week or term.
+type GoalReview struct {
+    Period  string         `json:"period"`
+    Term    string         `json:"term,omitempty"`
+    Start   string         `json:"start"`
+    End     string         `json:"end"`
+    Ended   bool           `json:"ended"`
+    Hits    int            `json:"hits"`
+    Misses  int            `json:"misses"`
+    Open    int            `json:"open"`
+    Dropped int            `json:"dropped"`
+    HitRate *float64       `json:"hit_rate"` // Of hits and misses; nil when there are none
+    Goals   []ReviewedGoal `json:"goals"`
+}
+
+func (s *GoalService) GetGoals(userID uint, status, period string, now time.Time) ([]GoalProgress, error) {
+    query := s.db.Where("user_id = ?", userID)
+    if status != "" {
+        query = query.Where("status = ?", status)
+    }
+    if period != "" {
+        query = query.Where("period = ?", period)
+    }
+    var goals []models.Goal
+    err := preloadGoal(query).
+        Order("end_date ASC, id ASC").
+        Find(&goals).Error
+    if err != nil {
+        return nil, err
+    }
+
+    today := goalToday(plannerLocation(s.db, userID), now)
+    result := make([]GoalProgress, 0, len(goals))
+    for i := range goals {
+        result = append(result, goalProgress(&goals[i], today))
+    }
+    return result, nil
+}
+
+func (s *GoalService) GetGoal(userID, goalID uint, now time.Time) (*GoalProgress, error) {
+    var goal models.Goal
+    if err := preloadGoal(s.db.Where("id = ? AND user_id = ?", goalID, userID)).First(&goal).Error; err != nil {
+        return nil, err
+    }
+    progress := goalProgress(&goal, goalToday(plannerLocation(s.db, userID), now))
+    return &progress, nil
+}
+
+func (s *GoalService) CreateGoal(userID uint, req CreateGoalRequest, now time.Time) (*GoalProgress, error) {
+    goal := models.Goal{
+        UserID:      userID,
+        Title:       strings.TrimSpace(req.Title),
+        Description: req.Description,
+        Period:      req.Period,
+        Status:      GoalActive,
+    }
+    if goal.Period == "" {
+        goal.Period = GoalPeriodWeek
+    }
+    loc := plannerLocation(s.db, userID)
+    switch goal.Period {
+    case GoalPeriodWeek:
+        start, err := ParseWeek(req.Week, loc, now)
+        if err != nil {
+            return nil, fmt.Errorf("%w: %v", ErrInvalidGoal, err)
+        }
+        goal.StartDate = localDate(start, time.UTC)
+        goal.EndDate = goal.StartDate.AddDate(0, 0, 6)
+    case GoalPeriodTerm:
+        term, err := s.goalTerm(userID, req.TermID, now)
+        if err != nil {
+            return nil, err
+        }
+        goal.TermID = &term.ID
+        goal.StartDate, goal.EndDate = term.StartDate, term.EndDate
+    case GoalPeriodCustom:
+        var err error
+        if goal.StartDate, err = parseGoalDate("start_date", req.StartDate); err != nil {
+            return nil, err
+        }
+        if goal.EndDate, err = parseGoalDate("end_date", req.EndDate); err != nil {
+            return nil, err
+        }
+    }
+    if err := normalizeGoal(&goal); err != nil {
+        return nil, err
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        keyResults := make([]models.KeyResult, 0, len(req.KeyResults))
+        for _, kr := range req.KeyResults {
+            keyResult, err := newKeyResult(tx, userID, kr)
+            if err != nil {
+                return err
+            }
+            keyResults = append(keyResults, keyResult)
+        }
+        if err := tx.Create(&goal).Error; err != nil {
+            return err
+        }
+        for i := range keyResults {
+            keyResults[i].GoalID = goal.ID
+            if err := tx.Create(&keyResults[i]).Error; err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return s.GetGoal(userID, goal.ID, now)
+}
+
+func (s *GoalService) UpdateGoal(userID, goalID uint, req UpdateGoalRequest, now time.Time) (*GoalProgress, error) {
+    var goal models.Goal
+    if err := s.db.Where("id = ? AND user_id = ?", goalID, userID).First(&goal).Error; err != nil {
+        return nil, err
+    }
+
+    if req.Title != nil {
+        goal.Title = strings.TrimSpace(*req.Title)
+    }
+    if req.Description != nil {
+        goal.Description = *req.Description
+    }
+    // Changing the dates makes a week or term goal a custom one
+    var err error
+    if req.StartDate != nil {
+        if goal.StartDate, err = parseGoalDate("start_date", *req.StartDate); err != nil {
+            return nil, err
+        }
+        goal.Period, goal.TermID = GoalPeriodCustom, nil
+    }
+    if req.EndDate != nil {
+        if goal.EndDate, err = parseGoalDate("end_date", *req.EndDate); err != nil {
+            return nil, err
+        }
+        goal.Period, goal.TermID = GoalPeriodCustom, nil
+    }
+    if req.Status != nil {
+        goal.Status = *req.Status
+    }
+    if req.ReviewNotes != nil {
+        goal.ReviewNotes = *req.ReviewNotes
+    }
+    if err := normalizeGoal(&goal); err != nil {
+        return nil, err
+    }
+
+    if err := s.db.Save(&goal).Error; err != nil {
+        return nil, err
+    }
+    return s.GetGoal(userID, goal.ID, now)
+}
+
+// DeleteGoal removes the goal with its key results and check-ins. Linked
+// assignments are kept.
+func (s *GoalService) DeleteGoal(userID, goalID uint) error {
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("id = ? AND user_id = ?", goalID, userID).Delete(&models.Goal{})
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return gorm.ErrRecordNotFound
+        }
+        if err := tx.Where("goal_id = ? AND user_id = ?", goalID, userID).Delete(&models.KeyResult{}).Error; err != nil {
+            return err
+        }
+        return tx.Where("goal_id = ? AND user_id = ?", goalID, userID).Delete(&models.GoalCheckIn{}).Error
+    })
+}
+
+func (s *GoalService) CreateKeyResult(userID, goalID uint, req KeyResultRequest) (*models.KeyResult, error) {
+    var goal models.Goal
+    if err := s.db.Where("id = ? AND user_id = ?", goalID, userID).First(&goal).Error; err != nil {
+        return nil, err
+    }
+    keyResult, err := newKeyResult(s.db, userID, req)
+    if err != nil {
+        return nil, err
+    }
+    keyResult.GoalID = goal.ID
+    if err := s.db.Create(&keyResult).Error; err != nil {
+        return nil, err
+    }
+    s.db.Preload("Assignment").First(&keyResult, keyResult.ID)
+    return &keyResult, nil
+}
+
+func (s *GoalService) UpdateKeyResult(userID, goalID, keyResultID uint, req UpdateKeyResultRequest) (*models.KeyResult, error) {
+    var keyResult models.KeyResult
+    if err := s.db.Where("id = ? AND goal_id = ? AND user_id = ?", keyResultID, goalID, userID).First(&keyResult).Error; err != nil {
+        return nil, err
+    }
+
+    if req.Title != nil {
+        keyResult.Title = strings.TrimSpace(*req.Title)
+    }
+    if req.AssignmentID != nil {
+        keyResult.AssignmentID = req.AssignmentID
+        if *req.AssignmentID == 0 {
+            keyResult.AssignmentID = nil
+        } else if err := checkGoalAssignment(s.db, userID, *req.AssignmentID); err != nil {
+            return nil, err
+        }
+    }
+    if req.Target != nil {
+        keyResult.Target = *req.Target
+    }
+    if req.Current != nil {
+        keyResult.Current = *req.Current
+    }
+    if req.Unit != nil {
+        keyResult.Unit = *req.Unit
+    }
+    if req.Weight != nil {
+        keyResult.Weight = *req.Weight
+    }
+    if keyResult.Title == "" {
+        return nil, fmt.Errorf("%w: key result title is required", ErrInvalidGoal)
+    }
+
+    keyResult.Assignment = nil
+    if err := s.db.Save(&keyResult).Error; err != nil {
+        return nil, err
+    }
+    s.db.Preload("Assignment").First(&keyResult, keyResult.ID)
+    return &keyResult, nil
+}
+
+func (s *GoalService) DeleteKeyResult(userID, goalID, keyResultID uint) error {
+    result := s.db.Where("id = ? AND goal_id = ? AND user_id = ?", keyResultID, goalID, userID).Delete(&models.KeyResult{})
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return gorm.ErrRecordNotFound
+    }
+    return nil
+}
+
+// CheckIn records the user's confidence in the goal along with its
+// progress so far.
+func (s *GoalService) CheckIn(userID, goalID uint, req CheckInRequest, now time.Time) (*models.GoalCheckIn, error) {
+    goal, err := s.GetGoal(userID, goalID, now)
+    if err != nil {
+        return nil, err
+    }
+    checkIn := models.GoalCheckIn{
+        UserID:     userID,
+        GoalID:     goal.ID,
+        Confidence: req.Confidence,
+        Progress:   goal.Progress,
+        Note:       req.Note,
+    }
+    if err := s.db.Create(&checkIn).Error; err != nil {
+        return nil, err
+    }
+    return &checkIn, nil
+}
+
+func (s *GoalService) DeleteCheckIn(userID, goalID, checkInID uint) error {
+    result := s.db.Where("id = ? AND goal_id = ? AND user_id = ?", checkInID, goalID, userID).Delete(&models.GoalCheckIn{})
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return gorm.ErrRecordNotFound
+    }
+    return nil
+}
+
+// Review sums up the goals that end within a week (period "week", start
+// from ParseWeek) or a term (period "term", default the current one).
+// Goals the user has marked achieved or missed keep that outcome; others
+// are hits once fully done and misses once their period is over.
+func (s *GoalService) Review(userID uint, period, week string, termID *uint, now time.Time) (*GoalReview, error) {
+    loc := plannerLocation(s.db, userID)
+    today := goalToday(loc, now)
+
+    review := &GoalReview{Period: period, Goals: []ReviewedGoal{}}
+    var start, end time.Time
+    switch period {
+    case GoalPeriodWeek:
+        weekStart, err := ParseWeek(week, loc, now)
+        if err != nil {
+            return nil, fmt.Errorf("%w: %v", ErrInvalidGoal, err)
+        }
+        start = localDate(weekStart, time.UTC)
+        end = start.AddDate(0, 0, 6)
+    case GoalPeriodTerm:
+        term, err := s.goalTerm(userID, termID, now)
+        if err != nil {
+            return nil, err
+        }
+        review.Term = term.Name
+        start, end = term.StartDate, term.EndDate
+    default:
+        return nil, fmt.Errorf("%w: period must be week or term", ErrInvalidGoal)
+    }
+    review.Start, review.End = start.Format(dateLayout), end.Format(dateLayout)
+    review.Ended = today.After(end)
+
+    var goals []models.Goal
+    err := preloadGoal(s.db.Where("user_id = ? AND end_date >= ? AND end_date <= ?", userID, start, end)).
+        Order("end_date ASC, id ASC").
+        Find(&goals).Error
+    if err != nil {
+        return nil, err
+    }
+
+    for i := range goals {
+        reviewed := ReviewedGoal{GoalProgress: goalProgress(&goals[i], today)}
+        switch {
+        case goals[i].Status == GoalDropped:
+            reviewed.Outcome = GoalDropped
+            review.Dropped++
+        case goals[i].Status == GoalAchieved,
+            goals[i].Status == GoalActive && reviewed.Progress >= 1:
+            reviewed.Outcome = GoalHit
+            review.Hits++
+        case goals[i].Status == GoalMissed, reviewed.Ended:
+            reviewed.Outcome = GoalMiss
+            review.Misses++
+        default:
+            reviewed.Outcome = GoalOpen
+            review.Open++
+        }
+        if checkIns := goals[i].CheckIns; len(checkIns) > 1 {
+            change := checkIns[len(checkIns)-1].Confidence - checkIns[0].Confidence
+            reviewed.ConfidenceChange = &change
+        }
+        review.Goals = append(review.Goals, reviewed)
+    }
+    if decided := review.Hits + review.Misses; decided > 0 {
+        rate := math.Round(float64(review.Hits)/float64(decided)*100) / 100
+        review.HitRate = &rate
+    }
+    return review, nil
+}
+
+// goalTerm is the given term, or the current (else next) one.
+func (s *GoalService) goalTerm(userID uint, termID *uint, now time.Time) (*models.Term, error) {
+    if termID != nil {
+        term, err := NewTermService().GetTerm(userID, *termID)
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, ErrTermNotFound
+        }
+        return term, err
+    }
+    current, err := NewTermService().GetCurrentTerm(userID, now)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil, fmt.Errorf("%w: no current term; pass term_id", ErrInvalidGoal)
+    }
+    if err != nil {
+        return nil, err
+    }
+    return current.Term, nil
+}
+
+func preloadGoal(query *gorm.DB) *gorm.DB {
+    return query.
+        Preload("KeyResults", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+        Preload("KeyResults.Assignment").
+        Preload("CheckIns", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") })
+}
+
+// goalProgress weighs each key result's progress. A linked assignment
+// counts by status; a deleted one counts as not done.
+func goalProgress(goal *models.Goal, today time.Time) GoalProgress {
+    progress := GoalProgress{
+        Goal:       *goal,
+        KeyResults: make([]KeyResultProgress, 0, len(goal.KeyResults)),
+        DaysLeft:   daysBetween(today, goal.EndDate),
+        Ended:      today.After(goal.EndDate),
+    }
+    if progress.DaysLeft < 0 {
+        progress.DaysLeft = 0
+    }
+    if n := len(goal.CheckIns); n > 0 {
+        confidence := goal.CheckIns[n-1].Confidence
+        progress.Confidence = &confidence
+    }
+
+    var total, weights float64
+    for _, kr := range goal.KeyResults {
+        var p float64
+        switch {
+        case kr.AssignmentID != nil:
+            if kr.Assignment != nil {
+                p = assignmentProgress[kr.Assignment.Status]
+            }
+        case kr.Target > 0:
+            p = math.Min(kr.Current/kr.Target, 1)
+        }
+        p = math.Round(p*100) / 100
+        progress.KeyResults = append(progress.KeyResults, KeyResultProgress{KeyResult: kr, Progress: p, Done: p >= 1})
+        total += p * kr.Weight
+        weights += kr.Weight
+    }
+    if weights > 0 {
+        progress.Progress = math.Round(total/weights*100) / 100
+    }
+    return progress
+}
+
+func newKeyResult(tx *gorm.DB, userID uint, req KeyResultRequest) (models.KeyResult, error) {
+    keyResult := models.KeyResult{
+        UserID:       userID,
+        Title:        strings.TrimSpace(req.Title),
+        AssignmentID: req.AssignmentID,
+        Target:       req.Target,
+        Current:      req.Current,
+        Unit:         req.Unit,
+        Weight:       req.Weight,
+    }
+    if keyResult.Weight == 0 {
+        keyResult.Weight = 1
+    }
+    if keyResult.AssignmentID != nil {
+        var assignment models.Assignment
+        if err := tx.Where("id = ? AND user_id = ?", *keyResult.AssignmentID, userID).First(&assignment).Error; err != nil {
+            if errors.Is(err, gorm.ErrRecordNotFound) {
+                return keyResult, ErrAssignmentNotFound
+            }
+            return keyResult, err
+        }
+        if keyResult.Title == "" {
+            keyResult.Title = assignment.Title
+        }
+    } else if keyResult.Target == 0 {
+        keyResult.Target = 1
+    }
+    if keyResult.Title == "" {
+        return keyResult, fmt.Errorf("%w: key result title is required", ErrInvalidGoal)
+    }
+    return keyResult, nil
+}
+
+func checkGoalAssignment(tx *gorm.DB, userID, assignmentID uint) error {
+    var count int64
+    if err := tx.Model(&models.Assignment{}).Where("id = ? AND user_id = ?", assignmentID, userID).Count(&count).Error; err != nil {
+        return err
+    }
+    if count == 0 {
+        return ErrAssignmentNotFound
+    }
+    return nil
+}
+
+func normalizeGoal(goal *models.Goal) error {
+    if goal.Title == "" {
+        return fmt.Errorf("%w: title is required", ErrInvalidGoal)
+    }
+    if !containsString(goalPeriods, goal.Period) {
+        return fmt.Errorf("%w: period must be one of %s", ErrInvalidGoal, strings.Join(goalPeriods, ", "))
+    }
+    if !containsString(goalStatuses, goal.Status) {
+        return fmt.Errorf("%w: status must be one of %s", ErrInvalidGoal, strings.Join(goalStatuses, ", "))
+    }
+    if goal.EndDate.Before(goal.StartDate) {
+        return fmt.Errorf("%w: end_date must not be before start_date", ErrInvalidGoal)
+    }
+    return nil
+}
+
+func parseGoalDate(field, value string) (time.Time, error) {
+    date, err := time.Parse(dateLayout, strings.TrimSpace(value))
+    if err != nil {
+        return time.Time{}, fmt.Errorf("%w: %s must be YYYY-MM-DD", ErrInvalidGoal, field)
+    }
+    return date, nil
+}
+
+// goalToday is today's date in loc, as the UTC midnight goal dates are
+// stored at.
+func goalToday(loc *time.Location, now time.Time) time.Time {
+    return localDate(now.In(loc), time.UTC)
+}
@@ -0,0 +1,430 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+var (
+    ErrInvalidGrade   = errors.New("invalid grade")
+    ErrInvalidWhatIf  = errors.New("invalid what-if request")
+    ErrWeightExceeded = errors.New("category weights would add up to more than 100%")
+)
+
+type GradebookService struct {
+    db               *gorm.DB
+    academicsService *AcademicsService
+}
+
+func NewGradebookService() *GradebookService {
+    return &GradebookService{
+        db:               database.GetDB(),
+        academicsService: NewAcademicsService(),
+    }
+}
+
+type CategoryRequest struct {
+    Name   string  `json:"name" binding:"required"`
+    Weight float64 `json:"weight" binding:"gte=0,lte=100"`
+}
+
+// GradeAssignmentRequest attaches an assignment to a grade category. A
+// nil category takes it out of the gradebook; a nil score means not graded
+// yet.
+type GradeAssignmentRequest struct {
+    CategoryID *uint    `json:"category_id"`
+    Score      *float64 `json:"score"`
+    MaxScore   *float64 `json:"max_score"`
+}
+
+// WhatIfRequest asks what average is needed on outstanding work to reach a
+// target. By default every ungraded assignment and empty category is
+// solved for; naming an assignment or category solves for that alone,
+// with the rest assumed at AssumePercent.
+type WhatIfRequest struct {
+    TargetLetter  string   `json:"target_letter"`
+    TargetPercent *float64 `json:"target_percent"`
+    AssignmentID  *uint    `json:"assignment_id"`
+    CategoryID    *uint    `json:"category_id"`
+    AssumePercent *float64 `json:"assume_percent"` // Defaults to the current course percentage
+}
+
+type CategoryGrade struct {
+    CategoryID      uint     `json:"category_id"`
+    Name            string   `json:"name"`
+    Weight          float64  `json:"weight"`
+    Earned          float64  `json:"earned"`
+    Possible        float64  `json:"possible"`
+    Percent         *float64 `json:"percent"` // nil until something in it is graded
+    Graded          int      `json:"graded"`
+    Remaining       int      `json:"remaining"`
+    RemainingPoints float64  `json:"remaining_points"`
+}
+
+type CourseGrade struct {
+    CourseID       uint            `json:"course_id"`
+    Scale          string          `json:"scale"`
+    Categories     []CategoryGrade `json:"categories"`
+    TotalWeight    float64         `json:"total_weight"`
+    GradedWeight   float64         `json:"graded_weight"`   // Weight of categories with graded work
+    CurrentPercent *float64        `json:"current_percent"` // Over graded work only
+    Letter         string          `json:"letter,omitempty"`
+    RecordedGrade  string          `json:"recorded_grade,omitempty"` // Course.Grade as entered
+    Warnings       []string        `json:"warnings"`
+}
+
+type WhatIfResult struct {
+    TargetPercent   float64  `json:"target_percent"`
+    TargetLetter    string   `json:"target_letter,omitempty"`
+    RequiredPercent *float64 `json:"required_percent"` // Average needed on the work solved for; nil if none is left
+    Achievable      bool     `json:"achievable"`       // Required percent is at most 100
+    AlreadySecured  bool     `json:"already_secured"`  // Reached even with zero on the rest
+    MinPossible     float64  `json:"min_possible"`     // Final percent with zero on the work solved for
+    MaxPossible     float64  `json:"max_possible"`     // Final percent with full marks on it
+    AssumedPercent  *float64 `json:"assumed_percent,omitempty"`
+    SolvingFor      []uint   `json:"solving_for"` // Assignment IDs; empty categories are included implicitly
+}
+
+// gradebook is a course's categories with their graded and outstanding
+// assignments.
+type gradebook struct {
+    course     *models.Course
+    categories []models.GradeCategory
+    graded     map[uint][]models.Assignment
+    remaining  map[uint][]models.Assignment
+}
+
+func (s *GradebookService) GetCategories(userID, courseID uint) ([]models.GradeCategory, error) {
+    if _, err := s.getCourse(userID, courseID); err != nil {
+        return nil, err
+    }
+    var categories []models.GradeCategory
+    err := s.db.Where("course_id = ? AND user_id = ?", courseID, userID).Order("id ASC").Find(&categories).Error
+    return categories, err
+}
+
+func (s *GradebookService) CreateCategory(userID, courseID uint, req CategoryRequest) (*models.GradeCategory, error) {
+    if _, err := s.getCourse(userID, courseID); err != nil {
+        return nil, err
+    }
+    category := models.GradeCategory{UserID: userID, CourseID: courseID, Name: strings.TrimSpace(req.Name), Weight: req.Weight}
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := checkCategoryWeights(tx, courseID, 0, category.Weight); err != nil {
+            return err
+        }
+        return tx.Create(&category).Error
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &category, nil
+}
+
+func (s *GradebookService) UpdateCategory(userID, courseID, categoryID uint, req CategoryRequest) (*models.GradeCategory, error) {
+    var category models.GradeCategory
+    if err := s.db.Where("id = ? AND course_id = ? AND user_id = ?", categoryID, courseID, userID).First(&category).Error; err != nil {
+        return nil, err
+    }
+    category.Name = strings.TrimSpace(req.Name)
+    category.Weight = req.Weight
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := checkCategoryWeights(tx, courseID, category.ID, category.Weight); err != nil {
+            return err
+        }
+        return tx.Save(&category).Error
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &category, nil
+}
+
+// DeleteCategory removes a category. Its assignments keep their scores but
+// no longer count towards the course grade.
+func (s *GradebookService) DeleteCategory(userID, courseID, categoryID uint) error {
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("id = ? AND course_id = ? AND user_id = ?", categoryID, courseID, userID).Delete(&models.GradeCategory{})
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return gorm.ErrRecordNotFound
+        }
+        return tx.Model(&models.Assignment{}).Where("category_id = ? AND user_id = ?", categoryID, userID).
+            Update("category_id", nil).Error
+    })
+}
+
+// GradeAssignment sets an assignment's category and score. The category
+// must belong to the assignment's course.
+func (s *GradebookService) GradeAssignment(userID, assignmentID uint, req GradeAssignmentRequest) (*models.Assignment, error) {
+    var assignment models.Assignment
+    if err := s.db.Where("id = ? AND user_id = ?", assignmentID, userID).First(&assignment).Error; err != nil {
+        return nil, err
+    }
+
+    if req.CategoryID != nil {
+        if assignment.CourseID == nil {
+            return nil, fmt.Errorf("%w: assignment is not part of a course", ErrInvalidGrade)
+        }
+        var category models.GradeCategory
+        err := s.db.Where("id = ? AND course_id = ? AND user_id = ?", *req.CategoryID, *assignment.CourseID, userID).First(&category).Error
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, fmt.Errorf("%w: category does not belong to the assignment's course", ErrInvalidGrade)
+        }
+        if err != nil {
+            return nil, err
+        }
+        if req.MaxScore == nil || *req.MaxScore <= 0 {
+            return nil, fmt.Errorf("%w: max_score must be greater than zero", ErrInvalidGrade)
+        }
+    }
+    // Scores above max_score are allowed for extra credit
+    if req.Score != nil && *req.Score < 0 {
+        return nil, fmt.Errorf("%w: score must not be negative", ErrInvalidGrade)
+    }
+
+    assignment.CategoryID = req.CategoryID
+    assignment.Score = req.Score
+    assignment.MaxScore = req.MaxScore
+    if err := s.db.Model(&assignment).Select("category_id", "score", "max_score", "updated_at").Updates(&assignment).Error; err != nil {
+        return nil, err
+    }
+
+    s.db.Preload("Course").First(&assignment, assignment.ID)
+    return &assignment, nil
+}
+
+// GetCourseGrade computes the weighted course percentage over the work
+// graded so far. Categories without graded work are left out and the
+// remaining weights rescaled, so the figure is a running average.
+func (s *GradebookService) GetCourseGrade(userID, courseID uint) (*CourseGrade, error) {
+    book, err := s.loadGradebook(userID, courseID)
+    if err != nil {
+        return nil, err
+    }
+    scale, err := s.academicsService.courseScale(book.course)
+    if err != nil {
+        return nil, err
+    }
+
+    grade := &CourseGrade{
+        CourseID:      courseID,
+        Scale:         scale.Name,
+        Categories:    []CategoryGrade{},
+        RecordedGrade: book.course.Grade,
+        Warnings:      []string{},
+    }
+    weighted := 0.0
+    for _, category := range book.categories {
+        cg := CategoryGrade{CategoryID: category.ID, Name: category.Name, Weight: category.Weight}
+        for _, a := range book.graded[category.ID] {
+            cg.Earned += *a.Score
+            cg.Possible += *a.MaxScore
+            cg.Graded++
+        }
+        for _, a := range book.remaining[category.ID] {
+            cg.RemainingPoints += *a.MaxScore
+            cg.Remaining++
+        }
+        if cg.Possible > 0 {
+            percent := cg.Earned / cg.Possible * 100
+            weighted += category.Weight * percent
+            grade.GradedWeight += category.Weight
+            cg.Percent = ptrFloat(round2(percent))
+        }
+        grade.TotalWeight += category.Weight
+        grade.Categories = append(grade.Categories, cg)
+    }
+
+    if grade.GradedWeight > 0 {
+        current := weighted / grade.GradedWeight
+        grade.CurrentPercent = ptrFloat(round2(current))
+        if letter, ok := scale.LetterFor(current); ok {
+            grade.Letter = letter
+        }
+    }
+    if len(book.categories) > 0 && grade.TotalWeight != 100 {
+        grade.Warnings = append(grade.Warnings, fmt.Sprintf("category weights add up to %g%%, not 100%%", grade.TotalWeight))
+    }
+    grade.TotalWeight = round2(grade.TotalWeight)
+    grade.GradedWeight = round2(grade.GradedWeight)
+    return grade, nil
+}
+
+// WhatIf solves for the average needed on outstanding work to finish the
+// course at a target percentage. The final percentage is linear in that
+// average, so it is solved directly rather than searched for.
+func (s *GradebookService) WhatIf(userID, courseID uint, req WhatIfRequest) (*WhatIfResult, error) {
+    book, err := s.loadGradebook(userID, courseID)
+    if err != nil {
+        return nil, err
+    }
+    scale, err := s.academicsService.courseScale(book.course)
+    if err != nil {
+        return nil, err
+    }
+
+    result := &WhatIfResult{TargetLetter: req.TargetLetter, SolvingFor: []uint{}}
+    switch {
+    case req.TargetPercent != nil:
+        result.TargetPercent = *req.TargetPercent
+    case req.TargetLetter != "":
+        mapping, ok := scale.Lookup(req.TargetLetter)
+        if !ok || mapping.MinPercent == nil {
+            return nil, fmt.Errorf("%w: %q has no percentage cutoff on the %s scale", ErrInvalidWhatIf, req.TargetLetter, scale.Name)
+        }
+        result.TargetPercent = *mapping.MinPercent
+    default:
+        return nil, fmt.Errorf("%w: target_letter or target_percent is required", ErrInvalidWhatIf)
+    }
+
+    totalWeight := 0.0
+    for _, category := range book.categories {
+        totalWeight += category.Weight
+    }
+    if totalWeight == 0 {
+        return nil, fmt.Errorf("%w: the course has no weighted grade categories", ErrInvalidWhatIf)
+    }
+
+    // Work outside the target is assumed at AssumePercent, defaulting to
+    // the current running percentage
+    selective := req.AssignmentID != nil || req.CategoryID != nil
+    assumed := 0.0
+    if selective {
+        assumed = 100
+        if req.AssumePercent != nil {
+            assumed = *req.AssumePercent
+        } else if current, err := s.GetCourseGrade(userID, courseID); err == nil && current.CurrentPercent != nil {
+            assumed = *current.CurrentPercent
+        }
+        result.AssumedPercent = ptrFloat(assumed)
+    }
+    unknown := func(categoryID uint, assignmentID uint) bool {
+        if !selective {
+            return true
+        }
+        if req.AssignmentID != nil {
+            return assignmentID != 0 && *req.AssignmentID == assignmentID
+        }
+        return *req.CategoryID == categoryID
+    }
+
+    // final = (fixed + coefficient*x) / totalWeight, x being the fraction
+    // scored on the unknown work
+    fixed, coefficient := 0.0, 0.0
+    found := req.AssignmentID == nil
+    for _, category := range book.categories {
+        earned, possible := 0.0, 0.0
+        for _, a := range book.graded[category.ID] {
+            earned += *a.Score
+            possible += *a.MaxScore
+        }
+        total := possible
+        for _, a := range book.remaining[category.ID] {
+            total += *a.MaxScore
+        }
+
+        if total == 0 {
+            // Nothing assigned yet; the whole category is outstanding
+            if unknown(category.ID, 0) {
+                coefficient += category.Weight
+            } else {
+                fixed += category.Weight * assumed / 100
+            }
+            continue
+        }
+
+        fixed += category.Weight * earned / total
+        for _, a := range book.remaining[category.ID] {
+            share := category.Weight * *a.MaxScore / total
+            if unknown(category.ID, a.ID) {
+                coefficient += share
+                result.SolvingFor = append(result.SolvingFor, a.ID)
+                found = true
+            } else {
+                fixed += share * assumed / 100
+            }
+        }
+    }
+    if req.CategoryID != nil && req.AssignmentID == nil {
+        found = false
+        for _, category := range book.categories {
+            found = found || category.ID == *req.CategoryID
+        }
+    }
+    if !found {
+        return nil, fmt.Errorf("%w: nothing outstanding in this course's gradebook matches the assignment or category", ErrInvalidWhatIf)
+    }
+
+    result.MinPossible = round2(fixed / totalWeight * 100)
+    result.MaxPossible = round2((fixed + coefficient) / totalWeight * 100)
+    if coefficient == 0 {
+        result.Achievable = result.MinPossible >= result.TargetPercent
+        result.AlreadySecured = result.Achievable
+        return result, nil
+    }
+
+    required := (result.TargetPercent/100*totalWeight - fixed) / coefficient * 100
+    result.RequiredPercent = ptrFloat(round2(required))
+    result.Achievable = required <= 100
+    result.AlreadySecured = required <= 0
+    return result, nil
+}
+
+func (s *GradebookService) getCourse(userID, courseID uint) (*models.Course, error) {
+    var course models.Course
+    if err := s.db.Where("id = ? AND user_id = ?", courseID, userID).First(&course).Error; err != nil {
+        return nil, err
+    }
+    return &course, nil
+}
+
+func (s *GradebookService) loadGradebook(userID, courseID uint) (*gradebook, error) {
+    course, err := s.getCourse(userID, courseID)
+    if err != nil {
+        return nil, err
+    }
+    book := &gradebook{course: course, graded: map[uint][]models.Assignment{}, remaining: map[uint][]models.Assignment{}}
+    if err := s.db.Where("course_id = ? AND user_id = ?", courseID, userID).Order("id ASC").Find(&book.categories).Error; err != nil {
+        return nil, err
+    }
+
+    var assignments []models.Assignment
+    if err := s.db.Where("course_id = ? AND user_id = ? AND category_id IS NOT NULL AND max_score > 0", courseID, userID).
+        Order("due_date ASC, id ASC").Find(&assignments).Error; err != nil {
+        return nil, err
+    }
+    for _, a := range assignments {
+        if a.Score != nil {
+            book.graded[*a.CategoryID] = append(book.graded[*a.CategoryID], a)
+        } else {
+            book.remaining[*a.CategoryID] = append(book.remaining[*a.CategoryID], a)
+        }
+    }
+    return book, nil
+}
+
+// checkCategoryWeights keeps a course's category weights within 100%.
+func checkCategoryWeights(tx *gorm.DB, courseID, excludeID uint, weight float64) error {
+    var total float64
+    if err := tx.Model(&models.GradeCategory{}).Where("course_id = ? AND id <> ?", courseID, excludeID).
+        Select("COALESCE(SUM(weight), 0)").Scan(&total).Error; err != nil {
+        return err
+    }
+    if total+weight > 100.0001 {
+        return ErrWeightExceeded
+    }
+    return nil
+}
+
+func ptrFloat(v float64) *float64 {
+    return &v
+}
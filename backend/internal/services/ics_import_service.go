@@ -0,0 +1,502 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/ical"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "github.com/anayy09/academiaflow-backend/internal/recurrence"
+    "gorm.io/gorm"
+)
+
+var ErrInvalidCalendar = errors.New("invalid iCalendar file")
+
+// Open-ended recurring entries are only expanded this far ahead.
+const icsImportHorizon = 26 * 7 * 24 * time.Hour
+
+const (
+    ImportCreate    = "create"
+    ImportUpdate    = "update"
+    ImportUnchanged = "unchanged"
+    ImportSkip      = "skip"
+)
+
+type ImportService struct {
+    db *gorm.DB
+}
+
+func NewImportService() *ImportService {
+    return &ImportService{
+        db: database.GetDB(),
+    }
+}
+
+type ICSImportOptions struct {
+    CourseID *uint
+    DryRun   bool
+}
+
+type ICSImportItem struct {
+    UID          string     `json:"uid"`
+    Component    string     `json:"component"`
+    Title        string     `json:"title"`
+    DueDate      *time.Time `json:"due_date"`
+    Action       string     `json:"action"`
+    AssignmentID *uint      `json:"assignment_id,omitempty"`
+    Changes      []string   `json:"changes,omitempty"`
+    Reason       string     `json:"reason,omitempty"`
+}
+
+type ICSImportResult struct {
+    DryRun    bool            `json:"dry_run"`
+    Created   int             `json:"created"`
+    Updated   int             `json:"updated"`
+    Unchanged int             `json:"unchanged"`
+    Skipped   int             `json:"skipped"`
+    Items     []ICSImportItem `json:"items"`
+}
+
+// icsEntry is one concrete (already expanded) calendar entry.
+type icsEntry struct {
+    uid         string
+    component   string
+    title       string
+    description string
+    due         time.Time
+    priority    string
+    status      string // empty for VEVENT: events carry no completion state
+}
+
+// ImportICS maps the VEVENTs and VTODOs of an iCalendar file onto
+// assignments. Entries are matched on their UID (plus RECURRENCE-ID for
+// expanded occurrences), so importing the same file again updates the
+// assignments it created instead of duplicating them.
+func (s *ImportService) ImportICS(userID uint, r io.Reader, opts ICSImportOptions) (*ICSImportResult, error) {
+    if opts.CourseID != nil {
+        var count int64
+        if err := s.db.Model(&models.Course{}).
+            Where("id = ? AND user_id = ?", *opts.CourseID, userID).
+            Count(&count).Error; err != nil {
+            return nil, err
+        }
+        if count == 0 {
+            return nil, ErrCourseNotFound
+        }
+    }
+
+    cal, err := ical.Parse(r)
+    if err != nil {
+        return nil, fmt.Errorf("%w: %w", ErrInvalidCalendar, err)
+    }
+    if cal.Name != "VCALENDAR" {
+        return nil, fmt.Errorf("%w: expected VCALENDAR", ErrInvalidCalendar)
+    }
+
+    result := &ICSImportResult{DryRun: opts.DryRun, Items: []ICSImportItem{}}
+    entries, skipped := collectICSEntries(cal, time.Now())
+    result.Items = append(result.Items, skipped...)
+
+    var existing []models.Assignment
+    if err := s.db.Where("user_id = ? AND ical_uid <> ''", userID).Find(&existing).Error; err != nil {
+        return nil, err
+    }
+    byUID := make(map[string]*models.Assignment, len(existing))
+    for i := range existing {
+        byUID[existing[i].ICalUID] = &existing[i]
+    }
+
+    type pending struct {
+        item    int
+        entry   icsEntry
+        target  *models.Assignment
+        updates map[string]interface{}
+    }
+    var work []pending
+
+    for _, entry := range entries {
+        due := entry.due
+        item := ICSImportItem{
+            UID:       entry.uid,
+            Component: entry.component,
+            Title:     entry.title,
+            DueDate:   &due,
+        }
+
+        if current, ok := byUID[entry.uid]; ok {
+            id := current.ID
+            item.AssignmentID = &id
+            updates := icsUpdates(current, entry, opts.CourseID)
+            if status, ok := updates["status"]; ok {
+                if err := checkStatusChange(s.db, current.ID, current.Status, status); err != nil {
+                    return nil, err
+                }
+            }
+            if len(updates) == 0 {
+                item.Action = ImportUnchanged
+            } else {
+                item.Action = ImportUpdate
+                for field := range updates {
+                    item.Changes = append(item.Changes, field)
+                }
+                sort.Strings(item.Changes)
+            }
+            work = append(work, pending{item: len(result.Items), entry: entry, target: current, updates: updates})
+        } else {
+            item.Action = ImportCreate
+            work = append(work, pending{item: len(result.Items), entry: entry})
+        }
+        result.Items = append(result.Items, item)
+    }
+
+    if !opts.DryRun {
+        err := s.db.Transaction(func(tx *gorm.DB) error {
+            for _, w := range work {
+                switch {
+                case w.target == nil:
+                    assignment := models.Assignment{
+                        UserID:      userID,
+                        CourseID:    opts.CourseID,
+                        Title:       w.entry.title,
+                        Description: w.entry.description,
+                        DueDate:     w.entry.due,
+                        Priority:    w.entry.priority,
+                        Status:      w.entry.status,
+                        ICalUID:     w.entry.uid,
+                    }
+                    if assignment.Status == "" {
+                        assignment.Status = "pending"
+                    }
+                    if assignment.Status == "completed" {
+                        now := time.Now()
+                        assignment.CompletedAt = &now
+                    }
+                    if err := tx.Create(&assignment).Error; err != nil {
+                        return err
+                    }
+                    id := assignment.ID
+                    result.Items[w.item].AssignmentID = &id
+                case len(w.updates) > 0:
+                    setCompletedAt(w.updates, w.target.Status, time.Now())
+                    if err := tx.Model(w.target).Updates(w.updates).Error; err != nil {
+                        return err
+                    }
+                }
+            }
+            return nil
+        })
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    for _, item := range result.Items {
+        switch item.Action {
+        case ImportCreate:
+            result.Created++
+        case ImportUpdate:
+            result.Updated++
+        case ImportUnchanged:
+            result.Unchanged++
+        default:
+            result.Skipped++
+        }
+    }
+
+    return result, nil
+}
+
+// icsUpdates lists what re-importing entry would change on an assignment.
+func icsUpdates(current *models.Assignment, entry icsEntry, courseID *uint) map[string]interface{} {
+    updates := map[string]interface{}{}
+    if current.Title != entry.title {
+        updates["title"] = entry.title
+    }
+    if current.Description != entry.description {
+        updates["description"] = entry.description
+    }
+    if !current.DueDate.Equal(entry.due) {
+        updates["due_date"] = entry.due
+    }
+    if current.Priority != entry.priority {
+        updates["priority"] = entry.priority
+    }
+    if entry.status != "" && current.Status != entry.status {
+        updates["status"] = entry.status
+    }
+    if courseID != nil && (current.CourseID == nil || *current.CourseID != *courseID) {
+        updates["course_id"] = *courseID
+    }
+    return updates
+}
+
+// collectICSEntries expands the calendar into concrete entries. Entries
+// that cannot be imported come back as skip items explaining why.
+func collectICSEntries(cal *ical.Component, now time.Time) ([]icsEntry, []ICSImportItem) {
+    loc := time.UTC
+    if tz := cal.Value("X-WR-TIMEZONE"); tz != "" {
+        loc = ical.LoadLocation(tz, time.UTC)
+    }
+
+    // Group by UID so RECURRENCE-ID overrides can replace occurrences of
+    // their master entry.
+    type group struct {
+        master    *ical.Component
+        overrides []*ical.Component
+    }
+    groups := map[string]*group{}
+    var uids []string
+    var skipped []ICSImportItem
+
+    for _, c := range cal.Components {
+        if c.Name != "VEVENT" && c.Name != "VTODO" {
+            continue
+        }
+        uid := c.Value("UID")
+        if uid == "" {
+            skipped = append(skipped, ICSImportItem{Component: c.Name, Title: summaryOf(c), Action: ImportSkip, Reason: "missing UID"})
+            continue
+        }
+        g, ok := groups[uid]
+        if !ok {
+            g = &group{}
+            groups[uid] = g
+            uids = append(uids, uid)
+        }
+        if c.Get("RECURRENCE-ID") != nil {
+            g.overrides = append(g.overrides, c)
+        } else {
+            if g.master != nil {
+                skipped = append(skipped, ICSImportItem{UID: uid, Component: c.Name, Title: summaryOf(g.master), Action: ImportSkip, Reason: "duplicate UID in file; the later entry was used"})
+            }
+            g.master = c
+        }
+    }
+
+    var entries []icsEntry
+    for _, uid := range uids {
+        g := groups[uid]
+
+        overrides := map[string]*ical.Component{}
+        for _, o := range g.overrides {
+            prop := o.Get("RECURRENCE-ID")
+            t, _, err := prop.Time(loc)
+            if err != nil {
+                skipped = append(skipped, ICSImportItem{UID: uid, Component: o.Name, Title: summaryOf(o), Action: ImportSkip, Reason: "invalid RECURRENCE-ID"})
+                continue
+            }
+            overrides[ical.FormatDateTime(t)] = o
+        }
+
+        if g.master == nil {
+            // Overrides without their master still stand on their own
+            keys := make([]string, 0, len(overrides))
+            for key := range overrides {
+                keys = append(keys, key)
+            }
+            sort.Strings(keys)
+            for _, key := range keys {
+                o := overrides[key]
+                entry, reason := icsEntryFrom(o, loc)
+                if reason != "" {
+                    skipped = append(skipped, ICSImportItem{UID: uid, Component: o.Name, Title: summaryOf(o), Action: ImportSkip, Reason: reason})
+                    continue
+                }
+                entry.uid = uid + "/" + key
+                entries = append(entries, entry)
+            }
+            continue
+        }
+
+        base, reason := icsEntryFrom(g.master, loc)
+        if reason != "" {
+            skipped = append(skipped, ICSImportItem{UID: uid, Component: g.master.Name, Title: summaryOf(g.master), Action: ImportSkip, Reason: reason})
+            continue
+        }
+        base.uid = uid
+
+        if g.master.Get("RRULE") == nil && g.master.Get("RDATE") == nil {
+            entries = append(entries, base)
+            continue
+        }
+
+        occurrences, err := expandICSRecurrence(g.master, loc, now)
+        if err != nil {
+            skipped = append(skipped, ICSImportItem{UID: uid, Component: g.master.Name, Title: base.title, Action: ImportSkip, Reason: err.Error()})
+            continue
+        }
+        // Occurrences follow DTSTART; a to-do's DUE keeps its distance from it
+        start, _, _ := g.master.Get("DTSTART").Time(loc)
+        offset := base.due.Sub(start)
+        allDay := isAllDay(g.master)
+        for _, occ := range occurrences {
+            key := ical.FormatDateTime(occ)
+            entry := base
+            entry.uid = uid + "/" + key
+            entry.due = occ.Add(offset)
+            if allDay {
+                entry.due = endOfDay(occ)
+            }
+            if o, ok := overrides[key]; ok {
+                overridden, reason := icsEntryFrom(o, loc)
+                if reason != "" {
+                    continue
+                }
+                overridden.uid = entry.uid
+                entry = overridden
+            }
+            entries = append(entries, entry)
+        }
+    }
+
+    return entries, skipped
+}
+
+// icsEntryFrom maps a single VEVENT/VTODO onto assignment fields. A
+// non-empty reason means the entry should be skipped.
+func icsEntryFrom(c *ical.Component, loc *time.Location) (icsEntry, string) {
+    entry := icsEntry{
+        component: c.Name,
+        title:     summaryOf(c),
+        priority:  priorityFromICal(c.Value("PRIORITY")),
+    }
+    if p := c.Get("DESCRIPTION"); p != nil {
+        entry.description = p.TextValue()
+    }
+    if entry.title == "" {
+        return entry, "missing SUMMARY"
+    }
+
+    status := strings.ToUpper(c.Value("STATUS"))
+    if status == "CANCELLED" {
+        return entry, "cancelled"
+    }
+
+    // Events are deadlines at their start; to-dos use DUE when they have one
+    prop := c.Get("DTSTART")
+    if c.Name == "VTODO" {
+        if due := c.Get("DUE"); due != nil {
+            prop = due
+        }
+        switch status {
+        case "COMPLETED":
+            entry.status = "completed"
+        case "IN-PROCESS":
+            entry.status = "in_progress"
+        default:
+            entry.status = "pending"
+        }
+    }
+    if prop == nil {
+        return entry, "no due date"
+    }
+
+    t, allDay, err := prop.Time(loc)
+    if err != nil {
+        return entry, "invalid date"
+    }
+    if allDay {
+        t = endOfDay(t)
+    }
+    entry.due = t
+    return entry, ""
+}
+
+func expandICSRecurrence(c *ical.Component, loc *time.Location, now time.Time) ([]time.Time, error) {
+    dtstart := c.Get("DTSTART")
+    if dtstart == nil {
+        return nil, errors.New("recurring entry without DTSTART")
+    }
+    start, _, err := dtstart.Time(loc)
+    if err != nil {
+        return nil, errors.New("recurring entry without a valid DTSTART")
+    }
+
+    var exdates []time.Time
+    for _, p := range c.GetAll("EXDATE") {
+        times, err := p.Times(start.Location())
+        if err != nil {
+            return nil, errors.New("invalid EXDATE")
+        }
+        exdates = append(exdates, times...)
+    }
+
+    var occurrences []time.Time
+    if p := c.Get("RRULE"); p != nil {
+        rule, err := recurrence.Parse(p.Value)
+        if err != nil {
+            return nil, fmt.Errorf("unsupported recurrence rule: %v", err)
+        }
+        if rule.Until == nil && rule.Count == 0 {
+            occurrences = rule.Between(start, exdates, start, now.Add(icsImportHorizon))
+        } else {
+            occurrences = rule.All(start, exdates)
+        }
+    } else {
+        occurrences = []time.Time{start}
+    }
+
+    for _, p := range c.GetAll("RDATE") {
+        times, err := p.Times(start.Location())
+        if err != nil {
+            return nil, errors.New("invalid RDATE")
+        }
+        for _, t := range times {
+            excluded := false
+            for _, ex := range exdates {
+                if ex.Equal(t) {
+                    excluded = true
+                }
+            }
+            if !excluded {
+                occurrences = append(occurrences, t)
+            }
+        }
+    }
+
+    sort.Slice(occurrences, func(i, j int) bool { return occurrences[i].Before(occurrences[j]) })
+    return occurrences, nil
+}
+
+func isAllDay(c *ical.Component) bool {
+    p := c.Get("DTSTART")
+    if c.Name == "VTODO" && c.Get("DUE") != nil {
+        p = c.Get("DUE")
+    }
+    if p == nil {
+        return false
+    }
+    _, allDay, _ := p.Time(time.UTC)
+    return allDay
+}
+
+func summaryOf(c *ical.Component) string {
+    if p := c.Get("SUMMARY"); p != nil {
+        return strings.TrimSpace(p.TextValue())
+    }
+    return ""
+}
+
+// priorityFromICal maps the 1 (highest) to 9 scale; 0 means undefined.
+func priorityFromICal(value string) string {
+    n, err := strconv.Atoi(strings.TrimSpace(value))
+    switch {
+    case err != nil || n == 0 || n == 5:
+        return "medium"
+    case n < 5:
+        return "high"
+    default:
+        return "low"
+    }
+}
+
+// endOfDay turns an all-day date into a deadline at the last minute of it.
+func endOfDay(t time.Time) time.Time {
+    return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 0, 0, t.Location())
+}
@@ -0,0 +1,398 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+const (
+    MeetingLecture     = "lecture"
+    MeetingLab         = "lab"
+    MeetingOfficeHours = "office_hours"
+)
+
+var ErrInvalidMeeting = errors.New("invalid meeting")
+
+type MeetingService struct {
+    db *gorm.DB
+}
+
+func NewMeetingService() *MeetingService {
+    return &MeetingService{
+        db: database.GetDB(),
+    }
+}
+
+type CreateMeetingRequest struct {
+    DayOfWeek *int   `json:"day_of_week" binding:"required,min=0,max=6"` // 0 = Sunday
+    StartTime string `json:"start_time" binding:"required"`              // HH:MM
+    EndTime   string `json:"end_time" binding:"required"`
+    Location  string `json:"location"`
+    Type      string `json:"type"`
+}
+
+// MeetingClash is a pair of overlapping meetings on the same day.
+type MeetingClash struct {
+    DayOfWeek int           `json:"day_of_week"`
+    Day       string        `json:"day"`
+    First     TimetableSlot `json:"first"`
+    Second    TimetableSlot `json:"second"`
+}
+
+type TimetableSlot struct {
+    MeetingID  uint   `json:"meeting_id"`
+    CourseID   uint   `json:"course_id"`
+    CourseCode string `json:"course_code"`
+    CourseName string `json:"course_name"`
+    Type       string `json:"type"`
+    Location   string `json:"location"`
+    StartTime  string `json:"start_time"`
+    EndTime    string `json:"end_time"`
+    Clash      bool   `json:"clash"`
+}
+
+type TimetableDay struct {
+    DayOfWeek int             `json:"day_of_week"`
+    Day       string          `json:"day"`
+    Slots     []TimetableSlot `json:"slots"`
+}
+
+// Timetable is the weekly grid for one semester. Days run Sunday to
+// Saturday to match day_of_week.
+type Timetable struct {
+    Semester      string         `json:"semester"`
+    Days          []TimetableDay `json:"days"`
+    EarliestStart string         `json:"earliest_start,omitempty"`
+    LatestEnd     string         `json:"latest_end,omitempty"`
+    WeeklyHours   float64        `json:"weekly_hours"`
+    Clashes       []MeetingClash `json:"clashes"`
+}
+
+func (s *MeetingService) GetCourseMeetings(userID, courseID uint) ([]models.CourseMeeting, error) {
+    if _, err := s.getCourse(userID, courseID); err != nil {
+        return nil, err
+    }
+    var meetings []models.CourseMeeting
+    err := s.db.Where("course_id = ? AND user_id = ?", courseID, userID).
+        Order("day_of_week ASC, start_time ASC").
+        Find(&meetings).Error
+    return meetings, err
+}
+
+// CreateMeeting adds a slot and returns any clashes it causes with the
+// user's other enrolled courses that semester. Clashes are reported, not
+// refused: students do sometimes take overlapping courses.
+func (s *MeetingService) CreateMeeting(userID, courseID uint, req CreateMeetingRequest) (*models.CourseMeeting, []MeetingClash, error) {
+    course, err := s.getCourse(userID, courseID)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    meeting := models.CourseMeeting{
+        UserID:    userID,
+        CourseID:  courseID,
+        DayOfWeek: *req.DayOfWeek,
+        StartTime: req.StartTime,
+        EndTime:   req.EndTime,
+        Location:  req.Location,
+        Type:      req.Type,
+    }
+    if err := normalizeMeeting(&meeting); err != nil {
+        return nil, nil, err
+    }
+
+    if err := s.db.Create(&meeting).Error; err != nil {
+        return nil, nil, err
+    }
+
+    clashes, err := s.clashesFor(userID, course, &meeting)
+    return &meeting, clashes, err
+}
+
+func (s *MeetingService) UpdateMeeting(userID, courseID, meetingID uint, updates map[string]interface{}) (*models.CourseMeeting, []MeetingClash, error) {
+    course, err := s.getCourse(userID, courseID)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    var meeting models.CourseMeeting
+    if err := s.db.Where("id = ? AND course_id = ? AND user_id = ?", meetingID, courseID, userID).First(&meeting).Error; err != nil {
+        return nil, nil, err
+    }
+
+    if v, ok := updates["day_of_week"].(float64); ok {
+        meeting.DayOfWeek = int(v)
+    }
+    if v, ok := updates["start_time"].(string); ok {
+        meeting.StartTime = v
+    }
+    if v, ok := updates["end_time"].(string); ok {
+        meeting.EndTime = v
+    }
+    if v, ok := updates["location"].(string); ok {
+        meeting.Location = v
+    }
+    if v, ok := updates["type"].(string); ok {
+        meeting.Type = v
+    }
+    if err := normalizeMeeting(&meeting); err != nil {
+        return nil, nil, err
+    }
+
+    if err := s.db.Save(&meeting).Error; err != nil {
+        return nil, nil, err
+    }
+
+    clashes, err := s.clashesFor(userID, course, &meeting)
+    return &meeting, clashes, err
+}
+
+func (s *MeetingService) DeleteMeeting(userID, courseID, meetingID uint) error {
+    result := s.db.Where("id = ? AND course_id = ? AND user_id = ?", meetingID, courseID, userID).
+        Delete(&models.CourseMeeting{})
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return gorm.ErrRecordNotFound
+    }
+    return nil
+}
+
+// GetTimetable lays out the meetings of the user's enrolled courses for a
+// semester. An empty semester picks the term in session, or failing that
+// the latest one with enrolled courses.
+func (s *MeetingService) GetTimetable(userID uint, semester string) (*Timetable, error) {
+    if semester == "" {
+        current, err := s.defaultSemester(userID)
+        if err != nil {
+            return nil, err
+        }
+        semester = current
+    }
+
+    meetings, courses, err := s.semesterMeetings(userID, semester)
+    if err != nil {
+        return nil, err
+    }
+
+    timetable := &Timetable{Semester: semester, Clashes: meetingClashes(meetings, courses)}
+    clashing := map[uint]bool{}
+    for _, clash := range timetable.Clashes {
+        clashing[clash.First.MeetingID] = true
+        clashing[clash.Second.MeetingID] = true
+    }
+
+    for day := 0; day < 7; day++ {
+        timetable.Days = append(timetable.Days, TimetableDay{
+            DayOfWeek: day,
+            Day:       time.Weekday(day).String(),
+            Slots:     []TimetableSlot{},
+        })
+    }
+
+    minutes := 0
+    for i := range meetings {
+        m := &meetings[i]
+        slot := timetableSlot(m, courses[m.CourseID])
+        slot.Clash = clashing[m.ID]
+        day := &timetable.Days[m.DayOfWeek]
+        day.Slots = append(day.Slots, slot)
+
+        if timetable.EarliestStart == "" || m.StartTime < timetable.EarliestStart {
+            timetable.EarliestStart = m.StartTime
+        }
+        if m.EndTime > timetable.LatestEnd {
+            timetable.LatestEnd = m.EndTime
+        }
+        minutes += clockMinutes(m.EndTime) - clockMinutes(m.StartTime)
+    }
+    timetable.WeeklyHours = round2(float64(minutes) / 60)
+
+    return timetable, nil
+}
+
+func (s *MeetingService) getCourse(userID, courseID uint) (*models.Course, error) {
+    var course models.Course
+    if err := s.db.Where("id = ? AND user_id = ?", courseID, userID).First(&course).Error; err != nil {
+        return nil, err
+    }
+    return &course, nil
+}
+
+func (s *MeetingService) defaultSemester(userID uint) (string, error) {
+    enrolledTerms := s.db.Model(&models.Course{}).Select("term_id").
+        Where("user_id = ? AND status = ? AND term_id IS NOT NULL", userID, "enrolled")
+    today := time.Now().UTC().Truncate(24 * time.Hour)
+
+    var current models.Term
+    err := s.db.Where("user_id = ? AND start_date <= ? AND end_date >= ? AND id IN (?)", userID, today, today, enrolledTerms).
+        Order("start_date DESC").First(&current).Error
+    if err == nil {
+        return current.Name, nil
+    }
+    if !errors.Is(err, gorm.ErrRecordNotFound) {
+        return "", err
+    }
+
+    var semesters []string
+    if err := s.db.Model(&models.Course{}).
+        Where("user_id = ? AND status = ?", userID, "enrolled").
+        Distinct().Pluck("semester", &semesters).Error; err != nil {
+        return "", err
+    }
+
+    latest, latestKey := "", -1
+    for _, semester := range semesters {
+        key := semesterSortKey(semester)
+        if key == math.MaxInt32 {
+            continue
+        }
+        if key > latestKey {
+            latest, latestKey = semester, key
+        }
+    }
+    if latest == "" && len(semesters) > 0 {
+        sort.Strings(semesters)
+        latest = semesters[len(semesters)-1]
+    }
+    return latest, nil
+}
+
+// semesterMeetings loads the meetings of the enrolled courses in a
+// semester, sorted by day and start time.
+func (s *MeetingService) semesterMeetings(userID uint, semester string) ([]models.CourseMeeting, map[uint]*models.Course, error) {
+    var courses []models.Course
+    if err := s.db.Where("user_id = ? AND status = ? AND LOWER(semester) = LOWER(?)", userID, "enrolled", semester).
+        Find(&courses).Error; err != nil {
+        return nil, nil, err
+    }
+
+    byID := make(map[uint]*models.Course, len(courses))
+    ids := make([]uint, 0, len(courses))
+    for i := range courses {
+        byID[courses[i].ID] = &courses[i]
+        ids = append(ids, courses[i].ID)
+    }
+
+    meetings := []models.CourseMeeting{}
+    if len(ids) == 0 {
+        return meetings, byID, nil
+    }
+    if err := s.db.Where("user_id = ? AND course_id IN ?", userID, ids).
+        Order("day_of_week ASC, start_time ASC, id ASC").
+        Find(&meetings).Error; err != nil {
+        return nil, nil, err
+    }
+    return meetings, byID, nil
+}
+
+// clashesFor lists the clashes involving one meeting. Courses the user is
+// not enrolled in have no timetable, so they never clash.
+func (s *MeetingService) clashesFor(userID uint, course *models.Course, meeting *models.CourseMeeting) ([]MeetingClash, error) {
+    clashes := []MeetingClash{}
+    if course.Status != "enrolled" {
+        return clashes, nil
+    }
+
+    meetings, courses, err := s.semesterMeetings(userID, course.Semester)
+    if err != nil {
+        return nil, err
+    }
+    for _, clash := range meetingClashes(meetings, courses) {
+        if clash.First.MeetingID == meeting.ID || clash.Second.MeetingID == meeting.ID {
+            clashes = append(clashes, clash)
+        }
+    }
+    return clashes, nil
+}
+
+// meetingClashes finds overlapping pairs in meetings, which must be sorted
+// by day and start time. Office hours are optional, so they never clash.
+func meetingClashes(meetings []models.CourseMeeting, courses map[uint]*models.Course) []MeetingClash {
+    clashes := []MeetingClash{}
+    for i := range meetings {
+        a := &meetings[i]
+        if a.Type == MeetingOfficeHours {
+            continue
+        }
+        for j := i + 1; j < len(meetings); j++ {
+            b := &meetings[j]
+            if b.DayOfWeek != a.DayOfWeek || b.StartTime >= a.EndTime {
+                break
+            }
+            if b.Type == MeetingOfficeHours {
+                continue
+            }
+            clashes = append(clashes, MeetingClash{
+                DayOfWeek: a.DayOfWeek,
+                Day:       time.Weekday(a.DayOfWeek).String(),
+                First:     timetableSlot(a, courses[a.CourseID]),
+                Second:    timetableSlot(b, courses[b.CourseID]),
+            })
+        }
+    }
+    return clashes
+}
+
+func timetableSlot(m *models.CourseMeeting, course *models.Course) TimetableSlot {
+    slot := TimetableSlot{
+        MeetingID: m.ID,
+        CourseID:  m.CourseID,
+        Type:      m.Type,
+        Location:  m.Location,
+        StartTime: m.StartTime,
+        EndTime:   m.EndTime,
+    }
+    if course != nil {
+        slot.CourseCode = course.CourseCode
+        slot.CourseName = course.CourseName
+    }
+    return slot
+}
+
+// normalizeMeeting validates a meeting and rewrites its times as
+// zero-padded HH:MM so they sort and compare as strings.
+func normalizeMeeting(m *models.CourseMeeting) error {
+    if m.DayOfWeek < 0 || m.DayOfWeek > 6 {
+        return fmt.Errorf("%w: day_of_week must be between 0 (Sunday) and 6 (Saturday)", ErrInvalidMeeting)
+    }
+
+    start, err := time.Parse("15:04", strings.TrimSpace(m.StartTime))
+    if err != nil {
+        return fmt.Errorf("%w: start_time must be HH:MM", ErrInvalidMeeting)
+    }
+    end, err := time.Parse("15:04", strings.TrimSpace(m.EndTime))
+    if err != nil {
+        return fmt.Errorf("%w: end_time must be HH:MM", ErrInvalidMeeting)
+    }
+    if !end.After(start) {
+        return fmt.Errorf("%w: end_time must be after start_time", ErrInvalidMeeting)
+    }
+    m.StartTime, m.EndTime = start.Format("15:04"), end.Format("15:04")
+
+    if m.Type == "" {
+        m.Type = MeetingLecture
+    }
+    if m.Type != MeetingLecture && m.Type != MeetingLab && m.Type != MeetingOfficeHours {
+        return fmt.Errorf("%w: type must be lecture, lab or office_hours", ErrInvalidMeeting)
+    }
+    return nil
+}
+
+// clockMinutes converts a normalized HH:MM time to minutes after midnight.
+func clockMinutes(clock string) int {
+    t, err := time.Parse("15:04", clock)
+    if err != nil {
+        return 0
+    }
+    return t.Hour()*60 + t.Minute()
+}
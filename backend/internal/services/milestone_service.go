@@ -0,0 +1,438 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+const (
+    MilestonePlanned   = "planned"
+    MilestoneScheduled = "scheduled"
+    MilestoneCompleted = "completed"
+    MilestoneDeferred  = "deferred"
+
+    // Days past the program norm before a milestone counts as slipping
+    milestoneSlipGraceDays = 30
+)
+
+var (
+    ErrInvalidMilestone   = errors.New("invalid milestone")
+    ErrAssignmentNotFound = errors.New("assignment not found")
+)
+
+// Typical months from program start, used when the attached degree
+// program doesn't set its own norms.
+var defaultMilestoneNorms = map[string]map[string]int{
+    "phd": {"qualifying_exam": 18, "proposal": 30, "candidacy": 36, "defense": 60},
+    "ms":  {"proposal": 9, "defense": 21},
+}
+
+type MilestoneService struct {
+    db *gorm.DB
+}
+
+func NewMilestoneService() *MilestoneService {
+    return &MilestoneService{
+        db: database.GetDB(),
+    }
+}
+
+type CreateMilestoneRequest struct {
+    Name       string                    `json:"name" binding:"required"`
+    Type       string                    `json:"type"`
+    Status     string                    `json:"status"`
+    TargetDate *time.Time                `json:"target_date"`
+    Committee  []models.CommitteeMember  `json:"committee"`
+    Documents  []models.RequiredDocument `json:"documents"`
+    Notes      string                    `json:"notes"`
+}
+
+type UpdateMilestoneRequest struct {
+    Name          *string                    `json:"name"`
+    Type          *string                    `json:"type"`
+    Status        *string                    `json:"status"`
+    TargetDate    *time.Time                 `json:"target_date"`
+    CompletedDate *time.Time                 `json:"completed_date"`
+    Committee     *[]models.CommitteeMember  `json:"committee"`
+    Documents     *[]models.RequiredDocument `json:"documents"`
+    Notes         *string                    `json:"notes"`
+}
+
+type LinkAssignmentRequest struct {
+    AssignmentID uint `json:"assignment_id" binding:"required"`
+}
+
+type MilestoneTimelineEntry struct {
+    MilestoneID        uint       `json:"milestone_id"`
+    Name               string     `json:"name"`
+    Type               string     `json:"type"`
+    Status             string     `json:"status"`
+    TargetDate         *time.Time `json:"target_date"`
+    CompletedDate      *time.Time `json:"completed_date"`
+    NormMonths         *int       `json:"norm_months"`
+    ExpectedDate       *time.Time `json:"expected_date"` // Program start plus the norm
+    SlipDays           int        `json:"slip_days"`     // Days behind the norm; negative is ahead
+    Slipping           bool       `json:"slipping"`
+    Overdue            bool       `json:"overdue"` // Target date passed without completion
+    AssignmentsDone    int        `json:"assignments_done"`
+    AssignmentsTotal   int        `json:"assignments_total"`
+    DocumentsSubmitted int        `json:"documents_submitted"`
+    DocumentsRequired  int        `json:"documents_required"`
+}
+
+type MilestoneTimeline struct {
+    ProgramStartDate   *time.Time               `json:"program_start_date"`
+    NormsSource        string                   `json:"norms_source"` // program, default or none
+    Milestones         []MilestoneTimelineEntry `json:"milestones"`
+    DelayDays          int                      `json:"delay_days"` // How far behind the norms the user currently is
+    ExpectedDefense    *time.Time               `json:"expected_defense"`
+    ProjectedDefense   *time.Time               `json:"projected_defense"`
+    TimeToDegreeMonths *float64                 `json:"time_to_degree_months"`
+    Slipping           int                      `json:"slipping"`
+    Warnings           []string                 `json:"warnings"`
+}
+
+func (s *MilestoneService) GetMilestones(userID uint) ([]models.Milestone, error) {
+    var milestones []models.Milestone
+    err := s.db.Where("user_id = ?", userID).Preload("Assignments").
+        Order("target_date ASC NULLS LAST, id ASC").Find(&milestones).Error
+    return milestones, err
+}
+
+func (s *MilestoneService) GetMilestone(userID, milestoneID uint) (*models.Milestone, error) {
+    var milestone models.Milestone
+    err := s.db.Where("id = ? AND user_id = ?", milestoneID, userID).Preload("Assignments").First(&milestone).Error
+    return &milestone, err
+}
+
+func (s *MilestoneService) CreateMilestone(userID uint, req CreateMilestoneRequest) (*models.Milestone, error) {
+    milestone := models.Milestone{
+        UserID:     userID,
+        Name:       strings.TrimSpace(req.Name),
+        Type:       req.Type,
+        Status:     req.Status,
+        TargetDate: req.TargetDate,
+        Committee:  req.Committee,
+        Documents:  req.Documents,
+        Notes:      req.Notes,
+    }
+    if err := normalizeMilestone(&milestone); err != nil {
+        return nil, err
+    }
+    if err := s.db.Create(&milestone).Error; err != nil {
+        return nil, err
+    }
+    return &milestone, nil
+}
+
+func (s *MilestoneService) UpdateMilestone(userID, milestoneID uint, req UpdateMilestoneRequest) (*models.Milestone, error) {
+    var milestone models.Milestone
+    if err := s.db.Where("id = ? AND user_id = ?", milestoneID, userID).First(&milestone).Error; err != nil {
+        return nil, err
+    }
+
+    if req.Name != nil {
+        milestone.Name = strings.TrimSpace(*req.Name)
+    }
+    if req.Type != nil {
+        milestone.Type = *req.Type
+    }
+    if req.Status != nil {
+        milestone.Status = *req.Status
+    }
+    if req.TargetDate != nil {
+        milestone.TargetDate = req.TargetDate
+    }
+    if req.CompletedDate != nil {
+        milestone.CompletedDate = req.CompletedDate
+    }
+    if req.Committee != nil {
+        milestone.Committee = *req.Committee
+    }
+    if req.Documents != nil {
+        milestone.Documents = *req.Documents
+    }
+    if req.Notes != nil {
+        milestone.Notes = *req.Notes
+    }
+    if err := normalizeMilestone(&milestone); err != nil {
+        return nil, err
+    }
+
+    if err := s.db.Save(&milestone).Error; err != nil {
+        return nil, err
+    }
+    return s.GetMilestone(userID, milestone.ID)
+}
+
+// DeleteMilestone removes a milestone and unlinks its assignments and any
+// thesis that took its defense date from it.
+func (s *MilestoneService) DeleteMilestone(userID, milestoneID uint) error {
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("id = ? AND user_id = ?", milestoneID, userID).Delete(&models.Milestone{})
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return gorm.ErrRecordNotFound
+        }
+        if err := tx.Model(&models.Assignment{}).Where("milestone_id = ? AND user_id = ?", milestoneID, userID).
+            Update("milestone_id", nil).Error; err != nil {
+            return err
+        }
+        return tx.Model(&models.Thesis{}).Where("milestone_id = ? AND user_id = ?", milestoneID, userID).
+            Update("milestone_id", nil).Error
+    })
+}
+
+// LinkAssignment attaches an assignment to a milestone, moving it off any
+// milestone it was linked to before.
+func (s *MilestoneService) LinkAssignment(userID, milestoneID, assignmentID uint) (*models.Milestone, error) {
+    if _, err := s.GetMilestone(userID, milestoneID); err != nil {
+        return nil, err
+    }
+    result := s.db.Model(&models.Assignment{}).Where("id = ? AND user_id = ?", assignmentID, userID).
+        Update("milestone_id", milestoneID)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrAssignmentNotFound
+    }
+    return s.GetMilestone(userID, milestoneID)
+}
+
+func (s *MilestoneService) UnlinkAssignment(userID, milestoneID, assignmentID uint) error {
+    result := s.db.Model(&models.Assignment{}).
+        Where("id = ? AND milestone_id = ? AND user_id = ?", assignmentID, milestoneID, userID).
+        Update("milestone_id", nil)
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return gorm.ErrRecordNotFound
+    }
+    return nil
+}
+
+// Timeline compares milestones against program norms and projects the
+// defense date. The user's current delay is the furthest any completed or
+// overdue milestone has fallen behind its norm, and the defense is pushed
+// back by that much.
+func (s *MilestoneService) Timeline(userID uint, now time.Time) (*MilestoneTimeline, error) {
+    var user models.User
+    if err := s.db.First(&user, userID).Error; err != nil {
+        return nil, err
+    }
+    milestones, err := s.GetMilestones(userID)
+    if err != nil {
+        return nil, err
+    }
+    norms, source, err := s.milestoneNorms(&user)
+    if err != nil {
+        return nil, err
+    }
+
+    timeline := &MilestoneTimeline{
+        ProgramStartDate: user.ProgramStartDate,
+        NormsSource:      source,
+        Milestones:       []MilestoneTimelineEntry{},
+        Warnings:         []string{},
+    }
+    if user.ProgramStartDate == nil {
+        timeline.Warnings = append(timeline.Warnings, "set program_start_date on your profile to compare milestones against program norms")
+    }
+    expectedAt := func(months *int) *time.Time {
+        if months == nil || user.ProgramStartDate == nil {
+            return nil
+        }
+        expected := user.ProgramStartDate.AddDate(0, *months, 0)
+        return &expected
+    }
+
+    var defense *models.Milestone
+    for i := range milestones {
+        m := &milestones[i]
+        entry := MilestoneTimelineEntry{
+            MilestoneID:       m.ID,
+            Name:              m.Name,
+            Type:              m.Type,
+            Status:            m.Status,
+            TargetDate:        m.TargetDate,
+            CompletedDate:     m.CompletedDate,
+            NormMonths:        normFor(norms, m.Name, m.Type),
+            AssignmentsTotal:  len(m.Assignments),
+            DocumentsRequired: len(m.Documents),
+        }
+        entry.ExpectedDate = expectedAt(entry.NormMonths)
+        for _, a := range m.Assignments {
+            if a.Status == "completed" {
+                entry.AssignmentsDone++
+            }
+        }
+        for _, d := range m.Documents {
+            if d.Submitted {
+                entry.DocumentsSubmitted++
+            }
+        }
+        entry.Overdue = m.Status != MilestoneCompleted && m.TargetDate != nil && m.TargetDate.Before(now)
+
+        // Measure against the completion date, else the planned date, else
+        // today once the norm has passed
+        var reference *time.Time
+        switch {
+        case m.CompletedDate != nil:
+            reference = m.CompletedDate
+        case m.TargetDate != nil && !entry.Overdue:
+            reference = m.TargetDate
+        case entry.ExpectedDate != nil && now.After(*entry.ExpectedDate):
+            reference = &now
+        }
+        if entry.ExpectedDate != nil && reference != nil {
+            entry.SlipDays = int(reference.Sub(*entry.ExpectedDate).Hours() / 24)
+            entry.Slipping = m.Status != MilestoneCompleted && entry.SlipDays > milestoneSlipGraceDays
+            if (m.Status == MilestoneCompleted || entry.Overdue) && entry.SlipDays > timeline.DelayDays {
+                timeline.DelayDays = entry.SlipDays
+            }
+        }
+        if entry.Slipping {
+            timeline.Slipping++
+        }
+        if m.Type == "defense" && defense == nil {
+            defense = m
+        }
+        timeline.Milestones = append(timeline.Milestones, entry)
+    }
+
+    sort.SliceStable(timeline.Milestones, func(i, j int) bool {
+        return timelineDate(&timeline.Milestones[i]).Before(timelineDate(&timeline.Milestones[j]))
+    })
+
+    timeline.ExpectedDefense = expectedAt(normFor(norms, "defense", "defense"))
+    switch {
+    case defense != nil && defense.CompletedDate != nil:
+        timeline.ProjectedDefense = defense.CompletedDate
+    case timeline.ExpectedDefense != nil:
+        projected := timeline.ExpectedDefense.AddDate(0, 0, timeline.DelayDays)
+        if defense != nil && defense.TargetDate != nil && defense.TargetDate.After(projected) {
+            projected = *defense.TargetDate
+        }
+        timeline.ProjectedDefense = &projected
+    case defense != nil:
+        timeline.ProjectedDefense = defense.TargetDate
+    }
+    if timeline.ProjectedDefense != nil && user.ProgramStartDate != nil {
+        months := round2(timeline.ProjectedDefense.Sub(*user.ProgramStartDate).Hours() / 24 / 30.44)
+        timeline.TimeToDegreeMonths = &months
+    }
+    return timeline, nil
+}
+
+// milestoneNorms picks the norms from the attached degree program, falling
+// back to typical values for the profile's program level.
+func (s *MilestoneService) milestoneNorms(user *models.User) (map[string]int, string, error) {
+    if user.DegreeProgramID != nil {
+        var program models.DegreeProgram
+        err := s.db.Where("id = ? AND user_id = ?", *user.DegreeProgramID, user.ID).First(&program).Error
+        if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, "", err
+        }
+        if err == nil && len(program.MilestoneNorms) > 0 {
+            return program.MilestoneNorms, "program", nil
+        }
+    }
+
+    level := strings.ToLower(strings.ReplaceAll(user.Program, ".", ""))
+    switch {
+    case strings.Contains(level, "phd") || strings.Contains(level, "doctor"):
+        return defaultMilestoneNorms["phd"], "default", nil
+    case strings.HasPrefix(level, "ms") || strings.Contains(level, "master"):
+        return defaultMilestoneNorms["ms"], "default", nil
+    }
+    return map[string]int{}, "none", nil
+}
+
+// normFor looks a milestone up by name first, then by type, ignoring case
+// and treating spaces and underscores alike.
+func normFor(norms map[string]int, name, milestoneType string) *int {
+    for _, want := range []string{milestoneKey(name), milestoneKey(milestoneType)} {
+        if want == "" {
+            continue
+        }
+        for k, months := range norms {
+            if milestoneKey(k) == want {
+                m := months
+                return &m
+            }
+        }
+    }
+    return nil
+}
+
+func milestoneKey(s string) string {
+    return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), " ", "_")
+}
+
+func timelineDate(entry *MilestoneTimelineEntry) time.Time {
+    switch {
+    case entry.CompletedDate != nil:
+        return *entry.CompletedDate
+    case entry.TargetDate != nil:
+        return *entry.TargetDate
+    case entry.ExpectedDate != nil:
+        return *entry.ExpectedDate
+    }
+    return time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+}
+
+func normalizeMilestone(m *models.Milestone) error {
+    if m.Name == "" {
+        return fmt.Errorf("%w: name is required", ErrInvalidMilestone)
+    }
+    if m.Type == "" {
+        m.Type = "other"
+    }
+    switch m.Type {
+    case "qualifying_exam", "proposal", "candidacy", "defense", "other":
+    default:
+        return fmt.Errorf("%w: type must be qualifying_exam, proposal, candidacy, defense or other", ErrInvalidMilestone)
+    }
+
+    if m.Status == "" {
+        m.Status = MilestonePlanned
+        if m.TargetDate != nil {
+            m.Status = MilestoneScheduled
+        }
+    }
+    switch m.Status {
+    case MilestonePlanned, MilestoneScheduled, MilestoneDeferred:
+        m.CompletedDate = nil
+    case MilestoneCompleted:
+        if m.CompletedDate == nil {
+            today := time.Now().UTC().Truncate(24 * time.Hour)
+            m.CompletedDate = &today
+        }
+    default:
+        return fmt.Errorf("%w: status must be planned, scheduled, completed or deferred", ErrInvalidMilestone)
+    }
+
+    if m.Committee == nil {
+        m.Committee = []models.CommitteeMember{}
+    }
+    for _, member := range m.Committee {
+        if strings.TrimSpace(member.Name) == "" {
+            return fmt.Errorf("%w: committee members need a name", ErrInvalidMilestone)
+        }
+    }
+    if m.Documents == nil {
+        m.Documents = []models.RequiredDocument{}
+    }
+    return nil
+}
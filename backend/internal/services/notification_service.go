@@ -0,0 +1,587 @@
+package services
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "math"
+    "net/url"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/jobs"
+    "github.com/anayy09/academiaflow-backend/internal/mail"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "github.com/anayy09/academiaflow-backend/internal/notify"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+const (
+    NotificationScheduled = "scheduled"
+    NotificationSent      = "sent"
+    NotificationFailed    = "failed"
+    NotificationCancelled = "cancelled"
+
+    NotificationAssignmentDue = "assignment_due"
+    NotificationTest          = "test"
+
+    JobDeliverNotification = "notifications.deliver"
+    JobReminderSweep       = "notifications.sweep"
+
+    // The sweep creates reminders up to reminderLookahead ahead and queues
+    // their delivery for the exact time. Reminders whose time passed more
+    // than reminderGrace ago, e.g. for assignments added at the last
+    // minute, are skipped rather than sent late.
+    reminderSweepInterval = 5 * time.Minute
+    reminderLookahead     = time.Hour
+    reminderGrace         = 2 * reminderSweepInterval
+    reminderSweepBatch    = 500
+
+    maxReminderMinutes = 30 * 24 * 60
+    maxReminders       = 5
+)
+
+var ErrInvalidPreference = errors.New("invalid notification preference")
+
+var defaultReminderMinutes = []int{24 * 60, 60}
+
+type NotificationService struct {
+    db       *gorm.DB
+    channels notify.Channels
+}
+
+func NewNotificationService(channels notify.Channels) *NotificationService {
+    return &NotificationService{
+        db:       database.GetDB(),
+        channels: channels,
+    }
+}
+
+type UpdateNotificationPreferenceRequest struct {
+    Enabled         *bool     `json:"enabled"`
+    Channels        *[]string `json:"channels"`
+    ReminderMinutes *[]int    `json:"reminder_minutes"`
+    QuietStart      *string   `json:"quiet_start"` // HH:MM; empty turns quiet hours off
+    QuietEnd        *string   `json:"quiet_end"`
+    TimeZone        *string   `json:"time_zone"`
+    Email           *string   `json:"email" binding:"omitempty,email"`
+    WebhookURL      *string   `json:"webhook_url"`
+}
+
+type deliverPayload struct {
+    NotificationID uint `json:"notification_id"`
+}
+
+// RegisterJobs sets up delivery and the periodic reminder sweep on the
+// scheduler.
+func (s *NotificationService) RegisterJobs(scheduler *jobs.Scheduler, now time.Time) error {
+    scheduler.Register(JobDeliverNotification, s.deliverJob)
+    scheduler.Register(JobReminderSweep, func(ctx context.Context, job *models.Job) error {
+        _, err := s.SweepReminders(time.Now())
+        return err
+    })
+    return scheduler.Every(JobReminderSweep, reminderSweepInterval, now)
+}
+
+// GetPreferences returns the user's preferences, or the defaults: in-app
+// reminders a day and an hour before each due date.
+func (s *NotificationService) GetPreferences(userID uint) (*models.NotificationPreference, error) {
+    var pref models.NotificationPreference
+    err := s.db.Where("user_id = ?", userID).First(&pref).Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return &models.NotificationPreference{
+            UserID:          userID,
+            Enabled:         true,
+            Channels:        []string{notify.ChannelInApp},
+            ReminderMinutes: append([]int(nil), defaultReminderMinutes...),
+            TimeZone:        plannerLocation(s.db, userID).String(),
+        }, nil
+    }
+    return &pref, err
+}
+
+func (s *NotificationService) UpdatePreferences(userID uint, req UpdateNotificationPreferenceRequest) (*models.NotificationPreference, error) {
+    pref, err := s.GetPreferences(userID)
+    if err != nil {
+        return nil, err
+    }
+    if req.Enabled != nil {
+        pref.Enabled = *req.Enabled
+    }
+    if req.Channels != nil {
+        pref.Channels = *req.Channels
+    }
+    if req.ReminderMinutes != nil {
+        pref.ReminderMinutes = *req.ReminderMinutes
+    }
+    if req.QuietStart != nil {
+        pref.QuietStart = strings.TrimSpace(*req.QuietStart)
+    }
+    if req.QuietEnd != nil {
+        pref.QuietEnd = strings.TrimSpace(*req.QuietEnd)
+    }
+    if req.TimeZone != nil {
+        pref.TimeZone = *req.TimeZone
+    }
+    if req.Email != nil {
+        pref.Email = strings.TrimSpace(*req.Email)
+    }
+    if req.WebhookURL != nil {
+        pref.WebhookURL = strings.TrimSpace(*req.WebhookURL)
+    }
+    if err := s.normalizePreference(pref); err != nil {
+        return nil, err
+    }
+
+    if err := s.db.Save(pref).Error; err != nil {
+        return nil, err
+    }
+    return pref, nil
+}
+
+// GetNotifications lists notifications delivered in-app, newest first,
+// with the number still unread.
+func (s *NotificationService) GetNotifications(userID uint, unreadOnly bool, limit int) ([]models.Notification, int64, error) {
+    base := s.db.Model(&models.Notification{}).Where("user_id = ? AND in_app = ?", userID, true)
+    var unread int64
+    if err := base.Session(&gorm.Session{}).Where("read_at IS NULL").Count(&unread).Error; err != nil {
+        return nil, 0, err
+    }
+
+    query := base.Session(&gorm.Session{})
+    if unreadOnly {
+        query = query.Where("read_at IS NULL")
+    }
+    var notifications []models.Notification
+    err := query.Order("sent_at DESC, id DESC").Limit(limit).Find(&notifications).Error
+    return notifications, unread, err
+}
+
+func (s *NotificationService) MarkRead(userID, notificationID uint, now time.Time) (*models.Notification, error) {
+    var notification models.Notification
+    if err := s.db.Where("id = ? AND user_id = ? AND in_app = ?", notificationID, userID, true).First(&notification).Error; err != nil {
+        return nil, err
+    }
+    if notification.ReadAt == nil {
+        notification.ReadAt = &now
+        if err := s.db.Model(&notification).Update("read_at", now).Error; err != nil {
+            return nil, err
+        }
+    }
+    return &notification, nil
+}
+
+func (s *NotificationService) MarkAllRead(userID uint, now time.Time) (int64, error) {
+    result := s.db.Model(&models.Notification{}).
+        Where("user_id = ? AND in_app = ? AND read_at IS NULL", userID, true).
+        Update("read_at", now)
+    return result.RowsAffected, result.Error
+}
+
+// SendTest queues a notification to every channel the user has chosen,
+// to check that email and webhooks arrive. It ignores quiet hours.
+func (s *NotificationService) SendTest(userID uint, now time.Time) (*models.Notification, error) {
+    pref, err := s.GetPreferences(userID)
+    if err != nil {
+        return nil, err
+    }
+    if len(pref.Channels) == 0 {
+        return nil, fmt.Errorf("%w: no channels are enabled", ErrInvalidPreference)
+    }
+    notification := models.Notification{
+        UserID:    userID,
+        Kind:      NotificationTest,
+        Title:     "Test notification",
+        Body:      "Notifications from AcademiaFlow will reach you here.",
+        Channels:  pref.Channels,
+        Delivered: []string{},
+        SendAt:    now,
+        Status:    NotificationScheduled,
+    }
+    err = s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(&notification).Error; err != nil {
+            return err
+        }
+        return enqueueDelivery(tx, &notification)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &notification, nil
+}
+
+// SweepReminders creates the reminders coming up for open assignments and
+// queues their delivery. Existing reminders are left alone, so the sweep
+// can run as often as needed. It returns how many reminders it created.
+// Only assignments with a reminder time in the sweep window under their
+// user's offsets, or the defaults, are loaded, a batch at a time.
+func (s *NotificationService) SweepReminders(now time.Time) (int, error) {
+    defaults, err := json.Marshal(defaultReminderMinutes)
+    if err != nil {
+        return 0, err
+    }
+
+    var assignments []models.Assignment
+    prefs := make(map[uint]*models.NotificationPreference)
+    created := 0
+    horizon := now.Add(time.Duration(maxReminderMinutes)*time.Minute + reminderLookahead)
+    err = s.db.Where("status <> ? AND due_date > ? AND due_date <= ?", "completed", now, horizon).
+        Where(`EXISTS (
+            SELECT 1 FROM jsonb_array_elements_text(COALESCE(
+                (SELECT CASE WHEN p.enabled THEN p.reminder_minutes ELSE '[]' END
+                 FROM notification_preferences p
+                 WHERE p.user_id = assignments.user_id AND p.deleted_at IS NULL),
+                ?)::jsonb) AS offsets(minutes)
+            WHERE assignments.due_date - offsets.minutes::int * INTERVAL '1 minute' BETWEEN ? AND ?)`,
+            string(defaults), now.Add(-reminderGrace), now.Add(reminderLookahead)).
+        Preload("Course").
+        FindInBatches(&assignments, reminderSweepBatch, func(tx *gorm.DB, batch int) error {
+            for i := range assignments {
+                assignment := &assignments[i]
+                pref, ok := prefs[assignment.UserID]
+                if !ok {
+                    var err error
+                    if pref, err = s.GetPreferences(assignment.UserID); err != nil {
+                        return err
+                    }
+                    prefs[assignment.UserID] = pref
+                }
+                if !pref.Enabled || len(pref.Channels) == 0 {
+                    continue
+                }
+                n, err := s.createReminders(assignment, pref, now)
+                created += n
+                if err != nil {
+                    return err
+                }
+            }
+            return nil
+        }).Error
+    return created, err
+}
+
+// createReminders creates and queues the reminders for assignment that
+// fall in the sweep window, returning how many were new.
+func (s *NotificationService) createReminders(assignment *models.Assignment, pref *models.NotificationPreference, now time.Time) (int, error) {
+    created := 0
+    loc := preferenceLocation(pref)
+
+    for _, minutes := range pref.ReminderMinutes {
+        remindAt := assignment.DueDate.Add(-time.Duration(minutes) * time.Minute)
+        if remindAt.After(now.Add(reminderLookahead)) || remindAt.Before(now.Add(-reminderGrace)) {
+            continue
+        }
+        if remindAt.Before(now) {
+            remindAt = now
+        }
+        due := assignment.DueDate
+        notification := models.Notification{
+            UserID:        assignment.UserID,
+            Kind:          NotificationAssignmentDue,
+            AssignmentID:  &assignment.ID,
+            OffsetMinutes: minutes,
+            DueDate:       &due,
+            Body:          reminderBody(assignment, loc),
+            Channels:      pref.Channels,
+            Delivered:     []string{},
+            SendAt:        deferQuietHours(remindAt, due, now, pref, loc),
+            Status:        NotificationScheduled,
+        }
+        notification.Title = fmt.Sprintf("%s is due in %s", assignment.Title, dueIn(due.Sub(notification.SendAt)))
+        err := s.db.Transaction(func(tx *gorm.DB) error {
+            result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&notification)
+            if result.Error != nil || result.RowsAffected == 0 {
+                return result.Error
+            }
+            created++
+            return enqueueDelivery(tx, &notification)
+        })
+        if err != nil {
+            return created, err
+        }
+    }
+    return created, nil
+}
+
+// Deliver sends a scheduled notification on each of its channels that it
+// hasn't gone out on yet. Reminders for assignments that have since been
+// completed, deleted or moved are cancelled instead. Channels that can't
+// work without the user's help, such as email with no address, are given
+// up on; other failures return an error so the job is retried.
+func (s *NotificationService) Deliver(ctx context.Context, notificationID uint, now time.Time) error {
+    var notification models.Notification
+    err := s.db.First(&notification, notificationID).Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+    if notification.Status != NotificationScheduled {
+        return nil
+    }
+
+    pref, err := s.GetPreferences(notification.UserID)
+    if err != nil {
+        return err
+    }
+    if notification.Kind == NotificationAssignmentDue {
+        stale := notification.AssignmentID == nil || notification.DueDate == nil
+        if !stale {
+            var assignment models.Assignment
+            err := s.db.Where("id = ? AND user_id = ?", *notification.AssignmentID, notification.UserID).First(&assignment).Error
+            if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+                return err
+            }
+            stale = err != nil || assignment.Status == "completed" || !assignment.DueDate.Equal(*notification.DueDate)
+        }
+        if stale || !pref.Enabled {
+            return s.db.Model(&notification).Update("status", NotificationCancelled).Error
+        }
+    }
+
+    var user models.User
+    if err := s.db.First(&user, notification.UserID).Error; err != nil {
+        return err
+    }
+    to := notify.Recipient{
+        UserID:     user.ID,
+        Name:       strings.TrimSpace(user.FirstName + " " + user.LastName),
+        Email:      pref.Email,
+        WebhookURL: pref.WebhookURL,
+    }
+    if to.Email == "" {
+        to.Email = user.Email
+    }
+    msg := notify.Message{
+        ID:    notification.ID,
+        Kind:  notification.Kind,
+        Title: notification.Title,
+        Body:  notification.Body,
+        At:    notification.SendAt,
+    }
+
+    var failures []string
+    retry := false
+    for _, name := range notification.Channels {
+        if containsString(notification.Delivered, name) {
+            continue
+        }
+        channel, ok := s.channels[name]
+        if !ok {
+            failures = append(failures, name+": unknown channel")
+            continue
+        }
+        if err := channel.Send(ctx, to, msg); err != nil {
+            failures = append(failures, name+": "+err.Error())
+            if !errors.Is(err, notify.ErrNoAddress) && !errors.Is(err, notify.ErrBlockedAddress) && !errors.Is(err, mail.ErrNotConfigured) {
+                retry = true
+            }
+            continue
+        }
+        notification.Delivered = append(notification.Delivered, name)
+    }
+
+    notification.InApp = containsString(notification.Delivered, notify.ChannelInApp)
+    notification.LastError = strings.Join(failures, "; ")
+    if !retry {
+        notification.Status = NotificationFailed
+        if len(notification.Delivered) > 0 {
+            notification.Status = NotificationSent
+            notification.SentAt = &now
+        }
+    }
+    // Updated through the struct so the channel list is stored as JSON
+    err = s.db.Model(&notification).
+        Select("delivered", "in_app", "last_error", "status", "sent_at").
+        Updates(&notification).Error
+    if err != nil {
+        return err
+    }
+    if retry {
+        return errors.New(strings.Join(failures, "; "))
+    }
+    return nil
+}
+
+// deliverJob runs Deliver for a queued notification and settles it once
+// the job has used up its retries: sent if any channel got it through,
+// failed otherwise. The last error is kept either way.
+func (s *NotificationService) deliverJob(ctx context.Context, job *models.Job) error {
+    var payload deliverPayload
+    if err := jobs.Decode(job, &payload); err != nil {
+        return err
+    }
+    now := time.Now()
+    err := s.Deliver(ctx, payload.NotificationID, now)
+    if err == nil || job.Attempts < job.MaxAttempts {
+        return err
+    }
+
+    var notification models.Notification
+    if lookupErr := s.db.First(&notification, payload.NotificationID).Error; lookupErr != nil {
+        if errors.Is(lookupErr, gorm.ErrRecordNotFound) {
+            return err
+        }
+        return fmt.Errorf("%w (settling notification: %v)", err, lookupErr)
+    }
+    updates := map[string]interface{}{"status": NotificationFailed}
+    if len(notification.Delivered) > 0 {
+        updates["status"] = NotificationSent
+        updates["sent_at"] = now
+    }
+    updateErr := s.db.Model(&models.Notification{}).
+        Where("id = ? AND status = ?", notification.ID, NotificationScheduled).
+        Updates(updates).Error
+    if updateErr != nil {
+        return fmt.Errorf("%w (settling notification: %v)", err, updateErr)
+    }
+    return err
+}
+
+func (s *NotificationService) normalizePreference(pref *models.NotificationPreference) error {
+    channels := make([]string, 0, len(pref.Channels))
+    for _, name := range pref.Channels {
+        name = strings.TrimSpace(name)
+        if _, ok := s.channels[name]; !ok {
+            return fmt.Errorf("%w: channels must be from %s", ErrInvalidPreference, strings.Join(s.channels.Names(), ", "))
+        }
+        if !containsString(channels, name) {
+            channels = append(channels, name)
+        }
+    }
+    pref.Channels = channels
+
+    minutes := make([]int, 0, len(pref.ReminderMinutes))
+    for _, m := range pref.ReminderMinutes {
+        if m <= 0 || m > maxReminderMinutes {
+            return fmt.Errorf("%w: reminder_minutes must be between 1 and %d", ErrInvalidPreference, maxReminderMinutes)
+        }
+        if !containsInt(minutes, m) {
+            minutes = append(minutes, m)
+        }
+    }
+    if len(minutes) > maxReminders {
+        return fmt.Errorf("%w: at most %d reminders per assignment", ErrInvalidPreference, maxReminders)
+    }
+    sort.Sort(sort.Reverse(sort.IntSlice(minutes)))
+    pref.ReminderMinutes = minutes
+
+    if (pref.QuietStart == "") != (pref.QuietEnd == "") {
+        return fmt.Errorf("%w: set both quiet_start and quiet_end, or neither", ErrInvalidPreference)
+    }
+    for _, clock := range []string{pref.QuietStart, pref.QuietEnd} {
+        if _, err := time.Parse("15:04", clock); clock != "" && err != nil {
+            return fmt.Errorf("%w: quiet hours must be HH:MM", ErrInvalidPreference)
+        }
+    }
+    if pref.TimeZone == "" {
+        pref.TimeZone = "UTC"
+    }
+    if _, err := time.LoadLocation(pref.TimeZone); err != nil {
+        return fmt.Errorf("%w: unknown time zone %q", ErrInvalidPreference, pref.TimeZone)
+    }
+    if pref.WebhookURL != "" {
+        u, err := url.Parse(pref.WebhookURL)
+        if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+            return fmt.Errorf("%w: webhook_url must be an http or https URL", ErrInvalidPreference)
+        }
+    }
+    if containsString(pref.Channels, notify.ChannelWebhook) && pref.WebhookURL == "" {
+        return fmt.Errorf("%w: the webhook channel needs a webhook_url", ErrInvalidPreference)
+    }
+    return nil
+}
+
+func enqueueDelivery(tx *gorm.DB, notification *models.Notification) error {
+    key := fmt.Sprintf("notification:%d", notification.ID)
+    return jobs.Enqueue(tx, JobDeliverNotification, key, deliverPayload{NotificationID: notification.ID}, notification.SendAt)
+}
+
+func preferenceLocation(pref *models.NotificationPreference) *time.Location {
+    loc, err := time.LoadLocation(pref.TimeZone)
+    if err != nil {
+        return time.UTC
+    }
+    return loc
+}
+
+// deferQuietHours moves a reminder falling in the user's quiet hours to
+// when they end. If that would be after the due date it goes out just
+// before they start instead, or straight away if they already have.
+func deferQuietHours(t, due, now time.Time, pref *models.NotificationPreference, loc *time.Location) time.Time {
+    if pref.QuietStart == "" || pref.QuietStart == pref.QuietEnd {
+        return t
+    }
+    start, end := clockMinutes(pref.QuietStart), clockMinutes(pref.QuietEnd)
+    local := t.In(loc)
+    minute := local.Hour()*60 + local.Minute()
+    at := func(days, minutes int) time.Time {
+        return time.Date(local.Year(), local.Month(), local.Day()+days, 0, minutes, 0, 0, loc)
+    }
+
+    var quietFrom, quietUntil time.Time
+    switch {
+    case start < end && minute >= start && minute < end:
+        quietFrom, quietUntil = at(0, start), at(0, end)
+    case start > end && minute >= start:
+        quietFrom, quietUntil = at(0, start), at(1, end)
+    case start > end && minute < end:
+        quietFrom, quietUntil = at(-1, start), at(0, end)
+    default:
+        return t
+    }
+    if quietUntil.Before(due) {
+        return quietUntil
+    }
+    if before := quietFrom.Add(-time.Minute); before.After(now) {
+        return before
+    }
+    return t
+}
+
+// dueIn phrases the time left when a reminder goes out, rounded to whole
+// days, hours or minutes, e.g. "1 day" or "45 minutes".
+func dueIn(d time.Duration) string {
+    unit, n := "minute", int(math.Round(d.Minutes()))
+    switch {
+    case d >= 24*time.Hour:
+        unit, n = "day", int(math.Round(d.Hours()/24))
+    case d >= time.Hour:
+        unit, n = "hour", int(math.Round(d.Hours()))
+    }
+    if n != 1 {
+        unit += "s"
+    }
+    return fmt.Sprintf("%d %s", n, unit)
+}
+
+func reminderBody(assignment *models.Assignment, loc *time.Location) string {
+    body := "Due " + assignment.DueDate.In(loc).Format("Mon Jan 2, 15:04 MST")
+    if assignment.Course != nil {
+        course := assignment.Course.CourseCode
+        if course == "" {
+            course = assignment.Course.CourseName
+        }
+        body += " for " + course
+    }
+    if assignment.Status == "pending" {
+        body += ". Not started yet."
+    } else {
+        body += "."
+    }
+    return body
+}
+
+func containsInt(values []int, target int) bool {
+    for _, v := range values {
+        if v == target {
+            return true
+        }
+    }
+    return false
+}
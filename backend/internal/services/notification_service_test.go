@@ -0,0 +1,54 @@
+package services
+
+import (
+    "testing"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/models"
+)
+
+func TestDeferQuietHours(t *testing.T) {
+    loc, err := time.LoadLocation("America/New_York")
+    if err != nil {
+        t.Skip("no time zone data:", err)
+    }
+    at := func(day, hour, minute int) time.Time {
+        return time.Date(2025, time.March, day, hour, minute, 0, 0, loc)
+    }
+    overnight := &models.NotificationPreference{QuietStart: "22:00", QuietEnd: "07:00"}
+    afternoon := &models.NotificationPreference{QuietStart: "13:00", QuietEnd: "15:00"}
+
+    tests := []struct {
+        name string
+        pref *models.NotificationPreference
+        t    time.Time
+        due  time.Time
+        now  time.Time
+        want time.Time
+    }{
+        {"no quiet hours", &models.NotificationPreference{}, at(3, 23, 0), at(4, 12, 0), at(3, 20, 0), at(3, 23, 0)},
+        {"empty window", &models.NotificationPreference{QuietStart: "22:00", QuietEnd: "22:00"}, at(3, 23, 0), at(4, 12, 0), at(3, 20, 0), at(3, 23, 0)},
+
+        {"within the day: inside", afternoon, at(3, 14, 0), at(3, 18, 0), at(3, 9, 0), at(3, 15, 0)},
+        {"within the day: at the start", afternoon, at(3, 13, 0), at(3, 18, 0), at(3, 9, 0), at(3, 15, 0)},
+        {"within the day: at the end", afternoon, at(3, 15, 0), at(3, 18, 0), at(3, 9, 0), at(3, 15, 0)},
+        {"within the day: before", afternoon, at(3, 12, 59), at(3, 18, 0), at(3, 9, 0), at(3, 12, 59)},
+
+        {"past midnight: late evening", overnight, at(3, 23, 0), at(4, 12, 0), at(3, 20, 0), at(4, 7, 0)},
+        {"past midnight: early morning", overnight, at(4, 3, 0), at(4, 12, 0), at(3, 20, 0), at(4, 7, 0)},
+        {"past midnight: outside", overnight, at(3, 12, 0), at(3, 18, 0), at(3, 9, 0), at(3, 12, 0)},
+        // The spring-forward night is an hour shorter
+        {"past midnight: across a DST change", overnight, at(8, 23, 0), at(9, 12, 0), at(8, 20, 0), at(9, 7, 0)},
+
+        {"due in quiet hours: sent just before", overnight, at(3, 23, 0), at(4, 1, 0), at(3, 20, 0), at(3, 21, 59)},
+        {"due in quiet hours: already too late to move", overnight, at(3, 23, 0), at(4, 1, 0), at(3, 22, 30), at(3, 23, 0)},
+        {"due as quiet hours end", overnight, at(4, 3, 0), at(4, 7, 0), at(3, 20, 0), at(3, 21, 59)},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := deferQuietHours(tt.t, tt.due, tt.now, tt.pref, loc); !got.Equal(tt.want) {
+                t.Errorf("got %v, want %v", got.In(loc), tt.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,506 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+const (
+    PaperDrafting    = "drafting"
+    PaperSubmitted   = "submitted"
+    PaperUnderReview = "under_review"
+    PaperRevise      = "revise"
+    PaperAccepted    = "accepted"
+    PaperPublished   = "published"
+
+    DecisionPending       = "pending"
+    DecisionAccept        = "accept"
+    DecisionMinorRevision = "minor_revision"
+    DecisionMajorRevision = "major_revision"
+    DecisionReject        = "reject"
+    DecisionWithdrawn     = "withdrawn"
+
+    PaperDeadlineSubmission  = "submission"
+    PaperDeadlineRebuttal    = "rebuttal"
+    PaperDeadlineCameraReady = "camera_ready"
+)
+
+var ErrInvalidPaper = errors.New("invalid paper")
+
+var paperStatuses = []string{PaperDrafting, PaperSubmitted, PaperUnderReview, PaperRevise, PaperAccepted, PaperPublished}
+
+var paperDecisions = []string{DecisionPending, DecisionAccept, DecisionMinorRevision, DecisionMajorRevision, DecisionReject, DecisionWithdrawn}
+
+// Status a paper moves to once the latest round gets each decision
+var decisionStatus = map[string]string{
+    DecisionAccept:        PaperAccepted,
+    DecisionMinorRevision: PaperRevise,
+    DecisionMajorRevision: PaperRevise,
+    DecisionReject:        PaperDrafting,
+    DecisionWithdrawn:     PaperDrafting,
+}
+
+type PaperService struct {
+    db *gorm.DB
+}
+
+func NewPaperService() *PaperService {
+    return &PaperService{
+        db: database.GetDB(),
+    }
+}
+
+type CreatePaperRequest struct {
+    Title               string               `json:"title" binding:"required"`
+    Authors             []models.PaperAuthor `json:"authors"`
+    Venue               string               `json:"venue"`
+    VenueType           string               `json:"venue_type"`
+    Status              string               `json:"status"`
+    SubmissionDeadline  *time.Time           `json:"submission_deadline"`
+    RebuttalDeadline    *time.Time           `json:"rebuttal_deadline"`
+    CameraReadyDeadline *time.Time           `json:"camera_ready_deadline"`
+    URL                 string               `json:"url"`
+    Notes               string               `json:"notes"`
+}
+
+type UpdatePaperRequest struct {
+    Title               *string               `json:"title"`
+    Authors             *[]models.PaperAuthor `json:"authors"`
+    Venue               *string               `json:"venue"`
+    VenueType           *string               `json:"venue_type"`
+    Status              *string               `json:"status"`
+    SubmissionDeadline  *time.Time            `json:"submission_deadline"`
+    RebuttalDeadline    *time.Time            `json:"rebuttal_deadline"`
+    CameraReadyDeadline *time.Time            `json:"camera_ready_deadline"`
+    ClearDeadlines      []string              `json:"clear_deadlines"` // submission, rebuttal, camera_ready
+    URL                 *string               `json:"url"`
+    Notes               *string               `json:"notes"`
+}
+
+type CreateRoundRequest struct {
+    Venue        string               `json:"venue"`        // Defaults to the paper's venue
+    SubmittedAt  *time.Time           `json:"submitted_at"` // Defaults to now
+    Decision     string               `json:"decision"`
+    DecisionDate *time.Time           `json:"decision_date"`
+    Reviews      []models.PaperReview `json:"reviews"`
+    Notes        string               `json:"notes"`
+}
+
+type UpdateRoundRequest struct {
+    Venue        *string               `json:"venue"`
+    SubmittedAt  *time.Time            `json:"submitted_at"`
+    Decision     *string               `json:"decision"`
+    DecisionDate *time.Time            `json:"decision_date"`
+    Reviews      *[]models.PaperReview `json:"reviews"`
+    Notes        *string               `json:"notes"`
+}
+
+func (s *PaperService) GetPapers(userID uint, status string) ([]models.Paper, error) {
+    var papers []models.Paper
+    query := s.db.Where("user_id = ?", userID).
+        Preload("Rounds", func(db *gorm.DB) *gorm.DB { return db.Order("number ASC") }).
+        Preload("Assignments", func(db *gorm.DB) *gorm.DB { return db.Order("due_date ASC") })
+    if status != "" {
+        query = query.Where("status = ?", status)
+    }
+    err := query.Order("updated_at DESC").Find(&papers).Error
+    return papers, err
+}
+
+func (s *PaperService) GetPaper(userID, paperID uint) (*models.Paper, error) {
+    var paper models.Paper
+    err := s.db.Where("id = ? AND user_id = ?", paperID, userID).
+        Preload("Rounds", func(db *gorm.DB) *gorm.DB { return db.Order("number ASC") }).
+        Preload("Assignments", func(db *gorm.DB) *gorm.DB { return db.Order("due_date ASC") }).
+        First(&paper).Error
+    return &paper, err
+}
+
+func (s *PaperService) CreatePaper(userID uint, req CreatePaperRequest) (*models.Paper, error) {
+    paper := models.Paper{
+        UserID:              userID,
+        Title:               strings.TrimSpace(req.Title),
+        Authors:             req.Authors,
+        Venue:               strings.TrimSpace(req.Venue),
+        VenueType:           req.VenueType,
+        Status:              req.Status,
+        SubmissionDeadline:  req.SubmissionDeadline,
+        RebuttalDeadline:    req.RebuttalDeadline,
+        CameraReadyDeadline: req.CameraReadyDeadline,
+        URL:                 req.URL,
+        Notes:               req.Notes,
+    }
+    if err := normalizePaper(&paper); err != nil {
+        return nil, err
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(&paper).Error; err != nil {
+            return err
+        }
+        return syncPaperDeadlines(tx, &paper)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return s.GetPaper(userID, paper.ID)
+}
+
+func (s *PaperService) UpdatePaper(userID, paperID uint, req UpdatePaperRequest) (*models.Paper, error) {
+    var paper models.Paper
+    if err := s.db.Where("id = ? AND user_id = ?", paperID, userID).First(&paper).Error; err != nil {
+        return nil, err
+    }
+
+    if req.Title != nil {
+        paper.Title = strings.TrimSpace(*req.Title)
+    }
+    if req.Authors != nil {
+        paper.Authors = *req.Authors
+    }
+    if req.Venue != nil {
+        paper.Venue = strings.TrimSpace(*req.Venue)
+    }
+    if req.VenueType != nil {
+        paper.VenueType = *req.VenueType
+    }
+    if req.Status != nil {
+        paper.Status = *req.Status
+    }
+    if req.SubmissionDeadline != nil {
+        paper.SubmissionDeadline = req.SubmissionDeadline
+    }
+    if req.RebuttalDeadline != nil {
+        paper.RebuttalDeadline = req.RebuttalDeadline
+    }
+    if req.CameraReadyDeadline != nil {
+        paper.CameraReadyDeadline = req.CameraReadyDeadline
+    }
+    for _, kind := range req.ClearDeadlines {
+        switch kind {
+        case PaperDeadlineSubmission:
+            paper.SubmissionDeadline = nil
+        case PaperDeadlineRebuttal:
+            paper.RebuttalDeadline = nil
+        case PaperDeadlineCameraReady:
+            paper.CameraReadyDeadline = nil
+        default:
+            return nil, fmt.Errorf("%w: unknown deadline %q", ErrInvalidPaper, kind)
+        }
+    }
+    if req.URL != nil {
+        paper.URL = *req.URL
+    }
+    if req.Notes != nil {
+        paper.Notes = *req.Notes
+    }
+    if err := normalizePaper(&paper); err != nil {
+        return nil, err
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Save(&paper).Error; err != nil {
+            return err
+        }
+        if err := syncPaperDeadlines(tx, &paper); err != nil {
+            return err
+        }
+        if paper.Status == PaperPublished {
+            return completePaperDeadline(tx, &paper, PaperDeadlineCameraReady)
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return s.GetPaper(userID, paper.ID)
+}
+
+// DeletePaper removes a paper with its rounds. Deadline assignments still
+// open are deleted; completed ones are kept but unlinked.
+func (s *PaperService) DeletePaper(userID, paperID uint) error {
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("id = ? AND user_id = ?", paperID, userID).Delete(&models.Paper{})
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return gorm.ErrRecordNotFound
+        }
+        if err := tx.Where("paper_id = ? AND user_id = ?", paperID, userID).Delete(&models.PaperRound{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("paper_id = ? AND user_id = ? AND status <> ?", paperID, userID, "completed").
+            Delete(&models.Assignment{}).Error; err != nil {
+            return err
+        }
+        return tx.Model(&models.Assignment{}).Where("paper_id = ? AND user_id = ?", paperID, userID).
+            Updates(map[string]interface{}{"paper_id": nil, "paper_deadline": ""}).Error
+    })
+}
+
+// CreateRound records a new submission. The paper moves to submitted (or
+// straight to the decision's status when one is given) and its submission
+// deadline assignment is marked done.
+func (s *PaperService) CreateRound(userID, paperID uint, req CreateRoundRequest) (*models.PaperRound, error) {
+    var paper models.Paper
+    if err := s.db.Where("id = ? AND user_id = ?", paperID, userID).First(&paper).Error; err != nil {
+        return nil, err
+    }
+
+    round := models.PaperRound{
+        UserID:       userID,
+        PaperID:      paper.ID,
+        Venue:        strings.TrimSpace(req.Venue),
+        SubmittedAt:  req.SubmittedAt,
+        Decision:     req.Decision,
+        DecisionDate: req.DecisionDate,
+        Reviews:      req.Reviews,
+        Notes:        req.Notes,
+    }
+    if round.Venue == "" {
+        round.Venue = paper.Venue
+    }
+    if round.SubmittedAt == nil {
+        now := time.Now()
+        round.SubmittedAt = &now
+    }
+    if err := normalizeRound(&round); err != nil {
+        return nil, err
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        // Locking the paper serializes submissions, so two can't take the
+        // same number; deleted rounds count so numbers are never reused
+        if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Paper{}, paper.ID).Error; err != nil {
+            return err
+        }
+        var last int
+        if err := tx.Unscoped().Model(&models.PaperRound{}).Where("paper_id = ?", paper.ID).
+            Select("COALESCE(MAX(number), 0)").Scan(&last).Error; err != nil {
+            return err
+        }
+        round.Number = last + 1
+        if err := tx.Create(&round).Error; err != nil {
+            return err
+        }
+
+        if round.Venue != "" {
+            paper.Venue = round.Venue
+        }
+        paper.Status = roundStatus(&round)
+        if err := tx.Save(&paper).Error; err != nil {
+            return err
+        }
+        return completePaperDeadline(tx, &paper, PaperDeadlineSubmission)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &round, nil
+}
+
+// UpdateRound edits a round's reviews and decision. Decisions on the
+// latest round carry over to the paper's status.
+func (s *PaperService) UpdateRound(userID, paperID, roundID uint, req UpdateRoundRequest) (*models.PaperRound, error) {
+    var round models.PaperRound
+    if err := s.db.Where("id = ? AND paper_id = ? AND user_id = ?", roundID, paperID, userID).First(&round).Error; err != nil {
+        return nil, err
+    }
+
+    if req.Venue != nil {
+        round.Venue = strings.TrimSpace(*req.Venue)
+    }
+    if req.SubmittedAt != nil {
+        round.SubmittedAt = req.SubmittedAt
+    }
+    if req.Decision != nil {
+        round.Decision = *req.Decision
+    }
+    if req.DecisionDate != nil {
+        round.DecisionDate = req.DecisionDate
+    }
+    if req.Reviews != nil {
+        round.Reviews = *req.Reviews
+    }
+    if req.Notes != nil {
+        round.Notes = *req.Notes
+    }
+    if err := normalizeRound(&round); err != nil {
+        return nil, err
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Save(&round).Error; err != nil {
+            return err
+        }
+
+        var latest models.PaperRound
+        if err := tx.Where("paper_id = ?", paperID).Order("number DESC").First(&latest).Error; err != nil {
+            return err
+        }
+        if latest.ID != round.ID {
+            return nil
+        }
+        var paper models.Paper
+        if err := tx.Where("id = ? AND user_id = ?", paperID, userID).First(&paper).Error; err != nil {
+            return err
+        }
+        // Keep a published paper published when the acceptance is re-saved
+        if status := roundStatus(&round); !(paper.Status == PaperPublished && status == PaperAccepted) {
+            paper.Status = status
+        }
+        return tx.Save(&paper).Error
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &round, nil
+}
+
+func (s *PaperService) DeleteRound(userID, paperID, roundID uint) error {
+    result := s.db.Where("id = ? AND paper_id = ? AND user_id = ?", roundID, paperID, userID).Delete(&models.PaperRound{})
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return gorm.ErrRecordNotFound
+    }
+    return nil
+}
+
+// roundStatus is the paper status implied by a round: waiting on a
+// decision it is submitted, or under review once reviews have come in.
+func roundStatus(round *models.PaperRound) string {
+    if status, ok := decisionStatus[round.Decision]; ok {
+        return status
+    }
+    if len(round.Reviews) > 0 {
+        return PaperUnderReview
+    }
+    return PaperSubmitted
+}
+
+// syncPaperDeadlines keeps an open assignment for each deadline set on the
+// paper. Completed assignments are left alone; open ones follow the
+// deadline and are removed when it is cleared.
+func syncPaperDeadlines(tx *gorm.DB, paper *models.Paper) error {
+    deadlines := []struct {
+        kind  string
+        label string
+        date  *time.Time
+    }{
+        {PaperDeadlineSubmission, "Submit", paper.SubmissionDeadline},
+        {PaperDeadlineRebuttal, "Rebuttal", paper.RebuttalDeadline},
+        {PaperDeadlineCameraReady, "Camera-ready", paper.CameraReadyDeadline},
+    }
+
+    for _, d := range deadlines {
+        var existing []models.Assignment
+        if err := tx.Where("paper_id = ? AND paper_deadline = ? AND user_id = ?", paper.ID, d.kind, paper.UserID).
+            Find(&existing).Error; err != nil {
+            return err
+        }
+
+        if d.date == nil {
+            for _, a := range existing {
+                if a.Status == "completed" {
+                    continue
+                }
+                if err := tx.Delete(&models.Assignment{}, a.ID).Error; err != nil {
+                    return err
+                }
+            }
+            continue
+        }
+
+        title := d.label + ": " + paper.Title
+        if paper.Venue != "" {
+            title += " (" + paper.Venue + ")"
+        }
+        // Open assignments follow the deadline. Once none is open, a new
+        // one is created unless this very deadline was already done, so a
+        // resubmission after a reject gets its own assignment.
+        open, done := 0, false
+        for _, a := range existing {
+            if a.Status == "completed" {
+                done = done || a.DueDate.Equal(*d.date)
+                continue
+            }
+            open++
+            if err := tx.Model(&models.Assignment{}).Where("id = ?", a.ID).
+                Updates(map[string]interface{}{"title": title, "due_date": *d.date}).Error; err != nil {
+                return err
+            }
+        }
+        if open == 0 && !done {
+            assignment := models.Assignment{
+                UserID:        paper.UserID,
+                Title:         title,
+                Description:   "Generated from the paper's " + strings.ReplaceAll(d.kind, "_", "-") + " deadline",
+                DueDate:       *d.date,
+                Priority:      "high",
+                Status:        "pending",
+                PaperID:       &paper.ID,
+                PaperDeadline: d.kind,
+            }
+            if err := tx.Create(&assignment).Error; err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
+
+func completePaperDeadline(tx *gorm.DB, paper *models.Paper, kind string) error {
+    return tx.Model(&models.Assignment{}).
+        Where("paper_id = ? AND paper_deadline = ? AND user_id = ? AND status <> ?", paper.ID, kind, paper.UserID, "completed").
+        Updates(map[string]interface{}{"status": "completed", "completed_at": time.Now()}).Error
+}
+
+func normalizePaper(paper *models.Paper) error {
+    if paper.Title == "" {
+        return fmt.Errorf("%w: title is required", ErrInvalidPaper)
+    }
+    if paper.Status == "" {
+        paper.Status = PaperDrafting
+    }
+    if !containsString(paperStatuses, paper.Status) {
+        return fmt.Errorf("%w: status must be one of %s", ErrInvalidPaper, strings.Join(paperStatuses, ", "))
+    }
+    switch paper.VenueType {
+    case "", "conference", "journal", "workshop":
+    default:
+        return fmt.Errorf("%w: venue_type must be conference, journal or workshop", ErrInvalidPaper)
+    }
+    if paper.Authors == nil {
+        paper.Authors = []models.PaperAuthor{}
+    }
+    for i := range paper.Authors {
+        paper.Authors[i].Name = strings.TrimSpace(paper.Authors[i].Name)
+        if paper.Authors[i].Name == "" {
+            return fmt.Errorf("%w: author %d has no name", ErrInvalidPaper, i+1)
+        }
+    }
+    return nil
+}
+
+func normalizeRound(round *models.PaperRound) error {
+    if round.Decision == "" {
+        round.Decision = DecisionPending
+    }
+    if !containsString(paperDecisions, round.Decision) {
+        return fmt.Errorf("%w: decision must be one of %s", ErrInvalidPaper, strings.Join(paperDecisions, ", "))
+    }
+    if round.Reviews == nil {
+        round.Reviews = []models.PaperReview{}
+    }
+    return nil
+}
@@ -0,0 +1,477 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "sort"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+const (
+    dateLayout = "2006-01-02"
+    // Work is scheduled in quarter-hour slices.
+    planGranularity = 15
+    // Planning keeps going past the requested window until every open
+    // assignment is placed, but never further than this.
+    maxPlanDays = 366
+)
+
+var (
+    ErrInvalidAvailability = errors.New("invalid availability")
+    ErrInvalidPlanRange    = errors.New("to must not be before from")
+)
+
+type PlannerService struct {
+    db *gorm.DB
+}
+
+func NewPlannerService() *PlannerService {
+    return &PlannerService{
+        db: database.GetDB(),
+    }
+}
+
+type UpdateAvailabilityRequest struct {
+    WeeklyHours    []float64 `json:"weekly_hours" binding:"required,len=7,dive,gte=0,lte=24"`
+    MaxHoursPerDay float64   `json:"max_hours_per_day" binding:"gte=0,lte=24"`
+    BlackoutDates  []string  `json:"blackout_dates"`
+    TimeZone       string    `json:"timezone"`
+}
+
+type PlanBlock struct {
+    AssignmentID uint    `json:"assignment_id"`
+    Title        string  `json:"title"`
+    CourseID     *uint   `json:"course_id"`
+    Hours        float64 `json:"hours"`
+    Late         bool    `json:"late"` // Scheduled after the assignment's due date
+}
+
+type PlanDay struct {
+    Date          string       `json:"date"`
+    CapacityHours float64      `json:"capacity_hours"` // Left for assignments after fixed blocks
+    PlannedHours  float64      `json:"planned_hours"`
+    FixedHours    float64      `json:"fixed_hours"`
+    Blackout      bool         `json:"blackout"`
+    Blocks        []PlanBlock  `json:"blocks"`
+    Fixed         []FixedBlock `json:"fixed"` // TA/RA shifts
+}
+
+// OverloadedDay is a due date by which more work is due than there is
+// available time between the start of the plan and that day.
+type OverloadedDay struct {
+    Date           string  `json:"date"`
+    RequiredHours  float64 `json:"required_hours"`
+    AvailableHours float64 `json:"available_hours"`
+    ShortfallHours float64 `json:"shortfall_hours"`
+    AssignmentIDs  []uint  `json:"assignment_ids"`
+}
+
+type ImpossibleDeadline struct {
+    AssignmentID     uint      `json:"assignment_id"`
+    Title            string    `json:"title"`
+    DueDate          time.Time `json:"due_date"`
+    ProjectedFinish  *string   `json:"projected_finish"` // nil when it never fits in the planning horizon
+    UnscheduledHours float64   `json:"unscheduled_hours"` // Work still left at the deadline
+}
+
+type Plan struct {
+    From       string               `json:"from"`
+    To         string               `json:"to"`
+    Days       []PlanDay            `json:"days"`
+    Overloaded []OverloadedDay      `json:"overloaded_days"`
+    Impossible []ImpossibleDeadline `json:"impossible_deadlines"`
+}
+
+// planTask is an assignment with the work still left on it.
+type planTask struct {
+    assignment *models.Assignment
+    remaining  int // minutes
+    blockers   []uint
+    finishedOn int // index of the day its last block lands on, -1 until done
+    lateFrom   int // remaining minutes at the deadline, -1 if on time
+}
+
+// defaultAvailability is used until the user saves their own.
+func defaultAvailability(userID uint) *models.Availability {
+    return &models.Availability{
+        UserID:         userID,
+        WeeklyHours:    []float64{2, 6, 6, 6, 6, 6, 2},
+        MaxHoursPerDay: 8,
+        BlackoutDates:  []string{},
+        TimeZone:       "UTC",
+    }
+}
+
+func (s *PlannerService) GetAvailability(userID uint) (*models.Availability, error) {
+    var availability models.Availability
+    err := s.db.Where("user_id = ?", userID).First(&availability).Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return defaultAvailability(userID), nil
+    }
+    return &availability, err
+}
+
+func (s *PlannerService) UpdateAvailability(userID uint, req UpdateAvailabilityRequest) (*models.Availability, error) {
+    if req.TimeZone == "" {
+        req.TimeZone = "UTC"
+    }
+    if _, err := time.LoadLocation(req.TimeZone); err != nil {
+        return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidAvailability, req.TimeZone)
+    }
+    for _, d := range req.BlackoutDates {
+        if _, err := time.Parse(dateLayout, d); err != nil {
+            return nil, fmt.Errorf("%w: blackout date %q must be YYYY-MM-DD", ErrInvalidAvailability, d)
+        }
+    }
+    if req.BlackoutDates == nil {
+        req.BlackoutDates = []string{}
+    }
+
+    var availability models.Availability
+    err := s.db.Where("user_id = ?", userID).First(&availability).Error
+    if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil, err
+    }
+
+    availability.UserID = userID
+    availability.WeeklyHours = req.WeeklyHours
+    availability.MaxHoursPerDay = req.MaxHoursPerDay
+    availability.BlackoutDates = req.BlackoutDates
+    availability.TimeZone = req.TimeZone
+
+    if err := s.db.Save(&availability).Error; err != nil {
+        return nil, err
+    }
+    return &availability, nil
+}
+
+// Plan schedules the remaining work on every open assignment into the
+// user's available time, earliest deadline first, never starting an
+// assignment before its blockers are done. The plan is computed from the
+// current assignments on every call, so it re-plans whenever they change.
+func (s *PlannerService) Plan(userID uint, from, to time.Time) (*Plan, error) {
+    availability, err := s.GetAvailability(userID)
+    if err != nil {
+        return nil, err
+    }
+    loc, err := time.LoadLocation(availability.TimeZone)
+    if err != nil {
+        loc = time.UTC
+    }
+
+    from = startOfDay(from, loc)
+    to = startOfDay(to, loc)
+    if today := startOfDay(time.Now(), loc); from.Before(today) {
+        from = today
+    }
+    if to.Before(from) {
+        return nil, ErrInvalidPlanRange
+    }
+
+    var assignments []models.Assignment
+    if err := s.db.Where("user_id = ? AND status <> ?", userID, "completed").
+        Order("due_date ASC").
+        Find(&assignments).Error; err != nil {
+        return nil, err
+    }
+
+    graph, err := NewDependencyService().loadGraph(userID)
+    if err != nil {
+        return nil, err
+    }
+
+    tasks := map[uint]*planTask{}
+    var order []*planTask
+    for i := range assignments {
+        minutes := remainingMinutes(&assignments[i])
+        if minutes <= 0 {
+            continue
+        }
+        task := &planTask{assignment: &assignments[i], remaining: minutes, finishedOn: -1, lateFrom: -1}
+        tasks[assignments[i].ID] = task
+        order = append(order, task)
+    }
+    for id, task := range tasks {
+        for _, blocker := range graph[id] {
+            if _, ok := tasks[blocker]; ok {
+                task.blockers = append(task.blockers, blocker)
+            }
+        }
+    }
+
+    // Earliest deadline first, then priority
+    sort.SliceStable(order, func(i, j int) bool {
+        a, b := order[i].assignment, order[j].assignment
+        if !a.DueDate.Equal(b.DueDate) {
+            return a.DueDate.Before(b.DueDate)
+        }
+        return priorityRank(a.Priority) < priorityRank(b.Priority)
+    })
+
+    blackout := map[string]bool{}
+    for _, d := range availability.BlackoutDates {
+        blackout[d] = true
+    }
+    fixed, err := NewAppointmentService().FixedBlocks(userID, from, from.AddDate(0, 0, maxPlanDays), loc)
+    if err != nil {
+        return nil, err
+    }
+
+    plan := &Plan{From: from.Format(dateLayout), To: to.Format(dateLayout)}
+    var capacities []int
+    remainingTasks := len(order)
+
+    for day := 0; day < maxPlanDays; day++ {
+        date := from.AddDate(0, 0, day)
+        if date.After(to) && remainingTasks == 0 {
+            break
+        }
+
+        key := date.Format(dateLayout)
+        capacity := dayCapacity(availability, date, blackout[key])
+        // Shifts come out of the day's hours whether or not it is a blackout
+        fixedMinutes := 0
+        for _, block := range fixed[key] {
+            fixedMinutes += clockMinutes(block.EndTime) - clockMinutes(block.StartTime)
+        }
+        if capacity -= fixedMinutes; capacity < 0 {
+            capacity = 0
+        }
+        capacity -= capacity % planGranularity
+        capacities = append(capacities, capacity)
+        planDay := PlanDay{
+            Date:          key,
+            CapacityHours: minutesToHours(capacity),
+            FixedHours:    minutesToHours(fixedMinutes),
+            Blackout:      blackout[key],
+            Blocks:        []PlanBlock{},
+            Fixed:         fixed[key],
+        }
+        if planDay.Fixed == nil {
+            planDay.Fixed = []FixedBlock{}
+        }
+
+        free := capacity
+        for free > 0 {
+            task := nextTask(order, tasks)
+            if task == nil {
+                break
+            }
+
+            slice := task.remaining
+            if slice > free {
+                slice = free
+            }
+            task.remaining -= slice
+            free -= slice
+
+            late := !date.Before(startOfDay(task.assignment.DueDate, loc).AddDate(0, 0, 1))
+            planDay.Blocks = appendBlock(planDay.Blocks, task.assignment, slice, late)
+
+            if task.remaining == 0 {
+                task.finishedOn = day
+                remainingTasks--
+            }
+        }
+
+        // Anything not finished by the end of its due day missed the deadline
+        for _, task := range order {
+            if task.lateFrom < 0 && task.remaining > 0 && startOfDay(task.assignment.DueDate, loc).Equal(date) {
+                task.lateFrom = task.remaining
+            }
+        }
+
+        planDay.PlannedHours = minutesToHours(capacity - free)
+        if !date.After(to) {
+            plan.Days = append(plan.Days, planDay)
+        }
+    }
+
+    plan.Overloaded = overloadedDays(order, capacities, from, loc)
+    plan.Impossible = impossibleDeadlines(order, from, len(capacities), loc)
+
+    return plan, nil
+}
+
+// nextTask picks the earliest-due task whose blockers are all scheduled.
+func nextTask(order []*planTask, tasks map[uint]*planTask) *planTask {
+    for _, task := range order {
+        if task.remaining == 0 {
+            continue
+        }
+        ready := true
+        for _, blocker := range task.blockers {
+            if tasks[blocker].remaining > 0 {
+                ready = false
+                break
+            }
+        }
+        if ready {
+            return task
+        }
+    }
+    return nil
+}
+
+func appendBlock(blocks []PlanBlock, a *models.Assignment, minutes int, late bool) []PlanBlock {
+    for i := range blocks {
+        if blocks[i].AssignmentID == a.ID {
+            blocks[i].Hours = minutesToHours(int(blocks[i].Hours*60) + minutes)
+            return blocks
+        }
+    }
+    return append(blocks, PlanBlock{
+        AssignmentID: a.ID,
+        Title:        a.Title,
+        CourseID:     a.CourseID,
+        Hours:        minutesToHours(minutes),
+        Late:         late,
+    })
+}
+
+// overloadedDays runs the EDF feasibility check: for each due day, the work
+// due by then must fit in the capacity up to and including that day.
+func overloadedDays(order []*planTask, capacities []int, from time.Time, loc *time.Location) []OverloadedDay {
+    byDay := map[int][]*planTask{}
+    var dueDays []int
+    for _, task := range order {
+        day := daysBetween(from, task.assignment.DueDate.In(loc))
+        if day < 0 {
+            day = 0
+        }
+        if day >= len(capacities) {
+            continue
+        }
+        if _, ok := byDay[day]; !ok {
+            dueDays = append(dueDays, day)
+        }
+        byDay[day] = append(byDay[day], task)
+    }
+    sort.Ints(dueDays)
+
+    var result []OverloadedDay
+    required, available, lastDay := 0, 0, -1
+    for _, day := range dueDays {
+        for d := lastDay + 1; d <= day; d++ {
+            available += capacities[d]
+        }
+        lastDay = day
+
+        var ids []uint
+        for _, task := range byDay[day] {
+            required += remainingMinutes(task.assignment)
+            ids = append(ids, task.assignment.ID)
+        }
+
+        if required > available {
+            result = append(result, OverloadedDay{
+                Date:           from.AddDate(0, 0, day).Format(dateLayout),
+                RequiredHours:  minutesToHours(required),
+                AvailableHours: minutesToHours(available),
+                ShortfallHours: minutesToHours(required - available),
+                AssignmentIDs:  ids,
+            })
+        }
+    }
+    return result
+}
+
+func impossibleDeadlines(order []*planTask, from time.Time, days int, loc *time.Location) []ImpossibleDeadline {
+    var result []ImpossibleDeadline
+    for _, task := range order {
+        dueDay := startOfDay(task.assignment.DueDate, loc)
+        if task.finishedOn < 0 && !dueDay.Before(from.AddDate(0, 0, days)) {
+            // Due beyond the planning horizon; nothing to say yet
+            continue
+        }
+        finishedLate := task.finishedOn >= 0 && from.AddDate(0, 0, task.finishedOn).After(dueDay)
+        if !finishedLate && task.finishedOn >= 0 {
+            continue
+        }
+
+        item := ImpossibleDeadline{
+            AssignmentID: task.assignment.ID,
+            Title:        task.assignment.Title,
+            DueDate:      task.assignment.DueDate,
+        }
+        if task.lateFrom > 0 {
+            item.UnscheduledHours = minutesToHours(task.lateFrom)
+        } else if task.lateFrom < 0 {
+            // Due before the plan starts: everything left is late
+            item.UnscheduledHours = minutesToHours(remainingMinutes(task.assignment))
+        }
+        if task.finishedOn >= 0 {
+            finish := from.AddDate(0, 0, task.finishedOn).Format(dateLayout)
+            item.ProjectedFinish = &finish
+        }
+        result = append(result, item)
+    }
+    return result
+}
+
+func dayCapacity(availability *models.Availability, date time.Time, blackout bool) int {
+    if blackout || len(availability.WeeklyHours) != 7 {
+        return 0
+    }
+    hours := availability.WeeklyHours[date.Weekday()]
+    if availability.MaxHoursPerDay > 0 && hours > availability.MaxHoursPerDay {
+        hours = availability.MaxHoursPerDay
+    }
+    minutes := int(hours * 60)
+    return minutes - minutes%planGranularity
+}
+
+// remainingMinutes is the estimated work left, using tracked time when
+// there is any.
+func remainingMinutes(a *models.Assignment) int {
+    done := a.TrackedMinutes
+    if done == 0 {
+        done = a.ActualHours * 60
+    }
+    left := a.EstimatedHours*60 - done
+    if left <= 0 {
+        return 0
+    }
+    // Round up to whole slices so nothing is left dangling
+    return int(math.Ceil(float64(left)/planGranularity)) * planGranularity
+}
+
+// Location is the time zone the user plans in, for reading dates given
+// without one.
+func (s *PlannerService) Location(userID uint) *time.Location {
+    return plannerLocation(s.db, userID)
+}
+
+// plannerLocation is the time zone set in the user's availability, which
+// decides which day things happen on.
+func plannerLocation(db *gorm.DB, userID uint) *time.Location {
+    var availability models.Availability
+    if err := db.Where("user_id = ?", userID).First(&availability).Error; err == nil {
+        if loc, err := time.LoadLocation(availability.TimeZone); err == nil {
+            return loc
+        }
+    }
+    return time.UTC
+}
+
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+    t = t.In(loc)
+    return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// daysBetween counts calendar days from a to b by their dates alone, so a
+// day that is 23 or 25 hours long around a DST change still counts as one.
+func daysBetween(a, b time.Time) int {
+    ay, am, ad := a.Date()
+    by, bm, bd := b.Date()
+    return int(time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC).Sub(time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+}
+
+func minutesToHours(minutes int) float64 {
+    return math.Round(float64(minutes)/60*100) / 100
+}
@@ -0,0 +1,666 @@
+package services
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "io"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+    "unicode"
+
+    "github.com/anayy09/academiaflow-backend/internal/bibtex"
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+const (
+    ReadingToRead  = "to_read"
+    ReadingReading = "reading"
+    ReadingRead    = "read"
+    ReadingSkipped = "skipped"
+
+    FormatBibTeX = "bibtex"
+    FormatRIS    = "ris"
+)
+
+var (
+    ErrInvalidReading      = errors.New("invalid reading")
+    ErrReadingLinkNotFound = errors.New("linked record not found")
+)
+
+var readingStatuses = []string{ReadingToRead, ReadingReading, ReadingRead, ReadingSkipped}
+
+var doiPrefix = regexp.MustCompile(`(?i)^(https?://(dx\.)?doi\.org/|doi:\s*)`)
+
+type ReadingService struct {
+    db *gorm.DB
+}
+
+func NewReadingService() *ReadingService {
+    return &ReadingService{
+        db: database.GetDB(),
+    }
+}
+
+type ReadingFilter struct {
+    Status       string
+    CourseID     *uint
+    AssignmentID *uint
+    PaperID      *uint
+}
+
+type CreateReadingRequest struct {
+    Title        string   `json:"title" binding:"required"`
+    CiteKey      string   `json:"cite_key"` // Generated from author, year and title when empty
+    EntryType    string   `json:"entry_type"`
+    Authors      []string `json:"authors"`
+    Year         int      `json:"year"`
+    Venue        string   `json:"venue"`
+    DOI          string   `json:"doi"`
+    URL          string   `json:"url"`
+    Abstract     string   `json:"abstract"`
+    Status       string   `json:"status"`
+    Notes        string   `json:"notes"`
+    CourseID     *uint    `json:"course_id"`
+    AssignmentID *uint    `json:"assignment_id"`
+    PaperID      *uint    `json:"paper_id"`
+}
+
+type UpdateReadingRequest struct {
+    Title        *string    `json:"title"`
+    CiteKey      *string    `json:"cite_key"`
+    EntryType    *string    `json:"entry_type"`
+    Authors      *[]string  `json:"authors"`
+    Year         *int       `json:"year"`
+    Venue        *string    `json:"venue"`
+    DOI          *string    `json:"doi"`
+    URL          *string    `json:"url"`
+    Abstract     *string    `json:"abstract"`
+    Status       *string    `json:"status"`
+    Notes        *string    `json:"notes"`
+    ReadAt       *time.Time `json:"read_at"`
+    CourseID     *uint      `json:"course_id"`
+    AssignmentID *uint      `json:"assignment_id"`
+    PaperID      *uint      `json:"paper_id"`
+    ClearLinks   []string   `json:"clear_links"` // course, assignment, paper
+}
+
+type ReadingImportOptions struct {
+    Format       string // bibtex or ris; detected from the content when empty
+    CourseID     *uint
+    AssignmentID *uint
+    PaperID      *uint
+    DryRun       bool
+}
+
+type ReadingImportItem struct {
+    CiteKey   string `json:"cite_key"`
+    Title     string `json:"title"`
+    Action    string `json:"action"` // created, duplicate or skipped
+    ReadingID *uint  `json:"reading_id,omitempty"`
+    Reason    string `json:"reason,omitempty"`
+}
+
+type ReadingImportResult struct {
+    Format     string              `json:"format"`
+    DryRun     bool                `json:"dry_run"`
+    Created    int                 `json:"created"`
+    Duplicates int                 `json:"duplicates"`
+    Skipped    int                 `json:"skipped"`
+    Items      []ReadingImportItem `json:"items"`
+}
+
+func (s *ReadingService) GetReadings(userID uint, filter ReadingFilter) ([]models.Reading, error) {
+    var readings []models.Reading
+    err := filterReadings(s.db.Where("user_id = ?", userID), filter).
+        Order("created_at DESC, id DESC").Find(&readings).Error
+    return readings, err
+}
+
+func (s *ReadingService) GetReading(userID, readingID uint) (*models.Reading, error) {
+    var reading models.Reading
+    err := s.db.Where("id = ? AND user_id = ?", readingID, userID).First(&reading).Error
+    return &reading, err
+}
+
+func (s *ReadingService) CreateReading(userID uint, req CreateReadingRequest) (*models.Reading, error) {
+    reading := models.Reading{
+        UserID:       userID,
+        CiteKey:      strings.TrimSpace(req.CiteKey),
+        EntryType:    req.EntryType,
+        Title:        strings.TrimSpace(req.Title),
+        Authors:      req.Authors,
+        Year:         req.Year,
+        Venue:        strings.TrimSpace(req.Venue),
+        DOI:          normalizeDOI(req.DOI),
+        URL:          strings.TrimSpace(req.URL),
+        Abstract:     req.Abstract,
+        Status:       req.Status,
+        Notes:        req.Notes,
+        CourseID:     req.CourseID,
+        AssignmentID: req.AssignmentID,
+        PaperID:      req.PaperID,
+    }
+    if err := normalizeReading(&reading, time.Now()); err != nil {
+        return nil, err
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := checkReadingLinks(tx, userID, reading.CourseID, reading.AssignmentID, reading.PaperID); err != nil {
+            return err
+        }
+        taken, err := citeKeys(tx, userID, 0)
+        if err != nil {
+            return err
+        }
+        reading.CiteKey = uniqueCiteKey(reading.CiteKey, &reading, taken)
+        return tx.Create(&reading).Error
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &reading, nil
+}
+
+func (s *ReadingService) UpdateReading(userID, readingID uint, req UpdateReadingRequest) (*models.Reading, error) {
+    var reading models.Reading
+    if err := s.db.Where("id = ? AND user_id = ?", readingID, userID).First(&reading).Error; err != nil {
+        return nil, err
+    }
+
+    if req.Title != nil {
+        reading.Title = strings.TrimSpace(*req.Title)
+    }
+    if req.CiteKey != nil {
+        reading.CiteKey = strings.TrimSpace(*req.CiteKey)
+    }
+    if req.EntryType != nil {
+        reading.EntryType = *req.EntryType
+    }
+    if req.Authors != nil {
+        reading.Authors = *req.Authors
+    }
+    if req.Year != nil {
+        reading.Year = *req.Year
+    }
+    if req.Venue != nil {
+        reading.Venue = strings.TrimSpace(*req.Venue)
+    }
+    if req.DOI != nil {
+        reading.DOI = normalizeDOI(*req.DOI)
+    }
+    if req.URL != nil {
+        reading.URL = strings.TrimSpace(*req.URL)
+    }
+    if req.Abstract != nil {
+        reading.Abstract = *req.Abstract
+    }
+    if req.Status != nil {
+        reading.Status = *req.Status
+    }
+    if req.Notes != nil {
+        reading.Notes = *req.Notes
+    }
+    if req.ReadAt != nil {
+        reading.ReadAt = req.ReadAt
+    }
+    if req.CourseID != nil {
+        reading.CourseID = req.CourseID
+    }
+    if req.AssignmentID != nil {
+        reading.AssignmentID = req.AssignmentID
+    }
+    if req.PaperID != nil {
+        reading.PaperID = req.PaperID
+    }
+    for _, link := range req.ClearLinks {
+        switch link {
+        case "course":
+            reading.CourseID = nil
+        case "assignment":
+            reading.AssignmentID = nil
+        case "paper":
+            reading.PaperID = nil
+        default:
+            return nil, fmt.Errorf("%w: unknown link %q", ErrInvalidReading, link)
+        }
+    }
+    if err := normalizeReading(&reading, time.Now()); err != nil {
+        return nil, err
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := checkReadingLinks(tx, userID, req.CourseID, req.AssignmentID, req.PaperID); err != nil {
+            return err
+        }
+        taken, err := citeKeys(tx, userID, reading.ID)
+        if err != nil {
+            return err
+        }
+        if req.CiteKey != nil && reading.CiteKey != "" && taken[reading.CiteKey] {
+            return fmt.Errorf("%w: cite key %q is already in use", ErrInvalidReading, reading.CiteKey)
+        }
+        reading.CiteKey = uniqueCiteKey(reading.CiteKey, &reading, taken)
+        return tx.Save(&reading).Error
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &reading, nil
+}
+
+func (s *ReadingService) DeleteReading(userID, readingID uint) error {
+    result := s.db.Where("id = ? AND user_id = ?", readingID, userID).Delete(&models.Reading{})
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return gorm.ErrRecordNotFound
+    }
+    return nil
+}
+
+// Import adds the references in a BibTeX or RIS file to the reading list.
+// An entry is a duplicate when its DOI, or failing that its title, matches
+// a reading already on the list or earlier in the file; duplicates are not
+// added again, but pick up the import's course, assignment or paper when
+// they have none yet.
+func (s *ReadingService) Import(userID uint, r io.Reader, opts ReadingImportOptions) (*ReadingImportResult, error) {
+    data, err := io.ReadAll(r)
+    if err != nil {
+        return nil, err
+    }
+    format := opts.Format
+    if format == "" {
+        format = detectReadingFormat(data)
+    }
+
+    var entries []*bibtex.Entry
+    switch format {
+    case FormatBibTeX:
+        entries, err = bibtex.Parse(bytes.NewReader(data))
+    case FormatRIS:
+        entries, err = bibtex.ParseRIS(bytes.NewReader(data))
+    default:
+        return nil, fmt.Errorf("%w: format must be bibtex or ris", ErrInvalidReading)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("%w: could not parse %s file: %v", ErrInvalidReading, format, err)
+    }
+
+    result := &ReadingImportResult{Format: format, DryRun: opts.DryRun, Items: []ReadingImportItem{}}
+    err = s.db.Transaction(func(tx *gorm.DB) error {
+        if err := checkReadingLinks(tx, userID, opts.CourseID, opts.AssignmentID, opts.PaperID); err != nil {
+            return err
+        }
+
+        var existing []models.Reading
+        if err := tx.Where("user_id = ?", userID).Find(&existing).Error; err != nil {
+            return err
+        }
+        byDOI, byTitle := map[string]*models.Reading{}, map[string]*models.Reading{}
+        taken := map[string]bool{}
+        for i := range existing {
+            indexReading(&existing[i], byDOI, byTitle)
+            taken[existing[i].CiteKey] = true
+        }
+
+        now := time.Now()
+        for _, entry := range entries {
+            reading := readingFromEntry(entry)
+            reading.UserID = userID
+            reading.CourseID, reading.AssignmentID, reading.PaperID = opts.CourseID, opts.AssignmentID, opts.PaperID
+            item := ReadingImportItem{CiteKey: entry.Key, Title: reading.Title}
+
+            if err := normalizeReading(reading, now); err != nil {
+                item.Action, item.Reason = "skipped", strings.TrimPrefix(err.Error(), ErrInvalidReading.Error()+": ")
+                result.Skipped++
+                result.Items = append(result.Items, item)
+                continue
+            }
+
+            if match := findDuplicate(reading, byDOI, byTitle); match != nil {
+                item.Action, item.CiteKey = "duplicate", match.CiteKey
+                if match.ID != 0 {
+                    item.ReadingID = &match.ID
+                    if !opts.DryRun {
+                        if err := linkDuplicate(tx, match, opts); err != nil {
+                            return err
+                        }
+                    }
+                }
+                result.Duplicates++
+                result.Items = append(result.Items, item)
+                continue
+            }
+
+            reading.CiteKey = uniqueCiteKey(reading.CiteKey, reading, taken)
+            taken[reading.CiteKey] = true
+            item.CiteKey = reading.CiteKey
+            if !opts.DryRun {
+                if err := tx.Create(reading).Error; err != nil {
+                    return err
+                }
+                item.ReadingID = &reading.ID
+            }
+            indexReading(reading, byDOI, byTitle)
+            item.Action = "created"
+            result.Created++
+            result.Items = append(result.Items, item)
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return result, nil
+}
+
+// Export returns the matching readings as BibTeX entries. Fields kept from
+// the original import are written as they were, so hand-made protections
+// like {BERT} survive, unless the reading has since been edited.
+func (s *ReadingService) Export(userID uint, filter ReadingFilter) ([]*bibtex.Entry, error) {
+    var readings []models.Reading
+    if err := filterReadings(s.db.Where("user_id = ?", userID), filter).
+        Order("cite_key ASC, id ASC").Find(&readings).Error; err != nil {
+        return nil, err
+    }
+
+    entries := make([]*bibtex.Entry, 0, len(readings))
+    for i := range readings {
+        entries = append(entries, entryFromReading(&readings[i]))
+    }
+    return entries, nil
+}
+
+func filterReadings(query *gorm.DB, filter ReadingFilter) *gorm.DB {
+    if filter.Status != "" {
+        query = query.Where("status = ?", filter.Status)
+    }
+    if filter.CourseID != nil {
+        query = query.Where("course_id = ?", *filter.CourseID)
+    }
+    if filter.AssignmentID != nil {
+        query = query.Where("assignment_id = ?", *filter.AssignmentID)
+    }
+    if filter.PaperID != nil {
+        query = query.Where("paper_id = ?", *filter.PaperID)
+    }
+    return query
+}
+
+func checkReadingLinks(tx *gorm.DB, userID uint, courseID, assignmentID, paperID *uint) error {
+    links := []struct {
+        name  string
+        model interface{}
+        id    *uint
+    }{
+        {"course", &models.Course{}, courseID},
+        {"assignment", &models.Assignment{}, assignmentID},
+        {"paper", &models.Paper{}, paperID},
+    }
+    for _, link := range links {
+        if link.id == nil {
+            continue
+        }
+        var count int64
+        if err := tx.Model(link.model).Where("id = ? AND user_id = ?", *link.id, userID).Count(&count).Error; err != nil {
+            return err
+        }
+        if count == 0 {
+            return fmt.Errorf("%w: %s %d", ErrReadingLinkNotFound, link.name, *link.id)
+        }
+    }
+    return nil
+}
+
+func linkDuplicate(tx *gorm.DB, reading *models.Reading, opts ReadingImportOptions) error {
+    updates := map[string]interface{}{}
+    if opts.CourseID != nil && reading.CourseID == nil {
+        updates["course_id"] = *opts.CourseID
+        reading.CourseID = opts.CourseID
+    }
+    if opts.AssignmentID != nil && reading.AssignmentID == nil {
+        updates["assignment_id"] = *opts.AssignmentID
+        reading.AssignmentID = opts.AssignmentID
+    }
+    if opts.PaperID != nil && reading.PaperID == nil {
+        updates["paper_id"] = *opts.PaperID
+        reading.PaperID = opts.PaperID
+    }
+    if len(updates) == 0 {
+        return nil
+    }
+    return tx.Model(&models.Reading{}).Where("id = ?", reading.ID).Updates(updates).Error
+}
+
+func detectReadingFormat(data []byte) string {
+    for _, line := range strings.Split(string(data), "\n") {
+        line = strings.TrimSpace(strings.TrimPrefix(line, "\ufeff"))
+        switch {
+        case line == "":
+            continue
+        case strings.HasPrefix(line, "TY  -"):
+            return FormatRIS
+        case strings.HasPrefix(line, "@") || strings.HasPrefix(line, "%"):
+            return FormatBibTeX
+        }
+    }
+    if bytes.Contains(data, []byte("@")) {
+        return FormatBibTeX
+    }
+    return ""
+}
+
+func readingFromEntry(entry *bibtex.Entry) *models.Reading {
+    reading := &models.Reading{
+        CiteKey:   entry.Key,
+        EntryType: entry.Type,
+        Title:     entry.Field("title"),
+        Authors:   entry.Names("author"),
+        DOI:       normalizeDOI(entry.Field("doi")),
+        URL:       entry.Field("url"),
+        Abstract:  entry.Field("abstract"),
+        BibFields: entry.Fields,
+    }
+    if len(reading.Authors) == 0 {
+        reading.Authors = entry.Names("editor")
+    }
+    if year, err := strconv.Atoi(entry.Field("year")); err == nil {
+        reading.Year = year
+    }
+    for _, field := range []string{"journal", "booktitle", "publisher", "school", "institution", "howpublished"} {
+        if venue := entry.Field(field); venue != "" {
+            reading.Venue = venue
+            break
+        }
+    }
+    return reading
+}
+
+// entryFromReading starts from the imported fields and overwrites the
+// ones the reading's own columns no longer agree with.
+func entryFromReading(reading *models.Reading) *bibtex.Entry {
+    entryType := reading.EntryType
+    if entryType == "" {
+        entryType = "misc"
+    }
+    entry := bibtex.NewEntry(entryType, reading.CiteKey)
+    for name, value := range reading.BibFields {
+        entry.Fields[name] = value
+    }
+
+    set := func(name, value string) {
+        if value == "" {
+            delete(entry.Fields, name)
+            return
+        }
+        if entry.Field(name) != value {
+            entry.Fields[name] = bibtex.EscapeField(name, value)
+        }
+    }
+    set("title", reading.Title)
+    set("doi", reading.DOI)
+    set("url", reading.URL)
+    set("abstract", reading.Abstract)
+    if strings.Join(entry.Names("author"), "\n") != strings.Join(reading.Authors, "\n") {
+        entry.Fields["author"] = bibtex.JoinNames(reading.Authors)
+    }
+    if reading.Year != 0 {
+        set("year", strconv.Itoa(reading.Year))
+    }
+
+    venueField := "journal"
+    for _, field := range []string{"journal", "booktitle", "publisher", "school", "institution", "howpublished"} {
+        if _, ok := entry.Fields[field]; ok {
+            venueField = field
+            break
+        }
+    }
+    if _, ok := entry.Fields[venueField]; !ok {
+        switch entryType {
+        case "inproceedings", "incollection", "conference":
+            venueField = "booktitle"
+        case "book":
+            venueField = "publisher"
+        case "phdthesis", "mastersthesis":
+            venueField = "school"
+        case "techreport":
+            venueField = "institution"
+        case "misc":
+            venueField = "howpublished"
+        }
+    }
+    set(venueField, reading.Venue)
+    return entry
+}
+
+func indexReading(reading *models.Reading, byDOI, byTitle map[string]*models.Reading) {
+    if reading.DOI != "" {
+        byDOI[reading.DOI] = reading
+    }
+    if key := titleKey(reading.Title); key != "" {
+        byTitle[key] = reading
+    }
+}
+
+func findDuplicate(reading *models.Reading, byDOI, byTitle map[string]*models.Reading) *models.Reading {
+    if reading.DOI != "" {
+        if match, ok := byDOI[reading.DOI]; ok {
+            return match
+        }
+    }
+    match, ok := byTitle[titleKey(reading.Title)]
+    // Two different DOIs mean two different works, even under one title
+    if !ok || (reading.DOI != "" && match.DOI != "" && match.DOI != reading.DOI) {
+        return nil
+    }
+    return match
+}
+
+func normalizeDOI(doi string) string {
+    return strings.ToLower(doiPrefix.ReplaceAllString(strings.TrimSpace(doi), ""))
+}
+
+func titleKey(title string) string {
+    var b strings.Builder
+    for _, r := range strings.ToLower(title) {
+        if unicode.IsLetter(r) || unicode.IsDigit(r) {
+            b.WriteRune(r)
+        }
+    }
+    return b.String()
+}
+
+// uniqueCiteKey keeps the requested key when it is free, otherwise builds
+// one like smith2020attention and adds a, b, ... until it is unused.
+func uniqueCiteKey(key string, reading *models.Reading, taken map[string]bool) string {
+    if key != "" && !taken[key] {
+        return key
+    }
+    if key == "" {
+        key = generateCiteKey(reading)
+    }
+    for suffix := 'a'; suffix <= 'z'; suffix++ {
+        if candidate := key + string(suffix); !taken[candidate] {
+            return candidate
+        }
+    }
+    for n := 2; ; n++ {
+        if candidate := key + strconv.Itoa(n); !taken[candidate] {
+            return candidate
+        }
+    }
+}
+
+func generateCiteKey(reading *models.Reading) string {
+    var key string
+    if len(reading.Authors) > 0 {
+        key = asciiWord(bibtex.LastName(reading.Authors[0]))
+    }
+    if key == "" {
+        key = "anon"
+    }
+    if reading.Year != 0 {
+        key += strconv.Itoa(reading.Year)
+    }
+    for _, word := range strings.Fields(reading.Title) {
+        word = asciiWord(word)
+        if len(word) > 3 && !stopWords[word] {
+            return key + word
+        }
+    }
+    return key
+}
+
+var stopWords = map[string]bool{"about": true, "from": true, "into": true, "over": true, "that": true, "their": true, "these": true, "this": true, "towards": true, "with": true, "when": true, "what": true}
+
+func asciiWord(s string) string {
+    var b strings.Builder
+    for _, r := range strings.ToLower(s) {
+        if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+            b.WriteRune(r)
+        }
+    }
+    return b.String()
+}
+
+func citeKeys(tx *gorm.DB, userID, excludeID uint) (map[string]bool, error) {
+    var keys []string
+    if err := tx.Model(&models.Reading{}).Where("user_id = ? AND id <> ?", userID, excludeID).
+        Pluck("cite_key", &keys).Error; err != nil {
+        return nil, err
+    }
+    taken := make(map[string]bool, len(keys))
+    for _, key := range keys {
+        taken[key] = true
+    }
+    return taken, nil
+}
+
+func normalizeReading(reading *models.Reading, now time.Time) error {
+    if reading.Title == "" {
+        return fmt.Errorf("%w: title is required", ErrInvalidReading)
+    }
+    if reading.Status == "" {
+        reading.Status = ReadingToRead
+    }
+    if !containsString(readingStatuses, reading.Status) {
+        return fmt.Errorf("%w: status must be one of %s", ErrInvalidReading, strings.Join(readingStatuses, ", "))
+    }
+    if reading.Status == ReadingRead && reading.ReadAt == nil {
+        reading.ReadAt = &now
+    }
+    if strings.ContainsAny(reading.CiteKey, " \t,{}\"#%'()=") {
+        return fmt.Errorf("%w: cite key %q contains characters BibTeX does not allow", ErrInvalidReading, reading.CiteKey)
+    }
+    reading.EntryType = strings.ToLower(strings.TrimSpace(reading.EntryType))
+    if reading.Authors == nil {
+        reading.Authors = []string{}
+    }
+    return nil
+}
@@ -0,0 +1,540 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "github.com/anayy09/academiaflow-backend/internal/recurrence"
+    "gorm.io/gorm"
+)
+
+// How far ahead of today occurrences are materialized as Assignment rows.
+const materializeHorizon = 8 * 7 * 24 * time.Hour
+
+const (
+    ScopeThis      = "this"
+    ScopeFollowing = "following"
+    ScopeAll       = "all"
+)
+
+var (
+    ErrInvalidScope  = errors.New("invalid scope; use this, following or all")
+    ErrNotRecurring  = errors.New("assignment is not part of a recurring series")
+    ErrInvalidSeries = errors.New("invalid series")
+)
+
+// Fields copied from a series onto each of its occurrences.
+var seriesTemplateFields = []string{"title", "description", "priority", "estimated_hours", "course_id"}
+
+type RecurrenceService struct {
+    db *gorm.DB
+}
+
+func NewRecurrenceService() *RecurrenceService {
+    return &RecurrenceService{
+        db: database.GetDB(),
+    }
+}
+
+type CreateSeriesRequest struct {
+    CourseID       *uint       `json:"course_id"`
+    Title          string      `json:"title" binding:"required"`
+    Description    string      `json:"description"`
+    Priority       string      `json:"priority"`
+    EstimatedHours int         `json:"estimated_hours"`
+    DTStart        time.Time   `json:"dtstart" binding:"required"`
+    TimeZone       string      `json:"timezone"`
+    RRule          string      `json:"rrule" binding:"required"`
+    ExDates        []time.Time `json:"exdates"`
+}
+
+func (s *RecurrenceService) GetUserSeries(userID uint) ([]models.AssignmentSeries, error) {
+    var series []models.AssignmentSeries
+    err := s.db.Where("user_id = ?", userID).Preload("Course").Order("dtstart ASC").Find(&series).Error
+    return series, err
+}
+
+func (s *RecurrenceService) GetSeries(userID, seriesID uint) (*models.AssignmentSeries, error) {
+    var series models.AssignmentSeries
+    err := s.db.Where("id = ? AND user_id = ?", seriesID, userID).Preload("Course").First(&series).Error
+    return &series, err
+}
+
+func (s *RecurrenceService) GetSeriesOccurrences(userID, seriesID uint) ([]models.Assignment, error) {
+    var assignments []models.Assignment
+    err := s.db.Where("series_id = ? AND user_id = ?", seriesID, userID).
+        Order("occurrence_date ASC").
+        Find(&assignments).Error
+    return assignments, err
+}
+
+func (s *RecurrenceService) CreateSeries(userID uint, req CreateSeriesRequest) (*models.AssignmentSeries, error) {
+    rule, err := recurrence.Parse(req.RRule)
+    if err != nil {
+        return nil, fmt.Errorf("%w: %v", ErrInvalidSeries, err)
+    }
+    if req.TimeZone == "" {
+        req.TimeZone = "UTC"
+    }
+    loc, err := time.LoadLocation(req.TimeZone)
+    if err != nil {
+        return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidSeries, req.TimeZone)
+    }
+
+    series := models.AssignmentSeries{
+        UserID:         userID,
+        CourseID:       req.CourseID,
+        Title:          req.Title,
+        Description:    req.Description,
+        Priority:       req.Priority,
+        EstimatedHours: req.EstimatedHours,
+        RRule:          rule.String(),
+        DTStart:        req.DTStart.In(loc),
+        TimeZone:       req.TimeZone,
+        ExDates:        req.ExDates,
+    }
+
+    if series.Priority == "" {
+        series.Priority = "medium"
+    }
+
+    err = s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(&series).Error; err != nil {
+            return err
+        }
+        return s.materialize(tx, &series, horizonFor(&series))
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return s.GetSeries(userID, series.ID)
+}
+
+// MaterializeUpcoming tops up every series of the user so occurrences
+// exist up to the materialization horizon.
+func (s *RecurrenceService) MaterializeUpcoming(userID uint) error {
+    var series []models.AssignmentSeries
+    if err := s.db.Where("user_id = ? AND materialized_until < ?", userID, time.Now().Add(materializeHorizon)).
+        Find(&series).Error; err != nil {
+        return err
+    }
+
+    for i := range series {
+        err := s.db.Transaction(func(tx *gorm.DB) error {
+            return s.materialize(tx, &series[i], horizonFor(&series[i]))
+        })
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// UpdateSeriesFrom applies updates to the series that the assignment
+// belongs to, either for the whole series or for this occurrence and every
+// one after it. The latter splits the series in two.
+func (s *RecurrenceService) UpdateSeriesFrom(userID, assignmentID uint, scope string, updates map[string]interface{}) (*models.AssignmentSeries, error) {
+    if scope != ScopeFollowing && scope != ScopeAll {
+        return nil, fmt.Errorf("%w: %q", ErrInvalidScope, scope)
+    }
+    assignment, series, err := s.loadOccurrence(userID, assignmentID)
+    if err != nil {
+        return nil, err
+    }
+
+    target := series
+    err = s.db.Transaction(func(tx *gorm.DB) error {
+        switch scope {
+        case ScopeAll:
+            return s.updateAll(tx, series, updates)
+        case ScopeFollowing:
+            if !assignment.OccurrenceDate.After(series.DTStart) {
+                return s.updateAll(tx, series, updates)
+            }
+            var err error
+            target, err = s.splitSeries(tx, series, *assignment.OccurrenceDate)
+            if err != nil {
+                return err
+            }
+            return s.updateAll(tx, target, updates)
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return s.GetSeries(userID, target.ID)
+}
+
+func (s *RecurrenceService) UpdateSeries(userID, seriesID uint, updates map[string]interface{}) (*models.AssignmentSeries, error) {
+    var series models.AssignmentSeries
+    if err := s.db.Where("id = ? AND user_id = ?", seriesID, userID).First(&series).Error; err != nil {
+        return nil, err
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        return s.updateAll(tx, &series, updates)
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return s.GetSeries(userID, series.ID)
+}
+
+// DeleteOccurrence removes this occurrence, this and following, or the whole
+// series. Completed occurrences are kept as history.
+func (s *RecurrenceService) DeleteOccurrence(userID, assignmentID uint, scope string) error {
+    if scope != ScopeThis && scope != ScopeFollowing && scope != ScopeAll {
+        return fmt.Errorf("%w: %q", ErrInvalidScope, scope)
+    }
+    assignment, series, err := s.loadOccurrence(userID, assignmentID)
+    if err != nil {
+        return err
+    }
+
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        switch scope {
+        case ScopeThis:
+            series.ExDates = append(series.ExDates, *assignment.OccurrenceDate)
+            if err := tx.Save(series).Error; err != nil {
+                return err
+            }
+            return tx.Delete(assignment).Error
+        case ScopeFollowing:
+            if !assignment.OccurrenceDate.After(series.DTStart) {
+                return s.deleteSeries(tx, series)
+            }
+            if err := s.truncateSeries(tx, series, *assignment.OccurrenceDate); err != nil {
+                return err
+            }
+            return tx.Where("series_id = ? AND user_id = ? AND occurrence_date >= ? AND status <> ?", series.ID, series.UserID, *assignment.OccurrenceDate, "completed").
+                Delete(&models.Assignment{}).Error
+        default: // ScopeAll
+            return s.deleteSeries(tx, series)
+        }
+    })
+}
+
+func (s *RecurrenceService) DeleteSeries(userID, seriesID uint) error {
+    var series models.AssignmentSeries
+    if err := s.db.Where("id = ? AND user_id = ?", seriesID, userID).First(&series).Error; err != nil {
+        return err
+    }
+
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        return s.deleteSeries(tx, &series)
+    })
+}
+
+func (s *RecurrenceService) loadOccurrence(userID, assignmentID uint) (*models.Assignment, *models.AssignmentSeries, error) {
+    var assignment models.Assignment
+    if err := s.db.Where("id = ? AND user_id = ?", assignmentID, userID).First(&assignment).Error; err != nil {
+        return nil, nil, err
+    }
+    if assignment.SeriesID == nil || assignment.OccurrenceDate == nil {
+        return nil, nil, ErrNotRecurring
+    }
+
+    var series models.AssignmentSeries
+    if err := s.db.Where("id = ? AND user_id = ?", *assignment.SeriesID, userID).First(&series).Error; err != nil {
+        return nil, nil, err
+    }
+    return &assignment, &series, nil
+}
+
+// materialize creates the occurrences between the series' previous
+// horizon and until. An occurrence that already has a row, even a deleted
+// one, is never recreated.
+func (s *RecurrenceService) materialize(tx *gorm.DB, series *models.AssignmentSeries, until time.Time) error {
+    rule, err := recurrence.Parse(series.RRule)
+    if err != nil {
+        return err
+    }
+
+    dtstart := series.DTStart.In(seriesLocation(series))
+    from := series.MaterializedUntil
+    if from.Before(dtstart) {
+        from = dtstart
+    }
+
+    for _, occ := range rule.Between(dtstart, series.ExDates, from, until) {
+        var count int64
+        if err := tx.Unscoped().Model(&models.Assignment{}).
+            Where("series_id = ? AND user_id = ? AND occurrence_date = ?", series.ID, series.UserID, occ).
+            Count(&count).Error; err != nil {
+            return err
+        }
+        if count > 0 {
+            continue
+        }
+
+        occurrence := occ
+        assignment := models.Assignment{
+            UserID:         series.UserID,
+            CourseID:       series.CourseID,
+            Title:          series.Title,
+            Description:    series.Description,
+            DueDate:        occ,
+            Priority:       series.Priority,
+            EstimatedHours: series.EstimatedHours,
+            Status:         "pending",
+            SeriesID:       &series.ID,
+            OccurrenceDate: &occurrence,
+        }
+        if err := tx.Create(&assignment).Error; err != nil {
+            return err
+        }
+    }
+
+    series.MaterializedUntil = until
+    return tx.Model(series).Update("materialized_until", until).Error
+}
+
+func (s *RecurrenceService) updateAll(tx *gorm.DB, series *models.AssignmentSeries, updates map[string]interface{}) error {
+    instanceUpdates, ruleChanged, err := applySeriesUpdates(series, updates)
+    if err != nil {
+        return err
+    }
+    if err := tx.Save(series).Error; err != nil {
+        return err
+    }
+
+    if len(instanceUpdates) > 0 {
+        if err := tx.Model(&models.Assignment{}).
+            Where("series_id = ? AND user_id = ? AND is_exception = ?", series.ID, series.UserID, false).
+            Updates(instanceUpdates).Error; err != nil {
+            return err
+        }
+    }
+
+    if !ruleChanged {
+        return nil
+    }
+
+    // The schedule changed: drop untouched pending occurrences outright so
+    // they can be regenerated on the new dates. Occurrences the user deleted
+    // stay soft-deleted and keep blocking their dates.
+    if err := tx.Unscoped().
+        Where("series_id = ? AND user_id = ? AND is_exception = ? AND status = ? AND deleted_at IS NULL", series.ID, series.UserID, false, "pending").
+        Delete(&models.Assignment{}).Error; err != nil {
+        return err
+    }
+    series.MaterializedUntil = time.Time{}
+    return s.materialize(tx, series, horizonFor(series))
+}
+
+// splitSeries ends series just before pivot and moves the remaining
+// occurrences to a new series that starts at pivot.
+func (s *RecurrenceService) splitSeries(tx *gorm.DB, series *models.AssignmentSeries, pivot time.Time) (*models.AssignmentSeries, error) {
+    rule, err := recurrence.Parse(series.RRule)
+    if err != nil {
+        return nil, err
+    }
+
+    tail := *series
+    tail.ID = 0
+    tail.CreatedAt = time.Time{}
+    tail.UpdatedAt = time.Time{}
+    tail.DTStart = pivot
+    tail.ExDates = filterTimes(series.ExDates, func(t time.Time) bool { return !t.Before(pivot) })
+    if rule.Count > 0 {
+        tailRule := *rule
+        tailRule.Count = rule.Count - rule.IndexOf(series.DTStart.In(seriesLocation(series)), pivot)
+        tail.RRule = tailRule.String()
+    }
+    if err := tx.Create(&tail).Error; err != nil {
+        return nil, err
+    }
+
+    if err := s.truncateSeries(tx, series, pivot); err != nil {
+        return nil, err
+    }
+
+    if err := tx.Unscoped().Model(&models.Assignment{}).
+        Where("series_id = ? AND user_id = ? AND occurrence_date >= ?", series.ID, series.UserID, pivot).
+        Update("series_id", tail.ID).Error; err != nil {
+        return nil, err
+    }
+
+    return &tail, nil
+}
+
+// truncateSeries makes pivot the exclusive end of the series.
+func (s *RecurrenceService) truncateSeries(tx *gorm.DB, series *models.AssignmentSeries, pivot time.Time) error {
+    rule, err := recurrence.Parse(series.RRule)
+    if err != nil {
+        return err
+    }
+
+    until := pivot.Add(-time.Second).UTC()
+    rule.Count = 0
+    rule.Until = &until
+
+    series.RRule = rule.String()
+    series.ExDates = filterTimes(series.ExDates, func(t time.Time) bool { return t.Before(pivot) })
+    if series.MaterializedUntil.After(pivot) {
+        series.MaterializedUntil = pivot
+    }
+    return tx.Save(series).Error
+}
+
+func (s *RecurrenceService) deleteSeries(tx *gorm.DB, series *models.AssignmentSeries) error {
+    // Completed occurrences are history; keep them as standalone assignments.
+    if err := tx.Model(&models.Assignment{}).
+        Where("series_id = ? AND user_id = ? AND status = ?", series.ID, series.UserID, "completed").
+        Updates(map[string]interface{}{"series_id": nil, "occurrence_date": nil}).Error; err != nil {
+        return err
+    }
+    if err := tx.Where("series_id = ? AND user_id = ?", series.ID, series.UserID).Delete(&models.Assignment{}).Error; err != nil {
+        return err
+    }
+    return tx.Delete(series).Error
+}
+
+// applySeriesUpdates writes the update map onto the series and returns the
+// subset that should be copied to its occurrences, plus whether the
+// schedule itself changed.
+func applySeriesUpdates(series *models.AssignmentSeries, updates map[string]interface{}) (map[string]interface{}, bool, error) {
+    instanceUpdates := map[string]interface{}{}
+    ruleChanged := false
+
+    for key, value := range updates {
+        switch key {
+        case "title", "description", "priority":
+            str, ok := value.(string)
+            if !ok {
+                return nil, false, fmt.Errorf("%w: %s must be a string", ErrInvalidSeries, key)
+            }
+            switch key {
+            case "title":
+                series.Title = str
+            case "description":
+                series.Description = str
+            case "priority":
+                series.Priority = str
+            }
+            instanceUpdates[key] = str
+        case "estimated_hours":
+            n, ok := toInt(value)
+            if !ok {
+                return nil, false, fmt.Errorf("%w: estimated_hours must be a number", ErrInvalidSeries)
+            }
+            series.EstimatedHours = n
+            instanceUpdates[key] = n
+        case "course_id":
+            if value == nil {
+                series.CourseID = nil
+                instanceUpdates[key] = nil
+                continue
+            }
+            n, ok := toInt(value)
+            if !ok || n <= 0 {
+                return nil, false, fmt.Errorf("%w: course_id must be a positive number", ErrInvalidSeries)
+            }
+            id := uint(n)
+            series.CourseID = &id
+            instanceUpdates[key] = id
+        case "rrule":
+            str, _ := value.(string)
+            rule, err := recurrence.Parse(str)
+            if err != nil {
+                return nil, false, fmt.Errorf("%w: %v", ErrInvalidSeries, err)
+            }
+            series.RRule = rule.String()
+            ruleChanged = true
+        case "dtstart", "due_date":
+            t, err := toTime(value)
+            if err != nil {
+                return nil, false, fmt.Errorf("%w: %s must be an RFC 3339 timestamp", ErrInvalidSeries, key)
+            }
+            series.DTStart = t
+            ruleChanged = true
+        case "timezone":
+            str, _ := value.(string)
+            if _, err := time.LoadLocation(str); err != nil {
+                return nil, false, fmt.Errorf("%w: unknown time zone %q", ErrInvalidSeries, str)
+            }
+            series.TimeZone = str
+            ruleChanged = true
+        default:
+            return nil, false, fmt.Errorf("%w: %s cannot be changed for a series", ErrInvalidSeries, key)
+        }
+    }
+
+    return instanceUpdates, ruleChanged, nil
+}
+
+// touchesSeriesFields reports whether an update to a single occurrence
+// diverges it from its series template.
+func touchesSeriesFields(updates map[string]interface{}) bool {
+    if _, ok := updates["due_date"]; ok {
+        return true
+    }
+    for _, field := range seriesTemplateFields {
+        if _, ok := updates[field]; ok {
+            return true
+        }
+    }
+    return false
+}
+
+func horizonFor(series *models.AssignmentSeries) time.Time {
+    start := time.Now()
+    if series.DTStart.After(start) {
+        start = series.DTStart
+    }
+    return start.Add(materializeHorizon)
+}
+
+func seriesLocation(series *models.AssignmentSeries) *time.Location {
+    if series.TimeZone == "" {
+        return time.UTC
+    }
+    loc, err := time.LoadLocation(series.TimeZone)
+    if err != nil {
+        return time.UTC
+    }
+    return loc
+}
+
+func filterTimes(list []time.Time, keep func(time.Time) bool) []time.Time {
+    var result []time.Time
+    for _, t := range list {
+        if keep(t) {
+            result = append(result, t)
+        }
+    }
+    return result
+}
+
+// toInt accepts the number types that arrive from decoded JSON as well as
+// plain ints from callers inside the package.
+func toInt(value interface{}) (int, bool) {
+    switch v := value.(type) {
+    case float64:
+        return int(v), true
+    case int:
+        return v, true
+    case uint:
+        return int(v), true
+    default:
+        return 0, false
+    }
+}
+
+func toTime(value interface{}) (time.Time, error) {
+    switch v := value.(type) {
+    case time.Time:
+        return v, nil
+    case string:
+        return time.Parse(time.RFC3339, v)
+    default:
+        return time.Time{}, errors.New("not a timestamp")
+    }
+}
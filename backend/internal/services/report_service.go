@@ -0,0 +1,533 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "net/mail"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/jobs"
+    mailer "github.com/anayy09/academiaflow-backend/internal/mail"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "github.com/anayy09/academiaflow-backend/internal/report"
+    "gorm.io/gorm"
+)
+
+const (
+    // Deadlines this far past the end of the report are listed as upcoming
+    reportUpcomingDays = 14
+    // Schedules default to Monday morning, covering the week just ended
+    defaultReportWeekday = int(time.Monday)
+    defaultReportHour    = 8
+    // Users can send a report by hand once per interval, and a new
+    // advisor email is only mailed once it has stood for the cooldown, so
+    // a hijacked session can't turn the server into a mail relay
+    manualReportInterval = 15 * time.Minute
+    advisorEmailCooldown = 24 * time.Hour
+
+    JobWeeklyReports = "reports.weekly"
+)
+
+var (
+    ErrInvalidWeek       = errors.New("invalid week")
+    ErrInvalidSchedule   = errors.New("invalid report schedule")
+    ErrNoRecipients      = errors.New("no report recipients; set an advisor email or schedule recipients")
+    // Reports only go to the advisor or the user, so the server can't be
+    // used to mail arbitrary addresses
+    ErrInvalidRecipient  = errors.New("invalid report recipient")
+    ErrReportRateLimited = errors.New("a report was sent recently; try again later")
+    ErrAdvisorEmailNew   = errors.New("the advisor email was changed recently; reports can be sent to it a day after the change")
+)
+
+var isoWeekPattern = regexp.MustCompile(`^(\d{4})-?W(\d{1,2})$`)
+
+type ReportService struct {
+    db     *gorm.DB
+    mailer mailer.Mailer
+}
+
+func NewReportService(m mailer.Mailer) *ReportService {
+    return &ReportService{
+        db:     database.GetDB(),
+        mailer: m,
+    }
+}
+
+type UpdateReportScheduleRequest struct {
+    Enabled    *bool     `json:"enabled"`
+    Weekday    *int      `json:"weekday"` // 0 = Sunday
+    Hour       *int      `json:"hour"`
+    TimeZone   *string   `json:"time_zone"`
+    Recipients *[]string `json:"recipients"`
+    Format     *string   `json:"format"` // html or text
+}
+
+// ReportLocation is the time zone reports are computed in when the caller
+// doesn't name one: the schedule's, else the planner's.
+func (s *ReportService) ReportLocation(userID uint) *time.Location {
+    var schedule models.ReportSchedule
+    if err := s.db.Where("user_id = ?", userID).First(&schedule).Error; err == nil && schedule.TimeZone != "" {
+        if loc, err := time.LoadLocation(schedule.TimeZone); err == nil {
+            return loc
+        }
+    }
+    var availability models.Availability
+    if err := s.db.Where("user_id = ?", userID).First(&availability).Error; err == nil {
+        if loc, err := time.LoadLocation(availability.TimeZone); err == nil {
+            return loc
+        }
+    }
+    return time.UTC
+}
+
+// ParseWeek returns the Monday starting the given week: an ISO week such
+// as 2025-W14, any date within the week, or the current week when empty.
+func ParseWeek(week string, loc *time.Location, now time.Time) (time.Time, error) {
+    week = strings.TrimSpace(week)
+    if week == "" {
+        return startOfWeek(now, loc), nil
+    }
+    if m := isoWeekPattern.FindStringSubmatch(strings.ToUpper(week)); m != nil {
+        year, _ := strconv.Atoi(m[1])
+        n, _ := strconv.Atoi(m[2])
+        // January 4th is always in week 1
+        first := startOfWeek(time.Date(year, time.January, 4, 12, 0, 0, 0, loc), loc)
+        start := first.AddDate(0, 0, (n-1)*7)
+        if y, w := start.ISOWeek(); n < 1 || y != year || w != n {
+            return time.Time{}, fmt.Errorf("%w: %s has no week %d", ErrInvalidWeek, m[1], n)
+        }
+        return start, nil
+    }
+    day, err := time.ParseInLocation(dateLayout, week, loc)
+    if err != nil {
+        return time.Time{}, fmt.Errorf("%w: use YYYY-Www or YYYY-MM-DD", ErrInvalidWeek)
+    }
+    return startOfWeek(day, loc), nil
+}
+
+// Weekly builds the progress report for the week starting at start.
+// Overdue and upcoming work is measured from the end of the week, or from
+// now for the week in progress.
+func (s *ReportService) Weekly(userID uint, start, now time.Time) (*report.Weekly, error) {
+    var user models.User
+    if err := s.db.First(&user, userID).Error; err != nil {
+        return nil, err
+    }
+    end := start.AddDate(0, 0, 7)
+    asOf := end
+    if now.Before(end) {
+        asOf = now
+    }
+
+    year, week := start.ISOWeek()
+    r := &report.Weekly{
+        Student:       strings.TrimSpace(user.FirstName + " " + user.LastName),
+        Program:       user.Program,
+        Advisor:       user.Advisor,
+        Week:          fmt.Sprintf("%d-W%02d", year, week),
+        Start:         start,
+        End:           end,
+        UpcomingDays:  reportUpcomingDays,
+        Completed:     []report.Item{},
+        HoursByCourse: []report.CourseHours{},
+        Upcoming:      []report.Item{},
+        Overdue:       []report.Item{},
+        Milestones:    []report.MilestoneChange{},
+    }
+    if r.Student == "" {
+        r.Student = user.Username
+    }
+
+    // Hours come from finished time entries started during the week
+    var entries []models.TimeEntry
+    if err := s.db.Where("user_id = ? AND started_at >= ? AND started_at < ? AND ended_at IS NOT NULL", userID, start, end).
+        Preload("Assignment.Course").
+        Find(&entries).Error; err != nil {
+        return nil, err
+    }
+    minutesByAssignment := map[uint]int{}
+    minutesByCourse := map[string]int{}
+    total := 0
+    for _, entry := range entries {
+        total += entry.Minutes
+        minutesByAssignment[entry.AssignmentID] += entry.Minutes
+        minutesByCourse[reportCourse(entry.Assignment)] += entry.Minutes
+    }
+    r.Hours = minutesToHours(total)
+    for course, minutes := range minutesByCourse {
+        r.HoursByCourse = append(r.HoursByCourse, report.CourseHours{Course: course, Hours: minutesToHours(minutes)})
+    }
+    sort.Slice(r.HoursByCourse, func(i, j int) bool {
+        if r.HoursByCourse[i].Hours != r.HoursByCourse[j].Hours {
+            return r.HoursByCourse[i].Hours > r.HoursByCourse[j].Hours
+        }
+        return r.HoursByCourse[i].Course < r.HoursByCourse[j].Course
+    })
+
+    var completed []models.Assignment
+    if err := s.db.Where("user_id = ? AND status = ? AND completed_at >= ? AND completed_at < ?", userID, "completed", start, end).
+        Preload("Course").
+        Order("completed_at ASC").
+        Find(&completed).Error; err != nil {
+        return nil, err
+    }
+    for i := range completed {
+        r.Completed = append(r.Completed, reportItem(&completed[i], minutesByAssignment))
+    }
+
+    var open []models.Assignment
+    if err := s.db.Where("user_id = ? AND status <> ? AND due_date < ?", userID, "completed", asOf.AddDate(0, 0, reportUpcomingDays)).
+        Preload("Course").
+        Order("due_date ASC").
+        Find(&open).Error; err != nil {
+        return nil, err
+    }
+    for i := range open {
+        item := reportItem(&open[i], minutesByAssignment)
+        if open[i].DueDate.Before(asOf) {
+            r.Overdue = append(r.Overdue, item)
+        } else {
+            r.Upcoming = append(r.Upcoming, item)
+        }
+    }
+
+    var milestones []models.Milestone
+    if err := s.db.Where("user_id = ? AND ((updated_at >= ? AND updated_at < ?) OR (completed_date >= ? AND completed_date < ?))", userID, start, end, start, end).
+        Order("target_date ASC").
+        Find(&milestones).Error; err != nil {
+        return nil, err
+    }
+    for _, m := range milestones {
+        r.Milestones = append(r.Milestones, report.MilestoneChange{
+            ID:            m.ID,
+            Name:          m.Name,
+            Type:          m.Type,
+            Status:        m.Status,
+            TargetDate:    m.TargetDate,
+            CompletedDate: m.CompletedDate,
+            Completed:     m.CompletedDate != nil && !m.CompletedDate.Before(start) && m.CompletedDate.Before(end),
+        })
+    }
+    return r, nil
+}
+
+func (s *ReportService) GetSchedule(userID uint) (*models.ReportSchedule, error) {
+    var schedule models.ReportSchedule
+    err := s.db.Where("user_id = ?", userID).First(&schedule).Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return s.defaultSchedule(userID), nil
+    }
+    return &schedule, err
+}
+
+func (s *ReportService) UpdateSchedule(userID uint, req UpdateReportScheduleRequest, now time.Time) (*models.ReportSchedule, error) {
+    schedule, err := s.GetSchedule(userID)
+    if err != nil {
+        return nil, err
+    }
+    if req.Enabled != nil {
+        schedule.Enabled = *req.Enabled
+    }
+    if req.Weekday != nil {
+        schedule.Weekday = *req.Weekday
+    }
+    if req.Hour != nil {
+        schedule.Hour = *req.Hour
+    }
+    if req.TimeZone != nil {
+        schedule.TimeZone = *req.TimeZone
+    }
+    if req.Recipients != nil {
+        schedule.Recipients = *req.Recipients
+    }
+    if req.Format != nil {
+        schedule.Format = *req.Format
+    }
+    if err := normalizeSchedule(schedule); err != nil {
+        return nil, err
+    }
+    if len(schedule.Recipients) > 0 {
+        allowed, err := s.allowedRecipients(userID)
+        if err != nil {
+            return nil, err
+        }
+        if err := checkRecipients(schedule.Recipients, allowed); err != nil {
+            return nil, fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+        }
+    }
+
+    schedule.NextRunAt = nil
+    if schedule.Enabled {
+        next := nextReportRun(schedule, now)
+        schedule.NextRunAt = &next
+    }
+    if err := s.db.Save(schedule).Error; err != nil {
+        return nil, err
+    }
+    return schedule, nil
+}
+
+// SendWeekly emails the report for the week starting at start. Recipients
+// default to the schedule's, then to the advisor's email, and must be the
+// advisor or the user.
+func (s *ReportService) SendWeekly(userID uint, start time.Time, recipients []string, now time.Time) (*report.Weekly, []string, error) {
+    schedule, err := s.GetSchedule(userID)
+    if err != nil {
+        return nil, nil, err
+    }
+    var user models.User
+    if err := s.db.First(&user, userID).Error; err != nil {
+        return nil, nil, err
+    }
+    if len(recipients) == 0 {
+        recipients = schedule.Recipients
+    }
+    if len(recipients) == 0 && user.AdvisorEmail != "" {
+        recipients = []string{user.AdvisorEmail}
+    }
+    if len(recipients) == 0 {
+        return nil, nil, ErrNoRecipients
+    }
+    if err := checkRecipients(recipients, userRecipients(&user)); err != nil {
+        return nil, nil, err
+    }
+    if newAdvisorEmail(&user, recipients, now) {
+        return nil, nil, ErrAdvisorEmailNew
+    }
+
+    r, err := s.Weekly(userID, start, now)
+    if err != nil {
+        return nil, nil, err
+    }
+    msg := mailer.Message{To: recipients, Subject: r.Subject()}
+    if msg.Text, err = r.Render(report.FormatText); err != nil {
+        return nil, nil, err
+    }
+    if schedule.Format != report.FormatText {
+        if msg.HTML, err = r.Render(report.FormatHTML); err != nil {
+            return nil, nil, err
+        }
+    }
+    if err := s.mailer.Send(msg); err != nil {
+        return nil, nil, err
+    }
+    return r, recipients, nil
+}
+
+// SendNow is SendWeekly for a report the user asked for, limited to one
+// per manualReportInterval. The slot is claimed before sending, so
+// concurrent requests can't both get through.
+func (s *ReportService) SendNow(userID uint, start time.Time, recipients []string, now time.Time) (*report.Weekly, []string, error) {
+    claim := s.db.Model(&models.User{}).
+        Where("id = ? AND (report_sent_at IS NULL OR report_sent_at <= ?)", userID, now.Add(-manualReportInterval)).
+        UpdateColumn("report_sent_at", now)
+    if claim.Error != nil {
+        return nil, nil, claim.Error
+    }
+    if claim.RowsAffected == 0 {
+        return nil, nil, ErrReportRateLimited
+    }
+    return s.SendWeekly(userID, start, recipients, now)
+}
+
+// RunDue sends every scheduled report that has come due, covering the
+// week before the one it runs in. Each schedule is claimed by moving its
+// next run forward before sending, so concurrent runners never send the
+// same report twice.
+func (s *ReportService) RunDue(now time.Time) (int, error) {
+    var due []models.ReportSchedule
+    if err := s.db.Where("enabled = ? AND next_run_at <= ?", true, now).Find(&due).Error; err != nil {
+        return 0, err
+    }
+
+    sent := 0
+    for i := range due {
+        schedule := &due[i]
+        runAt := *schedule.NextRunAt
+        next := nextReportRun(schedule, now)
+        claim := s.db.Model(&models.ReportSchedule{}).
+            Where("id = ? AND next_run_at = ?", schedule.ID, runAt).
+            Update("next_run_at", next)
+        if claim.Error != nil {
+            return sent, claim.Error
+        }
+        if claim.RowsAffected == 0 {
+            continue
+        }
+
+        loc, err := time.LoadLocation(schedule.TimeZone)
+        if err != nil {
+            loc = time.UTC
+        }
+        start := startOfWeek(runAt, loc).AddDate(0, 0, -7)
+        updates := map[string]interface{}{"last_error": ""}
+        if _, _, err := s.SendWeekly(schedule.UserID, start, nil, now); err != nil {
+            updates["last_error"] = err.Error()
+            log.Printf("weekly report for user %d: %v", schedule.UserID, err)
+        } else {
+            updates["last_sent_at"] = now
+            sent++
+        }
+        if err := s.db.Model(&models.ReportSchedule{}).Where("id = ?", schedule.ID).Updates(updates).Error; err != nil {
+            return sent, err
+        }
+    }
+    return sent, nil
+}
+
+// RegisterJobs has the scheduler check for due reports every ten
+// minutes.
+func (s *ReportService) RegisterJobs(scheduler *jobs.Scheduler, now time.Time) error {
+    scheduler.Register(JobWeeklyReports, func(ctx context.Context, job *models.Job) error {
+        _, err := s.RunDue(time.Now())
+        return err
+    })
+    return scheduler.Every(JobWeeklyReports, 10*time.Minute, now)
+}
+
+func (s *ReportService) defaultSchedule(userID uint) *models.ReportSchedule {
+    return &models.ReportSchedule{
+        UserID:     userID,
+        Weekday:    defaultReportWeekday,
+        Hour:       defaultReportHour,
+        TimeZone:   s.ReportLocation(userID).String(),
+        Recipients: []string{},
+        Format:     report.FormatHTML,
+    }
+}
+
+func normalizeSchedule(schedule *models.ReportSchedule) error {
+    if schedule.Weekday < 0 || schedule.Weekday > 6 {
+        return fmt.Errorf("%w: weekday must be 0 (Sunday) to 6", ErrInvalidSchedule)
+    }
+    if schedule.Hour < 0 || schedule.Hour > 23 {
+        return fmt.Errorf("%w: hour must be 0 to 23", ErrInvalidSchedule)
+    }
+    if schedule.TimeZone == "" {
+        schedule.TimeZone = "UTC"
+    }
+    if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+        return fmt.Errorf("%w: unknown time zone %q", ErrInvalidSchedule, schedule.TimeZone)
+    }
+    switch schedule.Format {
+    case "":
+        schedule.Format = report.FormatHTML
+    case report.FormatHTML, report.FormatText:
+    default:
+        return fmt.Errorf("%w: format must be html or text", ErrInvalidSchedule)
+    }
+
+    recipients := []string{}
+    for _, r := range schedule.Recipients {
+        if r = strings.TrimSpace(r); r != "" && !containsString(recipients, r) {
+            recipients = append(recipients, r)
+        }
+    }
+    schedule.Recipients = recipients
+    return nil
+}
+
+func (s *ReportService) allowedRecipients(userID uint) ([]string, error) {
+    var user models.User
+    if err := s.db.First(&user, userID).Error; err != nil {
+        return nil, err
+    }
+    return userRecipients(&user), nil
+}
+
+// userRecipients lists the addresses a user's report may be sent to: the
+// advisor's and the user's own.
+func userRecipients(user *models.User) []string {
+    var allowed []string
+    for _, addr := range []string{user.AdvisorEmail, user.Email} {
+        if addr != "" {
+            allowed = append(allowed, addr)
+        }
+    }
+    return allowed
+}
+
+func checkRecipients(recipients, allowed []string) error {
+    for _, r := range recipients {
+        addr, err := mail.ParseAddress(r)
+        if err != nil {
+            return fmt.Errorf("%w: %q is not an email address", ErrInvalidRecipient, r)
+        }
+        permitted := false
+        for _, a := range allowed {
+            if strings.EqualFold(addr.Address, a) {
+                permitted = true
+                break
+            }
+        }
+        if !permitted {
+            return fmt.Errorf("%w: %s is neither your advisor's email nor your own", ErrInvalidRecipient, addr.Address)
+        }
+    }
+    return nil
+}
+
+// newAdvisorEmail reports whether recipients include an advisor email
+// that is still within its cooldown. The user's own address is exempt.
+func newAdvisorEmail(user *models.User, recipients []string, now time.Time) bool {
+    if user.AdvisorEmailChangedAt == nil || now.Sub(*user.AdvisorEmailChangedAt) >= advisorEmailCooldown {
+        return false
+    }
+    for _, r := range recipients {
+        addr, err := mail.ParseAddress(r)
+        if err == nil && strings.EqualFold(addr.Address, user.AdvisorEmail) && !strings.EqualFold(addr.Address, user.Email) {
+            return true
+        }
+    }
+    return false
+}
+
+// nextReportRun is the first scheduled send time after now.
+func nextReportRun(schedule *models.ReportSchedule, now time.Time) time.Time {
+    loc, err := time.LoadLocation(schedule.TimeZone)
+    if err != nil {
+        loc = time.UTC
+    }
+    day := startOfDay(now, loc)
+    for i := 0; i <= 7; i++ {
+        d := day.AddDate(0, 0, i)
+        run := time.Date(d.Year(), d.Month(), d.Day(), schedule.Hour, 0, 0, 0, loc)
+        if int(d.Weekday()) == schedule.Weekday && run.After(now) {
+            return run
+        }
+    }
+    return day.AddDate(0, 0, 7)
+}
+
+// startOfWeek is midnight on the Monday of t's week.
+func startOfWeek(t time.Time, loc *time.Location) time.Time {
+    day := startOfDay(t, loc)
+    offset := (int(day.Weekday()) + 6) % 7
+    return day.AddDate(0, 0, -offset)
+}
+
+func reportItem(a *models.Assignment, minutesByAssignment map[uint]int) report.Item {
+    return report.Item{
+        ID:       a.ID,
+        Title:    a.Title,
+        Course:   reportCourse(a),
+        DueDate:  a.DueDate,
+        Priority: a.Priority,
+        Status:   a.Status,
+        Hours:    minutesToHours(minutesByAssignment[a.ID]),
+    }
+}
+
+func reportCourse(a *models.Assignment) string {
+    if a == nil || a.Course == nil {
+        return ""
+    }
+    return a.Course.CourseCode
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+var ErrInvalidTerm = errors.New("invalid term")
+
+type TermService struct {
+    db *gorm.DB
+}
+
+func NewTermService() *TermService {
+    return &TermService{
+        db: database.GetDB(),
+    }
+}
+
+// Dates in term requests are YYYY-MM-DD.
+type TermBreakRequest struct {
+    Name      string `json:"name" binding:"required"`
+    StartDate string `json:"start_date" binding:"required"`
+    EndDate   string `json:"end_date" binding:"required"`
+}
+
+type CreateTermRequest struct {
+    Name               string             `json:"name" binding:"required"`
+    StartDate          string             `json:"start_date" binding:"required"`
+    EndDate            string             `json:"end_date" binding:"required"`
+    AddDropDeadline    string             `json:"add_drop_deadline"`
+    WithdrawalDeadline string             `json:"withdrawal_deadline"`
+    Breaks             []TermBreakRequest `json:"breaks" binding:"dive"`
+}
+
+type UpdateTermRequest struct {
+    Name               *string             `json:"name"`
+    StartDate          *string             `json:"start_date"`
+    EndDate            *string             `json:"end_date"`
+    AddDropDeadline    *string             `json:"add_drop_deadline"` // "" clears it
+    WithdrawalDeadline *string             `json:"withdrawal_deadline"`
+    Breaks             *[]TermBreakRequest `json:"breaks" binding:"omitempty,dive"`
+}
+
+// CurrentTerm is the term in session on a given day, or the next one to
+// start when the user is between terms.
+type CurrentTerm struct {
+    Term      *models.Term      `json:"term"`
+    InSession bool              `json:"in_session"`
+    Week      int               `json:"week,omitempty"` // 1-based week of term while in session
+    DaysLeft  int               `json:"days_left"`      // Until the end of the term, or until it starts
+    OnBreak   *models.TermBreak `json:"on_break,omitempty"`
+}
+
+func (s *TermService) GetTerms(userID uint) ([]models.Term, error) {
+    var terms []models.Term
+    err := s.db.Where("user_id = ?", userID).Order("start_date ASC, id ASC").Find(&terms).Error
+    return terms, err
+}
+
+func (s *TermService) GetTerm(userID, termID uint) (*models.Term, error) {
+    var term models.Term
+    err := s.db.Where("id = ? AND user_id = ?", termID, userID).First(&term).Error
+    return &term, err
+}
+
+// GetCurrentTerm finds the term in session on now's date. When terms
+// overlap the one that started last wins. Between terms the next one to
+// start is returned instead.
+func (s *TermService) GetCurrentTerm(userID uint, now time.Time) (*CurrentTerm, error) {
+    today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+    var term models.Term
+    err := s.db.Where("user_id = ? AND start_date <= ? AND end_date >= ?", userID, today, today).
+        Order("start_date DESC").First(&term).Error
+    if err == nil {
+        current := &CurrentTerm{
+            Term:      &term,
+            InSession: true,
+            Week:      int(today.Sub(term.StartDate).Hours()/24)/7 + 1,
+            DaysLeft:  int(term.EndDate.Sub(today).Hours() / 24),
+        }
+        for i := range term.Breaks {
+            b := term.Breaks[i]
+            if !today.Before(b.StartDate) && !today.After(b.EndDate) {
+                current.OnBreak = &b
+                break
+            }
+        }
+        return current, nil
+    }
+    if !errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil, err
+    }
+
+    if err := s.db.Where("user_id = ? AND start_date > ?", userID, today).
+        Order("start_date ASC").First(&term).Error; err != nil {
+        return nil, err
+    }
+    return &CurrentTerm{
+        Term:     &term,
+        DaysLeft: int(term.StartDate.Sub(today).Hours() / 24),
+    }, nil
+}
+
+func (s *TermService) CreateTerm(userID uint, req CreateTermRequest) (*models.Term, error) {
+    term := models.Term{UserID: userID, Name: strings.TrimSpace(req.Name), Breaks: []models.TermBreak{}}
+
+    var err error
+    if term.StartDate, err = parseTermDate("start_date", req.StartDate); err != nil {
+        return nil, err
+    }
+    if term.EndDate, err = parseTermDate("end_date", req.EndDate); err != nil {
+        return nil, err
+    }
+    if term.AddDropDeadline, err = parseOptionalTermDate("add_drop_deadline", req.AddDropDeadline); err != nil {
+        return nil, err
+    }
+    if term.WithdrawalDeadline, err = parseOptionalTermDate("withdrawal_deadline", req.WithdrawalDeadline); err != nil {
+        return nil, err
+    }
+    if term.Breaks, err = termBreaks(req.Breaks); err != nil {
+        return nil, err
+    }
+    if err := normalizeTerm(&term); err != nil {
+        return nil, err
+    }
+
+    if err := s.db.Create(&term).Error; err != nil {
+        return nil, err
+    }
+    return &term, nil
+}
+
+// UpdateTerm edits a term. A rename is carried over to the semester string
+// of its courses.
+func (s *TermService) UpdateTerm(userID, termID uint, req UpdateTermRequest) (*models.Term, error) {
+    term, err := s.GetTerm(userID, termID)
+    if err != nil {
+        return nil, err
+    }
+
+    if req.Name != nil {
+        term.Name = strings.TrimSpace(*req.Name)
+    }
+    if req.StartDate != nil {
+        if term.StartDate, err = parseTermDate("start_date", *req.StartDate); err != nil {
+            return nil, err
+        }
+    }
+    if req.EndDate != nil {
+        if term.EndDate, err = parseTermDate("end_date", *req.EndDate); err != nil {
+            return nil, err
+        }
+    }
+    if req.AddDropDeadline != nil {
+        if term.AddDropDeadline, err = parseOptionalTermDate("add_drop_deadline", *req.AddDropDeadline); err != nil {
+            return nil, err
+        }
+    }
+    if req.WithdrawalDeadline != nil {
+        if term.WithdrawalDeadline, err = parseOptionalTermDate("withdrawal_deadline", *req.WithdrawalDeadline); err != nil {
+            return nil, err
+        }
+    }
+    if req.Breaks != nil {
+        if term.Breaks, err = termBreaks(*req.Breaks); err != nil {
+            return nil, err
+        }
+    }
+    if err := normalizeTerm(term); err != nil {
+        return nil, err
+    }
+    // Any edit means the dates are the user's own now
+    term.DatesEstimated = false
+
+    err = s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Save(term).Error; err != nil {
+            return err
+        }
+        return tx.Model(&models.Course{}).Where("term_id = ? AND user_id = ?", term.ID, userID).
+            Update("semester", term.Name).Error
+    })
+    if err != nil {
+        return nil, err
+    }
+    return term, nil
+}
+
+// DeleteTerm removes a term; its courses keep their semester string but
+// are unlinked.
+func (s *TermService) DeleteTerm(userID, termID uint) error {
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("id = ? AND user_id = ?", termID, userID).Delete(&models.Term{})
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return gorm.ErrRecordNotFound
+        }
+        return tx.Model(&models.Course{}).Where("term_id = ? AND user_id = ?", termID, userID).
+            Update("term_id", nil).Error
+    })
+}
+
+// linkCourseTerm sets course.TermID from its semester string, creating a
+// term with estimated dates when the user has none by that name. Semesters
+// that don't read like "Fall 2024" and match no existing term stay
+// unlinked.
+func linkCourseTerm(tx *gorm.DB, course *models.Course) error {
+    course.TermID = nil
+    name := strings.TrimSpace(course.Semester)
+    if name == "" {
+        return nil
+    }
+
+    var term models.Term
+    err := tx.Where("user_id = ? AND LOWER(name) = LOWER(?)", course.UserID, name).First(&term).Error
+    if err == nil {
+        course.TermID = &term.ID
+        return nil
+    }
+    if !errors.Is(err, gorm.ErrRecordNotFound) {
+        return err
+    }
+
+    estimated, ok := models.NewEstimatedTerm(course.UserID, name)
+    if !ok {
+        return nil
+    }
+    if err := tx.Where("user_id = ? AND LOWER(name) = LOWER(?)", course.UserID, estimated.Name).
+        Attrs(*estimated).FirstOrCreate(&term).Error; err != nil {
+        return err
+    }
+    course.TermID = &term.ID
+    return nil
+}
+
+// normalizeTerm checks a term's dates and fills in season and year from
+// its name when it has a conventional one.
+func normalizeTerm(term *models.Term) error {
+    if term.Name == "" {
+        return fmt.Errorf("%w: name is required", ErrInvalidTerm)
+    }
+    if term.EndDate.Before(term.StartDate) {
+        return fmt.Errorf("%w: end_date must not be before start_date", ErrInvalidTerm)
+    }
+    within := func(t time.Time) bool {
+        return !t.Before(term.StartDate) && !t.After(term.EndDate)
+    }
+    if term.AddDropDeadline != nil && !within(*term.AddDropDeadline) {
+        return fmt.Errorf("%w: add_drop_deadline must fall within the term", ErrInvalidTerm)
+    }
+    if term.WithdrawalDeadline != nil && !within(*term.WithdrawalDeadline) {
+        return fmt.Errorf("%w: withdrawal_deadline must fall within the term", ErrInvalidTerm)
+    }
+    for _, b := range term.Breaks {
+        if b.EndDate.Before(b.StartDate) || !within(b.StartDate) || !within(b.EndDate) {
+            return fmt.Errorf("%w: break %q must fall within the term and end after it starts", ErrInvalidTerm, b.Name)
+        }
+    }
+
+    term.Season, term.Year = "", 0
+    if season, year, ok := models.ParseTermName(term.Name); ok {
+        term.Season, term.Year = season, year
+    }
+    return nil
+}
+
+func termBreaks(reqs []TermBreakRequest) ([]models.TermBreak, error) {
+    breaks := []models.TermBreak{}
+    for _, req := range reqs {
+        start, err := parseTermDate("break start_date", req.StartDate)
+        if err != nil {
+            return nil, err
+        }
+        end, err := parseTermDate("break end_date", req.EndDate)
+        if err != nil {
+            return nil, err
+        }
+        breaks = append(breaks, models.TermBreak{Name: req.Name, StartDate: start, EndDate: end})
+    }
+    return breaks, nil
+}
+
+func parseTermDate(field, value string) (time.Time, error) {
+    t, err := time.Parse(dateLayout, value)
+    if err != nil {
+        return time.Time{}, fmt.Errorf("%w: %s must be YYYY-MM-DD", ErrInvalidTerm, field)
+    }
+    return t, nil
+}
+
+func parseOptionalTermDate(field, value string) (*time.Time, error) {
+    if value == "" {
+        return nil, nil
+    }
+    t, err := parseTermDate(field, value)
+    if err != nil {
+        return nil, err
+    }
+    return &t, nil
+}
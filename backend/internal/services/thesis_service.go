@@ -0,0 +1,740 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "strings"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+)
+
+const (
+    ChapterDraft  = "draft"
+    ChapterReview = "review"
+    ChapterFinal  = "final"
+
+    wordCountSourceManual = "manual"
+    wordCountSourceAPI    = "api"
+
+    // burnUpRateDays is the window the recent writing pace is measured over.
+    burnUpRateDays = 28
+)
+
+var (
+    ErrInvalidThesis     = errors.New("invalid thesis")
+    ErrMilestoneNotFound = errors.New("milestone not found")
+)
+
+var chapterStatuses = []string{ChapterDraft, ChapterReview, ChapterFinal}
+
+type ThesisService struct {
+    db *gorm.DB
+}
+
+func NewThesisService() *ThesisService {
+    return &ThesisService{
+        db: database.GetDB(),
+    }
+}
+
+type ChapterRequest struct {
+    Title        string `json:"title" binding:"required"`
+    Key          string `json:"key"`      // Defaults to a slug of the title
+    Position     *int   `json:"position"` // Defaults to after the last chapter
+    TargetWords  int    `json:"target_words" binding:"gte=0"`
+    CurrentWords int    `json:"current_words" binding:"gte=0"`
+    Status       string `json:"status"`
+    Notes        string `json:"notes"`
+}
+
+type UpdateChapterRequest struct {
+    Title        *string `json:"title"`
+    Key          *string `json:"key"`
+    Position     *int    `json:"position"`
+    TargetWords  *int    `json:"target_words" binding:"omitempty,gte=0"`
+    CurrentWords *int    `json:"current_words" binding:"omitempty,gte=0"`
+    Status       *string `json:"status"`
+    Notes        *string `json:"notes"`
+}
+
+type CreateThesisRequest struct {
+    Title       string           `json:"title" binding:"required"`
+    StartDate   *time.Time       `json:"start_date"`
+    DefenseDate *time.Time       `json:"defense_date"`
+    MilestoneID *uint            `json:"milestone_id"`
+    Chapters    []ChapterRequest `json:"chapters" binding:"dive"`
+}
+
+type UpdateThesisRequest struct {
+    Title            *string    `json:"title"`
+    StartDate        *time.Time `json:"start_date"`
+    DefenseDate      *time.Time `json:"defense_date"`
+    ClearDefenseDate bool       `json:"clear_defense_date"` // Falls back to the defense milestone
+    MilestoneID      *uint      `json:"milestone_id"`       // 0 unlinks the milestone
+}
+
+// WordCount is one chapter's count in a push, matched by chapter_id or,
+// failing that, by key.
+type WordCount struct {
+    ChapterID uint   `json:"chapter_id"`
+    Key       string `json:"key"`
+    Words     int    `json:"words" binding:"gte=0"`
+}
+
+type WordCountPush struct {
+    Source string      `json:"source"` // Name of the tool pushing, e.g. vscode or git-hook
+    Counts []WordCount `json:"counts" binding:"required,dive"`
+}
+
+type WordCountPushResult struct {
+    Updated   []models.ThesisChapter `json:"updated"`
+    Unmatched []WordCount            `json:"unmatched"`
+}
+
+type ChapterProgress struct {
+    ID           uint    `json:"id"`
+    Title        string  `json:"title"`
+    Status       string  `json:"status"`
+    TargetWords  int     `json:"target_words"`
+    CurrentWords int     `json:"current_words"`
+    Percent      float64 `json:"percent"`
+}
+
+// BurnUpPoint is the state at the end of one week. Words is nil for weeks
+// still to come; Ideal is the straight line from nothing at the start to
+// the target at the defense.
+type BurnUpPoint struct {
+    Date   string `json:"date"`
+    Words  *int   `json:"words"`
+    Target int    `json:"target"`
+    Ideal  *int   `json:"ideal,omitempty"`
+}
+
+type ThesisBurnUp struct {
+    ThesisID            uint              `json:"thesis_id"`
+    Title               string            `json:"title"`
+    StartDate           string            `json:"start_date"`
+    DefenseDate         string            `json:"defense_date,omitempty"`
+    DefenseSource       string            `json:"defense_source,omitempty"` // thesis or milestone
+    TargetWords         int               `json:"target_words"`
+    CurrentWords        int               `json:"current_words"`
+    Percent             float64           `json:"percent"`
+    StatusCounts        map[string]int    `json:"status_counts"`
+    RecentWordsPerDay   float64           `json:"recent_words_per_day"` // Over the last 28 days
+    RequiredWordsPerDay *float64          `json:"required_words_per_day,omitempty"`
+    ProjectedFinish     string            `json:"projected_finish,omitempty"`
+    OnTrack             *bool             `json:"on_track"` // Nil without a defense date or a target
+    Chapters            []ChapterProgress `json:"chapters"`
+    Points              []BurnUpPoint     `json:"points"`
+}
+
+func (s *ThesisService) GetTheses(userID uint) ([]models.Thesis, error) {
+    var theses []models.Thesis
+    err := s.db.Where("user_id = ?", userID).
+        Preload("Chapters", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC, id ASC") }).
+        Order("created_at DESC").
+        Find(&theses).Error
+    return theses, err
+}
+
+func (s *ThesisService) GetThesis(userID, thesisID uint) (*models.Thesis, error) {
+    var thesis models.Thesis
+    err := s.db.Where("id = ? AND user_id = ?", thesisID, userID).
+        Preload("Chapters", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC, id ASC") }).
+        First(&thesis).Error
+    return &thesis, err
+}
+
+func (s *ThesisService) CreateThesis(userID uint, req CreateThesisRequest, now time.Time) (*models.Thesis, error) {
+    token, err := generateToken()
+    if err != nil {
+        return nil, err
+    }
+    thesis := models.Thesis{
+        UserID:      userID,
+        Title:       strings.TrimSpace(req.Title),
+        StartDate:   req.StartDate,
+        DefenseDate: req.DefenseDate,
+        MilestoneID: req.MilestoneID,
+        PushToken:   token,
+    }
+    if thesis.StartDate == nil {
+        thesis.StartDate = &now
+    }
+    if err := normalizeThesis(&thesis); err != nil {
+        return nil, err
+    }
+    chapters := make([]models.ThesisChapter, 0, len(req.Chapters))
+    for i, c := range req.Chapters {
+        chapter := newChapter(userID, c, i+1)
+        if err := normalizeChapter(&chapter); err != nil {
+            return nil, err
+        }
+        chapters = append(chapters, chapter)
+    }
+    if err := checkChapterKeys(chapters); err != nil {
+        return nil, err
+    }
+
+    loc := plannerLocation(s.db, userID)
+    err = s.db.Transaction(func(tx *gorm.DB) error {
+        if err := checkMilestone(tx, userID, thesis.MilestoneID); err != nil {
+            return err
+        }
+        if err := tx.Create(&thesis).Error; err != nil {
+            return err
+        }
+        for i := range chapters {
+            chapters[i].ThesisID = thesis.ID
+            if chapters[i].CurrentWords > 0 {
+                chapters[i].WordsUpdatedAt = &now
+            }
+            if err := tx.Create(&chapters[i]).Error; err != nil {
+                return err
+            }
+            if chapters[i].CurrentWords > 0 {
+                if err := recordWordCount(tx, &chapters[i], wordCountSourceManual, now, loc); err != nil {
+                    return err
+                }
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return s.GetThesis(userID, thesis.ID)
+}
+
+func (s *ThesisService) UpdateThesis(userID, thesisID uint, req UpdateThesisRequest) (*models.Thesis, error) {
+    var thesis models.Thesis
+    if err := s.db.Where("id = ? AND user_id = ?", thesisID, userID).First(&thesis).Error; err != nil {
+        return nil, err
+    }
+
+    if req.Title != nil {
+        thesis.Title = strings.TrimSpace(*req.Title)
+    }
+    if req.StartDate != nil {
+        thesis.StartDate = req.StartDate
+    }
+    if req.ClearDefenseDate {
+        thesis.DefenseDate = nil
+    }
+    if req.DefenseDate != nil {
+        thesis.DefenseDate = req.DefenseDate
+    }
+    if req.MilestoneID != nil {
+        thesis.MilestoneID = req.MilestoneID
+        if *req.MilestoneID == 0 {
+            thesis.MilestoneID = nil
+        }
+    }
+    if err := normalizeThesis(&thesis); err != nil {
+        return nil, err
+    }
+    if err := checkMilestone(s.db, userID, thesis.MilestoneID); err != nil {
+        return nil, err
+    }
+
+    if err := s.db.Save(&thesis).Error; err != nil {
+        return nil, err
+    }
+    return s.GetThesis(userID, thesis.ID)
+}
+
+// DeleteThesis removes the thesis with its chapters and word count history.
+func (s *ThesisService) DeleteThesis(userID, thesisID uint) error {
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("id = ? AND user_id = ?", thesisID, userID).Delete(&models.Thesis{})
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return gorm.ErrRecordNotFound
+        }
+        if err := tx.Where("thesis_id = ? AND user_id = ?", thesisID, userID).Delete(&models.ThesisChapter{}).Error; err != nil {
+            return err
+        }
+        return tx.Where("thesis_id = ? AND user_id = ?", thesisID, userID).Delete(&models.WordCountSample{}).Error
+    })
+}
+
+// RotatePushToken replaces the token used to push word counts, so the old
+// push URL stops working.
+func (s *ThesisService) RotatePushToken(userID, thesisID uint) (*models.Thesis, error) {
+    var thesis models.Thesis
+    if err := s.db.Where("id = ? AND user_id = ?", thesisID, userID).First(&thesis).Error; err != nil {
+        return nil, err
+    }
+    token, err := generateToken()
+    if err != nil {
+        return nil, err
+    }
+    if err := s.db.Model(&thesis).Update("push_token", token).Error; err != nil {
+        return nil, err
+    }
+    return &thesis, nil
+}
+
+func (s *ThesisService) CreateChapter(userID, thesisID uint, req ChapterRequest, now time.Time) (*models.ThesisChapter, error) {
+    var thesis models.Thesis
+    if err := s.db.Where("id = ? AND user_id = ?", thesisID, userID).First(&thesis).Error; err != nil {
+        return nil, err
+    }
+    var chapters []models.ThesisChapter
+    if err := s.db.Where("thesis_id = ? AND user_id = ?", thesisID, userID).Find(&chapters).Error; err != nil {
+        return nil, err
+    }
+    last := 0
+    for _, c := range chapters {
+        if c.Position > last {
+            last = c.Position
+        }
+    }
+    chapter := newChapter(userID, req, last+1)
+    chapter.ThesisID = thesis.ID
+    if err := normalizeChapter(&chapter); err != nil {
+        return nil, err
+    }
+    if err := checkChapterKeys(append(chapters, chapter)); err != nil {
+        return nil, err
+    }
+
+    loc := plannerLocation(s.db, userID)
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if chapter.CurrentWords > 0 {
+            chapter.WordsUpdatedAt = &now
+        }
+        if err := tx.Create(&chapter).Error; err != nil {
+            return err
+        }
+        if chapter.CurrentWords == 0 {
+            return nil
+        }
+        return recordWordCount(tx, &chapter, wordCountSourceManual, now, loc)
+    })
+    return &chapter, err
+}
+
+func (s *ThesisService) UpdateChapter(userID, thesisID, chapterID uint, req UpdateChapterRequest, now time.Time) (*models.ThesisChapter, error) {
+    var chapter models.ThesisChapter
+    if err := s.db.Where("id = ? AND thesis_id = ? AND user_id = ?", chapterID, thesisID, userID).First(&chapter).Error; err != nil {
+        return nil, err
+    }
+
+    if req.Title != nil {
+        chapter.Title = strings.TrimSpace(*req.Title)
+    }
+    if req.Key != nil {
+        chapter.Key = *req.Key
+    }
+    if req.Position != nil {
+        chapter.Position = *req.Position
+    }
+    if req.TargetWords != nil {
+        chapter.TargetWords = *req.TargetWords
+    }
+    wordsChanged := req.CurrentWords != nil && *req.CurrentWords != chapter.CurrentWords
+    if wordsChanged {
+        chapter.CurrentWords = *req.CurrentWords
+        chapter.WordsUpdatedAt = &now
+    }
+    if req.Status != nil {
+        chapter.Status = *req.Status
+    }
+    if req.Notes != nil {
+        chapter.Notes = *req.Notes
+    }
+    if err := normalizeChapter(&chapter); err != nil {
+        return nil, err
+    }
+    if req.Key != nil {
+        var chapters []models.ThesisChapter
+        if err := s.db.Where("thesis_id = ? AND user_id = ? AND id <> ?", thesisID, userID, chapterID).Find(&chapters).Error; err != nil {
+            return nil, err
+        }
+        if err := checkChapterKeys(append(chapters, chapter)); err != nil {
+            return nil, err
+        }
+    }
+
+    loc := plannerLocation(s.db, userID)
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Save(&chapter).Error; err != nil {
+            return err
+        }
+        if !wordsChanged {
+            return nil
+        }
+        return recordWordCount(tx, &chapter, wordCountSourceManual, now, loc)
+    })
+    return &chapter, err
+}
+
+func (s *ThesisService) DeleteChapter(userID, thesisID, chapterID uint) error {
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("id = ? AND thesis_id = ? AND user_id = ?", chapterID, thesisID, userID).Delete(&models.ThesisChapter{})
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return gorm.ErrRecordNotFound
+        }
+        return tx.Where("chapter_id = ? AND user_id = ?", chapterID, userID).Delete(&models.WordCountSample{}).Error
+    })
+}
+
+// PushWordCounts sets the current word counts of the thesis's chapters.
+// Counts that match no chapter are returned rather than failing the push,
+// so a hook that also counts front matter or appendices keeps working.
+func (s *ThesisService) PushWordCounts(userID, thesisID uint, push WordCountPush, now time.Time) (*WordCountPushResult, error) {
+    var thesis models.Thesis
+    if err := s.db.Where("id = ? AND user_id = ?", thesisID, userID).First(&thesis).Error; err != nil {
+        return nil, err
+    }
+    return s.pushWordCounts(&thesis, push, now)
+}
+
+// PushWordCountsByToken is PushWordCounts for editor plugins and git hooks,
+// which identify the thesis by its push token. An unknown token yields
+// gorm.ErrRecordNotFound.
+func (s *ThesisService) PushWordCountsByToken(token string, push WordCountPush, now time.Time) (*WordCountPushResult, error) {
+    var thesis models.Thesis
+    if err := s.db.Where("push_token = ?", token).First(&thesis).Error; err != nil {
+        return nil, err
+    }
+    return s.pushWordCounts(&thesis, push, now)
+}
+
+func (s *ThesisService) pushWordCounts(thesis *models.Thesis, push WordCountPush, now time.Time) (*WordCountPushResult, error) {
+    var chapters []models.ThesisChapter
+    if err := s.db.Where("thesis_id = ? AND user_id = ?", thesis.ID, thesis.UserID).Find(&chapters).Error; err != nil {
+        return nil, err
+    }
+    byID := make(map[uint]*models.ThesisChapter, len(chapters))
+    byKey := make(map[string]*models.ThesisChapter, len(chapters))
+    for i := range chapters {
+        byID[chapters[i].ID] = &chapters[i]
+        if chapters[i].Key != "" {
+            byKey[strings.ToLower(chapters[i].Key)] = &chapters[i]
+        }
+    }
+
+    source := strings.TrimSpace(push.Source)
+    if source == "" {
+        source = wordCountSourceAPI
+    }
+    result := &WordCountPushResult{Updated: []models.ThesisChapter{}, Unmatched: []WordCount{}}
+    matched := make(map[uint]bool)
+    for _, count := range push.Counts {
+        chapter := byID[count.ChapterID]
+        if chapter == nil && count.Key != "" {
+            chapter = byKey[strings.ToLower(strings.TrimSpace(count.Key))]
+        }
+        if chapter == nil {
+            result.Unmatched = append(result.Unmatched, count)
+            continue
+        }
+        chapter.CurrentWords = count.Words
+        chapter.WordsUpdatedAt = &now
+        matched[chapter.ID] = true
+    }
+
+    loc := plannerLocation(s.db, thesis.UserID)
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        for i := range chapters {
+            if !matched[chapters[i].ID] {
+                continue
+            }
+            if err := tx.Model(&chapters[i]).Updates(map[string]interface{}{
+                "current_words":    chapters[i].CurrentWords,
+                "words_updated_at": chapters[i].WordsUpdatedAt,
+            }).Error; err != nil {
+                return err
+            }
+            if err := recordWordCount(tx, &chapters[i], source, now, loc); err != nil {
+                return err
+            }
+            result.Updated = append(result.Updated, chapters[i])
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return result, nil
+}
+
+// BurnUp compares the words written with the chapters' targets week by
+// week, from the start of the thesis to the defense. The pace over the
+// last four weeks projects when the target will be reached.
+func (s *ThesisService) BurnUp(userID, thesisID uint, now time.Time) (*ThesisBurnUp, error) {
+    thesis, err := s.GetThesis(userID, thesisID)
+    if err != nil {
+        return nil, err
+    }
+    var samples []models.WordCountSample
+    if err := s.db.Where("thesis_id = ? AND user_id = ?", thesis.ID, userID).
+        Order("day ASC").
+        Find(&samples).Error; err != nil {
+        return nil, err
+    }
+    loc := plannerLocation(s.db, userID)
+    today := startOfDay(now, loc)
+
+    burnUp := &ThesisBurnUp{
+        ThesisID:     thesis.ID,
+        Title:        thesis.Title,
+        StatusCounts: map[string]int{ChapterDraft: 0, ChapterReview: 0, ChapterFinal: 0},
+        Chapters:     make([]ChapterProgress, 0, len(thesis.Chapters)),
+        Points:       []BurnUpPoint{},
+    }
+    chapterIDs := make(map[uint]bool, len(thesis.Chapters))
+    for _, c := range thesis.Chapters {
+        chapterIDs[c.ID] = true
+        burnUp.TargetWords += c.TargetWords
+        burnUp.CurrentWords += c.CurrentWords
+        burnUp.StatusCounts[c.Status]++
+        burnUp.Chapters = append(burnUp.Chapters, ChapterProgress{
+            ID:           c.ID,
+            Title:        c.Title,
+            Status:       c.Status,
+            TargetWords:  c.TargetWords,
+            CurrentWords: c.CurrentWords,
+            Percent:      wordPercent(c.CurrentWords, c.TargetWords),
+        })
+    }
+    burnUp.Percent = wordPercent(burnUp.CurrentWords, burnUp.TargetWords)
+
+    // Latest sample per chapter on or before a day; chapters that have
+    // been deleted don't count.
+    wordsOn := func(day time.Time) int {
+        key := day.Format(dateLayout)
+        latest := make(map[uint]int)
+        for _, sample := range samples {
+            if sample.Day > key {
+                break
+            }
+            if chapterIDs[sample.ChapterID] {
+                latest[sample.ChapterID] = sample.Words
+            }
+        }
+        total := 0
+        for _, words := range latest {
+            total += words
+        }
+        return total
+    }
+
+    start := localDate(thesis.CreatedAt.In(loc), loc)
+    if thesis.StartDate != nil {
+        start = localDate(*thesis.StartDate, loc)
+    }
+    burnUp.StartDate = start.Format(dateLayout)
+    defense, source, err := s.defenseDate(thesis)
+    if err != nil {
+        return nil, err
+    }
+    var end time.Time
+    if defense != nil {
+        end = localDate(*defense, loc)
+        burnUp.DefenseDate = end.Format(dateLayout)
+        burnUp.DefenseSource = source
+    }
+
+    last := today
+    if end.After(last) {
+        last = end
+    }
+    span := end.Sub(start).Hours() / 24
+    for day := startOfWeek(start, loc).AddDate(0, 0, 6); ; day = day.AddDate(0, 0, 7) {
+        if day.After(last) {
+            day = last
+        }
+        point := BurnUpPoint{Date: day.Format(dateLayout), Target: burnUp.TargetWords}
+        if !day.After(today) {
+            words := wordsOn(day)
+            point.Words = &words
+        }
+        if defense != nil && span > 0 {
+            elapsed := math.Min(math.Max(day.Sub(start).Hours()/24/span, 0), 1)
+            ideal := int(math.Round(float64(burnUp.TargetWords) * elapsed))
+            point.Ideal = &ideal
+        }
+        burnUp.Points = append(burnUp.Points, point)
+        if !day.Before(last) {
+            break
+        }
+    }
+
+    written := burnUp.CurrentWords - wordsOn(today.AddDate(0, 0, -burnUpRateDays))
+    burnUp.RecentWordsPerDay = math.Round(float64(written)/burnUpRateDays*10) / 10
+    remaining := burnUp.TargetWords - burnUp.CurrentWords
+    if burnUp.TargetWords > 0 && remaining <= 0 {
+        burnUp.ProjectedFinish = today.Format(dateLayout)
+    } else if remaining > 0 && written > 0 {
+        days := int(math.Ceil(float64(remaining) / (float64(written) / burnUpRateDays)))
+        burnUp.ProjectedFinish = today.AddDate(0, 0, days).Format(dateLayout)
+    }
+    if defense != nil && burnUp.TargetWords > 0 {
+        onTrack := remaining <= 0
+        if days := end.Sub(today).Hours() / 24; remaining > 0 && days > 0 {
+            required := math.Round(float64(remaining)/days*10) / 10
+            burnUp.RequiredWordsPerDay = &required
+            onTrack = burnUp.ProjectedFinish != "" && burnUp.ProjectedFinish <= burnUp.DefenseDate
+        }
+        burnUp.OnTrack = &onTrack
+    }
+    return burnUp, nil
+}
+
+// defenseDate is the thesis's own defense date, else the date of its
+// milestone, else of the user's defense milestone.
+func (s *ThesisService) defenseDate(thesis *models.Thesis) (*time.Time, string, error) {
+    if thesis.DefenseDate != nil {
+        return thesis.DefenseDate, "thesis", nil
+    }
+    var milestone models.Milestone
+    query := s.db.Where("user_id = ?", thesis.UserID)
+    if thesis.MilestoneID != nil {
+        query = query.Where("id = ?", *thesis.MilestoneID)
+    } else {
+        query = query.Where("type = ?", "defense").Order("target_date ASC")
+    }
+    err := query.First(&milestone).Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil, "", nil
+    }
+    if err != nil {
+        return nil, "", err
+    }
+    if milestone.CompletedDate != nil {
+        return milestone.CompletedDate, "milestone", nil
+    }
+    if milestone.TargetDate != nil {
+        return milestone.TargetDate, "milestone", nil
+    }
+    return nil, "", nil
+}
+
+// recordWordCount keeps the chapter's count as the sample for the day, so
+// repeated pushes on one day leave a single point.
+func recordWordCount(tx *gorm.DB, chapter *models.ThesisChapter, source string, now time.Time, loc *time.Location) error {
+    sample := models.WordCountSample{
+        UserID:     chapter.UserID,
+        ThesisID:   chapter.ThesisID,
+        ChapterID:  chapter.ID,
+        Day:        now.In(loc).Format(dateLayout),
+        Words:      chapter.CurrentWords,
+        Source:     source,
+        RecordedAt: now,
+    }
+    // The day's sample is overwritten in one statement, so concurrent
+    // pushes can't both insert one
+    return tx.Clauses(clause.OnConflict{
+        Columns:   []clause.Column{{Name: "chapter_id"}, {Name: "day"}},
+        DoUpdates: clause.AssignmentColumns([]string{"words", "source", "recorded_at"}),
+    }).Create(&sample).Error
+}
+
+func checkMilestone(tx *gorm.DB, userID uint, milestoneID *uint) error {
+    if milestoneID == nil {
+        return nil
+    }
+    var count int64
+    if err := tx.Model(&models.Milestone{}).Where("id = ? AND user_id = ?", *milestoneID, userID).Count(&count).Error; err != nil {
+        return err
+    }
+    if count == 0 {
+        return ErrMilestoneNotFound
+    }
+    return nil
+}
+
+func checkChapterKeys(chapters []models.ThesisChapter) error {
+    seen := make(map[string]bool, len(chapters))
+    for _, c := range chapters {
+        key := strings.ToLower(c.Key)
+        if key == "" {
+            continue
+        }
+        if seen[key] {
+            return fmt.Errorf("%w: duplicate chapter key %q", ErrInvalidThesis, c.Key)
+        }
+        seen[key] = true
+    }
+    return nil
+}
+
+func newChapter(userID uint, req ChapterRequest, position int) models.ThesisChapter {
+    chapter := models.ThesisChapter{
+        UserID:       userID,
+        Position:     position,
+        Key:          req.Key,
+        Title:        strings.TrimSpace(req.Title),
+        TargetWords:  req.TargetWords,
+        CurrentWords: req.CurrentWords,
+        Status:       req.Status,
+        Notes:        req.Notes,
+    }
+    if req.Position != nil {
+        chapter.Position = *req.Position
+    }
+    return chapter
+}
+
+func normalizeThesis(thesis *models.Thesis) error {
+    if thesis.Title == "" {
+        return fmt.Errorf("%w: title is required", ErrInvalidThesis)
+    }
+    if thesis.StartDate != nil && thesis.DefenseDate != nil && !thesis.DefenseDate.After(*thesis.StartDate) {
+        return fmt.Errorf("%w: defense_date must be after start_date", ErrInvalidThesis)
+    }
+    return nil
+}
+
+func normalizeChapter(chapter *models.ThesisChapter) error {
+    if chapter.Title == "" {
+        return fmt.Errorf("%w: chapter title is required", ErrInvalidThesis)
+    }
+    if chapter.Status == "" {
+        chapter.Status = ChapterDraft
+    }
+    if !containsString(chapterStatuses, chapter.Status) {
+        return fmt.Errorf("%w: status must be one of %s", ErrInvalidThesis, strings.Join(chapterStatuses, ", "))
+    }
+    chapter.Key = strings.TrimSpace(chapter.Key)
+    if chapter.Key == "" {
+        chapter.Key = chapterKey(chapter.Title)
+    }
+    return nil
+}
+
+// chapterKey slugs a title, e.g. "Related Work" becomes related-work.
+func chapterKey(title string) string {
+    var b strings.Builder
+    dash := false
+    for _, r := range strings.ToLower(title) {
+        if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+            b.WriteRune(r)
+            dash = false
+        } else if !dash && b.Len() > 0 {
+            b.WriteByte('-')
+            dash = true
+        }
+    }
+    return strings.TrimSuffix(b.String(), "-")
+}
+
+func wordPercent(words, target int) float64 {
+    if target == 0 {
+        return 0
+    }
+    return math.Round(float64(words)/float64(target)*1000) / 10
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+    "errors"
+    "math"
+    "time"
+
+    "github.com/anayy09/academiaflow-backend/internal/database"
+    "github.com/anayy09/academiaflow-backend/internal/models"
+    "gorm.io/gorm"
+)
+
+var (
+    ErrTimerRunning    = errors.New("a timer is already running")
+    ErrNoTimerRunning  = errors.New("no timer is running for this assignment")
+    ErrInvalidTimeSpan = errors.New("ended_at must be after started_at")
+)
+
+type TimeEntryService struct {
+    db *gorm.DB
+}
+
+func NewTimeEntryService() *TimeEntryService {
+    return &TimeEntryService{
+        db: database.GetDB(),
+    }
+}
+
+type TimerRequest struct {
+    Note string `json:"note"`
+}
+
+type CreateTimeEntryRequest struct {
+    StartedAt time.Time `json:"started_at" binding:"required"`
+    EndedAt   time.Time `json:"ended_at" binding:"required"`
+    Note      string    `json:"note"`
+}
+
+type UpdateTimeEntryRequest struct {
+    StartedAt *time.Time `json:"started_at"`
+    EndedAt   *time.Time `json:"ended_at"`
+    Note      *string    `json:"note"`
+}
+
+func (s *TimeEntryService) GetRunningTimer(userID uint) (*models.TimeEntry, error) {
+    var entry models.TimeEntry
+    err := s.db.Where("user_id = ? AND ended_at IS NULL", userID).
+        Preload("Assignment").
+        First(&entry).Error
+    return &entry, err
+}
+
+func (s *TimeEntryService) GetTimeEntries(userID, assignmentID uint) ([]models.TimeEntry, error) {
+    var entries []models.TimeEntry
+    err := s.db.Where("user_id = ? AND assignment_id = ?", userID, assignmentID).
+        Order("started_at DESC").
+        Find(&entries).Error
+    return entries, err
+}
+
+func (s *TimeEntryService) StartTimer(userID, assignmentID uint, req TimerRequest) (*models.TimeEntry, error) {
+    if err := s.checkAssignment(userID, assignmentID); err != nil {
+        return nil, err
+    }
+
+    var running int64
+    if err := s.db.Model(&models.TimeEntry{}).
+        Where("user_id = ? AND ended_at IS NULL", userID).
+        Count(&running).Error; err != nil {
+        return nil, err
+    }
+    if running > 0 {
+        return nil, ErrTimerRunning
+    }
+
+    entry := models.TimeEntry{
+        UserID:       userID,
+        AssignmentID: assignmentID,
+        StartedAt:    time.Now().Truncate(time.Minute),
+        Note:         req.Note,
+    }
+    // The partial unique index on running timers catches concurrent starts
+    if err := s.db.Create(&entry).Error; err != nil {
+        if errors.Is(err, gorm.ErrDuplicatedKey) {
+            return nil, ErrTimerRunning
+        }
+        return nil, err
+    }
+
+    return &entry, nil
+}
+
+func (s *TimeEntryService) StopTimer(userID, assignmentID uint, req TimerRequest) (*models.TimeEntry, error) {
+    var entry models.TimeEntry
+    if err := s.db.Where("user_id = ? AND assignment_id = ? AND ended_at IS NULL", userID, assignmentID).
+        First(&entry).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, ErrNoTimerRunning
+        }
+        return nil, err
+    }
+
+    ended := time.Now().Truncate(time.Minute)
+    if ended.Before(entry.StartedAt) {
+        ended = entry.StartedAt
+    }
+    entry.EndedAt = &ended
+    entry.Minutes = spanMinutes(entry.StartedAt, ended)
+    if req.Note != "" {
+        entry.Note = req.Note
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Save(&entry).Error; err != nil {
+            return err
+        }
+        return recomputeTrackedTime(tx, assignmentID)
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return &entry, nil
+}
+
+func (s *TimeEntryService) CreateTimeEntry(userID, assignmentID uint, req CreateTimeEntryRequest) (*models.TimeEntry, error) {
+    if err := s.checkAssignment(userID, assignmentID); err != nil {
+        return nil, err
+    }
+
+    started := req.StartedAt.Truncate(time.Minute)
+    ended := req.EndedAt.Truncate(time.Minute)
+    if !ended.After(started) {
+        return nil, ErrInvalidTimeSpan
+    }
+
+    entry := models.TimeEntry{
+        UserID:       userID,
+        AssignmentID: assignmentID,
+        StartedAt:    started,
+        EndedAt:      &ended,
+        Minutes:      spanMinutes(started, ended),
+        Note:         req.Note,
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(&entry).Error; err != nil {
+            return err
+        }
+        return recomputeTrackedTime(tx, assignmentID)
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return &entry, nil
+}
+
+func (s *TimeEntryService) UpdateTimeEntry(userID, entryID uint, req UpdateTimeEntryRequest) (*models.TimeEntry, error) {
+    var entry models.TimeEntry
+    if err := s.db.Where("id = ? AND user_id = ?", entryID, userID).First(&entry).Error; err != nil {
+        return nil, err
+    }
+
+    if req.StartedAt != nil {
+        entry.StartedAt = req.StartedAt.Truncate(time.Minute)
+    }
+    if req.EndedAt != nil {
+        ended := req.EndedAt.Truncate(time.Minute)
+        entry.EndedAt = &ended
+    }
+    if req.Note != nil {
+        entry.Note = *req.Note
+    }
+
+    if entry.EndedAt != nil {
+        if !entry.EndedAt.After(entry.StartedAt) {
+            return nil, ErrInvalidTimeSpan
+        }
+        entry.Minutes = spanMinutes(entry.StartedAt, *entry.EndedAt)
+    }
+
+    err := s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Save(&entry).Error; err != nil {
+            return err
+        }
+        return recomputeTrackedTime(tx, entry.AssignmentID)
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return &entry, nil
+}
+
+func (s *TimeEntryService) DeleteTimeEntry(userID, entryID uint) error {
+    var entry models.TimeEntry
+    if err := s.db.Where("id = ? AND user_id = ?", entryID, userID).First(&entry).Error; err != nil {
+        return err
+    }
+
+    return s.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Delete(&entry).Error; err != nil {
+            return err
+        }
+        return recomputeTrackedTime(tx, entry.AssignmentID)
+    })
+}
+
+func (s *TimeEntryService) checkAssignment(userID, assignmentID uint) error {
+    var count int64
+    if err := s.db.Model(&models.Assignment{}).
+        Where("id = ? AND user_id = ?", assignmentID, userID).
+        Count(&count).Error; err != nil {
+        return err
+    }
+    if count == 0 {
+        return gorm.ErrRecordNotFound
+    }
+    return nil
+}
+
+// recomputeTrackedTime derives an assignment's TrackedMinutes and
+// ActualHours from its finished time entries.
+func recomputeTrackedTime(tx *gorm.DB, assignmentID uint) error {
+    var minutes int64
+    if err := tx.Model(&models.TimeEntry{}).
+        Where("assignment_id = ? AND ended_at IS NOT NULL", assignmentID).
+        Select("COALESCE(SUM(minutes), 0)").
+        Scan(&minutes).Error; err != nil {
+        return err
+    }
+
+    return tx.Model(&models.Assignment{}).
+        Where("id = ?", assignmentID).
+        Updates(map[string]interface{}{
+            "tracked_minutes": minutes,
+            "actual_hours":    int(math.Round(float64(minutes) / 60)),
+        }).Error
+}
+
+func spanMinutes(start, end time.Time) int {
+    return int(end.Sub(start) / time.Minute)
+}
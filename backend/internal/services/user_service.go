@@ -2,6 +2,8 @@ package services
 
 import (
     "errors"
+    "strings"
+    "time"
 
     "github.com/anayy09/academiaflow-backend/internal/auth"
     "github.com/anayy09/academiaflow-backend/internal/database"
@@ -36,14 +38,17 @@ type LoginRequest struct {
 }
 
 type UserResponse struct {
-    ID        uint   `json:"id"`
-    Email     string `json:"email"`
-    Username  string `json:"username"`
-    FirstName string `json:"first_name"`
-    LastName  string `json:"last_name"`
-    Program   string `json:"program"`
-    Year      int    `json:"year"`
-    Advisor   string `json:"advisor"`
+    ID               uint       `json:"id"`
+    Email            string     `json:"email"`
+    Username         string     `json:"username"`
+    FirstName        string     `json:"first_name"`
+    LastName         string     `json:"last_name"`
+    Program          string     `json:"program"`
+    Year             int        `json:"year"`
+    Advisor          string     `json:"advisor"`
+    AdvisorEmail     string     `json:"advisor_email"`
+    DegreeProgramID  *uint      `json:"degree_program_id,omitempty"`
+    ProgramStartDate *time.Time `json:"program_start_date,omitempty"`
 }
 
 func (s *UserService) Register(req RegisterRequest) (*models.User, error) {
@@ -91,6 +96,14 @@ func (s *UserService) Login(req LoginRequest) (*models.User, error) {
     return &user, nil
 }
 
+func (s *UserService) GetUserByEmail(email string) (*models.User, error) {
+    var user models.User
+    if err := s.db.Where("email = ?", email).First(&user).Error; err != nil {
+        return nil, err
+    }
+    return &user, nil
+}
+
 func (s *UserService) GetUserByID(id uint) (*models.User, error) {
     var user models.User
     if err := s.db.First(&user, id).Error; err != nil {
@@ -105,6 +118,7 @@ func (s *UserService) UpdateUser(id uint, updates map[string]interface{}) (*mode
         return nil, err
     }
 
+    stampAdvisorEmailChange(updates, &user, time.Now())
     if err := s.db.Model(&user).Updates(updates).Error; err != nil {
         return nil, err
     }
@@ -112,15 +126,27 @@ func (s *UserService) UpdateUser(id uint, updates map[string]interface{}) (*mode
     return &user, nil
 }
 
+// stampAdvisorEmailChange records when updates give the advisor a new
+// email, which holds off reports to it for a while.
+func stampAdvisorEmailChange(updates map[string]interface{}, user *models.User, now time.Time) {
+    email, ok := updates["advisor_email"].(string)
+    if ok && email != "" && !strings.EqualFold(email, user.AdvisorEmail) {
+        updates["advisor_email_changed_at"] = now
+    }
+}
+
 func ToUserResponse(user *models.User) UserResponse {
     return UserResponse{
-        ID:        user.ID,
-        Email:     user.Email,
-        Username:  user.Username,
-        FirstName: user.FirstName,
-        LastName:  user.LastName,
-        Program:   user.Program,
-        Year:      user.Year,
-        Advisor:   user.Advisor,
+        ID:               user.ID,
+        Email:            user.Email,
+        Username:         user.Username,
+        FirstName:        user.FirstName,
+        LastName:         user.LastName,
+        Program:          user.Program,
+        Year:             user.Year,
+        Advisor:          user.Advisor,
+        AdvisorEmail:     user.AdvisorEmail,
+        DegreeProgramID:  user.DegreeProgramID,
+        ProgramStartDate: user.ProgramStartDate,
     }
 }
\ No newline at end of file